@@ -0,0 +1,46 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type APIConfig interface {
+	Enabled() bool
+	Address() string
+	RateLimit() int
+	RateLimitWindow() time.Duration
+}
+
+type apiConfig struct {
+	enabled         bool
+	address         string
+	rateLimit       int
+	rateLimitWindow time.Duration
+}
+
+func NewAPIConfig() APIConfig {
+	return &apiConfig{
+		enabled:         viper.GetBool("settings.api.enabled"),
+		address:         viper.GetString("settings.api.address"),
+		rateLimit:       viper.GetInt("settings.api.rate-limit.requests"),
+		rateLimitWindow: viper.GetDuration("settings.api.rate-limit.window"),
+	}
+}
+
+func (cfg *apiConfig) Enabled() bool {
+	return cfg.enabled
+}
+
+func (cfg *apiConfig) Address() string {
+	return cfg.address
+}
+
+func (cfg *apiConfig) RateLimit() int {
+	return cfg.rateLimit
+}
+
+func (cfg *apiConfig) RateLimitWindow() time.Duration {
+	return cfg.rateLimitWindow
+}
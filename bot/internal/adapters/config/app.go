@@ -14,6 +14,7 @@ type AppConfig interface {
 	QRLogoPath() string
 	VersionNotifyOnStartup() bool
 	VersionChannelID() int64
+	FollowOnRegistration() bool
 }
 
 type appConfig struct {
@@ -26,6 +27,7 @@ type appConfig struct {
 	qrLogoPath                string
 	versionNotifyOnStartup    bool
 	versionChannelID          int64
+	followOnRegistration      bool
 }
 
 func NewAppConfig() AppConfig {
@@ -39,6 +41,7 @@ func NewAppConfig() AppConfig {
 		qrLogoPath:                viper.GetString("settings.qr.logo-path"),
 		versionNotifyOnStartup:    viper.GetBool("settings.version.notify-on-startup"),
 		versionChannelID:          viper.GetInt64("settings.version.channel-id"),
+		followOnRegistration:      viper.GetBool("settings.clubs.follow-on-registration"),
 	}
 }
 
@@ -77,3 +80,8 @@ func (cfg *appConfig) VersionNotifyOnStartup() bool {
 func (cfg *appConfig) VersionChannelID() int64 {
 	return cfg.versionChannelID
 }
+
+// FollowOnRegistration reports whether users follow the club on their first registration for its event
+func (cfg *appConfig) FollowOnRegistration() bool {
+	return cfg.followOnRegistration
+}
@@ -9,6 +9,7 @@ type BotConfig interface {
 	AdminIDs() []int64
 	GrantChatID() int64
 	MailingChannelID() int64
+	AnnouncementChannelID() int64
 	AvatarChannelID() int64
 	IntroChannelID() int64
 	PassChannelID() int64
@@ -17,15 +18,16 @@ type BotConfig interface {
 }
 
 type botConfig struct {
-	token             string
-	adminIDs          []int64
-	grantChatID       int64
-	mailingChannelID  int64
-	avatarChannelID   int64
-	introChannelID    int64
-	passChannelID     int64
-	qrChannelID       int64
-	validEmailDomains []string
+	token                 string
+	adminIDs              []int64
+	grantChatID           int64
+	mailingChannelID      int64
+	announcementChannelID int64
+	avatarChannelID       int64
+	introChannelID        int64
+	passChannelID         int64
+	qrChannelID           int64
+	validEmailDomains     []string
 }
 
 func NewBotConfig() BotConfig {
@@ -35,15 +37,16 @@ func NewBotConfig() BotConfig {
 		adminIDs[i] = int64(v)
 	}
 	return &botConfig{
-		token:             viper.GetString("bot.token"),
-		adminIDs:          adminIDs,
-		grantChatID:       viper.GetInt64("bot.auth.grant-chat-id"),
-		mailingChannelID:  viper.GetInt64("bot.mailing.channel-id"),
-		avatarChannelID:   viper.GetInt64("bot.avatar.channel-id"),
-		introChannelID:    viper.GetInt64("bot.intro.channel-id"),
-		passChannelID:     viper.GetInt64("settings.pass.channel-id"),
-		qrChannelID:       viper.GetInt64("bot.qr.channel-id"),
-		validEmailDomains: viper.GetStringSlice("bot.auth.valid-email-domains"),
+		token:                 viper.GetString("bot.token"),
+		adminIDs:              adminIDs,
+		grantChatID:           viper.GetInt64("bot.auth.grant-chat-id"),
+		mailingChannelID:      viper.GetInt64("bot.mailing.channel-id"),
+		announcementChannelID: viper.GetInt64("bot.announcement.channel-id"),
+		avatarChannelID:       viper.GetInt64("bot.avatar.channel-id"),
+		introChannelID:        viper.GetInt64("bot.intro.channel-id"),
+		passChannelID:         viper.GetInt64("settings.pass.channel-id"),
+		qrChannelID:           viper.GetInt64("bot.qr.channel-id"),
+		validEmailDomains:     viper.GetStringSlice("bot.auth.valid-email-domains"),
 	}
 }
 
@@ -63,6 +66,11 @@ func (cfg *botConfig) MailingChannelID() int64 {
 	return cfg.mailingChannelID
 }
 
+// AnnouncementChannelID is the public channel new events are announced to, 0 if announcements are sent to followers only
+func (cfg *botConfig) AnnouncementChannelID() int64 {
+	return cfg.announcementChannelID
+}
+
 func (cfg *botConfig) AvatarChannelID() int64 {
 	return cfg.avatarChannelID
 }
@@ -18,6 +18,8 @@ type Config struct {
 	App       AppConfig
 	Banner    BannerConfig
 	Session   SessionConfig
+	API       APIConfig
+	Outbox    OutboxConfig
 }
 
 func NewConfig() (*Config, error) {
@@ -53,6 +55,8 @@ func NewConfig() (*Config, error) {
 		App:       NewAppConfig(),
 		Banner:    bannerCfg,
 		Session:   NewSessionConfig(),
+		API:       NewAPIConfig(),
+		Outbox:    NewOutboxConfig(),
 	}
 
 	location.Init(cfg.App.Timezone())
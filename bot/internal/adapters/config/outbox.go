@@ -0,0 +1,38 @@
+package config
+
+import (
+	"github.com/spf13/viper"
+)
+
+type OutboxConfig interface {
+	Workers() int
+	RateLimit() int
+	MaxAttempts() int
+}
+
+type outboxConfig struct {
+	workers     int
+	rateLimit   int
+	maxAttempts int
+}
+
+func NewOutboxConfig() OutboxConfig {
+	return &outboxConfig{
+		workers:     viper.GetInt("settings.outbox.workers"),
+		rateLimit:   viper.GetInt("settings.outbox.rate-limit"),
+		maxAttempts: viper.GetInt("settings.outbox.max-attempts"),
+	}
+}
+
+func (cfg *outboxConfig) Workers() int {
+	return cfg.workers
+}
+
+// RateLimit is the maximum number of messages sent per second
+func (cfg *outboxConfig) RateLimit() int {
+	return cfg.rateLimit
+}
+
+func (cfg *outboxConfig) MaxAttempts() int {
+	return cfg.maxAttempts
+}
@@ -148,7 +148,7 @@ func (wm *WarningsManager) ValidateConfig(cfg *Config) {
 	// App warnings
 	wm.CheckEmptyString("App.Timezone", cfg.App.Timezone(), "timezone functionality may not work")
 	wm.CheckConditionalInt64("App.VersionChannelID", cfg.App.VersionChannelID(), cfg.App.VersionNotifyOnStartup(), "VersionNotifyOnStartup is enabled")
-	wm.CheckEmptySlice("App.PassLocationSubstrings", cfg.App.PassLocationSubstrings(), "pass location validation may not work")
+	wm.CheckEmptySlice("App.PassLocationSubstrings", cfg.App.PassLocationSubstrings(), "the first pass location will match every event")
 	wm.CheckEmptySlice("App.PassEmails", cfg.App.PassEmails(), "pass email notifications may not work")
 	wm.CheckEmptySlice("App.PassExcludedRoles", cfg.App.PassExcludedRoles(), "pass role validation may not work")
 	wm.CheckEmptyString("App.EmailConfirmationTemplate", cfg.App.EmailConfirmationTemplate(), "email confirmation may not work")
@@ -179,6 +179,18 @@ func (wm *WarningsManager) ValidateConfig(cfg *Config) {
 	wm.CheckZeroDuration("Session.EmailTTL", cfg.Session.EmailTTL(), "email sessions may not work")
 	wm.CheckZeroDuration("Session.EventIDTTL", cfg.Session.EventIDTTL(), "event sessions may not work")
 
+	// API warnings
+	wm.CheckConditionalString("API.Address", cfg.API.Address(), cfg.API.Enabled(), "API is enabled")
+	wm.CheckConditionalInt64("API.RateLimit", int64(cfg.API.RateLimit()), cfg.API.Enabled(), "API is enabled")
+	if cfg.API.Enabled() {
+		wm.CheckZeroDuration("API.RateLimitWindow", cfg.API.RateLimitWindow(), "API rate limiting may not work")
+	}
+
+	// Outbox warnings (defaults are used for zero values)
+	wm.CheckZeroInt64("Outbox.Workers", int64(cfg.Outbox.Workers()), "default number of outbox workers will be used")
+	wm.CheckZeroInt64("Outbox.RateLimit", int64(cfg.Outbox.RateLimit()), "default outbox rate limit will be used")
+	wm.CheckZeroInt64("Outbox.MaxAttempts", int64(cfg.Outbox.MaxAttempts()), "default number of delivery attempts will be used")
+
 	// Banner warnings (these are required, but we'll warn instead of error for some)
 	wm.CheckEmptyString("Banner.AuthID", cfg.Banner.AuthID(), "auth banner may not work")
 	wm.CheckEmptyString("Banner.MenuID", cfg.Banner.MenuID(), "menu banner may not work")
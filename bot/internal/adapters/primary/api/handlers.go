@@ -0,0 +1,359 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/validator"
+)
+
+const (
+	defaultEventsLimit  = 20
+	maxEventsLimit      = 100
+	maxPassesPerRequest = 200
+	// maxRequestBodySize is the maximum size of a JSON request body in bytes
+	maxRequestBodySize = 1 << 20
+
+	// validatorTimeLayout is the time format expected by the event validators
+	validatorTimeLayout = "02.01.2006 15:04"
+)
+
+type createEventRequest struct {
+	Name                  string     `json:"name"`
+	Description           string     `json:"description"`
+	AfterRegistrationText string     `json:"after_registration_text"`
+	Location              string     `json:"location"`
+	StartTime             time.Time  `json:"start_time"`
+	EndTime               *time.Time `json:"end_time"`
+	RegistrationEnd       time.Time  `json:"registration_end"`
+	MaxParticipants       int        `json:"max_participants"`
+	ExpectedParticipants  int        `json:"expected_participants"`
+	AllowedRoles          []string   `json:"allowed_roles"`
+}
+
+type requestPassesRequest struct {
+	UserIDs []int64 `json:"user_ids"`
+	Reason  string  `json:"reason"`
+}
+
+// clubEvent returns the event from the path if it belongs to the club of the token
+func (s *Server) clubEvent(w http.ResponseWriter, r *http.Request) (*entity.Event, bool) {
+	token := tokenFromContext(r.Context())
+
+	event, err := s.eventService.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			writeError(w, http.StatusNotFound, "event not found")
+			return nil, false
+		}
+		s.logger.Errorf("(token: %s) error while get event: %v", token.ID, err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return nil, false
+	}
+	if event.ClubID != token.ClubID {
+		writeError(w, http.StatusNotFound, "event not found")
+		return nil, false
+	}
+
+	return event, true
+}
+
+func (s *Server) listEvents(w http.ResponseWriter, r *http.Request) {
+	token := tokenFromContext(r.Context())
+
+	limit, offset := defaultEventsLimit, 0
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || parsed > maxEventsLimit {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxEventsLimit))
+			return
+		}
+		limit = parsed
+	}
+	if value := r.URL.Query().Get("offset"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			writeError(w, http.StatusBadRequest, "offset must be a non-negative number")
+			return
+		}
+		offset = parsed
+	}
+
+	events, err := s.eventService.GetByClubID(r.Context(), limit, offset, token.ClubID)
+	if err != nil {
+		s.logger.Errorf("(token: %s) error while get club events: %v", token.ID, err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	total, err := s.eventService.CountByClubID(r.Context(), token.ClubID)
+	if err != nil {
+		s.logger.Errorf("(token: %s) error while count club events: %v", token.ID, err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	response := eventsResponse{
+		Events: make([]eventResponse, 0, len(events)),
+		Total:  total,
+	}
+	for _, event := range events {
+		response.Events = append(response.Events, newEventResponse(event))
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) getEvent(w http.ResponseWriter, r *http.Request) {
+	event, ok := s.clubEvent(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newEventResponse(*event))
+}
+
+func (s *Server) createEvent(w http.ResponseWriter, r *http.Request) {
+	token := tokenFromContext(r.Context())
+
+	var request createEventRequest
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	club, err := s.clubService.Get(r.Context(), token.ClubID)
+	if err != nil {
+		s.logger.Errorf("(token: %s) error while get club: %v", token.ID, err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	if message := validateCreateEventRequest(request, club); message != "" {
+		writeError(w, http.StatusBadRequest, message)
+		return
+	}
+
+	event := &entity.Event{
+		ClubID:                club.ID,
+		Name:                  request.Name,
+		Description:           request.Description,
+		AfterRegistrationText: request.AfterRegistrationText,
+		Location:              request.Location,
+		StartTime:             request.StartTime.UTC(),
+		RegistrationEnd:       request.RegistrationEnd.UTC(),
+		MaxParticipants:       request.MaxParticipants,
+		ExpectedParticipants:  request.ExpectedParticipants,
+		AllowedRoles:          request.AllowedRoles,
+	}
+	if request.EndTime != nil {
+		event.EndTime = request.EndTime.UTC()
+	}
+
+	passLocation, err := s.passService.MatchLocation(r.Context(), event.Location)
+	if err != nil {
+		s.logger.Errorf("(token: %s) error while match pass location: %v", token.ID, err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	event.SetPassLocation(passLocation)
+
+	event, err = s.eventService.Create(r.Context(), event)
+	if err != nil {
+		s.logger.Errorf("(token: %s) error while create event: %v", token.ID, err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	s.logger.Infof("(token: %s) event created via API (event_id=%s, club_id=%s)", token.ID, event.ID, club.ID)
+	writeJSON(w, http.StatusCreated, newEventResponse(*event))
+}
+
+// validateCreateEventRequest applies the same rules as event creation in the bot.
+// It returns a description of the first invalid field or an empty string.
+func validateCreateEventRequest(request createEventRequest, club *entity.Club) string {
+	startTime := request.StartTime.In(location.Location()).Format(validatorTimeLayout)
+	params := map[string]interface{}{
+		"startTime": startTime,
+	}
+
+	switch {
+	case !validator.EventName(request.Name, nil):
+		return "name must be from 5 to 45 characters"
+	case !validator.EventDescription(request.Description, nil):
+		return "description must be at most 250 characters"
+	case request.AfterRegistrationText != "" && !validator.EventAfterRegistrationText(request.AfterRegistrationText, nil):
+		return "after_registration_text must be from 10 to 150 characters"
+	case !validator.EventLocation(request.Location, nil):
+		return "location must be from 5 to 75 characters"
+	case !validator.EventStartTime(startTime, nil):
+		return "start_time must be at least 24 hours from now"
+	case request.EndTime != nil && !validator.EventEndTime(request.EndTime.In(location.Location()).Format(validatorTimeLayout), params):
+		return "end_time must be after start_time"
+	case !validator.EventRegisteredEndTime(request.RegistrationEnd.In(location.Location()).Format(validatorTimeLayout), params):
+		return "registration_end must be before start_time and at least an hour from now"
+	case request.MaxParticipants < 0:
+		return "max_participants must be non-negative"
+	case request.ExpectedParticipants < 0:
+		return "expected_participants must be non-negative"
+	case len(request.AllowedRoles) == 0:
+		return "allowed_roles must not be empty"
+	}
+
+	for _, role := range request.AllowedRoles {
+		if !slices.Contains(club.AllowedRoles, role) {
+			return fmt.Sprintf("role %q is not allowed for the club", role)
+		}
+	}
+
+	return ""
+}
+
+func (s *Server) listParticipants(w http.ResponseWriter, r *http.Request) {
+	token := tokenFromContext(r.Context())
+
+	event, ok := s.clubEvent(w, r)
+	if !ok {
+		return
+	}
+
+	users, err := s.userService.GetEventUsers(r.Context(), event.ID)
+	if err != nil {
+		s.logger.Errorf("(token: %s) error while get event users: %v", token.ID, err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	response := participantsResponse{
+		Participants: make([]participantResponse, 0, len(users)),
+	}
+	for _, user := range users {
+		response.Participants = append(response.Participants, newParticipantResponse(user))
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) getAttendance(w http.ResponseWriter, r *http.Request) {
+	token := tokenFromContext(r.Context())
+
+	event, ok := s.clubEvent(w, r)
+	if !ok {
+		return
+	}
+
+	registered, err := s.eventParticipantService.CountByEventID(r.Context(), event.ID)
+	if err != nil {
+		s.logger.Errorf("(token: %s) error while count event participants: %v", token.ID, err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	visited, err := s.eventParticipantService.CountVisitedByEventID(r.Context(), event.ID)
+	if err != nil {
+		s.logger.Errorf("(token: %s) error while count event visits: %v", token.ID, err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, attendanceResponse{
+		Registered: registered,
+		Visited:    visited,
+	})
+}
+
+// requestPasses requests passes for the users on behalf of the club.
+// Passes are created independently, so the response lists both created passes and per-user errors.
+func (s *Server) requestPasses(w http.ResponseWriter, r *http.Request) {
+	token := tokenFromContext(r.Context())
+
+	event, ok := s.clubEvent(w, r)
+	if !ok {
+		return
+	}
+
+	var request requestPassesRequest
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(request.UserIDs) == 0 || len(request.UserIDs) > maxPassesPerRequest {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("user_ids must contain from 1 to %d users", maxPassesPerRequest))
+		return
+	}
+	if event.IsCancelled() {
+		writeError(w, http.StatusConflict, "event is cancelled")
+		return
+	}
+	if event.IsOver(0) {
+		writeError(w, http.StatusConflict, "event has already started")
+		return
+	}
+	if !event.PassRequired {
+		writeError(w, http.StatusConflict, "event does not require passes")
+		return
+	}
+
+	response := passesResponse{
+		Passes: []passResponse{},
+		Errors: []string{},
+	}
+
+	var userIDs []int64
+	for _, userID := range request.UserIDs {
+		if _, err := s.userService.Get(r.Context(), userID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				response.Errors = append(response.Errors, fmt.Sprintf("user %d: not found", userID))
+				continue
+			}
+			s.logger.Errorf("(token: %s) error while get user %d: %v", token.ID, userID, err)
+			writeError(w, http.StatusInternalServerError, "internal error")
+			return
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	if len(userIDs) > 0 {
+		scheduledAt, err := s.passService.CalculateScheduledAt(r.Context(), event)
+		if err != nil {
+			s.logger.Errorf("(token: %s) error while calculate pass schedule: %v", token.ID, err)
+			writeError(w, http.StatusInternalServerError, "internal error")
+			return
+		}
+
+		passes, errs := s.passService.CreatePassesByClub(
+			r.Context(),
+			event.ID,
+			userIDs,
+			token.ClubID,
+			request.Reason,
+			scheduledAt,
+		)
+		for _, pass := range passes {
+			response.Passes = append(response.Passes, passResponse{
+				ID:          pass.ID,
+				UserID:      pass.UserID,
+				Status:      pass.Status,
+				ScheduledAt: pass.ScheduledAt,
+			})
+		}
+		for _, err := range errs {
+			response.Errors = append(response.Errors, err.Error())
+		}
+	}
+
+	s.logger.Infof("(token: %s) passes requested via API (event_id=%s, created=%d, failed=%d)",
+		token.ID, event.ID, len(response.Passes), len(response.Errors))
+	writeJSON(w, http.StatusOK, response)
+}
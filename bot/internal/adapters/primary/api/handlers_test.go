@@ -0,0 +1,532 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/valueobject"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/primary"
+	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
+)
+
+const (
+	testClubID      = "11111111-1111-1111-1111-111111111111"
+	otherClubID     = "22222222-2222-2222-2222-222222222222"
+	testEventID     = "33333333-3333-3333-3333-333333333333"
+	cancelledID     = "44444444-4444-4444-4444-444444444444"
+	noPassID        = "55555555-5555-5555-5555-555555555555"
+	validToken      = "valid-token"
+	revokedToken    = "revoked-token"
+	otherClubToken  = "other-club-token"
+	testUserID      = int64(100)
+	missingUserID   = int64(404)
+	testRateLimit   = 5
+	testRateWindow  = time.Minute
+	testRequestPath = "/api/v1/events/" + testEventID
+)
+
+func TestMain(m *testing.M) {
+	location.Init("Europe/Moscow")
+	os.Exit(m.Run())
+}
+
+// The fakes embed the port interfaces, so calling a method the API does not use panics
+
+type fakeTokenService struct {
+	primary.APITokenService
+
+	mu     sync.Mutex
+	tokens map[string]*entity.APIToken
+	logs   []entity.APIRequestLog
+}
+
+func (f *fakeTokenService) Authenticate(_ context.Context, plain string) (*entity.APIToken, error) {
+	token, ok := f.tokens[plain]
+	if !ok || token.IsRevoked() {
+		return nil, errorz.ErrInvalidAPIToken
+	}
+	return token, nil
+}
+
+func (f *fakeTokenService) LogRequest(_ context.Context, log *entity.APIRequestLog) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.logs = append(f.logs, *log)
+	return nil
+}
+
+type fakeClubService struct {
+	primary.ClubService
+}
+
+func (f *fakeClubService) Get(_ context.Context, id string) (*entity.Club, error) {
+	return &entity.Club{ID: id, Name: "Test club", AllowedRoles: []string{valueobject.Student.String()}}, nil
+}
+
+type fakeEventService struct {
+	primary.EventService
+
+	events  map[string]*entity.Event
+	created *entity.Event
+}
+
+func (f *fakeEventService) Get(_ context.Context, id string) (*entity.Event, error) {
+	event, ok := f.events[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return event, nil
+}
+
+func (f *fakeEventService) GetByClubID(_ context.Context, limit, offset int, clubID string) ([]entity.Event, error) {
+	var events []entity.Event
+	for _, event := range f.events {
+		if event.ClubID == clubID {
+			events = append(events, *event)
+		}
+	}
+	return events, nil
+}
+
+func (f *fakeEventService) CountByClubID(ctx context.Context, clubID string) (int64, error) {
+	events, _ := f.GetByClubID(ctx, 0, 0, clubID)
+	return int64(len(events)), nil
+}
+
+func (f *fakeEventService) Create(_ context.Context, event *entity.Event) (*entity.Event, error) {
+	event.ID = "55555555-5555-5555-5555-555555555555"
+	f.created = event
+	return event, nil
+}
+
+type fakeEventParticipantService struct {
+	primary.EventParticipantService
+}
+
+func (f *fakeEventParticipantService) CountByEventID(context.Context, string) (int, error) {
+	return 7, nil
+}
+
+func (f *fakeEventParticipantService) CountVisitedByEventID(context.Context, string) (int, error) {
+	return 3, nil
+}
+
+type fakeUserService struct {
+	primary.UserService
+}
+
+func (f *fakeUserService) Get(_ context.Context, userID int64) (*entity.User, error) {
+	if userID == missingUserID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &entity.User{ID: userID}, nil
+}
+
+func (f *fakeUserService) GetEventUsers(context.Context, string) ([]dto.EventUser, error) {
+	return []dto.EventUser{
+		dto.NewEventUserFromEntity(entity.User{ID: testUserID, Username: "user", Role: valueobject.Student}, true),
+	}, nil
+}
+
+type fakePassService struct {
+	primary.PassService
+}
+
+func (f *fakePassService) MatchLocation(context.Context, string) (*entity.PassLocation, error) {
+	return nil, nil
+}
+
+func (f *fakePassService) CalculateScheduledAt(_ context.Context, event *entity.Event) (time.Time, error) {
+	return event.StartTime.Add(-24 * time.Hour), nil
+}
+
+func (f *fakePassService) CreatePassesByClub(_ context.Context, eventID string, userIDs []int64, _, _ string, scheduledAt time.Time) ([]entity.Pass, []error) {
+	passes := make([]entity.Pass, 0, len(userIDs))
+	for _, userID := range userIDs {
+		passes = append(passes, entity.Pass{
+			ID:          fmt.Sprintf("pass-%d", userID),
+			EventID:     eventID,
+			UserID:      userID,
+			Status:      entity.PassStatusPending,
+			ScheduledAt: scheduledAt,
+		})
+	}
+	return passes, nil
+}
+
+// fakeRateLimiter allows the limit of requests per key
+type fakeRateLimiter struct {
+	mu   sync.Mutex
+	hits map[string]int
+}
+
+func (f *fakeRateLimiter) Allow(_ context.Context, key string, limit int, _ time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.hits[key]++
+	return f.hits[key] <= limit, nil
+}
+
+type testServer struct {
+	handler http.Handler
+	tokens  *fakeTokenService
+	events  *fakeEventService
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	revokedAt := time.Now().Add(-time.Hour)
+	tokens := &fakeTokenService{
+		tokens: map[string]*entity.APIToken{
+			validToken:     {ID: "token-valid", ClubID: testClubID},
+			revokedToken:   {ID: "token-revoked", ClubID: testClubID, RevokedAt: &revokedAt},
+			otherClubToken: {ID: "token-other", ClubID: otherClubID},
+		},
+	}
+
+	cancelledAt := time.Now()
+	events := &fakeEventService{
+		events: map[string]*entity.Event{
+			testEventID: {
+				ID:           testEventID,
+				ClubID:       testClubID,
+				Name:         "Test event",
+				StartTime:    time.Now().Add(72 * time.Hour),
+				PassRequired: true,
+			},
+			cancelledID: {
+				ID:           cancelledID,
+				ClubID:       testClubID,
+				Name:         "Cancelled event",
+				StartTime:    time.Now().Add(72 * time.Hour),
+				PassRequired: true,
+				CancelledAt:  &cancelledAt,
+			},
+			noPassID: {
+				ID:        noPassID,
+				ClubID:    testClubID,
+				Name:      "Event without passes",
+				StartTime: time.Now().Add(72 * time.Hour),
+			},
+		},
+	}
+
+	s := New(
+		"",
+		tokens,
+		&fakeClubService{},
+		events,
+		&fakeEventParticipantService{},
+		&fakeUserService{},
+		&fakePassService{},
+		&fakeRateLimiter{hits: make(map[string]int)},
+		testRateLimit,
+		testRateWindow,
+		&types.Logger{SugaredLogger: zap.NewNop().Sugar()},
+	)
+
+	return &testServer{
+		handler: s.routes(),
+		tokens:  tokens,
+		events:  events,
+	}
+}
+
+func (s *testServer) do(t *testing.T, method, path, token string, body []byte) *httptest.ResponseRecorder {
+	t.Helper()
+
+	request := httptest.NewRequest(method, path, bytes.NewReader(body))
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	recorder := httptest.NewRecorder()
+	s.handler.ServeHTTP(recorder, request)
+
+	return recorder
+}
+
+func decode[T any](t *testing.T, recorder *httptest.ResponseRecorder) T {
+	t.Helper()
+
+	var value T
+	if err := json.NewDecoder(recorder.Body).Decode(&value); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return value
+}
+
+func TestAuthentication(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		status int
+	}{
+		{name: "missing token", header: "", status: http.StatusUnauthorized},
+		{name: "not bearer", header: "Basic " + validToken, status: http.StatusUnauthorized},
+		{name: "unknown token", header: "Bearer unknown", status: http.StatusUnauthorized},
+		{name: "revoked token", header: "Bearer " + revokedToken, status: http.StatusUnauthorized},
+		{name: "wrong club token", header: "Bearer " + otherClubToken, status: http.StatusNotFound},
+		{name: "valid token", header: "Bearer " + validToken, status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+
+			request := httptest.NewRequest(http.MethodGet, testRequestPath, nil)
+			if tt.header != "" {
+				request.Header.Set("Authorization", tt.header)
+			}
+			recorder := httptest.NewRecorder()
+			s.handler.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.status {
+				t.Errorf("got status %d, want %d", recorder.Code, tt.status)
+			}
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	s := newTestServer(t)
+
+	for i := 0; i < testRateLimit; i++ {
+		if recorder := s.do(t, http.MethodGet, testRequestPath, validToken, nil); recorder.Code != http.StatusOK {
+			t.Fatalf("request %d: got status %d, want %d", i+1, recorder.Code, http.StatusOK)
+		}
+	}
+
+	recorder := s.do(t, http.MethodGet, testRequestPath, validToken, nil)
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("got status %d, want %d", recorder.Code, http.StatusTooManyRequests)
+	}
+	if got, want := recorder.Header().Get("Retry-After"), "60"; got != want {
+		t.Errorf("got Retry-After %q, want %q", got, want)
+	}
+
+	// the limit is counted per token
+	if recorder := s.do(t, http.MethodGet, testRequestPath, otherClubToken, nil); recorder.Code == http.StatusTooManyRequests {
+		t.Errorf("other token is rate limited")
+	}
+}
+
+func TestAudit(t *testing.T) {
+	s := newTestServer(t)
+
+	s.do(t, http.MethodGet, testRequestPath, validToken, nil)
+	s.do(t, http.MethodGet, "/api/v1/events/"+cancelledID+"/unknown", validToken, nil)
+	s.do(t, http.MethodGet, testRequestPath, revokedToken, nil)
+
+	if len(s.tokens.logs) != 2 {
+		t.Fatalf("got %d audit entries, want 2", len(s.tokens.logs))
+	}
+
+	log := s.tokens.logs[0]
+	if log.TokenID != "token-valid" || log.ClubID != testClubID {
+		t.Errorf("got token %q of club %q, want %q of club %q", log.TokenID, log.ClubID, "token-valid", testClubID)
+	}
+	if log.Method != http.MethodGet || log.Path != testRequestPath || log.StatusCode != http.StatusOK {
+		t.Errorf("got %s %s %d, want %s %s %d", log.Method, log.Path, log.StatusCode, http.MethodGet, testRequestPath, http.StatusOK)
+	}
+	if got := s.tokens.logs[1].StatusCode; got != http.StatusNotFound {
+		t.Errorf("got audit status %d, want %d", got, http.StatusNotFound)
+	}
+}
+
+func TestListEvents(t *testing.T) {
+	s := newTestServer(t)
+
+	recorder := s.do(t, http.MethodGet, "/api/v1/events?limit=10&offset=0", validToken, nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", recorder.Code, http.StatusOK)
+	}
+	response := decode[eventsResponse](t, recorder)
+	if response.Total != 3 || len(response.Events) != 3 {
+		t.Errorf("got %d events of %d, want 3 of 3", len(response.Events), response.Total)
+	}
+
+	for _, query := range []string{"limit=0", "limit=1000", "limit=abc", "offset=-1"} {
+		if recorder := s.do(t, http.MethodGet, "/api/v1/events?"+query, validToken, nil); recorder.Code != http.StatusBadRequest {
+			t.Errorf("%s: got status %d, want %d", query, recorder.Code, http.StatusBadRequest)
+		}
+	}
+
+	recorder = s.do(t, http.MethodGet, "/api/v1/events", otherClubToken, nil)
+	if response := decode[eventsResponse](t, recorder); response.Total != 0 {
+		t.Errorf("other club got %d events, want 0", response.Total)
+	}
+}
+
+func TestGetEvent(t *testing.T) {
+	s := newTestServer(t)
+
+	recorder := s.do(t, http.MethodGet, testRequestPath, validToken, nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", recorder.Code, http.StatusOK)
+	}
+	if response := decode[eventResponse](t, recorder); response.ID != testEventID {
+		t.Errorf("got event %q, want %q", response.ID, testEventID)
+	}
+
+	if recorder := s.do(t, http.MethodGet, "/api/v1/events/unknown", validToken, nil); recorder.Code != http.StatusNotFound {
+		t.Errorf("unknown event: got status %d, want %d", recorder.Code, http.StatusNotFound)
+	}
+}
+
+func TestCreateEvent(t *testing.T) {
+	start := time.Now().Add(72 * time.Hour).Truncate(time.Minute)
+	valid := createEventRequest{
+		Name:            "Test event",
+		Description:     "Test description",
+		Location:        "Test location",
+		StartTime:       start,
+		RegistrationEnd: start.Add(-24 * time.Hour),
+		MaxParticipants: 10,
+		AllowedRoles:    []string{valueobject.Student.String()},
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		s := newTestServer(t)
+
+		body, _ := json.Marshal(valid)
+		recorder := s.do(t, http.MethodPost, "/api/v1/events", validToken, body)
+		if recorder.Code != http.StatusCreated {
+			t.Fatalf("got status %d, want %d: %s", recorder.Code, http.StatusCreated, recorder.Body.String())
+		}
+		if s.events.created == nil || s.events.created.ClubID != testClubID {
+			t.Errorf("event is not created for the club of the token")
+		}
+	})
+
+	invalid := map[string]func(request *createEventRequest){
+		"short name":         func(request *createEventRequest) { request.Name = "abc" },
+		"start too soon":     func(request *createEventRequest) { request.StartTime = time.Now().Add(time.Hour) },
+		"negative max":       func(request *createEventRequest) { request.MaxParticipants = -1 },
+		"no roles":           func(request *createEventRequest) { request.AllowedRoles = nil },
+		"role not allowed":   func(request *createEventRequest) { request.AllowedRoles = []string{valueobject.GrantUser.String()} },
+		"registration after": func(request *createEventRequest) { request.RegistrationEnd = start.Add(time.Hour) },
+	}
+	for name, modify := range invalid {
+		t.Run(name, func(t *testing.T) {
+			s := newTestServer(t)
+
+			request := valid
+			modify(&request)
+			body, _ := json.Marshal(request)
+			if recorder := s.do(t, http.MethodPost, "/api/v1/events", validToken, body); recorder.Code != http.StatusBadRequest {
+				t.Errorf("got status %d, want %d", recorder.Code, http.StatusBadRequest)
+			}
+			if s.events.created != nil {
+				t.Errorf("invalid event is created")
+			}
+		})
+	}
+
+	t.Run("malformed body", func(t *testing.T) {
+		s := newTestServer(t)
+
+		if recorder := s.do(t, http.MethodPost, "/api/v1/events", validToken, []byte("{")); recorder.Code != http.StatusBadRequest {
+			t.Errorf("got status %d, want %d", recorder.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("oversized body", func(t *testing.T) {
+		s := newTestServer(t)
+
+		// the request is valid apart from the leading whitespace exceeding the limit
+		body, _ := json.Marshal(valid)
+		body = append([]byte(strings.Repeat(" ", maxRequestBodySize)), body...)
+		if recorder := s.do(t, http.MethodPost, "/api/v1/events", validToken, body); recorder.Code != http.StatusBadRequest {
+			t.Errorf("got status %d, want %d", recorder.Code, http.StatusBadRequest)
+		}
+	})
+}
+
+func TestListParticipants(t *testing.T) {
+	s := newTestServer(t)
+
+	recorder := s.do(t, http.MethodGet, testRequestPath+"/participants", validToken, nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", recorder.Code, http.StatusOK)
+	}
+	response := decode[participantsResponse](t, recorder)
+	if len(response.Participants) != 1 || response.Participants[0].UserID != testUserID || !response.Participants[0].Visited {
+		t.Errorf("got participants %+v", response.Participants)
+	}
+
+	if recorder := s.do(t, http.MethodGet, testRequestPath+"/participants", otherClubToken, nil); recorder.Code != http.StatusNotFound {
+		t.Errorf("other club: got status %d, want %d", recorder.Code, http.StatusNotFound)
+	}
+}
+
+func TestGetAttendance(t *testing.T) {
+	s := newTestServer(t)
+
+	recorder := s.do(t, http.MethodGet, testRequestPath+"/attendance", validToken, nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", recorder.Code, http.StatusOK)
+	}
+	if response := decode[attendanceResponse](t, recorder); response.Registered != 7 || response.Visited != 3 {
+		t.Errorf("got %d registered and %d visited, want 7 and 3", response.Registered, response.Visited)
+	}
+}
+
+func TestRequestPasses(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		s := newTestServer(t)
+
+		body, _ := json.Marshal(requestPassesRequest{UserIDs: []int64{testUserID, missingUserID}, Reason: "guest"})
+		recorder := s.do(t, http.MethodPost, testRequestPath+"/passes", validToken, body)
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("got status %d, want %d", recorder.Code, http.StatusOK)
+		}
+		response := decode[passesResponse](t, recorder)
+		if len(response.Passes) != 1 || response.Passes[0].UserID != testUserID {
+			t.Errorf("got passes %+v", response.Passes)
+		}
+		if len(response.Errors) != 1 {
+			t.Errorf("got errors %v, want the missing user", response.Errors)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		path    string
+		token   string
+		userIDs []int64
+		status  int
+	}{
+		{name: "no users", path: testRequestPath, token: validToken, userIDs: nil, status: http.StatusBadRequest},
+		{name: "too many users", path: testRequestPath, token: validToken, userIDs: make([]int64, maxPassesPerRequest+1), status: http.StatusBadRequest},
+		{name: "cancelled event", path: "/api/v1/events/" + cancelledID, token: validToken, userIDs: []int64{testUserID}, status: http.StatusConflict},
+		{name: "pass not required", path: "/api/v1/events/" + noPassID, token: validToken, userIDs: []int64{testUserID}, status: http.StatusConflict},
+		{name: "other club", path: testRequestPath, token: otherClubToken, userIDs: []int64{testUserID}, status: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+
+			body, _ := json.Marshal(requestPassesRequest{UserIDs: tt.userIDs})
+			if recorder := s.do(t, http.MethodPost, tt.path+"/passes", tt.token, body); recorder.Code != tt.status {
+				t.Errorf("got status %d, want %d", recorder.Code, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+type contextKey string
+
+const tokenContextKey contextKey = "api_token"
+
+// tokenFromContext returns the token that authenticated the request
+func tokenFromContext(ctx context.Context) *entity.APIToken {
+	token, _ := ctx.Value(tokenContextKey).(*entity.APIToken)
+	return token
+}
+
+func (s *Server) recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				s.logger.Errorf("panic in API handler %s %s: %v", r.Method, r.URL.Path, rec)
+				writeError(w, http.StatusInternalServerError, "internal error")
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authenticate checks the "Authorization: Bearer <token>" header
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		plain, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || plain == "" {
+			writeError(w, http.StatusUnauthorized, "missing bearer token")
+			return
+		}
+
+		token, err := s.tokenService.Authenticate(r.Context(), plain)
+		if err != nil {
+			if errors.Is(err, errorz.ErrInvalidAPIToken) {
+				writeError(w, http.StatusUnauthorized, "invalid token")
+				return
+			}
+			s.logger.Errorf("error while authenticate API token: %v", err)
+			writeError(w, http.StatusInternalServerError, "internal error")
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), tokenContextKey, token)))
+	})
+}
+
+// limitRate limits the number of requests per token in a time window
+func (s *Server) limitRate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := tokenFromContext(r.Context())
+
+		allowed, err := s.rateLimiter.Allow(r.Context(), "api:"+token.ID, s.rateLimit, s.rateLimitWindow)
+		if err != nil {
+			s.logger.Errorf("(token: %s) error while check API rate limit: %v", token.ID, err)
+			writeError(w, http.StatusInternalServerError, "internal error")
+			return
+		}
+		if !allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(int(s.rateLimitWindow.Seconds())))
+			writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// statusRecorder remembers the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// audit writes every authenticated request to the API audit log
+func (s *Server) audit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := tokenFromContext(r.Context())
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+
+		next.ServeHTTP(recorder, r)
+
+		err := s.tokenService.LogRequest(context.WithoutCancel(r.Context()), &entity.APIRequestLog{
+			TokenID:    token.ID,
+			ClubID:     token.ClubID,
+			Method:     r.Method,
+			Path:       r.URL.Path,
+			StatusCode: recorder.status,
+			RemoteAddr: r.RemoteAddr,
+			DurationMs: time.Since(start).Milliseconds(),
+		})
+		if err != nil {
+			s.logger.Errorf("(token: %s) error while write API audit log: %v", token.ID, err)
+		}
+	})
+}
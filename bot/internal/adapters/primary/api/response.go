@@ -0,0 +1,113 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+type eventResponse struct {
+	ID                    string     `json:"id"`
+	Name                  string     `json:"name"`
+	Description           string     `json:"description"`
+	AfterRegistrationText string     `json:"after_registration_text"`
+	Location              string     `json:"location"`
+	StartTime             time.Time  `json:"start_time"`
+	EndTime               *time.Time `json:"end_time,omitempty"`
+	RegistrationEnd       time.Time  `json:"registration_end"`
+	MaxParticipants       int        `json:"max_participants"`
+	ExpectedParticipants  int        `json:"expected_participants"`
+	AllowedRoles          []string   `json:"allowed_roles"`
+	PassRequired          bool       `json:"pass_required"`
+	IsRecurring           bool       `json:"is_recurring"`
+	CancelledAt           *time.Time `json:"cancelled_at,omitempty"`
+	CancellationReason    string     `json:"cancellation_reason,omitempty"`
+}
+
+func newEventResponse(event entity.Event) eventResponse {
+	response := eventResponse{
+		ID:                    event.ID,
+		Name:                  event.Name,
+		Description:           event.Description,
+		AfterRegistrationText: event.AfterRegistrationText,
+		Location:              event.Location,
+		StartTime:             event.StartTime,
+		RegistrationEnd:       event.RegistrationEnd,
+		MaxParticipants:       event.MaxParticipants,
+		ExpectedParticipants:  event.ExpectedParticipants,
+		AllowedRoles:          event.AllowedRoles,
+		PassRequired:          event.PassRequired,
+		IsRecurring:           event.IsRecurring(),
+		CancelledAt:           event.CancelledAt,
+		CancellationReason:    event.CancellationReason,
+	}
+	if !event.EndTime.IsZero() {
+		response.EndTime = &event.EndTime
+	}
+	if response.AllowedRoles == nil {
+		response.AllowedRoles = []string{}
+	}
+
+	return response
+}
+
+type eventsResponse struct {
+	Events []eventResponse `json:"events"`
+	Total  int64           `json:"total"`
+}
+
+type participantResponse struct {
+	UserID   int64  `json:"user_id"`
+	FIO      string `json:"fio"`
+	Username string `json:"username,omitempty"`
+	Role     string `json:"role"`
+	Visited  bool   `json:"visited"`
+}
+
+func newParticipantResponse(user dto.EventUser) participantResponse {
+	return participantResponse{
+		UserID:   user.User.ID,
+		FIO:      user.User.FIO.String(),
+		Username: user.User.Username,
+		Role:     user.User.Role.String(),
+		Visited:  user.UserVisit,
+	}
+}
+
+type participantsResponse struct {
+	Participants []participantResponse `json:"participants"`
+}
+
+type attendanceResponse struct {
+	Registered int `json:"registered"`
+	Visited    int `json:"visited"`
+}
+
+type passResponse struct {
+	ID          string            `json:"id"`
+	UserID      int64             `json:"user_id"`
+	Status      entity.PassStatus `json:"status"`
+	ScheduledAt time.Time         `json:"scheduled_at"`
+}
+
+type passesResponse struct {
+	Passes []passResponse `json:"passes"`
+	Errors []string       `json:"errors"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
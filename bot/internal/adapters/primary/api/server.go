@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/primary"
+	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
+)
+
+// RateLimiter counts the requests of a key, it is implemented by ratelimit.Storage
+type RateLimiter interface {
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error)
+}
+
+//go:embed openapi.yaml
+var openAPISpec []byte
+
+// Server is the HTTP API for clubs, requests are authorized with club API tokens
+type Server struct {
+	logger *types.Logger
+	server *http.Server
+
+	tokenService            primary.APITokenService
+	clubService             primary.ClubService
+	eventService            primary.EventService
+	eventParticipantService primary.EventParticipantService
+	userService             primary.UserService
+	passService             primary.PassService
+
+	rateLimiter     RateLimiter
+	rateLimit       int
+	rateLimitWindow time.Duration
+}
+
+func New(
+	address string,
+	tokenSvc primary.APITokenService,
+	clubSvc primary.ClubService,
+	eventSvc primary.EventService,
+	eventParticipantSvc primary.EventParticipantService,
+	userSvc primary.UserService,
+	passSvc primary.PassService,
+	rateLimiter RateLimiter,
+	rateLimit int,
+	rateLimitWindow time.Duration,
+	lg *types.Logger,
+) *Server {
+	s := &Server{
+		logger:                  lg,
+		tokenService:            tokenSvc,
+		clubService:             clubSvc,
+		eventService:            eventSvc,
+		eventParticipantService: eventParticipantSvc,
+		userService:             userSvc,
+		passService:             passSvc,
+		rateLimiter:             rateLimiter,
+		rateLimit:               rateLimit,
+		rateLimitWindow:         rateLimitWindow,
+	}
+
+	s.server = &http.Server{
+		Addr:              address,
+		Handler:           s.routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+	}
+
+	return s
+}
+
+func (s *Server) routes() http.Handler {
+	api := http.NewServeMux()
+	api.HandleFunc("GET /api/v1/events", s.listEvents)
+	api.HandleFunc("POST /api/v1/events", s.createEvent)
+	api.HandleFunc("GET /api/v1/events/{id}", s.getEvent)
+	api.HandleFunc("GET /api/v1/events/{id}/participants", s.listParticipants)
+	api.HandleFunc("GET /api/v1/events/{id}/attendance", s.getAttendance)
+	api.HandleFunc("POST /api/v1/events/{id}/passes", s.requestPasses)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /openapi.yaml", s.openAPI)
+	mux.Handle("/api/", s.authenticate(s.limitRate(s.audit(api))))
+
+	return s.recoverer(mux)
+}
+
+// Start starts listening for HTTP requests, it blocks until the server is stopped
+func (s *Server) Start() error {
+	s.logger.Infof("API server listening on %s", s.server.Addr)
+	if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Stop gracefully shuts the server down waiting for active requests
+func (s *Server) Stop(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+func (s *Server) openAPI(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	_, _ = w.Write(openAPISpec)
+}
@@ -10,9 +10,11 @@ import (
 	"gopkg.in/telebot.v3/layout"
 	"gorm.io/gorm"
 
+	"github.com/lib/pq"
 	"github.com/nlypage/intele"
 	"github.com/nlypage/intele/collector"
 
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/primary/telegram/handlers/middlewares"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
@@ -29,28 +31,55 @@ type Handler struct {
 	bot    *tele.Bot
 	input  *intele.InputManager
 
-	adminUserService primary.UserService
-	clubService      primary.ClubService
-	clubOwnerService primary.ClubOwnerService
+	adminUserService       primary.UserService
+	clubService            primary.ClubService
+	clubOwnerService       primary.ClubOwnerService
+	apiTokenService        primary.APITokenService
+	mailingService         primary.MailingService
+	eventService           primary.EventService
+	passService            primary.PassService
+	shadowBanService       primary.ShadowBanService
+	banService             primary.BanService
+	adminService           primary.AdminService
+	auditService           primary.AuditService
+	clubApplicationService primary.ClubApplicationService
 }
 
 func New(
 	userSvc primary.UserService,
 	clubSvc primary.ClubService,
 	clubOwnerSvc primary.ClubOwnerService,
+	apiTokenSvc primary.APITokenService,
+	mailingSvc primary.MailingService,
+	eventSvc primary.EventService,
+	passSvc primary.PassService,
+	shadowBanSvc primary.ShadowBanService,
+	banSvc primary.BanService,
+	adminSvc primary.AdminService,
+	auditSvc primary.AuditService,
+	clubApplicationSvc primary.ClubApplicationService,
 	b *tele.Bot,
 	lt *layout.Layout,
 	lg *types.Logger,
 	in *intele.InputManager,
 ) *Handler {
 	return &Handler{
-		layout:           lt,
-		logger:           lg,
-		bot:              b,
-		input:            in,
-		adminUserService: userSvc,
-		clubService:      clubSvc,
-		clubOwnerService: clubOwnerSvc,
+		layout:                 lt,
+		logger:                 lg,
+		bot:                    b,
+		input:                  in,
+		adminUserService:       userSvc,
+		clubService:            clubSvc,
+		clubOwnerService:       clubOwnerSvc,
+		apiTokenService:        apiTokenSvc,
+		mailingService:         mailingSvc,
+		eventService:           eventSvc,
+		passService:            passSvc,
+		shadowBanService:       shadowBanSvc,
+		banService:             banSvc,
+		adminService:           adminSvc,
+		auditService:           auditSvc,
+		clubApplicationService: clubApplicationSvc,
 	}
 }
 
@@ -62,9 +91,27 @@ func (h Handler) adminMenu(c tele.Context) error {
 		h.logger.Errorf("(user: %d) error while set admin commands: %v", c.Sender().ID, errSetCommands)
 	}
 
+	role := h.role(c)
+	var hidden []string
+	if !role.CanAny(valueobject.ClubsPermission, valueobject.PassesPermission) {
+		hidden = append(hidden, "admin:clubs")
+	}
+	if !role.Can(valueobject.ClubsPermission) {
+		hidden = append(hidden, "admin:create_club", "admin:club_applications")
+	}
+	if !role.Can(valueobject.PassesPermission) {
+		hidden = append(hidden, "admin:pass_locations")
+	}
+
 	return c.Edit(
-		banner.Menu.Caption(h.layout.Text(c, "admin_menu_text", c.Sender().Username)),
-		h.layout.Markup(c, "admin:menu"),
+		banner.Menu.Caption(h.layout.Text(c, "admin_menu_text", struct {
+			Username string
+			Role     string
+		}{
+			Username: c.Sender().Username,
+			Role:     role.String(),
+		})),
+		h.withoutButtons(h.layout.Markup(c, "admin:menu"), hidden...),
 	)
 }
 
@@ -136,6 +183,7 @@ func (h Handler) createClub(c tele.Context) error {
 	}
 
 	h.logger.Infof("(user: %d) new club created: %s", c.Sender().ID, club.Name)
+	h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionClubCreated, entity.AuditTargetClub, club.ID, nil, club)
 	return c.Send(
 		banner.Menu.Caption(h.layout.Text(c, "club_created", club)),
 		h.layout.Markup(c, "admin:backToMenu"),
@@ -279,6 +327,7 @@ func (h Handler) clubMenu(c tele.Context) error {
 		)
 	}
 
+	before := *club
 	if c.Callback().Unique == "admin_club_qr" {
 		club.QrAllowed = !club.QrAllowed
 		club, err = h.clubService.Update(context.Background(), club)
@@ -293,6 +342,7 @@ func (h Handler) clubMenu(c tele.Context) error {
 				}),
 			)
 		}
+		h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionClubUpdated, entity.AuditTargetClub, club.ID, before, club)
 	} else if c.Callback().Unique == "admin_club_sub_req_allow" {
 		club.SubscriptionRequireAllowed = !club.SubscriptionRequireAllowed
 		if !club.SubscriptionRequireAllowed {
@@ -310,6 +360,7 @@ func (h Handler) clubMenu(c tele.Context) error {
 				}),
 			)
 		}
+		h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionClubUpdated, entity.AuditTargetClub, club.ID, before, club)
 	}
 
 	clubOwners, err := h.clubOwnerService.GetByClubID(context.Background(), clubID)
@@ -325,6 +376,23 @@ func (h Handler) clubMenu(c tele.Context) error {
 		)
 	}
 
+	var hidden []string
+	if !h.role(c).Can(valueobject.ClubsPermission) {
+		hidden = append(hidden,
+			"admin:club:add_owner",
+			"admin:club:del_owner",
+			"admin:club:qr_allowed",
+			"admin:club:subscription_require_allowed",
+			"admin:club:roles",
+			"admin:club:api_tokens",
+			"admin:club:mailings",
+			"admin:club:delete",
+		)
+	}
+	if !h.role(c).Can(valueobject.PassesPermission) {
+		hidden = append(hidden, "admin:club:passes")
+	}
+
 	return c.Edit(
 		banner.Menu.Caption(h.layout.Text(c, "admin_club_menu_text", struct {
 			Club   entity.Club
@@ -333,7 +401,7 @@ func (h Handler) clubMenu(c tele.Context) error {
 			Club:   *club,
 			Owners: clubOwners,
 		})),
-		h.layout.Markup(c, "admin:club:menu", struct {
+		h.withoutButtons(h.layout.Markup(c, "admin:club:menu", struct {
 			ID                         string
 			Page                       string
 			QrAllowed                  bool
@@ -343,7 +411,7 @@ func (h Handler) clubMenu(c tele.Context) error {
 			Page:                       page,
 			QrAllowed:                  club.QrAllowed,
 			SubscriptionRequireAllowed: club.SubscriptionRequireAllowed,
-		}),
+		}), hidden...),
 	)
 }
 
@@ -464,7 +532,7 @@ func (h Handler) addClubOwner(c tele.Context) error {
 		}
 	}
 
-	_, err = h.clubOwnerService.Add(context.Background(), user.ID, club.ID)
+	_, err = h.clubOwnerService.Add(context.Background(), user.ID, club.ID, valueobject.ClubCoOwner)
 	if err != nil {
 		_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
 		h.logger.Errorf(
@@ -492,6 +560,11 @@ func (h Handler) addClubOwner(c tele.Context) error {
 		club.ID,
 		user.ID,
 	)
+	h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionClubOwnerAdded, entity.AuditTargetClub, club.ID, nil, struct {
+		UserID int64
+	}{
+		UserID: user.ID,
+	})
 
 	_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
 	return c.Send(
@@ -645,6 +718,11 @@ func (h Handler) removeClubOwner(c tele.Context) error {
 		clubID,
 		user.ID,
 	)
+	h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionClubOwnerRemoved, entity.AuditTargetClub, clubID, struct {
+		UserID int64
+	}{
+		UserID: user.ID,
+	}, nil)
 
 	_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
 	return c.Send(
@@ -696,6 +774,7 @@ func (h Handler) manageRoles(c tele.Context) error {
 			return errorz.ErrInvalidCallbackData
 		}
 		role := callbackData[2]
+		before := append(pq.StringArray{}, club.AllowedRoles...)
 
 		var (
 			contains bool
@@ -728,6 +807,7 @@ func (h Handler) manageRoles(c tele.Context) error {
 				}),
 			)
 		}
+		h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionClubRolesChanged, entity.AuditTargetClub, clubID, before, club.AllowedRoles)
 	}
 
 	rolesMarkup := h.layout.Markup(c, "admin:club:roles", struct {
@@ -806,6 +886,7 @@ func (h Handler) deleteClub(c tele.Context) error {
 	}
 
 	h.logger.Infof("(user: %d) club deleted (club_id=%s)", c.Sender().ID, clubID)
+	h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionClubDeleted, entity.AuditTargetClub, clubID, club, nil)
 	return c.Edit(
 		banner.Menu.Caption(h.layout.Text(c, "club_deleted", club)),
 		h.layout.Markup(c, "admin:clubs:back", struct {
@@ -816,91 +897,75 @@ func (h Handler) deleteClub(c tele.Context) error {
 	)
 }
 
-func (h Handler) banUser(c tele.Context) error {
-	_ = c.Delete()
-	if c.Message() == nil || c.Message().Payload == "" {
-		return c.Send(
-			h.layout.Text(c, "invalid_ban_data"),
-			h.layout.Markup(c, "core:hide"),
-		)
-	}
-	userID, err := strconv.ParseInt(c.Message().Payload, 10, 64)
-	if err != nil {
-		return c.Send(
-			h.layout.Text(c, "invalid_ban_data"),
-			h.layout.Markup(c, "core:hide"),
-		)
-	}
+func (h Handler) AdminSetup(group *tele.Group, middle *middlewares.Handler) {
+	group.Use(middle.IsAdmin)
 
-	h.logger.Infof("(user: %d) attempt ban user: %d", c.Sender().ID, userID)
-	if userID == c.Sender().ID {
-		return c.Send(
-			h.layout.Text(c, "attempt_to_ban_self"),
-			h.layout.Markup(c, "core:hide"),
-		)
-	}
-	user, err := h.adminUserService.Ban(context.Background(), userID)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return c.Send(
-				h.layout.Text(c, "user_not_found", struct {
-					ID int64
-				}{
-					ID: userID,
-				}),
-				h.layout.Markup(c, "core:hide"),
-			)
-		}
-		h.logger.Errorf("(user: %d) error while ban user: %v", c.Sender().ID, err)
-		return c.Send(
-			h.layout.Text(c, "technical_issues", err.Error()),
-			h.layout.Markup(c, "core:hide"),
-		)
-	}
-
-	if user.IsBanned {
-		h.logger.Infof("(user: %d) user banned: %d", c.Sender().ID, userID)
-		return c.Send(
-			h.layout.Text(c, "user_banned", struct {
-				FIO string
-				ID  int64
-			}{
-				FIO: user.FIO.String(),
-				ID:  user.ID,
-			}),
-			h.layout.Markup(c, "core:hide"),
-		)
-	}
-
-	h.logger.Infof("(user: %d) user unbanned: %d", c.Sender().ID, userID)
-	return c.Send(
-		h.layout.Text(c, "user_unbanned", struct {
-			FIO string
-			ID  int64
-		}{
-			FIO: user.FIO.String(),
-			ID:  user.ID,
-		}),
-		h.layout.Markup(c, "core:hide"),
-	)
-}
+	clubs := middle.AdminCan(valueobject.ClubsPermission)
+	passes := middle.AdminCan(valueobject.PassesPermission)
+	clubsOrPasses := middle.AdminCan(valueobject.ClubsPermission, valueobject.PassesPermission)
+	users := middle.AdminCan(valueobject.UsersPermission)
+	admins := middle.AdminCan(valueobject.AdminsPermission)
+	audit := middle.AdminCan(valueobject.AuditPermission)
 
-func (h Handler) AdminSetup(group *tele.Group) {
 	group.Handle(h.layout.Callback("mainMenu:admin_menu"), h.adminMenu)
 	group.Handle(h.layout.Callback("admin:back_to_menu"), h.adminMenu)
-	group.Handle(h.layout.Callback("admin:create_club"), h.createClub)
-	group.Handle(h.layout.Callback("admin:clubs"), h.clubsList)
-	group.Handle(h.layout.Callback("admin:clubs:prev_page"), h.clubsList)
-	group.Handle(h.layout.Callback("admin:clubs:next_page"), h.clubsList)
-	group.Handle(h.layout.Callback("admin:clubs:back"), h.clubsList)
-	group.Handle(h.layout.Callback("admin:clubs:club"), h.clubMenu)
-	group.Handle(h.layout.Callback("admin:club:qr_allowed"), h.clubMenu)
-	group.Handle(h.layout.Callback("admin:club:subscription_require_allowed"), h.clubMenu)
-	group.Handle(h.layout.Callback("admin:club:back"), h.clubMenu)
-	group.Handle(h.layout.Callback("admin:club:add_owner"), h.addClubOwner)
-	group.Handle(h.layout.Callback("admin:club:del_owner"), h.removeClubOwner)
-	group.Handle(h.layout.Callback("admin:club:roles"), h.manageRoles)
-	group.Handle(h.layout.Callback("admin:club:roles:role"), h.manageRoles)
-	group.Handle(h.layout.Callback("admin:club:delete"), h.deleteClub)
-	group.Handle("/ban", h.banUser)
+	group.Handle(h.layout.Callback("admin:create_club"), h.createClub, clubs)
+	group.Handle(h.layout.Callback("admin:club_applications"), h.clubApplications, clubs)
+	group.Handle(h.layout.Callback("admin:club_applications:back"), h.clubApplications, clubs)
+	group.Handle(h.layout.Callback("admin:club_applications:application"), h.clubApplication, clubs)
+	group.Handle(h.layout.Callback("admin:club_application:open"), h.openClubApplication, clubs)
+	group.Handle(h.layout.Callback("admin:club_application:approve"), h.approveClubApplication, clubs)
+	group.Handle(h.layout.Callback("admin:club_application:reject"), h.rejectClubApplication, clubs)
+	group.Handle(h.layout.Callback("admin:clubs"), h.clubsList, clubsOrPasses)
+	group.Handle(h.layout.Callback("admin:clubs:prev_page"), h.clubsList, clubsOrPasses)
+	group.Handle(h.layout.Callback("admin:clubs:next_page"), h.clubsList, clubsOrPasses)
+	group.Handle(h.layout.Callback("admin:clubs:back"), h.clubsList, clubsOrPasses)
+	group.Handle(h.layout.Callback("admin:clubs:club"), h.clubMenu, clubsOrPasses)
+	group.Handle(h.layout.Callback("admin:club:qr_allowed"), h.clubMenu, clubs)
+	group.Handle(h.layout.Callback("admin:club:subscription_require_allowed"), h.clubMenu, clubs)
+	group.Handle(h.layout.Callback("admin:club:back"), h.clubMenu, clubsOrPasses)
+	group.Handle(h.layout.Callback("admin:club:add_owner"), h.addClubOwner, clubs)
+	group.Handle(h.layout.Callback("admin:club:del_owner"), h.removeClubOwner, clubs)
+	group.Handle(h.layout.Callback("admin:club:roles"), h.manageRoles, clubs)
+	group.Handle(h.layout.Callback("admin:club:roles:role"), h.manageRoles, clubs)
+	group.Handle(h.layout.Callback("admin:club:delete"), h.deleteClub, clubs)
+	group.Handle(h.layout.Callback("admin:club:api_tokens"), h.apiTokens, clubs)
+	group.Handle(h.layout.Callback("admin:api_tokens:back"), h.apiTokens, clubs)
+	group.Handle(h.layout.Callback("admin:api_tokens:issue"), h.issueAPIToken, clubs)
+	group.Handle(h.layout.Callback("admin:api_tokens:revoke"), h.revokeAPIToken, clubs)
+	group.Handle(h.layout.Callback("admin:club:mailings"), h.mailingsList, clubs)
+	group.Handle(h.layout.Callback("admin:mailings:prev_page"), h.mailingsList, clubs)
+	group.Handle(h.layout.Callback("admin:mailings:next_page"), h.mailingsList, clubs)
+	group.Handle(h.layout.Callback("admin:mailings:mailing"), h.mailingInfo, clubs)
+	group.Handle(h.layout.Callback("admin:club:passes"), h.clubPassEvents, passes)
+	group.Handle(h.layout.Callback("admin:club_passes:event"), h.eventPasses, passes)
+	group.Handle(h.layout.Callback("admin:event_passes:cancel"), h.cancelEventPass, passes)
+	group.Handle(h.layout.Callback("admin:event_passes:add"), h.addEventPasses, passes)
+	group.Handle(h.layout.Callback("admin:pass_locations"), h.passLocations, passes)
+	group.Handle(h.layout.Callback("admin:pass_locations:back"), h.passLocations, passes)
+	group.Handle(h.layout.Callback("admin:pass_locations:create"), h.createPassLocation, passes)
+	group.Handle(h.layout.Callback("admin:pass_locations:location"), h.passLocation, passes)
+	group.Handle(h.layout.Callback("admin:pass_location:back"), h.passLocation, passes)
+	group.Handle(h.layout.Callback("admin:pass_location:name"), h.editPassLocationName, passes)
+	group.Handle(h.layout.Callback("admin:pass_location:patterns"), h.editPassLocationPatterns, passes)
+	group.Handle(h.layout.Callback("admin:pass_location:emails"), h.editPassLocationEmails, passes)
+	group.Handle(h.layout.Callback("admin:pass_location:telegram_chat"), h.editPassLocationTelegramChat, passes)
+	group.Handle(h.layout.Callback("admin:pass_location:create_schedule"), h.createPassSchedule, passes)
+	group.Handle(h.layout.Callback("admin:pass_location:schedule"), h.passSchedule, passes)
+	group.Handle(h.layout.Callback("admin:pass_schedule:back"), h.passSchedule, passes)
+	group.Handle(h.layout.Callback("admin:pass_schedule:active"), h.togglePassSchedule, passes)
+	group.Handle(h.layout.Callback("admin:pass_schedule:cron"), h.editPassScheduleCron, passes)
+	group.Handle(h.layout.Callback("admin:pass_schedule:lead_time"), h.editPassScheduleLeadTime, passes)
+	group.Handle("/ban", h.banUser, users)
+	group.Handle("/unban", h.unbanUser, users)
+	group.Handle("/bans", h.bans, users)
+	group.Handle("/resend_passes", h.resendPassBatch, passes)
+	group.Handle("/add_guest", h.addGuest, passes)
+	group.Handle("/shadow_ban", h.addShadowBan, users)
+	group.Handle("/shadow_unban", h.removeShadowBan, users)
+	group.Handle("/shadow_bans", h.shadowBans, users)
+	group.Handle("/admins", h.admins, admins)
+	group.Handle("/grant_admin", h.grantAdmin, admins)
+	group.Handle("/revoke_admin", h.revokeAdmin, admins)
+	group.Handle("/audit", h.audit, audit)
 }
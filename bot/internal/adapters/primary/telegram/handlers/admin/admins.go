@@ -0,0 +1,229 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/primary/telegram/handlers/middlewares"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/valueobject"
+)
+
+// adminView is an admin shown to the superadmin
+type adminView struct {
+	UserID     int64
+	FIO        string
+	Role       string
+	GrantedBy  int64
+	FromConfig bool
+}
+
+// role returns the admin role of the sender stored by the IsAdmin middleware
+func (h Handler) role(c tele.Context) valueobject.AdminRole {
+	role, _ := c.Get(middlewares.AdminRoleKey).(valueobject.AdminRole)
+	return role
+}
+
+// withoutButtons removes the buttons of the callbacks from the markup and the rows left empty
+func (h Handler) withoutButtons(markup *tele.ReplyMarkup, callbacks ...string) *tele.ReplyMarkup {
+	if len(callbacks) == 0 {
+		return markup
+	}
+
+	hidden := make(map[string]struct{}, len(callbacks))
+	for _, callback := range callbacks {
+		hidden[h.layout.Callback(callback).CallbackUnique()] = struct{}{}
+	}
+
+	keyboard := make([][]tele.InlineButton, 0, len(markup.InlineKeyboard))
+	for _, row := range markup.InlineKeyboard {
+		buttons := make([]tele.InlineButton, 0, len(row))
+		for _, button := range row {
+			if _, ok := hidden[button.CallbackUnique()]; !ok {
+				buttons = append(buttons, button)
+			}
+		}
+		if len(buttons) > 0 {
+			keyboard = append(keyboard, buttons)
+		}
+	}
+	markup.InlineKeyboard = keyboard
+
+	return markup
+}
+
+// admins lists the admins with their roles
+func (h Handler) admins(c tele.Context) error {
+	_ = c.Delete()
+	h.logger.Infof("(user: %d) get admins", c.Sender().ID)
+
+	admins, err := h.adminService.GetAll(context.Background())
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get admins: %v", c.Sender().ID, err)
+		return c.Send(
+			h.layout.Text(c, "technical_issues", err.Error()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	return c.Send(
+		h.layout.Text(c, "admins_text", newAdminViews(admins)),
+		h.layout.Markup(c, "core:hide"),
+	)
+}
+
+// grantAdmin grants the admin role to the user or changes the role of the admin:
+// /grant_admin <ID, email или @username>; <роль>
+func (h Handler) grantAdmin(c tele.Context) error {
+	_ = c.Delete()
+
+	payload := ""
+	if c.Message() != nil {
+		payload = strings.TrimSpace(c.Message().Payload)
+	}
+
+	fields := strings.SplitN(payload, ";", 2)
+	if len(fields) != 2 {
+		return c.Send(
+			h.layout.Text(c, "invalid_grant_admin_data", valueobject.AllAdminRoles()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+	target, role := strings.TrimSpace(fields[0]), valueobject.AdminRole(strings.TrimSpace(fields[1]))
+	if target == "" || !role.IsValid() {
+		return c.Send(
+			h.layout.Text(c, "invalid_grant_admin_data", valueobject.AllAdminRoles()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	user, err := h.findUser(target)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Send(
+				h.layout.Text(c, "ban_user_not_found", target),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+
+		h.logger.Errorf("(user: %d) error while find user to grant admin: %v", c.Sender().ID, err)
+		return c.Send(
+			h.layout.Text(c, "technical_issues", err.Error()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	h.logger.Infof("(user: %d) grant admin role %s to user: %d", c.Sender().ID, role, user.ID)
+	user, err = h.adminService.Grant(context.Background(), user.ID, role, c.Sender().ID)
+	if err != nil {
+		if errors.Is(err, errorz.ErrConfigAdmin) {
+			return c.Send(
+				h.layout.Text(c, "config_admin"),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+
+		h.logger.Errorf("(user: %d) error while grant admin: %v", c.Sender().ID, err)
+		return c.Send(
+			h.layout.Text(c, "technical_issues", err.Error()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionAdminGranted, entity.AuditTargetUser, strconv.FormatInt(user.ID, 10), nil, role)
+	return c.Send(
+		h.layout.Text(c, "admin_granted", adminView{
+			UserID: user.ID,
+			FIO:    user.FIO.String(),
+			Role:   role.String(),
+		}),
+		h.layout.Markup(c, "core:hide"),
+	)
+}
+
+// revokeAdmin revokes the admin role of the user:
+// /revoke_admin <ID, email или @username>
+func (h Handler) revokeAdmin(c tele.Context) error {
+	_ = c.Delete()
+
+	payload := ""
+	if c.Message() != nil {
+		payload = strings.TrimSpace(c.Message().Payload)
+	}
+	if payload == "" {
+		return c.Send(
+			h.layout.Text(c, "invalid_revoke_admin_data"),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	user, err := h.findUser(payload)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Send(
+				h.layout.Text(c, "ban_user_not_found", payload),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+
+		h.logger.Errorf("(user: %d) error while find user to revoke admin: %v", c.Sender().ID, err)
+		return c.Send(
+			h.layout.Text(c, "technical_issues", err.Error()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	h.logger.Infof("(user: %d) revoke admin role of user: %d", c.Sender().ID, user.ID)
+	user, err = h.adminService.Revoke(context.Background(), user.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, errorz.ErrConfigAdmin):
+			return c.Send(
+				h.layout.Text(c, "config_admin"),
+				h.layout.Markup(c, "core:hide"),
+			)
+		case errors.Is(err, errorz.ErrNotAdmin):
+			return c.Send(
+				h.layout.Text(c, "user_not_admin"),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+
+		h.logger.Errorf("(user: %d) error while revoke admin: %v", c.Sender().ID, err)
+		return c.Send(
+			h.layout.Text(c, "technical_issues", err.Error()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionAdminRevoked, entity.AuditTargetUser, strconv.FormatInt(user.ID, 10), nil, nil)
+	return c.Send(
+		h.layout.Text(c, "admin_revoked", adminView{
+			UserID: user.ID,
+			FIO:    user.FIO.String(),
+		}),
+		h.layout.Markup(c, "core:hide"),
+	)
+}
+
+func newAdminViews(admins []dto.Admin) []adminView {
+	views := make([]adminView, 0, len(admins))
+	for _, admin := range admins {
+		views = append(views, adminView{
+			UserID:     admin.UserID,
+			FIO:        admin.FIO,
+			Role:       admin.Role.String(),
+			GrantedBy:  admin.GrantedBy,
+			FromConfig: admin.FromConfig,
+		})
+	}
+
+	return views
+}
@@ -0,0 +1,250 @@
+package admin
+
+import (
+	"context"
+	"strings"
+
+	"github.com/nlypage/intele/collector"
+	tele "gopkg.in/telebot.v3"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/validator"
+)
+
+// apiTokenAudit is the API token state recorded in the audit log, the token hash is never recorded
+type apiTokenAudit struct {
+	ClubID string
+	Name   string
+}
+
+// apiTokenView is a club API token shown in the admin menu
+type apiTokenView struct {
+	Name       string
+	CreatedAt  string
+	LastUsedAt string
+	Revoked    bool
+}
+
+func newAPITokenView(token entity.APIToken) apiTokenView {
+	view := apiTokenView{
+		Name:      token.Name,
+		CreatedAt: token.CreatedAt.In(location.Location()).Format("02.01.2006 15:04"),
+		Revoked:   token.IsRevoked(),
+	}
+	if token.LastUsedAt != nil {
+		view.LastUsedAt = token.LastUsedAt.In(location.Location()).Format("02.01.2006 15:04")
+	}
+
+	return view
+}
+
+// apiTokens shows API tokens of the club
+func (h Handler) apiTokens(c tele.Context) error {
+	callbackData := strings.Split(c.Callback().Data, " ")
+	if len(callbackData) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+	clubID, page := callbackData[0], callbackData[1]
+
+	h.logger.Infof("(user: %d) edit club api tokens (club_id=%s)", c.Sender().ID, clubID)
+
+	return h.renderAPITokens(c, clubID, page)
+}
+
+func (h Handler) renderAPITokens(c tele.Context, clubID, page string) error {
+	backMarkup := h.layout.Markup(c, "admin:club:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   clubID,
+		Page: page,
+	})
+
+	club, err := h.clubService.Get(context.Background(), clubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	tokens, err := h.apiTokenService.GetByClubID(context.Background(), clubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club api tokens: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	markup := h.layout.Markup(c, "admin:club:api_tokens", struct {
+		ID   string
+		Page string
+	}{
+		ID:   clubID,
+		Page: page,
+	})
+
+	views := make([]apiTokenView, 0, len(tokens))
+	var rows [][]tele.InlineButton
+	for _, token := range tokens {
+		views = append(views, newAPITokenView(token))
+		if token.IsRevoked() {
+			continue
+		}
+		rows = append(rows, []tele.InlineButton{*h.layout.Button(c, "admin:api_tokens:revoke", struct {
+			ID   string
+			Name string
+			Page string
+		}{
+			ID:   token.ID,
+			Name: token.Name,
+			Page: page,
+		}).Inline()})
+	}
+	markup.InlineKeyboard = append(rows, markup.InlineKeyboard...)
+
+	return c.Edit(
+		banner.Menu.Caption(h.layout.Text(c, "admin_api_tokens_text", struct {
+			ClubName string
+			Tokens   []apiTokenView
+		}{
+			ClubName: club.Name,
+			Tokens:   views,
+		})),
+		markup,
+	)
+}
+
+// issueAPIToken asks for the token name and shows the new token once
+func (h Handler) issueAPIToken(c tele.Context) error {
+	callbackData := strings.Split(c.Callback().Data, " ")
+	if len(callbackData) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+	clubID, page := callbackData[0], callbackData[1]
+
+	h.logger.Infof("(user: %d) issue api token request (club_id=%s)", c.Sender().ID, clubID)
+
+	backMarkup := h.layout.Markup(c, "admin:api_tokens:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   clubID,
+		Page: page,
+	})
+
+	inputCollector := collector.New()
+	_ = c.Edit(
+		banner.Menu.Caption(h.layout.Text(c, "input_api_token_name")),
+		backMarkup,
+	)
+	inputCollector.Collect(c.Message())
+
+	var (
+		name string
+		done bool
+	)
+	for {
+		response, errGet := h.input.Get(context.Background(), c.Sender().ID, 0)
+		if response.Message != nil {
+			inputCollector.Collect(response.Message)
+		}
+		switch {
+		case response.Canceled:
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true, ExcludeLast: true})
+			return nil
+		case errGet != nil:
+			h.logger.Errorf("(user: %d) error while input api token name: %v", c.Sender().ID, errGet)
+			_ = inputCollector.Send(c,
+				banner.Menu.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "input_api_token_name"))),
+				backMarkup,
+			)
+		case response.Message == nil:
+			_ = inputCollector.Send(c,
+				banner.Menu.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "input_api_token_name"))),
+				backMarkup,
+			)
+		case !validator.APITokenName(response.Message.Text, nil):
+			_ = inputCollector.Send(c,
+				banner.Menu.Caption(h.layout.Text(c, "invalid_api_token_name")),
+				backMarkup,
+			)
+		default:
+			name = response.Message.Text
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
+			done = true
+		}
+		if done {
+			break
+		}
+	}
+
+	plain, token, err := h.apiTokenService.Issue(context.Background(), clubID, name, c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while issue api token: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	h.logger.Infof("(user: %d) api token issued (club_id=%s, token_id=%s)", c.Sender().ID, clubID, token.ID)
+	h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionAPITokenIssued, entity.AuditTargetAPIToken, token.ID, nil, apiTokenAudit{
+		ClubID: token.ClubID,
+		Name:   token.Name,
+	})
+	return c.Send(
+		banner.Menu.Caption(h.layout.Text(c, "api_token_issued", struct {
+			Name  string
+			Token string
+		}{
+			Name:  token.Name,
+			Token: plain,
+		})),
+		backMarkup,
+	)
+}
+
+// revokeAPIToken revokes the token and shows the updated list of club tokens
+func (h Handler) revokeAPIToken(c tele.Context) error {
+	callbackData := strings.Split(c.Callback().Data, " ")
+	if len(callbackData) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+	tokenID, page := callbackData[0], callbackData[1]
+
+	h.logger.Infof("(user: %d) revoke api token (token_id=%s)", c.Sender().ID, tokenID)
+
+	token, err := h.apiTokenService.Revoke(context.Background(), tokenID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while revoke api token: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "admin:clubs:back", struct {
+				Page string
+			}{
+				Page: page,
+			}),
+		)
+	}
+
+	h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionAPITokenRevoked, entity.AuditTargetAPIToken, token.ID, apiTokenAudit{
+		ClubID: token.ClubID,
+		Name:   token.Name,
+	}, nil)
+
+	_ = c.Respond(&tele.CallbackResponse{
+		Text: h.layout.Text(c, "api_token_revoked", struct {
+			Name string
+		}{
+			Name: token.Name,
+		}),
+	})
+
+	return h.renderAPITokens(c, token.ClubID, page)
+}
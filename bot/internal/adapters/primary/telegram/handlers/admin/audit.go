@@ -0,0 +1,198 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+)
+
+// auditPreviewSize is the number of the newest entries shown in the message, all entries are in the XLSX
+const auditPreviewSize = 10
+
+// auditEntryView is an audit entry shown to the admin
+type auditEntryView struct {
+	CreatedAt  string
+	ActorID    int64
+	Action     string
+	TargetType string
+	TargetID   string
+}
+
+// audit searches the audit log and sends the found entries as XLSX:
+// /audit [ID, email или @username исполнителя]; [цель]; [с ДД.ММ.ГГГГ]; [по ДД.ММ.ГГГГ]
+func (h Handler) audit(c tele.Context) error {
+	_ = c.Delete()
+
+	payload := ""
+	if c.Message() != nil {
+		payload = strings.TrimSpace(c.Message().Payload)
+	}
+
+	fields := strings.SplitN(payload, ";", 4)
+	for len(fields) < 4 {
+		fields = append(fields, "")
+	}
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+		if fields[i] == "-" {
+			fields[i] = ""
+		}
+	}
+	actor, target, from, to := fields[0], fields[1], fields[2], fields[3]
+
+	filter := dto.AuditFilter{TargetID: target}
+	if actor != "" {
+		user, err := h.findUser(actor)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return c.Send(
+					h.layout.Text(c, "ban_user_not_found", actor),
+					h.layout.Markup(c, "core:hide"),
+				)
+			}
+
+			h.logger.Errorf("(user: %d) error while find audit actor: %v", c.Sender().ID, err)
+			return c.Send(
+				h.layout.Text(c, "technical_issues", err.Error()),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+		filter.ActorID = user.ID
+	}
+
+	if from != "" {
+		fromDate, err := time.ParseInLocation("02.01.2006", from, location.Location())
+		if err != nil {
+			return c.Send(
+				h.layout.Text(c, "invalid_audit_data"),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+		filter.From = &fromDate
+	}
+	if to != "" {
+		toDate, err := time.ParseInLocation("02.01.2006", to, location.Location())
+		if err != nil {
+			return c.Send(
+				h.layout.Text(c, "invalid_audit_data"),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+		// the end date is inclusive
+		toDate = toDate.AddDate(0, 0, 1)
+		filter.To = &toDate
+	}
+
+	h.logger.Infof("(user: %d) search audit log (actor_id=%d, target_id=%s, from=%s, to=%s)", c.Sender().ID, filter.ActorID, target, from, to)
+	entries, err := h.auditService.Search(context.Background(), filter)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while search audit log: %v", c.Sender().ID, err)
+		return c.Send(
+			h.layout.Text(c, "technical_issues", err.Error()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	if len(entries) == 0 {
+		return c.Send(
+			h.layout.Text(c, "audit_empty"),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	preview := entries
+	if len(preview) > auditPreviewSize {
+		preview = preview[:auditPreviewSize]
+	}
+	views := make([]auditEntryView, 0, len(preview))
+	for _, entry := range preview {
+		views = append(views, newAuditEntryView(entry))
+	}
+
+	err = c.Send(
+		h.layout.Text(c, "audit_text", struct {
+			Count   int
+			Entries []auditEntryView
+		}{
+			Count:   len(entries),
+			Entries: views,
+		}),
+		h.layout.Markup(c, "core:hide"),
+	)
+	if err != nil {
+		return err
+	}
+
+	buffer, err := auditToXLSX(entries)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while export audit log: %v", c.Sender().ID, err)
+		return c.Send(
+			h.layout.Text(c, "technical_issues", err.Error()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	return c.Send(
+		&tele.Document{
+			File:     tele.FromReader(buffer),
+			FileName: "audit.xlsx",
+		},
+		h.layout.Markup(c, "core:hide"),
+	)
+}
+
+func newAuditEntryView(entry entity.AuditEntry) auditEntryView {
+	return auditEntryView{
+		CreatedAt:  entry.CreatedAt.In(location.Location()).Format("02.01.2006 15:04"),
+		ActorID:    entry.ActorID,
+		Action:     string(entry.Action),
+		TargetType: string(entry.TargetType),
+		TargetID:   entry.TargetID,
+	}
+}
+
+func auditToXLSX(entries []entity.AuditEntry) (*bytes.Buffer, error) {
+	f := excelize.NewFile()
+
+	sheet := "Sheet1"
+	_ = f.SetCellValue(sheet, "A1", "Время")
+	_ = f.SetCellValue(sheet, "B1", "Исполнитель")
+	_ = f.SetCellValue(sheet, "C1", "Действие")
+	_ = f.SetCellValue(sheet, "D1", "Тип цели")
+	_ = f.SetCellValue(sheet, "E1", "Цель")
+	_ = f.SetCellValue(sheet, "F1", "До")
+	_ = f.SetCellValue(sheet, "G1", "После")
+
+	for i, entry := range entries {
+		row := strconv.Itoa(i + 2)
+		_ = f.SetCellValue(sheet, "A"+row, entry.CreatedAt.In(location.Location()).Format("02.01.2006 15:04:05"))
+		_ = f.SetCellValue(sheet, "B"+row, entry.ActorID)
+		_ = f.SetCellValue(sheet, "C"+row, string(entry.Action))
+		_ = f.SetCellValue(sheet, "D"+row, string(entry.TargetType))
+		_ = f.SetCellValue(sheet, "E"+row, entry.TargetID)
+		if entry.Before != nil {
+			_ = f.SetCellValue(sheet, "F"+row, *entry.Before)
+		}
+		if entry.After != nil {
+			_ = f.SetCellValue(sheet, "G"+row, *entry.After)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, err
+	}
+
+	return &buf, nil
+}
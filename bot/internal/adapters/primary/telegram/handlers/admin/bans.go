@@ -0,0 +1,223 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+)
+
+// banView is a ban shown to the admin
+type banView struct {
+	UserID    int64
+	FIO       string
+	AdminID   int64
+	Reason    string
+	StartedAt string
+	ExpiresAt string
+}
+
+func newBanView(ban entity.UserBan, fio string) banView {
+	view := banView{
+		UserID:    ban.UserID,
+		FIO:       fio,
+		AdminID:   ban.AdminID,
+		Reason:    ban.Reason,
+		StartedAt: ban.StartedAt.In(location.Location()).Format("02.01.2006 15:04"),
+	}
+	if ban.ExpiresAt != nil {
+		view.ExpiresAt = ban.ExpiresAt.In(location.Location()).Format("02.01.2006 15:04")
+	}
+
+	return view
+}
+
+// banUser bans the user, cancels their upcoming registrations and pending passes:
+// /ban <ID, email или @username>; [срок в днях]; [причина]
+func (h Handler) banUser(c tele.Context) error {
+	_ = c.Delete()
+
+	payload := ""
+	if c.Message() != nil {
+		payload = strings.TrimSpace(c.Message().Payload)
+	}
+
+	fields := strings.SplitN(payload, ";", 3)
+	for len(fields) < 3 {
+		fields = append(fields, "")
+	}
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+	target, days, reason := fields[0], fields[1], fields[2]
+	if reason == "-" {
+		reason = ""
+	}
+
+	if target == "" {
+		return c.Send(
+			h.layout.Text(c, "invalid_ban_data"),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	var duration time.Duration
+	if days != "" && days != "-" {
+		count, err := strconv.Atoi(days)
+		if err != nil || count <= 0 {
+			return c.Send(
+				h.layout.Text(c, "invalid_ban_data"),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+		duration = time.Duration(count) * 24 * time.Hour
+	}
+
+	user, err := h.findUser(target)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Send(
+				h.layout.Text(c, "ban_user_not_found", target),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+
+		h.logger.Errorf("(user: %d) error while find user to ban: %v", c.Sender().ID, err)
+		return c.Send(
+			h.layout.Text(c, "technical_issues", err.Error()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	h.logger.Infof("(user: %d) attempt ban user: %d", c.Sender().ID, user.ID)
+	if user.ID == c.Sender().ID {
+		return c.Send(
+			h.layout.Text(c, "attempt_to_ban_self"),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	ban, user, err := h.banService.Ban(context.Background(), user.ID, c.Sender().ID, reason, duration)
+	if err != nil {
+		if errors.Is(err, errorz.ErrAlreadyBanned) {
+			return c.Send(
+				h.layout.Text(c, "already_banned"),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+
+		h.logger.Errorf("(user: %d) error while ban user: %v", c.Sender().ID, err)
+		return c.Send(
+			h.layout.Text(c, "technical_issues", err.Error()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	h.logger.Infof("(user: %d) user banned: %d", c.Sender().ID, user.ID)
+	h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionUserBanned, entity.AuditTargetUser, strconv.FormatInt(user.ID, 10), nil, ban)
+	return c.Send(
+		h.layout.Text(c, "user_banned", newBanView(*ban, user.FIO.String())),
+		h.layout.Markup(c, "core:hide"),
+	)
+}
+
+// unbanUser lifts the active ban of the user:
+// /unban <ID, email или @username>
+func (h Handler) unbanUser(c tele.Context) error {
+	_ = c.Delete()
+
+	payload := ""
+	if c.Message() != nil {
+		payload = strings.TrimSpace(c.Message().Payload)
+	}
+	if payload == "" {
+		return c.Send(
+			h.layout.Text(c, "invalid_unban_data"),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	user, err := h.findUser(payload)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Send(
+				h.layout.Text(c, "ban_user_not_found", payload),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+
+		h.logger.Errorf("(user: %d) error while find user to unban: %v", c.Sender().ID, err)
+		return c.Send(
+			h.layout.Text(c, "technical_issues", err.Error()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	h.logger.Infof("(user: %d) attempt unban user: %d", c.Sender().ID, user.ID)
+	user, err = h.banService.Unban(context.Background(), user.ID, c.Sender().ID)
+	if err != nil {
+		if errors.Is(err, errorz.ErrNotBanned) {
+			return c.Send(
+				h.layout.Text(c, "user_not_banned"),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+
+		h.logger.Errorf("(user: %d) error while unban user: %v", c.Sender().ID, err)
+		return c.Send(
+			h.layout.Text(c, "technical_issues", err.Error()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	h.logger.Infof("(user: %d) user unbanned: %d", c.Sender().ID, user.ID)
+	h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionUserUnbanned, entity.AuditTargetUser, strconv.FormatInt(user.ID, 10), nil, nil)
+	return c.Send(
+		h.layout.Text(c, "user_unbanned", struct {
+			FIO string
+			ID  int64
+		}{
+			FIO: user.FIO.String(),
+			ID:  user.ID,
+		}),
+		h.layout.Markup(c, "core:hide"),
+	)
+}
+
+// bans lists the active bans
+func (h Handler) bans(c tele.Context) error {
+	_ = c.Delete()
+	h.logger.Infof("(user: %d) get bans", c.Sender().ID)
+
+	bans, err := h.banService.GetActive(context.Background())
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get bans: %v", c.Sender().ID, err)
+		return c.Send(
+			h.layout.Text(c, "technical_issues", err.Error()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	return c.Send(
+		h.layout.Text(c, "bans_text", newBanViews(bans)),
+		h.layout.Markup(c, "core:hide"),
+	)
+}
+
+func newBanViews(bans []dto.UserBan) []banView {
+	views := make([]banView, 0, len(bans))
+	for _, ban := range bans {
+		views = append(views, newBanView(ban.Ban, ban.FIO))
+	}
+
+	return views
+}
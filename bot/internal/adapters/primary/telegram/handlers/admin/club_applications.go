@@ -0,0 +1,202 @@
+package admin
+
+import (
+	"context"
+	"errors"
+
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/validator"
+)
+
+// clubApplicationsQueueSize is the number of the oldest pending applications shown in the queue
+const clubApplicationsQueueSize = 20
+
+// clubApplicationView is a club application shown to the admin
+type clubApplicationView struct {
+	Application *entity.ClubApplication
+	Applicant   string
+	Owners      []entity.User
+	CreatedAt   string
+}
+
+// clubApplications shows the queue of the club applications waiting for the review
+func (h Handler) clubApplications(c tele.Context) error {
+	h.logger.Infof("(user: %d) edit club applications", c.Sender().ID)
+
+	applications, err := h.clubApplicationService.GetPending(context.Background())
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club applications: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "admin:backToMenu"),
+		)
+	}
+
+	markup := c.Bot().NewMarkup()
+	var rows []tele.Row
+	for i, application := range applications {
+		if i == clubApplicationsQueueSize {
+			break
+		}
+		rows = append(rows, markup.Row(*h.layout.Button(c, "admin:club_applications:application", application)))
+	}
+	rows = append(rows, markup.Row(*h.layout.Button(c, "admin:back_to_menu")))
+	markup.Inline(rows...)
+
+	return c.Edit(
+		banner.Menu.Caption(h.layout.Text(c, "club_applications_text", len(applications))),
+		markup,
+	)
+}
+
+// clubApplication shows the club application with the buttons to approve or reject it
+func (h Handler) clubApplication(c tele.Context) error {
+	if c.Callback().Data == "" {
+		return errorz.ErrInvalidCallbackData
+	}
+	h.logger.Infof("(user: %d) edit club application (application_id=%s)", c.Sender().ID, c.Callback().Data)
+
+	return c.Edit(h.clubApplicationMenu(c, c.Callback().Data))
+}
+
+// openClubApplication sends the club application opened from the warning about a new application
+func (h Handler) openClubApplication(c tele.Context) error {
+	if c.Callback().Data == "" {
+		return errorz.ErrInvalidCallbackData
+	}
+	h.logger.Infof("(user: %d) open club application (application_id=%s)", c.Sender().ID, c.Callback().Data)
+
+	_ = c.Respond()
+	return c.Send(h.clubApplicationMenu(c, c.Callback().Data))
+}
+
+func (h Handler) clubApplicationMenu(c tele.Context, applicationID string) (interface{}, *tele.ReplyMarkup) {
+	application, err := h.clubApplicationService.Get(context.Background(), applicationID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club application: %v", c.Sender().ID, err)
+		return banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "admin:club_applications:back")
+	}
+
+	view, err := h.newClubApplicationView(application)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club application users: %v", c.Sender().ID, err)
+		return banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "admin:club_applications:back")
+	}
+
+	markup := h.layout.Markup(c, "admin:club_application", application)
+	if application.Status != entity.ClubApplicationPending {
+		markup = h.layout.Markup(c, "admin:club_applications:back")
+	}
+
+	return banner.Menu.Caption(h.layout.Text(c, "club_application_text", view)), markup
+}
+
+// approveClubApplication creates the club of the application
+func (h Handler) approveClubApplication(c tele.Context) error {
+	applicationID := c.Callback().Data
+	if applicationID == "" {
+		return errorz.ErrInvalidCallbackData
+	}
+	h.logger.Infof("(user: %d) approve club application (application_id=%s)", c.Sender().ID, applicationID)
+
+	application, club, err := h.clubApplicationService.Approve(context.Background(), applicationID, c.Sender().ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, errorz.ErrClubApplicationReviewed):
+			return c.Edit(
+				banner.Menu.Caption(h.layout.Text(c, "club_application_already_reviewed")),
+				h.layout.Markup(c, "admin:club_applications:back"),
+			)
+		case errors.Is(err, gorm.ErrDuplicatedKey):
+			return c.Edit(
+				banner.Menu.Caption(h.layout.Text(c, "club_already_exists")),
+				h.layout.Markup(c, "admin:club_applications:back"),
+			)
+		}
+
+		h.logger.Errorf("(user: %d) error while approve club application: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "admin:club_applications:back"),
+		)
+	}
+
+	h.logger.Infof("(user: %d) club application approved (application_id=%s, club_id=%s)", c.Sender().ID, applicationID, club.ID)
+	h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionClubApplicationApproved, entity.AuditTargetClubApplication, applicationID, nil, application)
+	h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionClubCreated, entity.AuditTargetClub, club.ID, nil, club)
+
+	return c.Edit(
+		banner.Menu.Caption(h.layout.Text(c, "club_application_approved", club)),
+		h.layout.Markup(c, "admin:club_applications:back"),
+	)
+}
+
+// rejectClubApplication asks for the comment and rejects the club application
+func (h Handler) rejectClubApplication(c tele.Context) error {
+	applicationID := c.Callback().Data
+	if applicationID == "" {
+		return errorz.ErrInvalidCallbackData
+	}
+	h.logger.Infof("(user: %d) reject club application (application_id=%s)", c.Sender().ID, applicationID)
+
+	backMarkup := h.layout.Markup(c, "admin:club_applications:back")
+	comment, ok := h.inputText(c, backMarkup, "input_club_application_comment", "invalid_club_application_comment", validator.ClubApplicationComment)
+	if !ok {
+		return nil
+	}
+
+	application, err := h.clubApplicationService.Reject(context.Background(), applicationID, c.Sender().ID, comment)
+	if err != nil {
+		if errors.Is(err, errorz.ErrClubApplicationReviewed) {
+			return c.Send(
+				banner.Menu.Caption(h.layout.Text(c, "club_application_already_reviewed")),
+				backMarkup,
+			)
+		}
+
+		h.logger.Errorf("(user: %d) error while reject club application: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	h.logger.Infof("(user: %d) club application rejected (application_id=%s)", c.Sender().ID, applicationID)
+	h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionClubApplicationRejected, entity.AuditTargetClubApplication, applicationID, nil, application)
+
+	return c.Send(
+		banner.Menu.Caption(h.layout.Text(c, "club_application_rejected", application)),
+		backMarkup,
+	)
+}
+
+func (h Handler) newClubApplicationView(application *entity.ClubApplication) (clubApplicationView, error) {
+	applicant, err := h.adminUserService.Get(context.Background(), application.ApplicantID)
+	if err != nil {
+		return clubApplicationView{}, err
+	}
+
+	owners := make([]entity.User, 0, len(application.OwnerIDs))
+	for _, ownerID := range application.OwnerIDs {
+		owner, err := h.adminUserService.Get(context.Background(), ownerID)
+		if err != nil {
+			return clubApplicationView{}, err
+		}
+		owners = append(owners, *owner)
+	}
+
+	return clubApplicationView{
+		Application: application,
+		Applicant:   applicant.FIO.String(),
+		Owners:      owners,
+		CreatedAt:   application.CreatedAt.In(location.Location()).Format("02.01.2006 15:04"),
+	}, nil
+}
@@ -0,0 +1,88 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/validator"
+)
+
+// addGuest adds the guest pass to the event on behalf of the admin:
+// /add_guest <event_id> <ФИО>[, телефон]
+func (h Handler) addGuest(c tele.Context) error {
+	_ = c.Delete()
+
+	payload := ""
+	if c.Message() != nil {
+		payload = strings.TrimSpace(c.Message().Payload)
+	}
+
+	eventID, guest, _ := strings.Cut(payload, " ")
+	fio, phone, _ := strings.Cut(guest, ",")
+	fio, phone = strings.TrimSpace(fio), strings.TrimSpace(phone)
+	if _, err := uuid.Parse(eventID); err != nil || !validator.GuestFIO(fio, nil) || (phone != "" && !validator.GuestPhone(phone, nil)) {
+		return c.Send(
+			h.layout.Text(c, "invalid_add_guest_data"),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+	h.logger.Infof("(user: %d) add event guest (event_id=%s)", c.Sender().ID, eventID)
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Send(
+				h.layout.Text(c, "invalid_add_guest_data"),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Send(
+			h.layout.Text(c, "technical_issues", err.Error()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	pass, err := h.passService.AddGuest(context.Background(), event, fio, phone, entity.PassRequesterTypeAdmin, c.Sender().ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, errorz.ErrGuestAlreadyAdded):
+			return c.Send(
+				h.layout.Text(c, "guest_already_added"),
+				h.layout.Markup(c, "core:hide"),
+			)
+		case errors.Is(err, errorz.ErrPassNotRequired),
+			errors.Is(err, errorz.ErrEventCancelled),
+			errors.Is(err, errorz.ErrRegistrationEnded):
+			return c.Send(
+				h.layout.Text(c, "guest_not_allowed"),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+
+		h.logger.Errorf("(user: %d) error while add event guest: %v", c.Sender().ID, err)
+		return c.Send(
+			h.layout.Text(c, "technical_issues", err.Error()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	return c.Send(
+		h.layout.Text(c, "guest_added", struct {
+			FIO       string
+			EventName string
+		}{
+			FIO:       pass.GuestFIO,
+			EventName: event.Name,
+		}),
+		h.layout.Markup(c, "core:hide"),
+	)
+}
@@ -0,0 +1,229 @@
+package admin
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	tele "gopkg.in/telebot.v3"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+)
+
+// mailingsList shows the mailing history of the club
+//
+// Callback data is "clubID clubsPage" or "clubID clubsPage mailingsPage"
+func (h Handler) mailingsList(c tele.Context) error {
+	const mailingsOnPage = 5
+
+	callbackData := strings.Split(c.Callback().Data, " ")
+	if len(callbackData) != 2 && len(callbackData) != 3 {
+		return errorz.ErrInvalidCallbackData
+	}
+	clubID, clubsPage := callbackData[0], callbackData[1]
+
+	var (
+		p        int
+		prevPage int
+		nextPage int
+		err      error
+		rows     []tele.Row
+	)
+	if len(callbackData) == 3 {
+		p, err = strconv.Atoi(callbackData[2])
+		if err != nil {
+			return errorz.ErrInvalidCallbackData
+		}
+	}
+
+	h.logger.Infof("(user: %d) edit club mailings list (club_id=%s, page=%d)", c.Sender().ID, clubID, p)
+
+	backMarkup := h.layout.Markup(c, "admin:club:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   clubID,
+		Page: clubsPage,
+	})
+
+	club, err := h.clubService.Get(context.Background(), clubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	mailingsCount, err := h.mailingService.CountByClubID(context.Background(), clubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club mailings count: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	mailings, err := h.mailingService.GetByClubID(context.Background(), clubID, mailingsOnPage, p*mailingsOnPage)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club mailings: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	markup := c.Bot().NewMarkup()
+	for _, mailing := range mailings {
+		rows = append(rows, markup.Row(*h.layout.Button(c, "admin:mailings:mailing", struct {
+			ID        string
+			Page      string
+			CreatedAt string
+			Audience  string
+		}{
+			ID:        mailing.ID,
+			Page:      clubsPage,
+			CreatedAt: mailing.CreatedAt.In(location.Location()).Format("02.01.2006 15:04"),
+			Audience:  h.layout.Text(c, "mailing_audience_"+string(mailing.Audience)),
+		})))
+	}
+
+	pagesCount := max(int(mailingsCount)-1, 0) / mailingsOnPage
+	if p == 0 {
+		prevPage = pagesCount
+	} else {
+		prevPage = p - 1
+	}
+
+	if p >= pagesCount {
+		nextPage = 0
+	} else {
+		nextPage = p + 1
+	}
+
+	rows = append(
+		rows,
+		markup.Row(
+			*h.layout.Button(c, "admin:mailings:prev_page", struct {
+				ID           string
+				Page         string
+				MailingsPage int
+			}{
+				ID:           clubID,
+				Page:         clubsPage,
+				MailingsPage: prevPage,
+			}),
+			*h.layout.Button(c, "core:page_counter", struct {
+				Page       int
+				PagesCount int
+			}{
+				Page:       p + 1,
+				PagesCount: pagesCount + 1,
+			}),
+			*h.layout.Button(c, "admin:mailings:next_page", struct {
+				ID           string
+				Page         string
+				MailingsPage int
+			}{
+				ID:           clubID,
+				Page:         clubsPage,
+				MailingsPage: nextPage,
+			}),
+		),
+		markup.Row(*h.layout.Button(c, "admin:club:back", struct {
+			ID   string
+			Page string
+		}{
+			ID:   clubID,
+			Page: clubsPage,
+		})),
+	)
+
+	markup.Inline(rows...)
+
+	return c.Edit(
+		banner.Menu.Caption(h.layout.Text(c, "admin_mailings_text", struct {
+			ClubName string
+			Count    int64
+		}{
+			ClubName: club.Name,
+			Count:    mailingsCount,
+		})),
+		markup,
+	)
+}
+
+// mailingInfo shows the mailing with its delivery report
+func (h Handler) mailingInfo(c tele.Context) error {
+	callbackData := strings.Split(c.Callback().Data, " ")
+	if len(callbackData) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+	mailingID, clubsPage := callbackData[0], callbackData[1]
+
+	h.logger.Infof("(user: %d) edit mailing info (mailing_id=%s)", c.Sender().ID, mailingID)
+
+	mailing, err := h.mailingService.Get(context.Background(), mailingID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get mailing: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "admin:backToMenu"),
+		)
+	}
+
+	backMarkup := h.layout.Markup(c, "admin:mailing:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   mailing.ClubID,
+		Page: clubsPage,
+	})
+
+	stats, err := h.mailingService.GetStats(context.Background(), mailingID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get mailing stats: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	author := strconv.FormatInt(mailing.AuthorID, 10)
+	if user, errGet := h.adminUserService.Get(context.Background(), mailing.AuthorID); errGet == nil {
+		author = user.FIO.String()
+	}
+
+	var status string
+	switch {
+	case mailing.CancelledAt != nil:
+		status = h.layout.Text(c, "mailing_status_cancelled")
+	case mailing.IsScheduled():
+		status = h.layout.Text(c, "mailing_status_scheduled", mailing.ScheduledAt.In(location.Location()).Format("02.01.2006 15:04"))
+	}
+
+	return c.Edit(
+		banner.Menu.Caption(h.layout.Text(c, "admin_mailing_text", struct {
+			CreatedAt string
+			Status    string
+			Audience  string
+			Author    string
+			IsMedia   bool
+			Text      string
+			Stats     dto.MailingStats
+		}{
+			CreatedAt: mailing.CreatedAt.In(location.Location()).Format("02.01.2006 15:04"),
+			Status:    status,
+			Audience:  h.layout.Text(c, "mailing_audience_"+string(mailing.Audience)),
+			Author:    author,
+			IsMedia:   mailing.Kind != entity.MessageText,
+			Text:      mailing.Text,
+			Stats:     stats,
+		})),
+		backMarkup,
+	)
+}
@@ -0,0 +1,117 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+)
+
+// recentPassBatchesLimit is the number of batches shown by /resend_passes without arguments
+const recentPassBatchesLimit = 10
+
+// passBatchView is a pass batch shown to the admin
+type passBatchView struct {
+	ID         string
+	Subject    string
+	CreatedAt  string
+	Delivered  int
+	Recipients int
+	Failed     []passBatchDeliveryView
+}
+
+type passBatchDeliveryView struct {
+	Channel   string
+	Recipient string
+	Error     string
+}
+
+func newPassBatchView(batch entity.PassBatch) passBatchView {
+	view := passBatchView{
+		ID:         batch.ID,
+		Subject:    batch.Subject,
+		CreatedAt:  batch.CreatedAt.In(location.Location()).Format("02.01.2006 15:04"),
+		Delivered:  batch.CountDelivered(),
+		Recipients: len(batch.Deliveries),
+	}
+	for _, delivery := range batch.Deliveries {
+		if delivery.Status != entity.PassDeliveryStatusSent {
+			view.Failed = append(view.Failed, passBatchDeliveryView{
+				Channel:   string(delivery.Channel),
+				Recipient: delivery.Recipient,
+				Error:     delivery.LastError,
+			})
+		}
+	}
+
+	return view
+}
+
+// resendPassBatch sends the pass batch to all its recipients once more,
+// without arguments the latest batches are listed
+func (h Handler) resendPassBatch(c tele.Context) error {
+	_ = c.Delete()
+
+	batchID := ""
+	if c.Message() != nil {
+		batchID = strings.TrimSpace(c.Message().Payload)
+	}
+
+	if batchID == "" {
+		h.logger.Infof("(user: %d) list pass batches", c.Sender().ID)
+
+		batches, err := h.passService.GetRecentBatches(context.Background(), recentPassBatchesLimit)
+		if err != nil {
+			h.logger.Errorf("(user: %d) error while get pass batches: %v", c.Sender().ID, err)
+			return c.Send(
+				h.layout.Text(c, "technical_issues", err.Error()),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+
+		views := make([]passBatchView, 0, len(batches))
+		for _, batch := range batches {
+			views = append(views, newPassBatchView(batch))
+		}
+
+		return c.Send(
+			h.layout.Text(c, "pass_batches_text", views),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	h.logger.Infof("(user: %d) resend pass batch (batch_id=%s)", c.Sender().ID, batchID)
+	if _, err := uuid.Parse(batchID); err != nil {
+		return c.Send(
+			h.layout.Text(c, "pass_batch_not_found", batchID),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	batch, err := h.passService.ResendBatch(context.Background(), batchID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Send(
+				h.layout.Text(c, "pass_batch_not_found", batchID),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+
+		h.logger.Errorf("(user: %d) error while resend pass batch: %v", c.Sender().ID, err)
+		return c.Send(
+			h.layout.Text(c, "technical_issues", err.Error()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	return c.Send(
+		h.layout.Text(c, "pass_batch_resent", newPassBatchView(*batch)),
+		h.layout.Markup(c, "core:hide"),
+	)
+}
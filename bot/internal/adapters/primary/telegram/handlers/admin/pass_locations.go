@@ -0,0 +1,312 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/validator"
+)
+
+// passLocationView is a pass location shown in the admin menu
+type passLocationView struct {
+	Name            string
+	Patterns        string
+	EmailRecipients string
+	TelegramChatID  int64
+	Schedules       []passScheduleView
+	Covered         bool
+}
+
+func newPassLocationView(passLocation entity.PassLocation, schedules []entity.PassSchedule) passLocationView {
+	view := passLocationView{
+		Name:            passLocation.Name,
+		Patterns:        strings.Join(passLocation.Patterns, ", "),
+		EmailRecipients: strings.Join(passLocation.EmailRecipients, ", "),
+		TelegramChatID:  passLocation.TelegramChatID,
+		Schedules:       make([]passScheduleView, 0, len(schedules)),
+	}
+	for _, schedule := range schedules {
+		view.Schedules = append(view.Schedules, newPassScheduleView(schedule))
+	}
+	_, uncovered := entity.FindUncoveredPassTime(schedules, time.Now())
+	view.Covered = !uncovered
+
+	return view
+}
+
+// passLocations shows the buildings the passes are sent to
+func (h Handler) passLocations(c tele.Context) error {
+	h.logger.Infof("(user: %d) edit pass locations list", c.Sender().ID)
+
+	locations, err := h.passService.GetLocations(context.Background())
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get pass locations: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "admin:backToMenu"),
+		)
+	}
+
+	markup := c.Bot().NewMarkup()
+	views := make([]passLocationView, 0, len(locations))
+	var rows []tele.Row
+	for _, passLocation := range locations {
+		schedules, err := h.passService.GetSchedules(context.Background(), passLocation.ID)
+		if err != nil {
+			h.logger.Errorf("(user: %d) error while get pass schedules: %v", c.Sender().ID, err)
+			return c.Edit(
+				banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+				h.layout.Markup(c, "admin:backToMenu"),
+			)
+		}
+
+		views = append(views, newPassLocationView(passLocation, schedules))
+		rows = append(rows, markup.Row(*h.layout.Button(c, "admin:pass_locations:location", struct {
+			ID   string
+			Name string
+		}{
+			ID:   passLocation.ID,
+			Name: passLocation.Name,
+		})))
+	}
+	rows = append(rows,
+		markup.Row(*h.layout.Button(c, "admin:pass_locations:create")),
+		markup.Row(*h.layout.Button(c, "admin:back_to_menu")),
+	)
+	markup.Inline(rows...)
+
+	return c.Edit(
+		banner.Menu.Caption(h.layout.Text(c, "admin_pass_locations_text", views)),
+		markup,
+	)
+}
+
+func (h Handler) passLocation(c tele.Context) error {
+	if c.Callback().Data == "" {
+		return errorz.ErrInvalidCallbackData
+	}
+	locationID := c.Callback().Data
+	h.logger.Infof("(user: %d) edit pass location (location_id=%s)", c.Sender().ID, locationID)
+
+	caption, markup := h.passLocationMenu(c, locationID)
+	return c.Edit(caption, markup)
+}
+
+// passLocationMenu returns the pass location text and the edit menu with its schedules
+func (h Handler) passLocationMenu(c tele.Context, locationID string) (interface{}, *tele.ReplyMarkup) {
+	backMarkup := h.layout.Markup(c, "admin:pass_locations:back")
+
+	passLocation, err := h.passService.GetLocation(context.Background(), locationID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get pass location: %v", c.Sender().ID, err)
+		return banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())), backMarkup
+	}
+
+	schedules, err := h.passService.GetSchedules(context.Background(), locationID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get pass schedules: %v", c.Sender().ID, err)
+		return banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())), backMarkup
+	}
+
+	markup := h.layout.Markup(c, "admin:pass_location", struct {
+		ID string
+	}{
+		ID: passLocation.ID,
+	})
+
+	var rows [][]tele.InlineButton
+	for _, schedule := range schedules {
+		rows = append(rows, []tele.InlineButton{*h.layout.Button(c, "admin:pass_location:schedule", struct {
+			ID       string
+			Name     string
+			IsActive bool
+		}{
+			ID:       schedule.ID,
+			Name:     schedule.Name,
+			IsActive: schedule.IsActive,
+		}).Inline()})
+	}
+	markup.InlineKeyboard = append(rows, markup.InlineKeyboard...)
+
+	return banner.Menu.Caption(h.layout.Text(c, "admin_pass_location_text", newPassLocationView(*passLocation, schedules))), markup
+}
+
+func (h Handler) editPassLocationName(c tele.Context) error {
+	return h.editPassLocationField(c, "input_pass_location_name", "invalid_pass_location_name", validator.PassLocationName,
+		func(passLocation *entity.PassLocation, value string) {
+			passLocation.Name = strings.TrimSpace(value)
+		},
+	)
+}
+
+func (h Handler) editPassLocationPatterns(c tele.Context) error {
+	return h.editPassLocationField(c, "input_pass_location_patterns", "invalid_pass_location_patterns", validator.PassLocationPatterns,
+		func(passLocation *entity.PassLocation, value string) {
+			passLocation.Patterns = parsePassLocationPatterns(value)
+		},
+	)
+}
+
+func (h Handler) editPassLocationEmails(c tele.Context) error {
+	return h.editPassLocationField(c, "input_pass_location_emails", "invalid_pass_location_emails", validator.PassLocationEmails,
+		func(passLocation *entity.PassLocation, value string) {
+			if strings.TrimSpace(value) == "-" {
+				passLocation.EmailRecipients = nil
+				return
+			}
+			passLocation.EmailRecipients = validator.ParseEmails(value)
+		},
+	)
+}
+
+func (h Handler) editPassLocationTelegramChat(c tele.Context) error {
+	return h.editPassLocationField(c, "input_pass_location_telegram_chat", "invalid_pass_location_telegram_chat", validator.ChannelID,
+		func(passLocation *entity.PassLocation, value string) {
+			passLocation.TelegramChatID, _ = strconv.ParseInt(value, 10, 64)
+		},
+	)
+}
+
+// editPassLocationField runs the input of a single location field and saves the result
+func (h Handler) editPassLocationField(
+	c tele.Context,
+	promptKey, errorKey string,
+	validate func(string, map[string]interface{}) bool,
+	apply func(passLocation *entity.PassLocation, value string),
+) error {
+	if c.Callback().Data == "" {
+		return errorz.ErrInvalidCallbackData
+	}
+	locationID := c.Callback().Data
+	h.logger.Infof("(user: %d) edit pass location field %s (location_id=%s)", c.Sender().ID, promptKey, locationID)
+
+	backMarkup := h.layout.Markup(c, "admin:pass_location:back", struct {
+		ID string
+	}{
+		ID: locationID,
+	})
+
+	passLocation, err := h.passService.GetLocation(context.Background(), locationID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get pass location: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	value, ok := h.inputText(c, backMarkup, promptKey, errorKey, validate)
+	if !ok {
+		return nil
+	}
+
+	before := *passLocation
+	apply(passLocation, value)
+	if _, err = h.passService.UpdateLocation(context.Background(), passLocation); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return c.Send(
+				banner.Menu.Caption(h.layout.Text(c, "pass_location_already_exists")),
+				backMarkup,
+			)
+		}
+		if errors.Is(err, errorz.ErrPassLocationNoRecipient) {
+			return c.Send(
+				banner.Menu.Caption(h.layout.Text(c, "pass_location_no_recipients")),
+				backMarkup,
+			)
+		}
+
+		h.logger.Errorf("(user: %d) error while update pass location: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	h.logger.Infof("(user: %d) pass location updated (location_id=%s)", c.Sender().ID, locationID)
+	h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionPassLocationUpdated, entity.AuditTargetPassLocation, locationID, before, passLocation)
+	caption, markup := h.passLocationMenu(c, locationID)
+	return c.Send(caption, markup)
+}
+
+// createPassLocation asks for the name, the patterns and the recipients of the new location,
+// schedules are set up in the location menu afterwards
+func (h Handler) createPassLocation(c tele.Context) error {
+	h.logger.Infof("(user: %d) create pass location request", c.Sender().ID)
+
+	backMarkup := h.layout.Markup(c, "admin:pass_locations:back")
+
+	name, ok := h.inputText(c, backMarkup, "input_pass_location_name", "invalid_pass_location_name", validator.PassLocationName)
+	if !ok {
+		return nil
+	}
+
+	patterns, ok := h.inputText(c, backMarkup, "input_pass_location_patterns", "invalid_pass_location_patterns", validator.PassLocationPatterns)
+	if !ok {
+		return nil
+	}
+
+	emails, ok := h.inputText(c, backMarkup, "input_pass_location_emails", "invalid_pass_location_emails", validator.PassLocationEmails)
+	if !ok {
+		return nil
+	}
+
+	chatID, ok := h.inputText(c, backMarkup, "input_pass_location_telegram_chat", "invalid_pass_location_telegram_chat", validator.ChannelID)
+	if !ok {
+		return nil
+	}
+
+	passLocation := &entity.PassLocation{
+		Name:     strings.TrimSpace(name),
+		Patterns: parsePassLocationPatterns(patterns),
+	}
+	if strings.TrimSpace(emails) != "-" {
+		passLocation.EmailRecipients = validator.ParseEmails(emails)
+	}
+	passLocation.TelegramChatID, _ = strconv.ParseInt(chatID, 10, 64)
+
+	passLocation, err := h.passService.CreateLocation(context.Background(), passLocation)
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return c.Send(
+				banner.Menu.Caption(h.layout.Text(c, "pass_location_already_exists")),
+				backMarkup,
+			)
+		}
+		if errors.Is(err, errorz.ErrPassLocationNoRecipient) {
+			return c.Send(
+				banner.Menu.Caption(h.layout.Text(c, "pass_location_no_recipients")),
+				backMarkup,
+			)
+		}
+
+		h.logger.Errorf("(user: %d) error while create pass location: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	h.logger.Infof("(user: %d) pass location created: %s", c.Sender().ID, passLocation.Name)
+	h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionPassLocationCreated, entity.AuditTargetPassLocation, passLocation.ID, nil, passLocation)
+	caption, markup := h.passLocationMenu(c, passLocation.ID)
+	return c.Send(caption, markup)
+}
+
+// parsePassLocationPatterns parses the validated patterns input, "-" clears the patterns
+func parsePassLocationPatterns(value string) []string {
+	if strings.TrimSpace(value) == "-" {
+		return nil
+	}
+
+	return validator.ParsePatterns(value)
+}
@@ -0,0 +1,303 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nlypage/intele/collector"
+	"github.com/robfig/cron/v3"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/validator"
+)
+
+// passScheduleView is a pass schedule shown in the admin menu
+type passScheduleView struct {
+	Name         string
+	CronSchedule string
+	LeadTime     int
+	IsActive     bool
+	NextRun      string
+}
+
+func newPassScheduleView(schedule entity.PassSchedule) passScheduleView {
+	view := passScheduleView{
+		Name:         schedule.Name,
+		CronSchedule: schedule.CronSchedule,
+		LeadTime:     int(schedule.LeadTime.Hours()),
+		IsActive:     schedule.IsActive,
+	}
+	if parsed, err := cron.ParseStandard(schedule.CronSchedule); err == nil && schedule.IsActive {
+		view.NextRun = parsed.Next(time.Now().In(location.Location())).Format("02.01.2006 15:04")
+	}
+
+	return view
+}
+
+func (h Handler) passSchedule(c tele.Context) error {
+	if c.Callback().Data == "" {
+		return errorz.ErrInvalidCallbackData
+	}
+	scheduleID := c.Callback().Data
+	h.logger.Infof("(user: %d) edit pass schedule (schedule_id=%s)", c.Sender().ID, scheduleID)
+
+	caption, markup := h.passScheduleMenu(c, scheduleID)
+	return c.Edit(caption, markup)
+}
+
+// passScheduleMenu returns the pass schedule text and the edit menu
+func (h Handler) passScheduleMenu(c tele.Context, scheduleID string) (interface{}, *tele.ReplyMarkup) {
+	schedule, err := h.passService.GetSchedule(context.Background(), scheduleID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get pass schedule: %v", c.Sender().ID, err)
+		return banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "admin:pass_locations:back")
+	}
+
+	var locationID string
+	if schedule.LocationID != nil {
+		locationID = *schedule.LocationID
+	}
+
+	return banner.Menu.Caption(h.layout.Text(c, "admin_pass_schedule_text", newPassScheduleView(*schedule))),
+		h.layout.Markup(c, "admin:pass_schedule", struct {
+			ID         string
+			LocationID string
+			IsActive   bool
+		}{
+			ID:         schedule.ID,
+			LocationID: locationID,
+			IsActive:   schedule.IsActive,
+		})
+}
+
+// togglePassSchedule enables or disables the schedule,
+// a schedule can't be disabled if some events of the location would not be covered by other runs
+func (h Handler) togglePassSchedule(c tele.Context) error {
+	if c.Callback().Data == "" {
+		return errorz.ErrInvalidCallbackData
+	}
+	scheduleID := c.Callback().Data
+	h.logger.Infof("(user: %d) toggle pass schedule (schedule_id=%s)", c.Sender().ID, scheduleID)
+
+	schedule, err := h.passService.GetSchedule(context.Background(), scheduleID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get pass schedule: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "admin:pass_locations:back"),
+		)
+	}
+
+	before := *schedule
+	schedule.IsActive = !schedule.IsActive
+	if _, err = h.passService.UpdateSchedule(context.Background(), schedule); err != nil {
+		if errors.Is(err, errorz.ErrPassSchedulesNotCovered) {
+			return c.Respond(&tele.CallbackResponse{
+				Text:      h.layout.Text(c, "pass_schedules_not_covered_alert"),
+				ShowAlert: true,
+			})
+		}
+
+		h.logger.Errorf("(user: %d) error while update pass schedule: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "admin:pass_locations:back"),
+		)
+	}
+
+	h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionPassScheduleUpdated, entity.AuditTargetPassSchedule, scheduleID, before, schedule)
+
+	caption, markup := h.passScheduleMenu(c, scheduleID)
+	return c.Edit(caption, markup)
+}
+
+func (h Handler) editPassScheduleCron(c tele.Context) error {
+	return h.editPassScheduleField(c, "input_pass_schedule_cron", "invalid_pass_schedule_cron", validator.PassScheduleCron,
+		func(schedule *entity.PassSchedule, value string) {
+			schedule.CronSchedule = strings.TrimSpace(value)
+		},
+	)
+}
+
+func (h Handler) editPassScheduleLeadTime(c tele.Context) error {
+	return h.editPassScheduleField(c, "input_pass_schedule_lead_time", "invalid_pass_schedule_lead_time", validator.PassScheduleLeadTime,
+		func(schedule *entity.PassSchedule, value string) {
+			hours, _ := strconv.Atoi(value)
+			schedule.LeadTime = time.Duration(hours) * time.Hour
+		},
+	)
+}
+
+// editPassScheduleField runs the input of a single schedule field and saves the result
+func (h Handler) editPassScheduleField(
+	c tele.Context,
+	promptKey, errorKey string,
+	validate func(string, map[string]interface{}) bool,
+	apply func(schedule *entity.PassSchedule, value string),
+) error {
+	if c.Callback().Data == "" {
+		return errorz.ErrInvalidCallbackData
+	}
+	scheduleID := c.Callback().Data
+	h.logger.Infof("(user: %d) edit pass schedule field %s (schedule_id=%s)", c.Sender().ID, promptKey, scheduleID)
+
+	backMarkup := h.layout.Markup(c, "admin:pass_schedule:back", struct {
+		ID string
+	}{
+		ID: scheduleID,
+	})
+
+	schedule, err := h.passService.GetSchedule(context.Background(), scheduleID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get pass schedule: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	value, ok := h.inputText(c, backMarkup, promptKey, errorKey, validate)
+	if !ok {
+		return nil
+	}
+
+	before := *schedule
+	apply(schedule, value)
+	if _, err = h.passService.UpdateSchedule(context.Background(), schedule); err != nil {
+		if errors.Is(err, errorz.ErrPassSchedulesNotCovered) {
+			return c.Send(
+				banner.Menu.Caption(h.layout.Text(c, "pass_schedules_not_covered", err.Error())),
+				backMarkup,
+			)
+		}
+
+		h.logger.Errorf("(user: %d) error while update pass schedule: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	h.logger.Infof("(user: %d) pass schedule updated (schedule_id=%s)", c.Sender().ID, scheduleID)
+	h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionPassScheduleUpdated, entity.AuditTargetPassSchedule, scheduleID, before, schedule)
+	caption, markup := h.passScheduleMenu(c, scheduleID)
+	return c.Send(caption, markup)
+}
+
+// createPassSchedule asks for the name, the cron expression and the lead time of the new location schedule,
+// the schedule is created disabled so that the admin can check it first
+func (h Handler) createPassSchedule(c tele.Context) error {
+	if c.Callback().Data == "" {
+		return errorz.ErrInvalidCallbackData
+	}
+	locationID := c.Callback().Data
+	h.logger.Infof("(user: %d) create pass schedule request (location_id=%s)", c.Sender().ID, locationID)
+
+	backMarkup := h.layout.Markup(c, "admin:pass_location:back", struct {
+		ID string
+	}{
+		ID: locationID,
+	})
+
+	name, ok := h.inputText(c, backMarkup, "input_pass_schedule_name", "invalid_pass_schedule_name", validator.PassScheduleName)
+	if !ok {
+		return nil
+	}
+
+	spec, ok := h.inputText(c, backMarkup, "input_pass_schedule_cron", "invalid_pass_schedule_cron", validator.PassScheduleCron)
+	if !ok {
+		return nil
+	}
+
+	leadTime, ok := h.inputText(c, backMarkup, "input_pass_schedule_lead_time", "invalid_pass_schedule_lead_time", validator.PassScheduleLeadTime)
+	if !ok {
+		return nil
+	}
+	hours, _ := strconv.Atoi(leadTime)
+
+	schedule, err := h.passService.CreateSchedule(context.Background(), &entity.PassSchedule{
+		LocationID:   &locationID,
+		Name:         name,
+		CronSchedule: strings.TrimSpace(spec),
+		LeadTime:     time.Duration(hours) * time.Hour,
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return c.Send(
+				banner.Menu.Caption(h.layout.Text(c, "pass_schedule_already_exists")),
+				backMarkup,
+			)
+		}
+
+		h.logger.Errorf("(user: %d) error while create pass schedule: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	h.logger.Infof("(user: %d) pass schedule created: %s", c.Sender().ID, schedule.Name)
+	h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionPassScheduleCreated, entity.AuditTargetPassSchedule, schedule.ID, nil, schedule)
+	caption, markup := h.passScheduleMenu(c, schedule.ID)
+	return c.Send(caption, markup)
+}
+
+// inputText asks the admin for a text value until it passes the validation,
+// returns false if the input was canceled
+func (h Handler) inputText(
+	c tele.Context,
+	backMarkup *tele.ReplyMarkup,
+	promptKey, errorKey string,
+	validate func(string, map[string]interface{}) bool,
+) (string, bool) {
+	inputCollector := collector.New()
+	// the previous message is already cleared when the value is not the first one in a row
+	if err := c.Edit(banner.Menu.Caption(h.layout.Text(c, promptKey)), backMarkup); err != nil {
+		_ = inputCollector.Send(c,
+			banner.Menu.Caption(h.layout.Text(c, promptKey)),
+			backMarkup,
+		)
+	} else {
+		inputCollector.Collect(c.Message())
+	}
+
+	for {
+		response, errGet := h.input.Get(context.Background(), c.Sender().ID, 0)
+		if response.Message != nil {
+			inputCollector.Collect(response.Message)
+		}
+		switch {
+		case response.Canceled:
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true, ExcludeLast: true})
+			return "", false
+		case errGet != nil:
+			h.logger.Errorf("(user: %d) error while input (%s): %v", c.Sender().ID, promptKey, errGet)
+			_ = inputCollector.Send(c,
+				banner.Menu.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, promptKey))),
+				backMarkup,
+			)
+		case response.Message == nil:
+			_ = inputCollector.Send(c,
+				banner.Menu.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, promptKey))),
+				backMarkup,
+			)
+		case !validate(response.Message.Text, nil):
+			_ = inputCollector.Send(c,
+				banner.Menu.Caption(h.layout.Text(c, errorKey)),
+				backMarkup,
+			)
+		default:
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
+			return response.Message.Text, true
+		}
+	}
+}
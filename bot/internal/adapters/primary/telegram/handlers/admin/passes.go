@@ -0,0 +1,550 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/nlypage/intele/collector"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/validator"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/valueobject"
+)
+
+const (
+	// passEventsLimit is the number of the upcoming club events shown in the passes menu
+	passEventsLimit = 10
+	passesOnPage    = 8
+)
+
+// passFilters are the filters of the event passes list
+var passFilters = []string{"all", "pending", "sent", "cancelled", "manual"}
+
+// eventPassView is a pass of the event shown to the admin
+type eventPassView struct {
+	Number    int
+	FIO       string
+	Requester string
+	Status    string
+	SentAt    string
+	Reason    string
+}
+
+// matchPassFilter checks if the pass is shown with the filter
+func matchPassFilter(pass entity.Pass, filter string) bool {
+	switch filter {
+	case "pending", "sent", "cancelled":
+		return string(pass.Status) == filter
+	case "manual":
+		return pass.Type == entity.PassTypeManual
+	default:
+		return true
+	}
+}
+
+// clubPassEvents shows the upcoming club events to manage their passes
+func (h Handler) clubPassEvents(c tele.Context) error {
+	callbackData := strings.Split(c.Callback().Data, " ")
+	if len(callbackData) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+	clubID, clubsPage := callbackData[0], callbackData[1]
+
+	h.logger.Infof("(user: %d) edit club pass events (club_id=%s)", c.Sender().ID, clubID)
+
+	backMarkup := h.layout.Markup(c, "admin:club:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   clubID,
+		Page: clubsPage,
+	})
+
+	club, err := h.clubService.Get(context.Background(), clubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	events, err := h.eventService.GetFutureByClubID(context.Background(), passEventsLimit, 0, "start_time", clubID, 0)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club events: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	markup := c.Bot().NewMarkup()
+	var rows []tele.Row
+	for _, event := range events {
+		if !event.PassRequired {
+			continue
+		}
+
+		rows = append(rows, markup.Row(*h.layout.Button(c, "admin:club_passes:event", struct {
+			ID        string
+			Filter    string
+			Page      int
+			Name      string
+			StartTime string
+		}{
+			ID:        event.ID,
+			Filter:    passFilters[0],
+			Name:      event.Name,
+			StartTime: event.StartTime.In(location.Location()).Format("02.01 15:04"),
+		})))
+	}
+	rows = append(rows, markup.Row(*h.layout.Button(c, "admin:club:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   clubID,
+		Page: clubsPage,
+	})))
+	markup.Inline(rows...)
+
+	return c.Edit(
+		banner.Menu.Caption(h.layout.Text(c, "admin_club_passes_text", struct {
+			ClubName string
+			Count    int
+		}{
+			ClubName: club.Name,
+			Count:    len(rows) - 1,
+		})),
+		markup,
+	)
+}
+
+// eventPasses shows the passes of the event
+//
+// Callback data is "eventID filter page"
+func (h Handler) eventPasses(c tele.Context) error {
+	callbackData := strings.Split(c.Callback().Data, " ")
+	if len(callbackData) != 3 {
+		return errorz.ErrInvalidCallbackData
+	}
+	eventID, filter := callbackData[0], callbackData[1]
+	p, err := strconv.Atoi(callbackData[2])
+	if err != nil {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	h.logger.Infof("(user: %d) edit event passes (event_id=%s, filter=%s, page=%d)", c.Sender().ID, eventID, filter, p)
+
+	caption, markup := h.eventPassesMenu(c, eventID, filter, p)
+	return c.Edit(caption, markup)
+}
+
+// eventPassesMenu returns the page of the filtered event passes with the buttons to cancel the active ones
+func (h Handler) eventPassesMenu(c tele.Context, eventID, filter string, p int) (interface{}, *tele.ReplyMarkup) {
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "admin:backToMenu")
+	}
+
+	backMarkup := h.layout.Markup(c, "admin:event_passes:to_events", struct {
+		ID string
+	}{
+		ID: event.ClubID,
+	})
+
+	passes, err := h.passService.GetEventPasses(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event passes: %v", c.Sender().ID, err)
+		return banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())), backMarkup
+	}
+
+	var filtered []dto.EventPass
+	for _, pass := range passes {
+		if matchPassFilter(pass.Pass, filter) {
+			filtered = append(filtered, pass)
+		}
+	}
+
+	pagesCount := max(len(filtered)-1, 0) / passesOnPage
+	p = min(max(p, 0), pagesCount)
+	pagePasses := filtered[min(p*passesOnPage, len(filtered)):min((p+1)*passesOnPage, len(filtered))]
+
+	markup := c.Bot().NewMarkup()
+	var rows []tele.Row
+	views := make([]eventPassView, 0, len(pagePasses))
+	for i, pass := range pagePasses {
+		view := eventPassView{
+			Number:    p*passesOnPage + i + 1,
+			FIO:       pass.FIO,
+			Requester: h.layout.Text(c, "pass_requester_"+string(pass.Pass.RequesterType)),
+			Status:    h.layout.Text(c, "pass_status_"+string(pass.Pass.Status)),
+			Reason:    pass.Pass.Reason,
+		}
+		if pass.Pass.SentAt != nil {
+			view.SentAt = pass.Pass.SentAt.In(location.Location()).Format("02.01.2006 15:04")
+		}
+		views = append(views, view)
+
+		if pass.Pass.Status != entity.PassStatusCancelled {
+			rows = append(rows, markup.Row(*h.layout.Button(c, "admin:event_passes:cancel", struct {
+				ID     string
+				Filter string
+				Page   int
+				Number int
+				FIO    string
+			}{
+				ID:     pass.Pass.ID,
+				Filter: filter,
+				Page:   p,
+				Number: view.Number,
+				FIO:    pass.FIO,
+			})))
+		}
+	}
+
+	var filterButtons []tele.Btn
+	for _, passFilter := range passFilters {
+		filterButtons = append(filterButtons, *h.layout.Button(c, "admin:event_passes:filter", struct {
+			ID       string
+			Filter   string
+			Page     int
+			Name     string
+			Selected bool
+		}{
+			ID:       eventID,
+			Filter:   passFilter,
+			Name:     h.layout.Text(c, "pass_filter_"+passFilter),
+			Selected: passFilter == filter,
+		}))
+	}
+	rows = append(rows, markup.Row(filterButtons[:3]...), markup.Row(filterButtons[3:]...))
+
+	if pagesCount > 0 {
+		prevPage, nextPage := p-1, p+1
+		if p == 0 {
+			prevPage = pagesCount
+		}
+		if p >= pagesCount {
+			nextPage = 0
+		}
+
+		rows = append(rows, markup.Row(
+			*h.layout.Button(c, "admin:event_passes:prev_page", struct {
+				ID     string
+				Filter string
+				Page   int
+			}{
+				ID:     eventID,
+				Filter: filter,
+				Page:   prevPage,
+			}),
+			*h.layout.Button(c, "core:page_counter", struct {
+				Page       int
+				PagesCount int
+			}{
+				Page:       p + 1,
+				PagesCount: pagesCount + 1,
+			}),
+			*h.layout.Button(c, "admin:event_passes:next_page", struct {
+				ID     string
+				Filter string
+				Page   int
+			}{
+				ID:     eventID,
+				Filter: filter,
+				Page:   nextPage,
+			}),
+		))
+	}
+
+	rows = append(rows,
+		markup.Row(*h.layout.Button(c, "admin:event_passes:add", struct {
+			ID string
+		}{
+			ID: eventID,
+		})),
+		markup.Row(*h.layout.Button(c, "admin:event_passes:to_events", struct {
+			ID string
+		}{
+			ID: event.ClubID,
+		})),
+	)
+	markup.Inline(rows...)
+
+	return banner.Menu.Caption(h.layout.Text(c, "admin_event_passes_text", struct {
+		Name      string
+		StartTime string
+		Location  string
+		Total     int
+		Filtered  int
+		Filter    string
+		Passes    []eventPassView
+	}{
+		Name:      event.Name,
+		StartTime: event.StartTime.In(location.Location()).Format("02.01.2006 15:04"),
+		Location:  event.Location,
+		Total:     len(passes),
+		Filtered:  len(filtered),
+		Filter:    h.layout.Text(c, "pass_filter_"+filter),
+		Passes:    views,
+	})), markup
+}
+
+// cancelEventPass cancels the pass, the security gets a correction if the pass was already sent
+//
+// Callback data is "passID filter page"
+func (h Handler) cancelEventPass(c tele.Context) error {
+	callbackData := strings.Split(c.Callback().Data, " ")
+	if len(callbackData) != 3 {
+		return errorz.ErrInvalidCallbackData
+	}
+	passID, filter := callbackData[0], callbackData[1]
+	p, err := strconv.Atoi(callbackData[2])
+	if err != nil {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	h.logger.Infof("(user: %d) cancel pass (pass_id=%s)", c.Sender().ID, passID)
+
+	pass, err := h.passService.CancelPass(context.Background(), passID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while cancel pass: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "admin:backToMenu"),
+		)
+	}
+
+	h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionPassCancelled, entity.AuditTargetPass, pass.ID, nil, pass)
+
+	caption, markup := h.eventPassesMenu(c, pass.EventID, filter, p)
+	return c.Edit(caption, markup)
+}
+
+// addEventPasses asks for the users, the reason and the sending mode and issues the manual passes
+func (h Handler) addEventPasses(c tele.Context) error {
+	if c.Callback().Data == "" {
+		return errorz.ErrInvalidCallbackData
+	}
+	eventID := c.Callback().Data
+	h.logger.Infof("(user: %d) add event passes (event_id=%s)", c.Sender().ID, eventID)
+
+	backMarkup := h.layout.Markup(c, "admin:event_passes:back", struct {
+		ID     string
+		Filter string
+		Page   int
+	}{
+		ID:     eventID,
+		Filter: passFilters[0],
+	})
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	users, ok := h.inputPassUsers(c, backMarkup)
+	if !ok {
+		return nil
+	}
+
+	reason, ok := h.inputText(c, backMarkup, "input_pass_reason", "invalid_pass_reason", validator.PassReason)
+	if !ok {
+		return nil
+	}
+
+	sendModeMarkup := h.layout.Markup(c, "admin:event_passes:send_mode", struct {
+		ID     string
+		Filter string
+		Page   int
+	}{
+		ID:     eventID,
+		Filter: passFilters[0],
+	})
+	sendModeMessage, err := c.Bot().Send(c.Chat(),
+		banner.Menu.Caption(h.layout.Text(c, "input_pass_send_mode", len(users))),
+		sendModeMarkup,
+	)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while send pass send mode request: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	response, err := h.input.Get(
+		context.Background(),
+		c.Sender().ID,
+		0,
+		h.layout.Callback("admin:event_passes:send_now"),
+		h.layout.Callback("admin:event_passes:send_later"),
+	)
+	if response.Canceled {
+		return nil
+	}
+	_ = c.Bot().Delete(sendModeMessage)
+	if err != nil || response.Callback == nil {
+		h.logger.Errorf("(user: %d) error while get pass send mode: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.Menu.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "input_pass_send_mode", len(users)))),
+			backMarkup,
+		)
+	}
+	sendNow := strings.Contains(response.Callback.Data, "now")
+
+	userIDs := make([]int64, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.ID)
+	}
+
+	passes, errs := h.passService.CreateManualPasses(context.Background(), event, userIDs, c.Sender().ID, strings.TrimSpace(reason), sendNow)
+	errTexts := make([]string, 0, len(errs))
+	for _, err := range errs {
+		switch {
+		case errors.Is(err, errorz.ErrPassNotRequired),
+			errors.Is(err, errorz.ErrEventCancelled),
+			errors.Is(err, errorz.ErrRegistrationEnded):
+			errTexts = append(errTexts, h.layout.Text(c, "pass_not_allowed"))
+		default:
+			h.logger.Errorf("(user: %d) error while create manual pass: %v", c.Sender().ID, err)
+			errTexts = append(errTexts, err.Error())
+		}
+	}
+
+	h.logger.Infof("(user: %d) manual passes created (event_id=%s, created=%d, failed=%d, send_now=%t)",
+		c.Sender().ID, eventID, len(passes), len(errs), sendNow)
+	if len(passes) > 0 {
+		h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionPassesAdded, entity.AuditTargetEvent, eventID, nil, passes)
+	}
+	return c.Send(
+		banner.Menu.Caption(h.layout.Text(c, "manual_passes_created", struct {
+			Created int
+			SendNow bool
+			Errors  []string
+		}{
+			Created: len(passes),
+			SendNow: sendNow,
+			Errors:  errTexts,
+		})),
+		backMarkup,
+	)
+}
+
+// inputPassUsers asks for the users by username, email, Telegram ID or a forwarded message
+// until all of them are found, returns false if the input was canceled
+func (h Handler) inputPassUsers(c tele.Context, backMarkup *tele.ReplyMarkup) ([]entity.User, bool) {
+	inputCollector := collector.New()
+	_ = c.Edit(banner.Menu.Caption(h.layout.Text(c, "input_pass_users")), backMarkup)
+	inputCollector.Collect(c.Message())
+
+	for {
+		response, errGet := h.input.Get(context.Background(), c.Sender().ID, 0)
+		if response.Message != nil {
+			inputCollector.Collect(response.Message)
+		}
+		switch {
+		case response.Canceled:
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true, ExcludeLast: true})
+			return nil, false
+		case errGet != nil:
+			h.logger.Errorf("(user: %d) error while input pass users: %v", c.Sender().ID, errGet)
+			_ = inputCollector.Send(c,
+				banner.Menu.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "input_pass_users"))),
+				backMarkup,
+			)
+		case response.Message == nil:
+			_ = inputCollector.Send(c,
+				banner.Menu.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "input_pass_users"))),
+				backMarkup,
+			)
+		default:
+			users, notFound, err := h.findPassUsers(response.Message)
+			if err != nil {
+				h.logger.Errorf("(user: %d) error while find pass users: %v", c.Sender().ID, err)
+				_ = inputCollector.Send(c,
+					banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+					backMarkup,
+				)
+				continue
+			}
+			if len(notFound) > 0 || len(users) == 0 {
+				_ = inputCollector.Send(c,
+					banner.Menu.Caption(h.layout.Text(c, "pass_users_not_found", notFound)),
+					backMarkup,
+				)
+				continue
+			}
+
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
+			return users, true
+		}
+	}
+}
+
+// findPassUsers finds the users of the forwarded message or listed in the message text,
+// the identifiers of the users which were not found are returned separately
+func (h Handler) findPassUsers(message *tele.Message) ([]entity.User, []string, error) {
+	if message.IsForwarded() {
+		if message.OriginalSender == nil {
+			return nil, []string{message.OriginalSenderName}, nil
+		}
+
+		user, err := h.adminUserService.Get(context.Background(), message.OriginalSender.ID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, []string{strconv.FormatInt(message.OriginalSender.ID, 10)}, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		return []entity.User{*user}, nil, nil
+	}
+
+	var (
+		users    []entity.User
+		notFound []string
+		seen     = make(map[int64]bool)
+	)
+	for _, identifier := range strings.FieldsFunc(message.Text, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\n'
+	}) {
+		user, err := h.findUser(identifier)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			notFound = append(notFound, identifier)
+		case err != nil:
+			return nil, nil, err
+		case !seen[user.ID]:
+			seen[user.ID] = true
+			users = append(users, *user)
+		}
+	}
+
+	return users, notFound, nil
+}
+
+// findUser finds the user by the numeric ID, the email or the @username
+func (h Handler) findUser(identifier string) (*entity.User, error) {
+	if id, err := strconv.ParseInt(identifier, 10, 64); err == nil {
+		return h.adminUserService.Get(context.Background(), id)
+	}
+	if email, err := valueobject.NewEmail(identifier); err == nil && !strings.HasPrefix(identifier, "@") {
+		return h.adminUserService.GetByEmail(context.Background(), email)
+	}
+
+	return h.adminUserService.GetByUsername(context.Background(), strings.TrimPrefix(identifier, "@"))
+}
@@ -0,0 +1,236 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/validator"
+)
+
+// shadowBanView is a shadow ban shown to the admin
+type shadowBanView struct {
+	ID        string
+	UserID    int64
+	FIO       string
+	Patterns  string
+	Reason    string
+	ExpiresAt string
+}
+
+func newShadowBanView(ban entity.ShadowBan, fio string) shadowBanView {
+	view := shadowBanView{
+		ID:       ban.ID,
+		UserID:   ban.UserID,
+		FIO:      fio,
+		Patterns: strings.Join(ban.Patterns, ", "),
+		Reason:   ban.Reason,
+	}
+	if ban.ExpiresAt != nil {
+		view.ExpiresAt = ban.ExpiresAt.In(location.Location()).Format("02.01.2006 15:04")
+	}
+
+	return view
+}
+
+// addShadowBan shadow bans the user or everyone with the name and surname:
+// /shadow_ban <ID, email, @username или Имя Фамилия>; [срок в днях]; [ФИО гостей через запятую]; [причина]
+func (h Handler) addShadowBan(c tele.Context) error {
+	_ = c.Delete()
+
+	payload := ""
+	if c.Message() != nil {
+		payload = strings.TrimSpace(c.Message().Payload)
+	}
+
+	fields := strings.SplitN(payload, ";", 4)
+	for len(fields) < 4 {
+		fields = append(fields, "")
+	}
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+	target, days, guests, reason := fields[0], fields[1], fields[2], fields[3]
+
+	ban := &entity.ShadowBan{
+		Reason:   reason,
+		AuthorID: c.Sender().ID,
+	}
+
+	var fio string
+	switch {
+	case target == "":
+		return c.Send(
+			h.layout.Text(c, "invalid_shadow_ban_data"),
+			h.layout.Markup(c, "core:hide"),
+		)
+	case strings.Contains(target, " "):
+		if !validator.GuestFIO(target, nil) {
+			return c.Send(
+				h.layout.Text(c, "invalid_shadow_ban_data"),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+		ban.Patterns = append(ban.Patterns, target)
+	default:
+		user, err := h.findUser(target)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return c.Send(
+					h.layout.Text(c, "shadow_ban_user_not_found", target),
+					h.layout.Markup(c, "core:hide"),
+				)
+			}
+
+			h.logger.Errorf("(user: %d) error while find user to shadow ban: %v", c.Sender().ID, err)
+			return c.Send(
+				h.layout.Text(c, "technical_issues", err.Error()),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+		ban.UserID = user.ID
+		fio = user.FIO.String()
+	}
+
+	if days != "" && days != "-" {
+		count, err := strconv.Atoi(days)
+		if err != nil || count <= 0 {
+			return c.Send(
+				h.layout.Text(c, "invalid_shadow_ban_data"),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+		expiresAt := time.Now().AddDate(0, 0, count)
+		ban.ExpiresAt = &expiresAt
+	}
+
+	if guests != "" && guests != "-" {
+		for _, guest := range strings.Split(guests, ",") {
+			guest = strings.TrimSpace(guest)
+			if !validator.GuestFIO(guest, nil) {
+				return c.Send(
+					h.layout.Text(c, "invalid_shadow_ban_data"),
+					h.layout.Markup(c, "core:hide"),
+				)
+			}
+			ban.Patterns = append(ban.Patterns, guest)
+		}
+	}
+
+	h.logger.Infof("(user: %d) add shadow ban (user_id=%d, patterns=%v)", c.Sender().ID, ban.UserID, ban.Patterns)
+	ban, err := h.shadowBanService.Add(context.Background(), ban)
+	if err != nil {
+		if errors.Is(err, errorz.ErrAlreadyShadowBanned) {
+			return c.Send(
+				h.layout.Text(c, "already_shadow_banned"),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+
+		h.logger.Errorf("(user: %d) error while add shadow ban: %v", c.Sender().ID, err)
+		return c.Send(
+			h.layout.Text(c, "technical_issues", err.Error()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionShadowBanAdded, entity.AuditTargetShadowBan, ban.ID, nil, ban)
+	return c.Send(
+		h.layout.Text(c, "shadow_ban_added", newShadowBanView(*ban, fio)),
+		h.layout.Markup(c, "core:hide"),
+	)
+}
+
+// removeShadowBan removes the shadow ban by its ID or all active bans of the user by the user ID:
+// /shadow_unban <ID бана или ID пользователя>
+func (h Handler) removeShadowBan(c tele.Context) error {
+	_ = c.Delete()
+
+	payload := ""
+	if c.Message() != nil {
+		payload = strings.TrimSpace(c.Message().Payload)
+	}
+	h.logger.Infof("(user: %d) remove shadow ban: %s", c.Sender().ID, payload)
+
+	var (
+		removed int
+		err     error
+	)
+	targetType := entity.AuditTargetUser
+	if userID, errParse := strconv.ParseInt(payload, 10, 64); errParse == nil {
+		removed, err = h.shadowBanService.RemoveByUserID(context.Background(), userID)
+	} else if _, errParse = uuid.Parse(payload); errParse == nil {
+		targetType = entity.AuditTargetShadowBan
+		_, err = h.shadowBanService.Remove(context.Background(), payload)
+		if err == nil {
+			removed = 1
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = nil
+		}
+	} else {
+		return c.Send(
+			h.layout.Text(c, "invalid_shadow_unban_data"),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while remove shadow ban: %v", c.Sender().ID, err)
+		return c.Send(
+			h.layout.Text(c, "technical_issues", err.Error()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	if removed == 0 {
+		return c.Send(
+			h.layout.Text(c, "shadow_ban_not_found"),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionShadowBanRemoved, targetType, payload, nil, nil)
+	return c.Send(
+		h.layout.Text(c, "shadow_ban_removed", removed),
+		h.layout.Markup(c, "core:hide"),
+	)
+}
+
+// shadowBans lists the active shadow bans
+func (h Handler) shadowBans(c tele.Context) error {
+	_ = c.Delete()
+	h.logger.Infof("(user: %d) get shadow bans", c.Sender().ID)
+
+	bans, err := h.shadowBanService.GetActive(context.Background())
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get shadow bans: %v", c.Sender().ID, err)
+		return c.Send(
+			h.layout.Text(c, "technical_issues", err.Error()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	return c.Send(
+		h.layout.Text(c, "shadow_bans_text", newShadowBanViews(bans)),
+		h.layout.Markup(c, "core:hide"),
+	)
+}
+
+func newShadowBanViews(bans []dto.ShadowBan) []shadowBanView {
+	views := make([]shadowBanView, 0, len(bans))
+	for _, ban := range bans {
+		views = append(views, newShadowBanView(ban.Ban, ban.FIO))
+	}
+
+	return views
+}
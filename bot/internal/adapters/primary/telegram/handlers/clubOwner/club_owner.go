@@ -20,6 +20,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/primary/telegram/handlers/middlewares"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/secondary/redis/callbacks"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/secondary/redis/events"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
@@ -27,6 +28,7 @@ import (
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/validator"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/valueobject"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/primary"
 	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
 )
@@ -37,20 +39,24 @@ type Handler struct {
 	logger *types.Logger
 	input  *intele.InputManager
 
-	eventsStorage *events.Storage
+	eventsStorage    *events.Storage
+	callbacksStorage callbacks.CallbackStorage
 
 	clubService             primary.ClubService
 	clubOwnerService        primary.ClubOwnerService
 	userService             primary.UserService
 	eventService            primary.EventService
 	eventParticipantService primary.EventParticipantService
+	eventSeriesService      primary.EventSeriesService
 	qrService               primary.QrService
 	notificationService     primary.NotifyService
+	outboxService           primary.OutboxService
+	mailingService          primary.MailingService
+	passService             primary.PassService
+	auditService            primary.AuditService
 
-	mailingChannelID       int64
-	avatarChannelID        int64
-	introChannelID         int64
-	passLocationSubstrings []string
+	avatarChannelID int64
+	introChannelID  int64
 }
 
 func NewHandler(
@@ -59,17 +65,21 @@ func NewHandler(
 	lg *types.Logger,
 	in *intele.InputManager,
 	eventsStorage *events.Storage,
+	callbacksStorage callbacks.CallbackStorage,
 	clubSvc primary.ClubService,
 	clubOwnerSvc primary.ClubOwnerService,
 	userSvc primary.UserService,
 	eventSvc primary.EventService,
 	eventParticipantSvc primary.EventParticipantService,
+	eventSeriesSvc primary.EventSeriesService,
 	qrSvc primary.QrService,
 	notifySvc primary.NotifyService,
-	mailingChannelID int64,
+	outboxSvc primary.OutboxService,
+	mailingSvc primary.MailingService,
+	passSvc primary.PassService,
+	auditSvc primary.AuditService,
 	avatarChannelID int64,
 	introChannelID int64,
-	passLocationSubstrings []string,
 ) *Handler {
 	return &Handler{
 		bot:    b,
@@ -77,20 +87,24 @@ func NewHandler(
 		logger: lg,
 		input:  in,
 
-		eventsStorage: eventsStorage,
+		eventsStorage:    eventsStorage,
+		callbacksStorage: callbacksStorage,
 
 		clubService:             clubSvc,
 		clubOwnerService:        clubOwnerSvc,
 		userService:             userSvc,
 		eventService:            eventSvc,
 		eventParticipantService: eventParticipantSvc,
+		eventSeriesService:      eventSeriesSvc,
 		qrService:               qrSvc,
 		notificationService:     notifySvc,
+		outboxService:           outboxSvc,
+		mailingService:          mailingSvc,
+		passService:             passSvc,
+		auditService:            auditSvc,
 
-		mailingChannelID:       mailingChannelID,
-		avatarChannelID:        avatarChannelID,
-		introChannelID:         introChannelID,
-		passLocationSubstrings: passLocationSubstrings,
+		avatarChannelID: avatarChannelID,
+		introChannelID:  introChannelID,
 	}
 }
 
@@ -170,11 +184,11 @@ func (h Handler) clubMenu(c tele.Context) error {
 		)
 	}
 
-	menuMarkup := h.layout.Markup(c, "clubOwner:club:menu", struct {
+	menuMarkup := h.withoutForbidden(h.layout.Markup(c, "clubOwner:club:menu", struct {
 		ID string
 	}{
 		ID: clubID,
-	})
+	}), h.clubRole(c))
 
 	if len(clubs) > 0 {
 		menuMarkup.InlineKeyboard = append(menuMarkup.InlineKeyboard, []tele.InlineButton{*h.layout.Button(c, "clubOwner:myClubs:back").Inline()})
@@ -353,6 +367,7 @@ func (h Handler) clubMailing(c tele.Context) error {
 		0,
 		h.layout.Callback("clubOwner:confirmMailing"),
 		h.layout.Callback("clubOwner:cancelMailing"),
+		h.layout.Callback("clubOwner:scheduleMailing"),
 	)
 	_ = c.Bot().Delete(confirmMessage)
 	if err != nil {
@@ -391,65 +406,188 @@ func (h Handler) clubMailing(c tele.Context) error {
 	}
 
 	h.logger.Infof("(user: %d) sending club mailing (club_id=%s)", c.Sender().ID, club.ID)
-	clubUsers, err := h.userService.GetUsersByClubID(context.Background(), club.ID)
+	return h.sendMailing(c, response.Callback.Data, &entity.Mailing{
+		ClubID:   club.ID,
+		AuthorID: c.Sender().ID,
+		Audience: entity.MailingAudienceClub,
+	}, message, h.layout.Markup(c, "clubOwner:club:back", struct {
+		ID string
+	}{
+		ID: club.ID,
+	}))
+}
+
+// sendMailing sends the confirmed mailing, or schedules it if the owner has chosen to send it later
+func (h Handler) sendMailing(
+	c tele.Context,
+	confirmData string,
+	mailing *entity.Mailing,
+	message interface{},
+	backMarkup *tele.ReplyMarkup,
+) error {
+	if strings.Contains(confirmData, "schedule") {
+		scheduledAt, ok := h.inputMailingTime(c, backMarkup)
+		if !ok {
+			return nil
+		}
+		mailing.ScheduledAt = &scheduledAt
+	}
+
+	loading, _ := c.Bot().Send(c.Chat(), h.layout.Text(c, "loading"))
+	mailing, err := h.mailingService.Send(context.Background(), mailing, message)
+	_ = c.Bot().Delete(loading)
 	if err != nil {
-		h.logger.Errorf("(user: %d) error while get club users: %v", c.Sender().ID, err)
+		h.logger.Errorf("(user: %d) error while send mailing: %v", c.Sender().ID, err)
 		return c.Send(
 			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
-			h.layout.Markup(c, "clubOwner:club:back", struct {
-				ID string
-			}{
-				ID: club.ID,
-			}),
+			backMarkup,
 		)
 	}
+	h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionMailingSent, entity.AuditTargetMailing, mailing.ID, nil, mailing)
 
-	loading, _ := c.Bot().Send(c.Chat(), h.layout.Text(c, "loading"))
-	for _, user := range clubUsers {
-		if user.IsMailingAllowed(club.ID) {
-			chat, err := c.Bot().ChatByID(user.ID)
-			if err != nil {
-				continue
-			}
+	if mailing.IsScheduled() {
+		scheduledAt := mailing.ScheduledAt.In(location.Location()).Format(eventTimeLayout)
+		h.logger.Infof("(user: %d) mailing scheduled (club_id=%s, mailing_id=%s, scheduled_at=%s)", c.Sender().ID, mailing.ClubID, mailing.ID, scheduledAt)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "mailing_scheduled", scheduledAt)),
+			backMarkup,
+		)
+	}
 
-			_, _ = c.Bot().Send(
-				chat,
-				message,
-				h.layout.Markup(c, "mailing", struct {
-					ClubID  string
-					Allowed bool
-				}{
-					ClubID:  club.ID,
-					Allowed: true,
-				}),
+	h.logger.Infof("(user: %d) mailing enqueued (club_id=%s, mailing_id=%s)", c.Sender().ID, mailing.ClubID, mailing.ID)
+	caption, markup := h.mailingReport(c, mailing.ID)
+	return c.Send(caption, markup)
+}
+
+// inputMailingTime asks the club owner for the send time of the mailing.
+// It returns false if the input was cancelled.
+func (h Handler) inputMailingTime(c tele.Context, backMarkup *tele.ReplyMarkup) (time.Time, bool) {
+	inputCollector := collector.New()
+	prompt, err := c.Bot().Send(c.Chat(),
+		banner.ClubOwner.Caption(h.layout.Text(c, "input_mailing_time")),
+		backMarkup,
+	)
+	if err == nil {
+		inputCollector.Collect(prompt)
+	}
+
+	for {
+		response, errGet := h.input.Get(context.Background(), c.Sender().ID, 0)
+		if response.Message != nil {
+			inputCollector.Collect(response.Message)
+		}
+		switch {
+		case response.Canceled:
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true, ExcludeLast: true})
+			return time.Time{}, false
+		case errGet != nil:
+			h.logger.Errorf("(user: %d) error while input mailing time: %v", c.Sender().ID, errGet)
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "input_mailing_time"))),
+				backMarkup,
+			)
+		case response.Message == nil:
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "input_mailing_time"))),
+				backMarkup,
+			)
+		case !validator.MailingScheduledAt(response.Message.Text, nil):
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "invalid_mailing_time")),
+				backMarkup,
 			)
+		default:
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
+			scheduledAt, _ := time.ParseInLocation(eventTimeLayout, response.Message.Text, location.Location())
+			return scheduledAt, true
 		}
 	}
+}
 
-	h.logger.Infof("(user: %d) club mailing sent (club_id=%s)", c.Sender().ID, club.ID)
+// mailingReport returns the delivery report of the mailing
+func (h Handler) mailingReport(c tele.Context, mailingID string) (interface{}, *tele.ReplyMarkup) {
+	mailing, err := h.mailingService.Get(context.Background(), mailingID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get mailing: %v", c.Sender().ID, err)
+		return banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "mainMenu:back")
+	}
 
-	mailingChannel, err := c.Bot().ChatByID(h.mailingChannelID)
+	stats, err := h.mailingService.GetStats(context.Background(), mailingID)
 	if err != nil {
-		h.logger.Errorf("(user: %d) error while get mailing channel: %v", c.Sender().ID, err)
-	} else {
-		_, err = c.Bot().Send(
-			mailingChannel,
-			message,
-		)
-		if err != nil {
-			h.logger.Errorf("(user: %d) error while send message to mailing channel: %v", c.Sender().ID, err)
-		}
+		h.logger.Errorf("(user: %d) error while get mailing stats: %v", c.Sender().ID, err)
+		return banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:club:back", struct {
+				ID string
+			}{
+				ID: mailing.ClubID,
+			})
 	}
 
-	_ = c.Bot().Delete(loading)
-	return c.Send(
-		banner.ClubOwner.Caption(h.layout.Text(c, "mailing_sent")),
-		h.layout.Markup(c, "clubOwner:club:back", struct {
-			ID string
+	markupName := "clubOwner:mailing:report"
+	if stats.Failed() > 0 {
+		markupName = "clubOwner:mailing:report:failed"
+	}
+
+	return banner.ClubOwner.Caption(h.layout.Text(c, "mailing_report", struct {
+			CreatedAt string
+			Stats     dto.MailingStats
 		}{
-			ID: club.ID,
-		}),
-	)
+			CreatedAt: mailing.CreatedAt.In(location.Location()).Format("02.01.2006 15:04"),
+			Stats:     stats,
+		})),
+		h.layout.Markup(c, markupName, struct {
+			ID     string
+			ClubID string
+		}{
+			ID:     mailing.ID,
+			ClubID: mailing.ClubID,
+		})
+}
+
+func (h Handler) refreshMailingReport(c tele.Context) error {
+	h.logger.Infof("(user: %d) refresh mailing report (mailing_id=%s)", c.Sender().ID, c.Callback().Data)
+
+	caption, markup := h.mailingReport(c, c.Callback().Data)
+	err := c.Edit(caption, markup)
+	if errors.Is(err, tele.ErrSameMessageContent) {
+		return c.Respond()
+	}
+	return err
+}
+
+func (h Handler) retryMailing(c tele.Context) error {
+	mailingID := c.Callback().Data
+	h.logger.Infof("(user: %d) retry failed mailing messages (mailing_id=%s)", c.Sender().ID, mailingID)
+
+	mailing, err := h.mailingService.Get(context.Background(), mailingID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get mailing: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+
+	retried, err := h.mailingService.RetryFailed(context.Background(), mailingID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while retry mailing: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:club:back", struct {
+				ID string
+			}{
+				ID: mailing.ClubID,
+			}),
+		)
+	}
+
+	_ = c.Respond(&tele.CallbackResponse{
+		Text: h.layout.Text(c, "mailing_retried", retried),
+	})
+
+	caption, markup := h.mailingReport(c, mailingID)
+	return c.Edit(caption, markup)
 }
 
 func (h Handler) clubSettings(c tele.Context) error {
@@ -590,7 +728,8 @@ func (h Handler) addOwner(c tele.Context) error {
 		}
 	}
 
-	_, err = h.clubOwnerService.Add(context.Background(), user.ID, club.ID)
+	// new staff members get the least privileged role, co-owners raise it in the staff settings
+	clubOwner, err := h.clubOwnerService.Add(context.Background(), user.ID, club.ID, valueobject.ClubScanner)
 	if err != nil {
 		_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
 		h.logger.Errorf(
@@ -616,15 +755,24 @@ func (h Handler) addOwner(c tele.Context) error {
 		clubID,
 		user.ID,
 	)
+	h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionClubOwnerAdded, entity.AuditTargetClub, clubID, nil, struct {
+		UserID int64
+		Role   valueobject.ClubOwnerRole
+	}{
+		UserID: user.ID,
+		Role:   clubOwner.Role,
+	})
 
 	_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
 	return c.Send(
-		banner.Menu.Caption(h.layout.Text(c, "club_owner_added", struct {
+		banner.Menu.Caption(h.layout.Text(c, "club_staff_added", struct {
 			Club entity.Club
 			User entity.User
+			Role string
 		}{
 			Club: *club,
 			User: *user,
+			Role: clubOwner.Role.String(),
 		})),
 		h.layout.Markup(c, "clubOwner:club:settings:back", struct {
 			ID string
@@ -1513,6 +1661,8 @@ func (h Handler) createEvent(c tele.Context) error {
 	h.logger.Infof("(user: %d) create new event request(club=%s)", c.Sender().ID, clubID)
 
 	h.eventsStorage.Clear(c.Sender().ID)
+	h.eventsStorage.ClearSeries(c.Sender().ID)
+	h.eventsStorage.SetAnnouncementDisabled(c.Sender().ID, false, 0)
 
 	club, err := h.clubService.Get(context.Background(), clubID)
 	if err != nil {
@@ -1749,11 +1899,8 @@ func (h Handler) createEvent(c tele.Context) error {
 	var (
 		eventDescription             string
 		eventStartTime               time.Time
-		eventStartTimeStr            string
 		eventEndTime                 time.Time
-		eventEndTimeStr              string
 		eventRegistrationEndTime     time.Time
-		eventRegistrationEndTimeStr  string
 		eventAfterRegistrationText   string
 		eventMaxParticipants         int
 		eventMaxExpectedParticipants int
@@ -1761,33 +1908,18 @@ func (h Handler) createEvent(c tele.Context) error {
 
 	eventDescription = *steps[1].result
 	eventStartTime, _ = time.ParseInLocation(timeLayout, *steps[3].result, location.Location())
-	eventStartTimeStr = eventStartTime.Format(timeLayout)
 
 	eventEndTime, err = time.ParseInLocation(timeLayout, *steps[4].result, location.Location())
-	eventEndTimeStr = eventEndTime.Format(timeLayout)
 	if err != nil {
 		eventEndTime = time.Time{}
-		eventEndTimeStr = ""
 	}
 
 	eventRegistrationEndTime, _ = time.ParseInLocation(timeLayout, *steps[5].result, location.Location())
-	eventRegistrationEndTimeStr = eventRegistrationEndTime.Format(timeLayout)
 
 	eventAfterRegistrationText = *steps[6].result
 	eventMaxParticipants, _ = strconv.Atoi(*steps[7].result)
 	eventMaxExpectedParticipants, _ = strconv.Atoi(*steps[8].result)
 
-	locationSubstrings := h.passLocationSubstrings
-	passRequired := len(locationSubstrings) == 0
-	if !passRequired {
-		for _, substring := range locationSubstrings {
-			if strings.Contains(strings.ToLower(*steps[2].result), strings.ToLower(substring)) {
-				passRequired = true
-				break
-			}
-		}
-	}
-
 	event := entity.Event{
 		ClubID:                club.ID,
 		Name:                  *steps[0].result,
@@ -1799,60 +1931,26 @@ func (h Handler) createEvent(c tele.Context) error {
 		AfterRegistrationText: eventAfterRegistrationText,
 		MaxParticipants:       eventMaxParticipants,
 		ExpectedParticipants:  eventMaxExpectedParticipants,
-		PassRequired:          passRequired,
-	}
-	h.eventsStorage.Set(c.Sender().ID, event, 0)
-
-	markup := h.layout.Markup(c, "clubOwner:createClub:confirm", struct {
-		ID string
-	}{
-		ID: clubID,
-	})
-
-	var row []tele.InlineButton
-	for _, role := range club.AllowedRoles {
-		row = append(row, []tele.InlineButton{*h.layout.Button(c, "clubOwner:create_event:role", struct {
-			Role     entity.Role
-			ID       string
-			RoleName string
-			Allowed  bool
-		}{
-			Role:     entity.Role(role),
-			ID:       club.ID,
-			RoleName: h.layout.Text(c, role),
-			Allowed:  slices.Contains(event.AllowedRoles, role),
-		}).Inline()}...)
 	}
 
-	markup.InlineKeyboard = append(
-		[][]tele.InlineButton{row},
-		markup.InlineKeyboard...,
-	)
-
-	confirmationPayload := struct {
-		Name                  string
-		Description           string
-		Location              string
-		StartTime             string
-		EndTime               string
-		RegistrationEnd       string
-		AfterRegistrationText string
-		MaxParticipants       int
-		ExpectedParticipants  int
-	}{
-		Name:                  event.Name,
-		Description:           event.Description,
-		Location:              event.Location,
-		StartTime:             eventStartTimeStr,
-		EndTime:               eventEndTimeStr,
-		RegistrationEnd:       eventRegistrationEndTimeStr,
-		AfterRegistrationText: event.AfterRegistrationText,
-		MaxParticipants:       event.MaxParticipants,
-		ExpectedParticipants:  event.ExpectedParticipants,
+	passLocation, err := h.passService.MatchLocation(context.Background(), event.Location)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while match pass location: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:club:back", struct {
+				ID string
+			}{
+				ID: club.ID,
+			}),
+		)
 	}
+	event.SetPassLocation(passLocation)
+	h.eventsStorage.Set(c.Sender().ID, event, 0)
 
+	text, markup := h.eventConfirmation(c, club, event)
 	return c.Send(
-		banner.ClubOwner.Caption(h.layout.Text(c, "event_confirmation", confirmationPayload)),
+		banner.ClubOwner.Caption(text),
 		markup,
 	)
 }
@@ -1908,14 +2006,27 @@ func (h Handler) eventAllowedRoles(c tele.Context) error {
 
 	h.eventsStorage.Set(c.Sender().ID, event, 0)
 
+	text, markup := h.eventConfirmation(c, club, event)
+	return c.Edit(
+		banner.ClubOwner.Caption(text),
+		markup,
+	)
+}
+
+// eventConfirmation renders the confirmation of the event draft with allowed roles toggles and recurrence
+func (h Handler) eventConfirmation(c tele.Context, club *entity.Club, event entity.Event) (string, *tele.ReplyMarkup) {
+	const timeLayout = "02.01.2006 15:04"
+
 	markup := h.layout.Markup(c, "clubOwner:createClub:confirm", struct {
-		ID string
+		ID       string
+		Announce bool
 	}{
-		ID: club.ID,
+		ID:       club.ID,
+		Announce: !h.eventsStorage.IsAnnouncementDisabled(c.Sender().ID),
 	})
 
 	var row []tele.InlineButton
-	for _, role = range club.AllowedRoles {
+	for _, role := range club.AllowedRoles {
 		row = append(row, []tele.InlineButton{*h.layout.Button(c, "clubOwner:create_event:role", struct {
 			Role     entity.Role
 			ID       string
@@ -1934,13 +2045,26 @@ func (h Handler) eventAllowedRoles(c tele.Context) error {
 		markup.InlineKeyboard...,
 	)
 
-	const timeLayout = "02.01.2006 15:04"
-
 	eventTimeStr := event.EndTime.Format(timeLayout)
 	if event.EndTime.Year() == 1 {
 		eventTimeStr = ""
 	}
 
+	var recurrence string
+	if series, err := h.eventsStorage.GetSeries(c.Sender().ID); err == nil {
+		recurrence = h.recurrenceText(c, series)
+	}
+
+	var passLocationName string
+	if event.PassLocationID != nil {
+		passLocation, err := h.passService.GetLocation(context.Background(), *event.PassLocationID)
+		if err != nil {
+			h.logger.Errorf("(user: %d) error while get pass location: %v", c.Sender().ID, err)
+		} else {
+			passLocationName = passLocation.Name
+		}
+	}
+
 	confirmationPayload := struct {
 		Name                  string
 		Description           string
@@ -1951,6 +2075,8 @@ func (h Handler) eventAllowedRoles(c tele.Context) error {
 		AfterRegistrationText string
 		MaxParticipants       int
 		ExpectedParticipants  int
+		Recurrence            string
+		PassLocation          string
 	}{
 		Name:                  event.Name,
 		Description:           event.Description,
@@ -1961,12 +2087,27 @@ func (h Handler) eventAllowedRoles(c tele.Context) error {
 		AfterRegistrationText: event.AfterRegistrationText,
 		MaxParticipants:       event.MaxParticipants,
 		ExpectedParticipants:  event.ExpectedParticipants,
+		Recurrence:            recurrence,
+		PassLocation:          passLocationName,
 	}
 
-	return c.Edit(
-		banner.ClubOwner.Caption(h.layout.Text(c, "event_confirmation", confirmationPayload)),
-		markup,
-	)
+	return h.layout.Text(c, "event_confirmation", confirmationPayload), markup
+}
+
+// recurrenceText describes the recurrence rule of the series draft
+func (h Handler) recurrenceText(c tele.Context, series entity.EventSeries) string {
+	var dates []string
+	for _, date := range series.CustomDates() {
+		dates = append(dates, date.In(location.Location()).Format("02.01.2006 15:04"))
+	}
+
+	return h.layout.Text(c, "recurrence_"+string(series.Rule), struct {
+		Until string
+		Dates string
+	}{
+		Until: series.Until.In(location.Location()).Format("02.01.2006"),
+		Dates: strings.Join(dates, ", "),
+	})
 }
 
 func (h Handler) confirmEventCreation(c tele.Context) error {
@@ -1998,8 +2139,30 @@ func (h Handler) confirmEventCreation(c tele.Context) error {
 	event.EndTime = event.EndTime.UTC()
 	event.RegistrationEnd = event.RegistrationEnd.UTC()
 
-	_, err = h.eventService.Create(context.Background(), &event)
+	// created is the event to announce, the first occurrence for series
+	var created *entity.Event
+	if series, errGet := h.eventsStorage.GetSeries(c.Sender().ID); errGet == nil {
+		var newSeries *entity.EventSeries
+		newSeries, err = h.eventSeriesService.Create(
+			context.Background(),
+			event,
+			series.Rule,
+			series.Until,
+			series.CustomDates(),
+		)
+		if err == nil {
+			occurrences, errGet := h.eventSeriesService.GetFutureOccurrences(context.Background(), newSeries.ID)
+			if errGet != nil {
+				h.logger.Errorf("(user: %d) error while get series occurrences: %v", c.Sender().ID, errGet)
+			} else if len(occurrences) > 0 {
+				created = &occurrences[0]
+			}
+		}
+	} else {
+		created, err = h.eventService.Create(context.Background(), &event)
+	}
 	if err != nil {
+		h.logger.Errorf("(user: %d) error while create event: %v", c.Sender().ID, err)
 		return c.Edit(
 			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
 			h.layout.Markup(c, "clubOwner:club:back", struct {
@@ -2010,7 +2173,16 @@ func (h Handler) confirmEventCreation(c tele.Context) error {
 		)
 	}
 
+	announce := !h.eventsStorage.IsAnnouncementDisabled(c.Sender().ID)
 	h.eventsStorage.Clear(c.Sender().ID)
+	h.eventsStorage.ClearSeries(c.Sender().ID)
+	h.eventsStorage.SetAnnouncementDisabled(c.Sender().ID, false, 0)
+
+	if announce && created != nil {
+		if err = h.notificationService.SendEventAnnouncement(created); err != nil {
+			h.logger.Errorf("(user: %d) error while announce event: %v", c.Sender().ID, err)
+		}
+	}
 
 	return c.Edit(
 		banner.ClubOwner.Caption(h.layout.Text(c, "event_created", struct {
@@ -2025,31 +2197,54 @@ func (h Handler) confirmEventCreation(c tele.Context) error {
 		}))
 }
 
-func (h Handler) eventsList(c tele.Context) error {
-	const eventsOnPage = 5
-	h.logger.Infof("(user: %d) edit events list", c.Sender().ID)
+func (h Handler) eventRepeatMenu(c tele.Context) error {
+	clubID := c.Callback().Data
+	if clubID == "" {
+		return errorz.ErrInvalidCallbackData
+	}
+	h.logger.Infof("(user: %d) event recurrence menu (club_id=%s)", c.Sender().ID, clubID)
 
-	var (
-		p           int
-		prevPage    int
-		nextPage    int
-		err         error
-		eventsCount int64
-		e           []entity.Event
-		rows        []tele.Row
-		menuRow     tele.Row
+	markup := h.layout.Markup(c, "clubOwner:create_event:repeat", struct {
+		ID string
+	}{
+		ID: clubID,
+	})
+
+	var rows [][]tele.InlineButton
+	for _, rule := range []entity.RecurrenceRule{
+		entity.RecurrenceWeekly,
+		entity.RecurrenceBiweekly,
+		entity.RecurrenceCustom,
+	} {
+		rows = append(rows, []tele.InlineButton{*h.layout.Button(c, "clubOwner:create_event:repeat:rule", struct {
+			ID       string
+			Rule     entity.RecurrenceRule
+			RuleName string
+		}{
+			ID:       clubID,
+			Rule:     rule,
+			RuleName: h.layout.Text(c, "recurrence_rule_"+string(rule)),
+		}).Inline()})
+	}
+	markup.InlineKeyboard = append(rows, markup.InlineKeyboard...)
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "event_repeat_text")),
+		markup,
 	)
+}
 
-	clubID, p, err := parseEventCallback(c.Callback().Data)
-	if err != nil {
-		return err
+func (h Handler) eventRepeatBack(c tele.Context) error {
+	clubID := c.Callback().Data
+	if clubID == "" {
+		return errorz.ErrInvalidCallbackData
 	}
 
-	eventsCount, err = h.eventService.CountByClubID(context.Background(), clubID)
+	club, err := h.clubService.Get(context.Background(), clubID)
 	if err != nil {
-		h.logger.Errorf("(user: %d) error while get events count: %v", c.Sender().ID, err)
+		h.logger.Errorf("(user: %d) error while get club: %v", c.Sender().ID, err)
 		return c.Edit(
-			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
 			h.layout.Markup(c, "clubOwner:club:back", struct {
 				ID string
 			}{
@@ -2058,16 +2253,10 @@ func (h Handler) eventsList(c tele.Context) error {
 		)
 	}
 
-	e, err = h.eventService.GetByClubID(
-		context.Background(),
-		eventsOnPage,
-		p*eventsOnPage,
-		clubID,
-	)
+	event, err := h.eventsStorage.Get(c.Sender().ID)
 	if err != nil {
-		h.logger.Errorf("(user: %d) error while get events: %v", c.Sender().ID, err)
 		return c.Edit(
-			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
 			h.layout.Markup(c, "clubOwner:club:back", struct {
 				ID string
 			}{
@@ -2076,23 +2265,223 @@ func (h Handler) eventsList(c tele.Context) error {
 		)
 	}
 
-	markup := c.Bot().NewMarkup()
-	for _, event := range e {
-		rows = append(rows, markup.Row(*h.layout.Button(c, "clubOwner:events:event", struct {
-			ID     string
-			Page   int
-			Name   string
-			IsOver bool
-		}{
-			ID:     event.ID,
-			Page:   p,
-			Name:   event.Name,
-			IsOver: event.IsOver(0),
-		})))
-	}
-	pagesCount := (int(eventsCount) - 1) / eventsOnPage
-	if p == 0 {
-		prevPage = pagesCount
+	text, markup := h.eventConfirmation(c, club, event)
+	return c.Edit(
+		banner.ClubOwner.Caption(text),
+		markup,
+	)
+}
+
+// eventAnnouncementSwitch turns the announcement of the event being created on or off
+func (h Handler) eventAnnouncementSwitch(c tele.Context) error {
+	disabled := !h.eventsStorage.IsAnnouncementDisabled(c.Sender().ID)
+	h.logger.Infof("(user: %d) switch event announcement (club_id=%s, disabled=%t)", c.Sender().ID, c.Callback().Data, disabled)
+	h.eventsStorage.SetAnnouncementDisabled(c.Sender().ID, disabled, 0)
+
+	return h.eventRepeatBack(c)
+}
+
+func (h Handler) eventRepeatOnce(c tele.Context) error {
+	h.logger.Infof("(user: %d) clear event recurrence (club_id=%s)", c.Sender().ID, c.Callback().Data)
+	h.eventsStorage.ClearSeries(c.Sender().ID)
+
+	return h.eventRepeatBack(c)
+}
+
+func (h Handler) eventRepeat(c tele.Context) error {
+	const (
+		timeLayout = "02.01.2006 15:04"
+		dateLayout = "02.01.2006"
+	)
+
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+	clubID, rule := data[0], entity.RecurrenceRule(data[1])
+	h.logger.Infof("(user: %d) set event recurrence (club_id=%s, rule=%s)", c.Sender().ID, clubID, rule)
+
+	club, err := h.clubService.Get(context.Background(), clubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:club:back", struct {
+				ID string
+			}{
+				ID: clubID,
+			}),
+		)
+	}
+
+	event, err := h.eventsStorage.Get(c.Sender().ID)
+	if err != nil {
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:club:back", struct {
+				ID string
+			}{
+				ID: clubID,
+			}),
+		)
+	}
+
+	promptKey, errorKey, validate := "input_series_until", "invalid_series_until", validator.EventSeriesUntil
+	if rule == entity.RecurrenceCustom {
+		promptKey, errorKey, validate = "input_series_dates", "invalid_series_dates", validator.EventSeriesDates
+	}
+	params := map[string]interface{}{
+		"startTime": event.StartTime.In(location.Location()).Format(timeLayout),
+	}
+
+	backMarkup := h.layout.Markup(c, "clubOwner:create_event:repeat:back", struct {
+		ID string
+	}{
+		ID: clubID,
+	})
+
+	inputCollector := collector.New()
+	_ = c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, promptKey)),
+		backMarkup,
+	)
+	inputCollector.Collect(c.Message())
+
+	var (
+		input string
+		done  bool
+	)
+	for {
+		response, errGet := h.input.Get(context.Background(), c.Sender().ID, 0)
+		if response.Message != nil {
+			inputCollector.Collect(response.Message)
+		}
+		switch {
+		case response.Canceled:
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true, ExcludeLast: true})
+			return nil
+		case errGet != nil:
+			h.logger.Errorf("(user: %d) error while input event recurrence: %v", c.Sender().ID, errGet)
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, promptKey))),
+				backMarkup,
+			)
+		case response.Message == nil:
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, promptKey))),
+				backMarkup,
+			)
+		case !validate(response.Message.Text, params):
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, errorKey)),
+				backMarkup,
+			)
+		default:
+			input = response.Message.Text
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
+			done = true
+		}
+		if done {
+			break
+		}
+	}
+
+	var (
+		until time.Time
+		dates []time.Time
+	)
+	if rule == entity.RecurrenceCustom {
+		for _, line := range strings.Split(strings.TrimSpace(input), "\n") {
+			date, _ := time.ParseInLocation(timeLayout, strings.TrimSpace(line), location.Location())
+			dates = append(dates, date)
+		}
+	} else {
+		until, _ = time.ParseInLocation(dateLayout, input, location.Location())
+		// the series lasts until the end of the day
+		until = until.AddDate(0, 0, 1).Add(-time.Minute)
+	}
+
+	h.eventsStorage.SetSeries(c.Sender().ID, *entity.NewEventSeries(event, rule, until, dates), 0)
+
+	text, markup := h.eventConfirmation(c, club, event)
+	return c.Send(
+		banner.ClubOwner.Caption(text),
+		markup,
+	)
+}
+
+func (h Handler) eventsList(c tele.Context) error {
+	const eventsOnPage = 5
+	h.logger.Infof("(user: %d) edit events list", c.Sender().ID)
+
+	var (
+		p           int
+		prevPage    int
+		nextPage    int
+		err         error
+		eventsCount int64
+		e           []entity.Event
+		rows        []tele.Row
+		menuRow     tele.Row
+	)
+
+	clubID, p, err := parseEventCallback(c.Callback().Data)
+	if err != nil {
+		return err
+	}
+
+	eventsCount, err = h.eventService.CountByClubID(context.Background(), clubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get events count: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:club:back", struct {
+				ID string
+			}{
+				ID: clubID,
+			}),
+		)
+	}
+
+	e, err = h.eventService.GetByClubID(
+		context.Background(),
+		eventsOnPage,
+		p*eventsOnPage,
+		clubID,
+	)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get events: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:club:back", struct {
+				ID string
+			}{
+				ID: clubID,
+			}),
+		)
+	}
+
+	markup := c.Bot().NewMarkup()
+	for _, event := range e {
+		rows = append(rows, markup.Row(*h.layout.Button(c, "clubOwner:events:event", struct {
+			ID          string
+			Page        int
+			Name        string
+			IsOver      bool
+			IsRecurring bool
+			IsCancelled bool
+		}{
+			ID:          event.ID,
+			Page:        p,
+			Name:        event.Name,
+			IsOver:      event.IsOver(0),
+			IsRecurring: event.IsRecurring(),
+			IsCancelled: event.IsCancelled(),
+		})))
+	}
+	pagesCount := (int(eventsCount) - 1) / eventsOnPage
+	if p == 0 {
+		prevPage = pagesCount
 	} else {
 		prevPage = p - 1
 	}
@@ -2225,7 +2614,11 @@ func (h Handler) event(c tele.Context) error {
 		)
 	}
 
-	eventMarkup := h.layout.Markup(c, "clubOwner:event:menu", struct {
+	menuKey := "clubOwner:event:menu"
+	if event.IsCancelled() {
+		menuKey = "clubOwner:event:menu:cancelled"
+	}
+	eventMarkup := h.layout.Markup(c, menuKey, struct {
 		ID     string
 		ClubID string
 		Page   string
@@ -2235,7 +2628,7 @@ func (h Handler) event(c tele.Context) error {
 		Page:   page,
 	})
 
-	if club.QrAllowed {
+	if club.QrAllowed && !event.IsCancelled() {
 		eventMarkup.InlineKeyboard = append(
 			[][]tele.InlineButton{{*h.layout.Button(c, "clubOwner:event:qr", struct {
 				ID   string
@@ -2248,6 +2641,21 @@ func (h Handler) event(c tele.Context) error {
 		)
 	}
 
+	if event.PassRequired && !event.IsCancelled() {
+		// the guests go after the participants, before the cancel and back buttons
+		eventMarkup.InlineKeyboard = slices.Insert(eventMarkup.InlineKeyboard, len(eventMarkup.InlineKeyboard)-2,
+			[]tele.InlineButton{*h.layout.Button(c, "clubOwner:event:guests", struct {
+				ID   string
+				Page string
+			}{
+				ID:   eventID,
+				Page: page,
+			}).Inline()},
+		)
+	}
+
+	eventMarkup = h.withoutForbidden(eventMarkup, h.clubRole(c))
+
 	endTime := event.EndTime.In(location.Location()).Format("02.01.2006 15:04")
 	if event.EndTime.Year() == 1 {
 		endTime = ""
@@ -2267,6 +2675,8 @@ func (h Handler) event(c tele.Context) error {
 			AfterRegistrationText string
 			IsRegistered          bool
 			Link                  string
+			IsCancelled           bool
+			CancellationReason    string
 		}{
 			Name:                  event.Name,
 			Description:           event.Description,
@@ -2279,6 +2689,8 @@ func (h Handler) event(c tele.Context) error {
 			VisitedCount:          visitedUsersCount,
 			AfterRegistrationText: event.AfterRegistrationText,
 			Link:                  event.Link(c.Bot().Me.Username),
+			IsCancelled:           event.IsCancelled(),
+			CancellationReason:    event.CancellationReason,
 		})),
 		eventMarkup,
 	)
@@ -2339,6 +2751,32 @@ func (h Handler) eventSettings(c tele.Context) error {
 		)
 	}
 
+	settingsMarkup := h.layout.Markup(c, "clubOwner:event:settings", struct {
+		ID   string
+		Page string
+	}{
+		ID:   eventID,
+		Page: page,
+	})
+
+	if event.IsRecurring() {
+		seriesButtons := struct {
+			ID   string
+			Page string
+		}{
+			ID:   eventID,
+			Page: page,
+		}
+		settingsMarkup.InlineKeyboard = append(
+			[][]tele.InlineButton{
+				{*h.layout.Button(c, "clubOwner:event:settings:apply_to_series", seriesButtons).Inline()},
+				{*h.layout.Button(c, "clubOwner:event:settings:cancel_occurrence", seriesButtons).Inline()},
+			},
+			settingsMarkup.InlineKeyboard...,
+		)
+	}
+	settingsMarkup = h.withoutForbidden(settingsMarkup, h.clubRole(c))
+
 	endTime := event.EndTime.In(location.Location()).Format("02.01.2006 15:04")
 	if event.EndTime.Year() == 1 {
 		endTime = ""
@@ -2358,6 +2796,8 @@ func (h Handler) eventSettings(c tele.Context) error {
 			AfterRegistrationText string
 			IsRegistered          bool
 			Link                  string
+			IsCancelled           bool
+			CancellationReason    string
 		}{
 			Name:                  event.Name,
 			Description:           event.Description,
@@ -2370,14 +2810,182 @@ func (h Handler) eventSettings(c tele.Context) error {
 			VisitedCount:          visitedUsersCount,
 			AfterRegistrationText: event.AfterRegistrationText,
 			Link:                  event.Link(c.Bot().Me.Username),
+			IsCancelled:           event.IsCancelled(),
+			CancellationReason:    event.CancellationReason,
+		})),
+		settingsMarkup)
+}
+
+// applyToSeries copies the settings of the occurrence to all future occurrences of its series
+func (h Handler) applyToSeries(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) apply event settings to series (event_id=%s)", c.Sender().ID, eventID)
+
+	occurrences, err := h.eventSeriesService.UpdateFutureOccurrences(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while update series occurrences: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:event:settings:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   eventID,
+				Page: page,
+			}),
+		)
+	}
+
+	for _, occurrence := range occurrences {
+		if err = h.eventParticipantService.SyncPasses(context.Background(), &occurrence); err != nil {
+			h.logger.Errorf("(user: %d) error while sync occurrence passes: %v", c.Sender().ID, err)
+		}
+
+		err = h.notificationService.SendEventUpdate(occurrence.ID,
+			h.layout.Text(c, "series_notification_update", struct {
+				Name      string
+				StartTime string
+			}{
+				Name:      occurrence.Name,
+				StartTime: occurrence.StartTime.In(location.Location()).Format("02.01.2006 15:04"),
+			}),
+			h.layout.Markup(c, "core:hide"),
+		)
+		if err != nil {
+			h.logger.Errorf("(user: %d) error while send series update notification: %v", c.Sender().ID, err)
+		}
+	}
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "series_updated", struct {
+			Count int
+		}{
+			Count: len(occurrences),
 		})),
-		h.layout.Markup(c, "clubOwner:event:settings", struct {
+		h.layout.Markup(c, "clubOwner:event:settings:back", struct {
 			ID   string
 			Page string
 		}{
 			ID:   eventID,
 			Page: page,
-		}))
+		}),
+	)
+}
+
+func (h Handler) cancelOccurrence(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) cancel event occurrence(eventID=%s) request", c.Sender().ID, eventID)
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:event:settings:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   eventID,
+				Page: page,
+			}),
+		)
+	}
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "cancel_occurrence_text", struct {
+			Name      string
+			StartTime string
+		}{
+			Name:      event.Name,
+			StartTime: event.StartTime.In(location.Location()).Format("02.01.2006 15:04"),
+		})),
+		h.layout.Markup(c, "clubOwner:event:cancel_occurrence", struct {
+			ID   string
+			Page string
+		}{
+			ID:   event.ID,
+			Page: page,
+		}),
+	)
+}
+
+func (h Handler) acceptOccurrenceCancel(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) cancel event occurrence(eventID=%s)", c.Sender().ID, eventID)
+
+	// the series is updated only after the occurrence is cancelled,
+	// an already cancelled occurrence is a retry of the cancellation and its participants are notified again
+	event, err := h.eventService.Cancel(context.Background(), eventID, "")
+	alreadyCancelled := errors.Is(err, errorz.ErrEventCancelled)
+	if err == nil || alreadyCancelled {
+		_, err = h.eventSeriesService.CancelOccurrence(context.Background(), eventID)
+	}
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while cancel event occurrence: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:event:settings:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   eventID,
+				Page: page,
+			}),
+		)
+	}
+	if !alreadyCancelled {
+		h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionEventCancelled, entity.AuditTargetEvent, eventID, nil, event)
+	}
+
+	startTime := event.StartTime.In(location.Location()).Format("02.01.2006 15:04")
+	err = h.notificationService.SendEventUpdate(eventID,
+		h.layout.Text(c, "event_notification_occurrence_cancelled", struct {
+			Name      string
+			StartTime string
+		}{
+			Name:      event.Name,
+			StartTime: startTime,
+		}),
+		h.layout.Markup(c, "core:hide"),
+	)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while send occurrence cancel notification: %v", c.Sender().ID, err)
+	}
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "occurrence_cancelled", struct {
+			Name      string
+			StartTime string
+		}{
+			Name:      event.Name,
+			StartTime: startTime,
+		})),
+		h.layout.Markup(c, "clubOwner:event:cancel:back", struct {
+			ClubID string
+			Page   string
+		}{
+			ClubID: event.ClubID,
+			Page:   page,
+		}),
+	)
 }
 
 func (h Handler) editEventName(c tele.Context) error {
@@ -3119,6 +3727,7 @@ func (h Handler) mailingRegistered(c tele.Context) error {
 		0,
 		h.layout.Callback("clubOwner:confirmMailing"),
 		h.layout.Callback("clubOwner:cancelMailing"),
+		h.layout.Callback("clubOwner:scheduleMailing"),
 	)
 	_ = c.Bot().Delete(confirmMessage)
 	if err != nil {
@@ -3163,69 +3772,18 @@ func (h Handler) mailingRegistered(c tele.Context) error {
 	}
 
 	h.logger.Infof("(user: %d) sending event registered mailing (club_id=%s)", c.Sender().ID, club.ID)
-	eventUsers, err := h.userService.GetUsersByEventID(context.Background(), event.ID)
-	if err != nil {
-		h.logger.Errorf("(user: %d) error while get event users: %v", c.Sender().ID, err)
-		return c.Send(
-			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
-			h.layout.Markup(c, "clubOwner:event:mailing:back", struct {
-				ID   string
-				Page string
-			}{
-				ID:   event.ID,
-				Page: page,
-			}),
-		)
-	}
-
-	loading, _ := c.Bot().Send(c.Chat(), h.layout.Text(c, "loading"))
-	for _, user := range eventUsers {
-		if user.IsMailingAllowed(club.ID) {
-			chat, err := c.Bot().ChatByID(user.ID)
-			if err != nil {
-				continue
-			}
-
-			_, _ = c.Bot().Send(
-				chat,
-				message,
-				h.layout.Markup(c, "mailing", struct {
-					ClubID  string
-					Allowed bool
-				}{
-					ClubID:  club.ID,
-					Allowed: true,
-				}),
-			)
-		}
-	}
-
-	h.logger.Infof("(user: %d) event mailing sent (club_id=%s, event_id=%s)", c.Sender().ID, club.ID, event.ID)
-
-	mailingChannel, err := c.Bot().ChatByID(h.mailingChannelID)
-	if err != nil {
-		h.logger.Errorf("(user: %d) error while get mailing channel: %v", c.Sender().ID, err)
-	} else {
-		_, err = c.Bot().Send(
-			mailingChannel,
-			message,
-		)
-		if err != nil {
-			h.logger.Errorf("(user: %d) error while send message to mailing channel: %v", c.Sender().ID, err)
-		}
-	}
-
-	_ = c.Bot().Delete(loading)
-	return c.Send(
-		banner.ClubOwner.Caption(h.layout.Text(c, "mailing_sent")),
-		h.layout.Markup(c, "clubOwner:event:mailing:back", struct {
-			ID   string
-			Page string
-		}{
-			ID:   event.ID,
-			Page: page,
-		}),
-	)
+	return h.sendMailing(c, response.Callback.Data, &entity.Mailing{
+		ClubID:   club.ID,
+		EventID:  &event.ID,
+		AuthorID: c.Sender().ID,
+		Audience: entity.MailingAudienceEventRegistered,
+	}, message, h.layout.Markup(c, "clubOwner:event:mailing:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   event.ID,
+		Page: page,
+	}))
 }
 
 func (h Handler) mailingVisited(c tele.Context) error {
@@ -3357,6 +3915,7 @@ func (h Handler) mailingVisited(c tele.Context) error {
 		0,
 		h.layout.Callback("clubOwner:confirmMailing"),
 		h.layout.Callback("clubOwner:cancelMailing"),
+		h.layout.Callback("clubOwner:scheduleMailing"),
 	)
 	_ = c.Bot().Delete(confirmMessage)
 	if err != nil {
@@ -3401,72 +3960,21 @@ func (h Handler) mailingVisited(c tele.Context) error {
 	}
 
 	h.logger.Infof("(user: %d) sending event visited mailing (club_id=%s)", c.Sender().ID, club.ID)
-	eventUsers, err := h.userService.GetEventUsers(context.Background(), event.ID)
-	if err != nil {
-		h.logger.Errorf("(user: %d) error while get event users: %v", c.Sender().ID, err)
-		return c.Send(
-			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
-			h.layout.Markup(c, "clubOwner:event:mailing:back", struct {
-				ID   string
-				Page string
-			}{
-				ID:   event.ID,
-				Page: page,
-			}),
-		)
-	}
-
-	loading, _ := c.Bot().Send(c.Chat(), h.layout.Text(c, "loading"))
-	for _, user := range eventUsers {
-		if user.User.IsMailingAllowed(club.ID) && user.UserVisit {
-			chat, err := c.Bot().ChatByID(user.User.ID)
-			if err != nil {
-				continue
-			}
-
-			_, _ = c.Bot().Send(
-				chat,
-				message,
-				h.layout.Markup(c, "mailing", struct {
-					ClubID  string
-					Allowed bool
-				}{
-					ClubID:  club.ID,
-					Allowed: true,
-				}),
-			)
-		}
-	}
-
-	h.logger.Infof("(user: %d) event mailing sent (club_id=%s, event_id=%s)", c.Sender().ID, club.ID, event.ID)
-
-	mailingChannel, err := c.Bot().ChatByID(h.mailingChannelID)
-	if err != nil {
-		h.logger.Errorf("(user: %d) error while get mailing channel: %v", c.Sender().ID, err)
-	} else {
-		_, err = c.Bot().Send(
-			mailingChannel,
-			message,
-		)
-		if err != nil {
-			h.logger.Errorf("(user: %d) error while send message to mailing channel: %v", c.Sender().ID, err)
-		}
-	}
-
-	_ = c.Bot().Delete(loading)
-	return c.Send(
-		banner.ClubOwner.Caption(h.layout.Text(c, "mailing_sent")),
-		h.layout.Markup(c, "clubOwner:event:mailing:back", struct {
-			ID   string
-			Page string
-		}{
-			ID:   event.ID,
-			Page: page,
-		}),
-	)
+	return h.sendMailing(c, response.Callback.Data, &entity.Mailing{
+		ClubID:   club.ID,
+		EventID:  &event.ID,
+		AuthorID: c.Sender().ID,
+		Audience: entity.MailingAudienceEventVisited,
+	}, message, h.layout.Markup(c, "clubOwner:event:mailing:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   event.ID,
+		Page: page,
+	}))
 }
 
-func (h Handler) deleteEvent(c tele.Context) error {
+func (h Handler) cancelEvent(c tele.Context) error {
 	data := strings.Split(c.Callback().Data, " ")
 	if len(data) != 2 {
 		return errorz.ErrInvalidCallbackData
@@ -3474,7 +3982,7 @@ func (h Handler) deleteEvent(c tele.Context) error {
 
 	eventID := data[0]
 	page := data[1]
-	h.logger.Infof("(user: %d) delete event(eventID=%s) request", c.Sender().ID, eventID)
+	h.logger.Infof("(user: %d) cancel event(eventID=%s) request", c.Sender().ID, eventID)
 
 	event, err := h.eventService.Get(context.Background(), eventID)
 	if err != nil {
@@ -3492,12 +4000,12 @@ func (h Handler) deleteEvent(c tele.Context) error {
 	}
 
 	return c.Edit(
-		banner.ClubOwner.Caption(h.layout.Text(c, "delete_event_text", struct {
+		banner.ClubOwner.Caption(h.layout.Text(c, "cancel_event_text", struct {
 			Name string
 		}{
 			Name: event.Name,
 		})),
-		h.layout.Markup(c, "clubOwner:event:delete", struct {
+		h.layout.Markup(c, "clubOwner:event:cancel", struct {
 			ID   string
 			Page string
 		}{
@@ -3507,7 +4015,8 @@ func (h Handler) deleteEvent(c tele.Context) error {
 	)
 }
 
-func (h Handler) acceptEventDelete(c tele.Context) error {
+// acceptEventCancel asks for the cancellation reason, cancels the event and notifies its participants
+func (h Handler) acceptEventCancel(c tele.Context) error {
 	data := strings.Split(c.Callback().Data, " ")
 	if len(data) != 2 {
 		return errorz.ErrInvalidCallbackData
@@ -3515,57 +4024,70 @@ func (h Handler) acceptEventDelete(c tele.Context) error {
 
 	eventID := data[0]
 	page := data[1]
-	h.logger.Infof("(user: %d) delete event(eventID=%s)", c.Sender().ID, eventID)
+	h.logger.Infof("(user: %d) cancel event(eventID=%s)", c.Sender().ID, eventID)
+
+	backMarkup := h.layout.Markup(c, "clubOwner:event:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   eventID,
+		Page: page,
+	})
 
 	event, err := h.eventService.Get(context.Background(), eventID)
 	if err != nil {
-		h.logger.Errorf("(user: %d) error while delete event: %v", c.Sender().ID, err)
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
 		return c.Edit(
 			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
-			h.layout.Markup(c, "clubOwner:event:delete:back", struct {
-				ID   string
-				Page string
-			}{
-				ID:   eventID,
-				Page: page,
-			}),
+			backMarkup,
 		)
 	}
 
-	err = h.eventService.Delete(context.Background(), eventID)
-	if err != nil {
-		h.logger.Errorf("(user: %d) error while delete event: %v", c.Sender().ID, err)
-		return c.Edit(
+	reason, ok := h.inputText(c, backMarkup,
+		"input_event_cancellation_reason", "invalid_event_cancellation_reason",
+		validator.EventCancellationReason, nil, nil,
+	)
+	if !ok {
+		return nil
+	}
+
+	// an already cancelled event is a retry of the cancellation, its participants are notified again
+	event, err = h.eventService.Cancel(context.Background(), eventID, reason)
+	alreadyCancelled := errors.Is(err, errorz.ErrEventCancelled)
+	if err != nil && !alreadyCancelled {
+		h.logger.Errorf("(user: %d) error while cancel event: %v", c.Sender().ID, err)
+		return c.Send(
 			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
-			h.layout.Markup(c, "clubOwner:event:delete:back", struct {
-				ID   string
-				Page string
-			}{
-				ID:   eventID,
-				Page: page,
-			}),
+			backMarkup,
 		)
 	}
+	if !alreadyCancelled {
+		h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionEventCancelled, entity.AuditTargetEvent, eventID, nil, event)
+	}
 
 	err = h.notificationService.SendEventUpdate(eventID,
-		h.layout.Text(c, "event_notification_delete", struct {
-			Name string
+		h.layout.Text(c, "event_notification_cancel", struct {
+			Name      string
+			StartTime string
+			Reason    string
 		}{
-			Name: event.Name,
+			Name:      event.Name,
+			StartTime: event.StartTime.In(location.Location()).Format("02.01.2006 15:04"),
+			Reason:    event.CancellationReason,
 		}),
 		h.layout.Markup(c, "core:hide"),
 	)
 	if err != nil {
-		h.logger.Errorf("(user: %d) error while send event delete notification: %v", c.Sender().ID, err)
+		h.logger.Errorf("(user: %d) error while send event cancel notification: %v", c.Sender().ID, err)
 	}
 
-	return c.Edit(
-		banner.ClubOwner.Caption(h.layout.Text(c, "event_deleted", struct {
+	return c.Send(
+		banner.ClubOwner.Caption(h.layout.Text(c, "event_cancelled", struct {
 			Name string
 		}{
 			Name: event.Name,
 		})),
-		h.layout.Markup(c, "clubOwner:event:delete:back", struct {
+		h.layout.Markup(c, "clubOwner:event:cancel:back", struct {
 			ClubID string
 			Page   string
 		}{
@@ -3575,7 +4097,7 @@ func (h Handler) acceptEventDelete(c tele.Context) error {
 	)
 }
 
-func (h Handler) declineEventDelete(c tele.Context) error {
+func (h Handler) declineEventCancel(c tele.Context) error {
 	data := strings.Split(c.Callback().Data, " ")
 	if len(data) != 2 {
 		return errorz.ErrInvalidCallbackData
@@ -3583,13 +4105,13 @@ func (h Handler) declineEventDelete(c tele.Context) error {
 
 	eventID := data[0]
 	page := data[1]
-	h.logger.Infof("(user: %d) decline delete event(eventID=%s)", c.Sender().ID, eventID)
+	h.logger.Infof("(user: %d) decline cancel event(eventID=%s)", c.Sender().ID, eventID)
 
 	event, err := h.eventService.Get(context.Background(), eventID)
 	if err != nil {
 		return c.Edit(
 			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
-			h.layout.Markup(c, "clubOwner:event:delete:back", struct {
+			h.layout.Markup(c, "clubOwner:event:cancel:back", struct {
 				ID   string
 				Page string
 			}{
@@ -3603,7 +4125,7 @@ func (h Handler) declineEventDelete(c tele.Context) error {
 	if err != nil {
 		return c.Edit(
 			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
-			h.layout.Markup(c, "clubOwner:event:delete:back", struct {
+			h.layout.Markup(c, "clubOwner:event:cancel:back", struct {
 				ID   string
 				Page string
 			}{
@@ -3643,7 +4165,11 @@ func (h Handler) declineEventDelete(c tele.Context) error {
 		)
 	}
 
-	eventMarkup := h.layout.Markup(c, "clubOwner:event:menu", struct {
+	menuKey := "clubOwner:event:menu"
+	if event.IsCancelled() {
+		menuKey = "clubOwner:event:menu:cancelled"
+	}
+	eventMarkup := h.layout.Markup(c, menuKey, struct {
 		ID     string
 		ClubID string
 		Page   string
@@ -3653,7 +4179,7 @@ func (h Handler) declineEventDelete(c tele.Context) error {
 		Page:   page,
 	})
 
-	if club.QrAllowed {
+	if club.QrAllowed && !event.IsCancelled() {
 		eventMarkup.InlineKeyboard = append(
 			[][]tele.InlineButton{{*h.layout.Button(c, "clubOwner:event:qr", struct {
 				ID   string
@@ -3685,6 +4211,8 @@ func (h Handler) declineEventDelete(c tele.Context) error {
 			AfterRegistrationText string
 			IsRegistered          bool
 			Link                  string
+			IsCancelled           bool
+			CancellationReason    string
 		}{
 			Name:                  event.Name,
 			Description:           event.Description,
@@ -3697,6 +4225,8 @@ func (h Handler) declineEventDelete(c tele.Context) error {
 			VisitedCount:          visitedUsersCount,
 			AfterRegistrationText: event.AfterRegistrationText,
 			Link:                  event.Link(c.Bot().Me.Username),
+			IsCancelled:           event.IsCancelled(),
+			CancellationReason:    event.CancellationReason,
 		})),
 		eventMarkup,
 	)
@@ -3758,6 +4288,32 @@ func (h Handler) registeredUsers(c tele.Context) error {
 	//	)
 	//}
 
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	registeredUsersCount, err := h.eventParticipantService.CountByEventID(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get registered users count: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	waitlistCount, err := h.eventParticipantService.CountWaitlist(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get waitlist count: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
 	users, err := h.userService.GetEventUsers(context.Background(), eventID)
 	if err != nil {
 		return c.Send(
@@ -3775,8 +4331,16 @@ func (h Handler) registeredUsers(c tele.Context) error {
 	}
 
 	file := &tele.Document{
-		File:     tele.FromReader(buffer),
-		Caption:  h.layout.Text(c, "registered_users_text"),
+		File: tele.FromReader(buffer),
+		Caption: h.layout.Text(c, "registered_users_text", struct {
+			ParticipantsCount int
+			MaxParticipants   int
+			WaitlistCount     int
+		}{
+			ParticipantsCount: registeredUsersCount,
+			MaxParticipants:   event.MaxParticipants,
+			WaitlistCount:     waitlistCount,
+		}),
 		FileName: "users.xlsx",
 	}
 
@@ -3873,59 +4437,108 @@ func (h Handler) eventQRCode(c tele.Context) error {
 
 func (h Handler) ClubOwnerSetup(group *tele.Group, middle *middlewares.Handler) {
 	group.Use(middle.IsClubOwner)
+
+	clubScan := h.can(h.clubFromCallback, valueobject.ClubScanPermission)
+	clubEvents := h.can(h.clubFromCallback, valueobject.ClubEventsPermission)
+	clubManage := h.can(h.clubFromCallback, valueobject.ClubManagePermission)
+	eventScan := h.can(h.clubOfEvent, valueobject.ClubScanPermission)
+	eventEvents := h.can(h.clubOfEvent, valueobject.ClubEventsPermission)
+	eventManage := h.can(h.clubOfEvent, valueobject.ClubManagePermission)
+	draftEvents := h.can(h.clubOfDraft, valueobject.ClubEventsPermission)
+	guestEvents := h.can(h.clubOfGuest, valueobject.ClubEventsPermission)
+	mailingManage := h.can(h.clubOfMailing, valueobject.ClubManagePermission)
+
 	group.Handle(h.layout.Callback("clubOwner:my_clubs"), h.clubsList)
 	group.Handle(h.layout.Callback("clubOwner:myClubs:back"), h.clubsList)
-	group.Handle(h.layout.Callback("clubOwner:myClubs:club"), h.clubMenu)
-	group.Handle(h.layout.Callback("clubOwner:club:back"), h.clubMenu)
-
-	group.Handle(h.layout.Callback("clubOwner:club:create_event"), h.createEvent)
-	group.Handle(h.layout.Callback("clubOwner:create_event:refill"), h.createEvent)
-	group.Handle(h.layout.Callback("clubOwner:create_event:confirm"), h.confirmEventCreation)
-	group.Handle(h.layout.Callback("clubOwner:create_event:role"), h.eventAllowedRoles)
-	group.Handle(h.layout.Callback("clubOwner:club:back"), h.clubMenu)
-
-	group.Handle(h.layout.Callback("clubOwner:club:events"), h.eventsList)
-	group.Handle(h.layout.Callback("clubOwner:events:back"), h.eventsList)
-	group.Handle(h.layout.Callback("clubOwner:events:prev_page"), h.eventsList)
-	group.Handle(h.layout.Callback("clubOwner:events:next_page"), h.eventsList)
-	group.Handle(h.layout.Callback("clubOwner:events:event"), h.event)
-	group.Handle(h.layout.Callback("clubOwner:event:back"), h.event)
-	group.Handle(h.layout.Callback("clubOwner:event:settings"), h.eventSettings)
-	group.Handle(h.layout.Callback("clubOwner:event:settings:back"), h.eventSettings)
-	group.Handle(h.layout.Callback("clubOwner:event:settings:edit_name"), h.editEventName)
-	group.Handle(h.layout.Callback("clubOwner:event:settings:edit_description"), h.editEventDescription)
-	group.Handle(h.layout.Callback("clubOwner:event:settings:edit_after_reg_text"), h.editEventAfterRegistrationText)
-	group.Handle(h.layout.Callback("clubOwner:event:settings:edit:max_participants"), h.editEventMaxParticipants)
-	group.Handle(h.layout.Callback("clubOwner:event:delete"), h.deleteEvent)
-	group.Handle(h.layout.Callback("clubOwner:event:delete:accept"), h.acceptEventDelete)
-	group.Handle(h.layout.Callback("clubOwner:event:delete:decline"), h.declineEventDelete)
-
-	group.Handle(h.layout.Callback("clubOwner:event:users"), h.registeredUsers)
-	group.Handle(h.layout.Callback("clubOwner:event:qr"), h.eventQRCode)
-
-	group.Handle(h.layout.Callback("clubOwner:event:mailing"), h.eventMailing)
-	group.Handle(h.layout.Callback("clubOwner:event:mailing:back"), h.eventMailing)
-	group.Handle(h.layout.Callback("clubOwner:event:mailing:registered"), h.mailingRegistered)
-	group.Handle(h.layout.Callback("clubOwner:event:mailing:visited"), h.mailingVisited)
-	group.Handle(h.layout.Callback("clubOwner:club:mailing"), h.clubMailing)
-
-	group.Handle(h.layout.Callback("clubOwner:club:settings"), h.clubSettings)
-	group.Handle(h.layout.Callback("clubOwner:club:settings:back"), h.clubSettings)
-	group.Handle(h.layout.Callback("clubOwner:club:settings:add_owner"), h.addOwner)
-	group.Handle(h.layout.Callback("clubOwner:club:settings:warnings"), h.warnings)
-	group.Handle(h.layout.Callback("clubOwner:club:settings:profile"), h.profile)
-	group.Handle(h.layout.Callback("clubOwner:club:settings:profile:set_name"), h.setClubName)
-	group.Handle(h.layout.Callback("clubOwner:club:settings:profile:set_description"), h.setClubDescription)
-	group.Handle(h.layout.Callback("clubOwner:club:settings:profile:set_link"), h.setClubLink)
-	group.Handle(h.layout.Callback("clubOwner:club:settings:profile:set_avatar"), h.setClubAvatar)
-	group.Handle(h.layout.Callback("clubOwner:club:settings:profile:set_intro"), h.setClubIntro)
-	group.Handle(h.layout.Callback("clubOwner:club:settings:profile:should_show"), h.shouldShow)
-	group.Handle(h.layout.Callback("clubOwner:club:settings:subscription_access"), h.subscriptionAccess)
-	group.Handle(h.layout.Callback("clubOwner:club:settings:subscription_access:required"), h.subscriptionAccess)
-	group.Handle(h.layout.Callback("clubOwner:club:settings:subscription_access:set_channel_id"), h.setChannelID)
-	group.Handle(h.layout.Callback("clubOwner:club:settings:subscription_access:back"), h.subscriptionAccess)
-	group.Handle(h.layout.Callback("clubOwner:club:settings:profile:back"), h.profile)
-	group.Handle(h.layout.Callback("clubOwner:club:settings:warnings:user"), h.warnings)
+	group.Handle(h.layout.Callback("clubOwner:myClubs:club"), h.clubMenu, clubScan)
+	group.Handle(h.layout.Callback("clubOwner:club:back"), h.clubMenu, clubScan)
+	group.Handle(h.layout.Callback("clubOwner:club:leave"), h.leaveClub, clubScan)
+	group.Handle(h.layout.Callback("clubOwner:club:leave:accept"), h.acceptClubLeave, clubScan)
+
+	group.Handle(h.layout.Callback("clubOwner:club:create_event"), h.createEvent, clubEvents)
+	group.Handle(h.layout.Callback("clubOwner:create_event:refill"), h.createEvent, clubEvents)
+	group.Handle(h.layout.Callback("clubOwner:create_event:confirm"), h.confirmEventCreation, clubEvents)
+	group.Handle(h.layout.Callback("clubOwner:create_event:repeat"), h.eventRepeatMenu, clubEvents)
+	group.Handle(h.layout.Callback("clubOwner:create_event:repeat:rule"), h.eventRepeat, clubEvents)
+	group.Handle(h.layout.Callback("clubOwner:create_event:repeat:once"), h.eventRepeatOnce, clubEvents)
+	group.Handle(h.layout.Callback("clubOwner:create_event:announce"), h.eventAnnouncementSwitch, clubEvents)
+	group.Handle(h.layout.Callback("clubOwner:create_event:repeat:back"), h.eventRepeatBack, clubEvents)
+	group.Handle(h.layout.Callback("clubOwner:create_event:role"), h.eventAllowedRoles, clubEvents)
+	group.Handle(h.layout.Callback("clubOwner:club:back"), h.clubMenu, clubScan)
+
+	group.Handle(h.layout.Callback("clubOwner:club:events"), h.eventsList, clubScan)
+	group.Handle(h.layout.Callback("clubOwner:events:back"), h.eventsList, clubScan)
+	group.Handle(h.layout.Callback("clubOwner:events:prev_page"), h.eventsList, clubScan)
+	group.Handle(h.layout.Callback("clubOwner:events:next_page"), h.eventsList, clubScan)
+	group.Handle(h.layout.Callback("clubOwner:events:event"), h.event, eventScan)
+	group.Handle(h.layout.Callback("clubOwner:event:back"), h.event, eventScan)
+	group.Handle(h.layout.Callback("clubOwner:event:settings"), h.eventSettings, eventEvents)
+	group.Handle(h.layout.Callback("clubOwner:event:settings:back"), h.eventSettings, eventEvents)
+	group.Handle(h.layout.Callback("clubOwner:event:settings:edit_name"), h.editEventName, eventEvents)
+	group.Handle(h.layout.Callback("clubOwner:event:settings:edit_description"), h.editEventDescription, eventEvents)
+	group.Handle(h.layout.Callback("clubOwner:event:settings:edit_after_reg_text"), h.editEventAfterRegistrationText, eventEvents)
+	group.Handle(h.layout.Callback("clubOwner:event:settings:edit:max_participants"), h.editEventMaxParticipants, eventEvents)
+	group.Handle(h.layout.Callback("clubOwner:event:settings:edit_start_time"), h.editEventStartTime, eventEvents)
+	group.Handle(h.layout.Callback("clubOwner:event:settings:edit_end_time"), h.editEventEndTime, eventEvents)
+	group.Handle(h.layout.Callback("clubOwner:event:settings:edit_location"), h.editEventLocation, eventEvents)
+	group.Handle(h.layout.Callback("clubOwner:event:settings:edit_registration_end"), h.editEventRegistrationEnd, eventEvents)
+	group.Handle(h.layout.Callback("clubOwner:event:settings:edit_roles"), h.editEventRoles, eventEvents)
+	group.Handle(h.layout.Callback("clubOwner:event:settings:edit_roles:role"), h.toggleEventRole, draftEvents)
+	group.Handle(h.layout.Callback("clubOwner:event:settings:edit_roles:save"), h.saveEventRoles, eventEvents)
+	group.Handle(h.layout.Callback("clubOwner:event:cancel"), h.cancelEvent, eventManage)
+	group.Handle(h.layout.Callback("clubOwner:event:cancel:accept"), h.acceptEventCancel, eventManage)
+	group.Handle(h.layout.Callback("clubOwner:event:cancel:decline"), h.declineEventCancel, eventManage)
+	group.Handle(h.layout.Callback("clubOwner:event:settings:apply_to_series"), h.applyToSeries, eventEvents)
+	group.Handle(h.layout.Callback("clubOwner:event:settings:cancel_occurrence"), h.cancelOccurrence, eventManage)
+	group.Handle(h.layout.Callback("clubOwner:event:cancel_occurrence:accept"), h.acceptOccurrenceCancel, eventManage)
+
+	group.Handle(h.layout.Callback("clubOwner:event:users"), h.registeredUsers, eventEvents)
+	group.Handle(h.layout.Callback("clubOwner:event:qr"), h.eventQRCode, eventScan)
+
+	group.Handle(h.layout.Callback("clubOwner:event:guests"), h.eventGuests, eventEvents)
+	group.Handle(h.layout.Callback("clubOwner:event:guests:back"), h.eventGuests, eventEvents)
+	group.Handle(h.layout.Callback("clubOwner:event:guests:add"), h.addEventGuest, eventEvents)
+	group.Handle(h.layout.Callback("clubOwner:event:guests:remove"), h.removeEventGuest, guestEvents)
+	group.Handle(h.layout.Callback("clubOwner:event:mailing"), h.eventMailing, eventEvents)
+	group.Handle(h.layout.Callback("clubOwner:event:mailing:back"), h.eventMailing, eventEvents)
+	group.Handle(h.layout.Callback("clubOwner:event:mailing:registered"), h.mailingRegistered, eventEvents)
+	group.Handle(h.layout.Callback("clubOwner:event:mailing:visited"), h.mailingVisited, eventEvents)
+	group.Handle(h.layout.Callback("clubOwner:club:mailing"), h.clubMailing, clubManage)
+	group.Handle(h.layout.Callback("clubOwner:mailing:refresh"), h.refreshMailingReport, mailingManage)
+	group.Handle(h.layout.Callback("clubOwner:mailing:retry"), h.retryMailing, mailingManage)
+	group.Handle(h.layout.Callback("clubOwner:club:scheduled_mailings"), h.scheduledMailings, clubManage)
+	group.Handle(h.layout.Callback("clubOwner:scheduled_mailings:mailing"), h.scheduledMailing, mailingManage)
+	group.Handle(h.layout.Callback("clubOwner:scheduled_mailing:edit_time"), h.editScheduledMailingTime, mailingManage)
+	group.Handle(h.layout.Callback("clubOwner:scheduled_mailing:edit_text"), h.editScheduledMailingText, mailingManage)
+	group.Handle(h.layout.Callback("clubOwner:scheduled_mailing:cancel"), h.cancelScheduledMailing, mailingManage)
+
+	group.Handle(h.layout.Callback("clubOwner:club:settings"), h.clubSettings, clubManage)
+	group.Handle(h.layout.Callback("clubOwner:club:settings:back"), h.clubSettings, clubManage)
+	group.Handle(h.layout.Callback("clubOwner:club:settings:add_owner"), h.addOwner, clubManage)
+	group.Handle(h.layout.Callback("clubOwner:club:settings:transfer"), h.transferClub, clubManage)
+	group.Handle(h.layout.Callback("clubOwner:club:settings:warnings"), h.warnings, clubManage)
+	group.Handle(h.layout.Callback("clubOwner:club:settings:profile"), h.profile, clubManage)
+	group.Handle(h.layout.Callback("clubOwner:club:settings:profile:set_name"), h.setClubName, clubManage)
+	group.Handle(h.layout.Callback("clubOwner:club:settings:profile:set_description"), h.setClubDescription, clubManage)
+	group.Handle(h.layout.Callback("clubOwner:club:settings:profile:set_link"), h.setClubLink, clubManage)
+	group.Handle(h.layout.Callback("clubOwner:club:settings:profile:set_avatar"), h.setClubAvatar, clubManage)
+	group.Handle(h.layout.Callback("clubOwner:club:settings:profile:set_intro"), h.setClubIntro, clubManage)
+	group.Handle(h.layout.Callback("clubOwner:club:settings:profile:should_show"), h.shouldShow, clubManage)
+	group.Handle(h.layout.Callback("clubOwner:club:settings:subscription_access"), h.subscriptionAccess, clubManage)
+	group.Handle(h.layout.Callback("clubOwner:club:settings:subscription_access:required"), h.subscriptionAccess, clubManage)
+	group.Handle(h.layout.Callback("clubOwner:club:settings:subscription_access:set_channel_id"), h.setChannelID, clubManage)
+	group.Handle(h.layout.Callback("clubOwner:club:settings:subscription_access:back"), h.subscriptionAccess, clubManage)
+	group.Handle(h.layout.Callback("clubOwner:club:settings:profile:back"), h.profile, clubManage)
+	group.Handle(h.layout.Callback("clubOwner:club:settings:warnings:user"), h.warnings, clubManage)
+	group.Handle(h.layout.Callback("clubOwner:club:settings:staff"), h.staff, clubManage)
+	group.Handle(h.layout.Callback("clubOwner:club:settings:staff:back"), h.staff, clubManage)
+	group.Handle(h.layout.Callback("clubOwner:club:settings:staff:member"), h.staffMember, clubManage)
+	group.Handle(h.layout.Callback("clubOwner:club:settings:staff:member:back"), h.staffMember, clubManage)
+	for callback := range staffRoleButtons {
+		group.Handle(h.layout.Callback(callback), h.setStaffRole, clubManage)
+	}
+	group.Handle(h.layout.Callback("clubOwner:club:settings:staff:remove"), h.removeStaff, clubManage)
+	group.Handle(h.layout.Callback("clubOwner:club:settings:staff:remove:accept"), h.acceptStaffRemoval, clubManage)
 }
 
 func parseEventCallback(callbackData string) (string, int, error) {
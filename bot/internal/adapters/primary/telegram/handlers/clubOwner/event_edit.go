@@ -0,0 +1,483 @@
+package clubowner
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nlypage/intele/collector"
+	tele "gopkg.in/telebot.v3"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/validator"
+)
+
+const eventTimeLayout = "02.01.2006 15:04"
+
+// eventChange is a single changed field of the event shown to participants
+type eventChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// eventChanges returns the diff of time, location, registration end and allowed roles of the event
+func (h Handler) eventChanges(c tele.Context, old, event entity.Event) []eventChange {
+	formatTime := func(t time.Time) string {
+		if t.IsZero() {
+			return h.layout.Text(c, "not_specified")
+		}
+		return t.In(location.Location()).Format(eventTimeLayout)
+	}
+	formatRoles := func(roles []string) string {
+		names := make([]string, 0, len(roles))
+		for _, role := range roles {
+			names = append(names, h.layout.Text(c, role))
+		}
+		return strings.Join(names, ", ")
+	}
+
+	var changes []eventChange
+	if !old.StartTime.Equal(event.StartTime) {
+		changes = append(changes, eventChange{
+			Field: h.layout.Text(c, "event_field_start_time"),
+			Old:   formatTime(old.StartTime),
+			New:   formatTime(event.StartTime),
+		})
+	}
+	if !old.EndTime.Equal(event.EndTime) {
+		changes = append(changes, eventChange{
+			Field: h.layout.Text(c, "event_field_end_time"),
+			Old:   formatTime(old.EndTime),
+			New:   formatTime(event.EndTime),
+		})
+	}
+	if old.Location != event.Location {
+		changes = append(changes, eventChange{
+			Field: h.layout.Text(c, "event_field_location"),
+			Old:   old.Location,
+			New:   event.Location,
+		})
+	}
+	if !old.RegistrationEnd.Equal(event.RegistrationEnd) {
+		changes = append(changes, eventChange{
+			Field: h.layout.Text(c, "event_field_registration_end"),
+			Old:   formatTime(old.RegistrationEnd),
+			New:   formatTime(event.RegistrationEnd),
+		})
+	}
+	if !slices.Equal(old.AllowedRoles, event.AllowedRoles) {
+		changes = append(changes, eventChange{
+			Field: h.layout.Text(c, "event_field_allowed_roles"),
+			Old:   formatRoles(old.AllowedRoles),
+			New:   formatRoles(event.AllowedRoles),
+		})
+	}
+
+	return changes
+}
+
+// passLocationChanged checks if the edited event is sent to another pass location
+func passLocationChanged(old, event entity.Event) bool {
+	if old.PassLocationID == nil || event.PassLocationID == nil {
+		return old.PassLocationID != event.PassLocationID
+	}
+
+	return *old.PassLocationID != *event.PassLocationID
+}
+
+// saveEventChanges saves the edited event, syncs its passes and notifies registered participants about the diff
+func (h Handler) saveEventChanges(c tele.Context, old entity.Event, event *entity.Event) error {
+	passLocation, err := h.passService.MatchLocation(context.Background(), event.Location)
+	if err != nil {
+		return err
+	}
+	event.SetPassLocation(passLocation)
+
+	_, err = h.eventService.Update(context.Background(), event)
+	if err != nil {
+		return err
+	}
+
+	if old.PassRequired != event.PassRequired || !old.StartTime.Equal(event.StartTime) || passLocationChanged(old, *event) {
+		if err = h.eventParticipantService.SyncPasses(context.Background(), event); err != nil {
+			h.logger.Errorf("(user: %d) error while sync event passes: %v", c.Sender().ID, err)
+		}
+	}
+
+	changes := h.eventChanges(c, old, *event)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	err = h.notificationService.SendEventUpdate(event.ID,
+		h.layout.Text(c, "event_notification_changes", struct {
+			Name    string
+			Changes []eventChange
+		}{
+			Name:    event.Name,
+			Changes: changes,
+		}),
+		h.layout.Markup(c, "core:hide"),
+	)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while send event update notification: %v", c.Sender().ID, err)
+	}
+
+	return nil
+}
+
+// inputEventField asks for a new value of the event field until it passes the validator.
+// It returns false if the input was cancelled.
+func (h Handler) inputEventField(
+	c tele.Context,
+	eventID, page string,
+	promptKey, errorKey string,
+	validate func(string, map[string]interface{}) bool,
+	params map[string]interface{},
+	textData interface{},
+) (string, bool) {
+	backMarkup := h.layout.Markup(c, "clubOwner:event:settings:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   eventID,
+		Page: page,
+	})
+
+	return h.inputText(c, backMarkup, promptKey, errorKey, validate, params, textData)
+}
+
+// inputText asks the club owner for a text and waits until it passes validation.
+// It returns false if the input was cancelled.
+func (h Handler) inputText(
+	c tele.Context,
+	backMarkup *tele.ReplyMarkup,
+	promptKey, errorKey string,
+	validate func(string, map[string]interface{}) bool,
+	params map[string]interface{},
+	textData interface{},
+) (string, bool) {
+	inputCollector := collector.New()
+	// the previous message is already cleared when the value is not the first one in a row
+	if err := c.Edit(banner.ClubOwner.Caption(h.layout.Text(c, promptKey, textData)), backMarkup); err != nil {
+		_ = inputCollector.Send(c,
+			banner.ClubOwner.Caption(h.layout.Text(c, promptKey, textData)),
+			backMarkup,
+		)
+	} else {
+		inputCollector.Collect(c.Message())
+	}
+
+	for {
+		response, errGet := h.input.Get(context.Background(), c.Sender().ID, 0)
+		if response.Message != nil {
+			inputCollector.Collect(response.Message)
+		}
+		switch {
+		case response.Canceled:
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true, ExcludeLast: true})
+			return "", false
+		case errGet != nil:
+			h.logger.Errorf("(user: %d) error while input (%s): %v", c.Sender().ID, promptKey, errGet)
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, promptKey, textData))),
+				backMarkup,
+			)
+		case response.Message == nil:
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, promptKey, textData))),
+				backMarkup,
+			)
+		case !validate(response.Message.Text, params):
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, errorKey, textData)),
+				backMarkup,
+			)
+		default:
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
+			return response.Message.Text, true
+		}
+	}
+}
+
+// editEventField runs the input of a single event field and saves the result
+func (h Handler) editEventField(
+	c tele.Context,
+	promptKey, errorKey string,
+	validate func(string, map[string]interface{}) bool,
+	apply func(event *entity.Event, value string),
+) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) edit event (event_id=%s, field=%s)", c.Sender().ID, eventID, promptKey)
+
+	backMarkup := h.layout.Markup(c, "clubOwner:event:settings:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   eventID,
+		Page: page,
+	})
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+	old := *event
+
+	startTime := event.StartTime.In(location.Location()).Format(eventTimeLayout)
+	value, ok := h.inputEventField(c, eventID, page, promptKey, errorKey, validate,
+		map[string]interface{}{
+			"startTime": startTime,
+		},
+		struct {
+			MaxRegisteredEndTime string
+		}{
+			MaxRegisteredEndTime: startTime,
+		},
+	)
+	if !ok {
+		return nil
+	}
+
+	apply(event, value)
+	if err = h.saveEventChanges(c, old, event); err != nil {
+		h.logger.Errorf("(user: %d) error while update event: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	return c.Send(
+		banner.ClubOwner.Caption(h.layout.Text(c, "event_changed")),
+		backMarkup,
+	)
+}
+
+// editEventStartTime moves the event, end time and registration end are moved by the same offset
+func (h Handler) editEventStartTime(c tele.Context) error {
+	return h.editEventField(c, "input_edit_event_start_time", "invalid_event_start_time", validator.EventStartTime,
+		func(event *entity.Event, value string) {
+			startTime, _ := time.ParseInLocation(eventTimeLayout, value, location.Location())
+			offset := startTime.Sub(event.StartTime)
+
+			event.StartTime = startTime.UTC()
+			event.RegistrationEnd = event.RegistrationEnd.Add(offset).UTC()
+			if !event.EndTime.IsZero() {
+				event.EndTime = event.EndTime.Add(offset).UTC()
+			}
+		},
+	)
+}
+
+func (h Handler) editEventEndTime(c tele.Context) error {
+	return h.editEventField(c, "input_event_end_time", "invalid_event_end_time", validator.EventEndTime,
+		func(event *entity.Event, value string) {
+			endTime, _ := time.ParseInLocation(eventTimeLayout, value, location.Location())
+			event.EndTime = endTime.UTC()
+		},
+	)
+}
+
+func (h Handler) editEventLocation(c tele.Context) error {
+	return h.editEventField(c, "input_event_location", "invalid_event_location", validator.EventLocation,
+		func(event *entity.Event, value string) {
+			event.Location = value
+		},
+	)
+}
+
+func (h Handler) editEventRegistrationEnd(c tele.Context) error {
+	return h.editEventField(c, "input_event_registered_end_time", "invalid_event_registered_end_time", validator.EventRegisteredEndTime,
+		func(event *entity.Event, value string) {
+			registrationEnd, _ := time.ParseInLocation(eventTimeLayout, value, location.Location())
+			event.RegistrationEnd = registrationEnd.UTC()
+		},
+	)
+}
+
+// editEventRoles opens the allowed roles editor, changes are kept in a draft until saved
+func (h Handler) editEventRoles(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) edit event allowed roles (event_id=%s)", c.Sender().ID, eventID)
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:event:settings:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   eventID,
+				Page: page,
+			}),
+		)
+	}
+
+	h.eventsStorage.SetDraft(c.Sender().ID, *event, 0)
+	return h.renderEventRoles(c, *event, page)
+}
+
+func (h Handler) toggleEventRole(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	role := data[0]
+	page := data[1]
+
+	event, err := h.eventsStorage.GetDraft(c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event draft: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	if i := slices.Index(event.AllowedRoles, role); i != -1 {
+		event.AllowedRoles = slices.Delete(event.AllowedRoles, i, i+1)
+	} else {
+		event.AllowedRoles = append(event.AllowedRoles, role)
+	}
+
+	h.eventsStorage.SetDraft(c.Sender().ID, event, 0)
+	return h.renderEventRoles(c, event, page)
+}
+
+func (h Handler) saveEventRoles(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) save event allowed roles (event_id=%s)", c.Sender().ID, eventID)
+
+	backMarkup := h.layout.Markup(c, "clubOwner:event:settings:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   eventID,
+		Page: page,
+	})
+
+	draft, err := h.eventsStorage.GetDraft(c.Sender().ID)
+	if err != nil || draft.ID != eventID {
+		if err == nil {
+			err = errorz.ErrInvalidCallbackData
+		}
+		h.logger.Errorf("(user: %d) error while get event draft: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	if len(draft.AllowedRoles) == 0 {
+		return c.Respond(&tele.CallbackResponse{
+			Text:      h.layout.Text(c, "event_without_allowed_roles"),
+			ShowAlert: true,
+		})
+	}
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+	old := *event
+
+	event.AllowedRoles = draft.AllowedRoles
+	if err = h.saveEventChanges(c, old, event); err != nil {
+		h.logger.Errorf("(user: %d) error while update event allowed roles: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+	h.eventsStorage.ClearDraft(c.Sender().ID)
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "event_changed")),
+		backMarkup,
+	)
+}
+
+func (h Handler) renderEventRoles(c tele.Context, event entity.Event, page string) error {
+	club, err := h.clubService.Get(context.Background(), event.ClubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:event:settings:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   event.ID,
+				Page: page,
+			}),
+		)
+	}
+
+	markup := h.layout.Markup(c, "clubOwner:event:settings:edit_roles", struct {
+		ID   string
+		Page string
+	}{
+		ID:   event.ID,
+		Page: page,
+	})
+
+	var row []tele.InlineButton
+	for _, role := range club.AllowedRoles {
+		row = append(row, *h.layout.Button(c, "clubOwner:event:settings:edit_roles:role", struct {
+			Role     string
+			Page     string
+			RoleName string
+			Allowed  bool
+		}{
+			Role:     role,
+			Page:     page,
+			RoleName: h.layout.Text(c, role),
+			Allowed:  slices.Contains(event.AllowedRoles, role),
+		}).Inline())
+	}
+
+	markup.InlineKeyboard = append(
+		[][]tele.InlineButton{row},
+		markup.InlineKeyboard...,
+	)
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "input_edit_event_roles")),
+		markup,
+	)
+}
@@ -0,0 +1,203 @@
+package clubowner
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/validator"
+)
+
+// eventGuestView is a guest of the event shown to the club owner
+type eventGuestView struct {
+	FIO   string
+	Phone string
+	Sent  bool
+}
+
+// eventGuests shows the guests of the event without a bot account
+func (h Handler) eventGuests(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) edit event guests (event_id=%s)", c.Sender().ID, eventID)
+
+	caption, markup := h.eventGuestsMenu(c, eventID, page)
+	return c.Edit(caption, markup)
+}
+
+// eventGuestsMenu returns the guests list with the buttons to remove the guests whose passes are not sent yet
+func (h Handler) eventGuestsMenu(c tele.Context, eventID, page string) (interface{}, *tele.ReplyMarkup) {
+	backMarkup := h.layout.Markup(c, "clubOwner:event:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   eventID,
+		Page: page,
+	})
+
+	guests, err := h.passService.GetGuests(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event guests: %v", c.Sender().ID, err)
+		return banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())), backMarkup
+	}
+
+	markup := c.Bot().NewMarkup()
+	views := make([]eventGuestView, 0, len(guests))
+	var rows []tele.Row
+	for _, guest := range guests {
+		if guest.Status == entity.PassStatusCancelled {
+			continue
+		}
+
+		views = append(views, eventGuestView{
+			FIO:   guest.GuestFIO,
+			Phone: guest.GuestPhone,
+			Sent:  guest.Status == entity.PassStatusSent,
+		})
+		if guest.Status == entity.PassStatusPending {
+			rows = append(rows, markup.Row(*h.layout.Button(c, "clubOwner:event:guests:remove", struct {
+				ID   string
+				Page string
+				FIO  string
+			}{
+				ID:   guest.ID,
+				Page: page,
+				FIO:  guest.GuestFIO,
+			})))
+		}
+	}
+	rows = append(rows,
+		markup.Row(*h.layout.Button(c, "clubOwner:event:guests:add", struct {
+			ID   string
+			Page string
+		}{
+			ID:   eventID,
+			Page: page,
+		})),
+		markup.Row(*h.layout.Button(c, "clubOwner:event:back", struct {
+			ID   string
+			Page string
+		}{
+			ID:   eventID,
+			Page: page,
+		})),
+	)
+	markup.Inline(rows...)
+
+	return banner.ClubOwner.Caption(h.layout.Text(c, "event_guests_text", views)), markup
+}
+
+// addEventGuest asks for the full name and the phone of the guest and adds the guest pass on behalf of the club
+func (h Handler) addEventGuest(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) add event guest (event_id=%s)", c.Sender().ID, eventID)
+
+	backMarkup := h.layout.Markup(c, "clubOwner:event:guests:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   eventID,
+		Page: page,
+	})
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	fio, ok := h.inputText(c, backMarkup, "input_guest_fio", "invalid_guest_fio", validator.GuestFIO, nil, nil)
+	if !ok {
+		return nil
+	}
+
+	phone, ok := h.inputText(c, backMarkup, "input_guest_phone", "invalid_guest_phone", validator.GuestPhone, nil, nil)
+	if !ok {
+		return nil
+	}
+	if strings.TrimSpace(phone) == "-" {
+		phone = ""
+	}
+
+	_, err = h.passService.AddGuest(context.Background(), event, fio, phone, entity.PassRequesterTypeClub, event.ClubID)
+	if err != nil {
+		switch {
+		case errors.Is(err, errorz.ErrGuestAlreadyAdded):
+			return c.Send(
+				banner.ClubOwner.Caption(h.layout.Text(c, "guest_already_added")),
+				backMarkup,
+			)
+		case errors.Is(err, errorz.ErrPassNotRequired),
+			errors.Is(err, errorz.ErrEventCancelled),
+			errors.Is(err, errorz.ErrRegistrationEnded):
+			return c.Send(
+				banner.ClubOwner.Caption(h.layout.Text(c, "guest_not_allowed")),
+				backMarkup,
+			)
+		}
+
+		h.logger.Errorf("(user: %d) error while add event guest: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	h.logger.Infof("(user: %d) event guest added (event_id=%s)", c.Sender().ID, eventID)
+	caption, markup := h.eventGuestsMenu(c, eventID, page)
+	return c.Send(caption, markup)
+}
+
+// removeEventGuest cancels the guest pass, the guest can't be removed after the pass is sent to the security
+func (h Handler) removeEventGuest(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	passID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) remove event guest (pass_id=%s)", c.Sender().ID, passID)
+
+	pass, err := h.passService.RemoveGuest(context.Background(), passID)
+	if err != nil {
+		switch {
+		case errors.Is(err, errorz.ErrPassAlreadySent):
+			return c.Respond(&tele.CallbackResponse{
+				Text:      h.layout.Text(c, "guest_pass_already_sent"),
+				ShowAlert: true,
+			})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return errorz.ErrInvalidCallbackData
+		}
+
+		h.logger.Errorf("(user: %d) error while remove event guest: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+
+	caption, markup := h.eventGuestsMenu(c, pass.EventID, page)
+	return c.Edit(caption, markup)
+}
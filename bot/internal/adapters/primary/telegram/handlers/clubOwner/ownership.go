@@ -0,0 +1,409 @@
+package clubowner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nlypage/intele/collector"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/valueobject"
+)
+
+// clubTransferTTL is how long the recipient can accept the club ownership transfer
+const clubTransferTTL = 24 * time.Hour
+
+// leaveClub asks to confirm leaving the club staff
+func (h Handler) leaveClub(c tele.Context) error {
+	clubID := c.Callback().Data
+	if clubID == "" {
+		return errorz.ErrInvalidCallbackData
+	}
+	h.logger.Infof("(user: %d) leave club (club_id=%s)", c.Sender().ID, clubID)
+
+	club, err := h.clubService.Get(context.Background(), clubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "leave_club_text", club)),
+		h.layout.Markup(c, "clubOwner:club:leave", club),
+	)
+}
+
+// acceptClubLeave removes the sender from the club staff unless they are the last co-owner
+func (h Handler) acceptClubLeave(c tele.Context) error {
+	clubID := c.Callback().Data
+	if clubID == "" {
+		return errorz.ErrInvalidCallbackData
+	}
+	h.logger.Infof("(user: %d) accept club leave (club_id=%s)", c.Sender().ID, clubID)
+
+	club, err := h.clubService.Get(context.Background(), clubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+
+	role := h.clubRole(c)
+	err = h.clubOwnerService.Leave(context.Background(), clubID, c.Sender().ID)
+	if err != nil {
+		if errors.Is(err, errorz.ErrLastClubOwner) {
+			return c.Edit(
+				banner.ClubOwner.Caption(h.layout.Text(c, "last_club_owner")),
+				h.layout.Markup(c, "clubOwner:club:back", club),
+			)
+		}
+
+		h.logger.Errorf("(user: %d) error while leave club (club_id=%s): %v", c.Sender().ID, clubID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+
+	h.logger.Infof("(user: %d) club left (club_id=%s)", c.Sender().ID, clubID)
+	h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionClubOwnerLeft, entity.AuditTargetClub, clubID, struct {
+		UserID int64
+		Role   valueobject.ClubOwnerRole
+	}{
+		UserID: c.Sender().ID,
+		Role:   role,
+	}, nil)
+
+	user, err := h.userService.Get(context.Background(), c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get user: %v", c.Sender().ID, err)
+	} else {
+		h.notifyClubStaff(c, club, h.layout.Text(c, "club_staff_left_notification", struct {
+			FIO  string
+			Club string
+		}{
+			FIO:  user.FIO.String(),
+			Club: club.Name,
+		}))
+	}
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "club_left", club)),
+		h.layout.Markup(c, "mainMenu:back"),
+	)
+}
+
+// transferClub asks for the recipient of the club ownership and sends them the transfer offer
+func (h Handler) transferClub(c tele.Context) error {
+	if c.Callback().Data == "" {
+		return errorz.ErrInvalidCallbackData
+	}
+	clubID := c.Callback().Data
+
+	inputCollector := collector.New()
+	inputCollector.Collect(c.Message())
+	club, err := h.clubService.Get(context.Background(), clubID)
+	if err != nil {
+		_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
+		h.logger.Errorf("(user: %d) error while get club: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+
+	h.logger.Infof("(user: %d) transfer club (club_id=%s)", c.Sender().ID, clubID)
+	_ = c.Edit(
+		banner.Menu.Caption(h.layout.Text(c, "input_transfer_user_id")),
+		h.layout.Markup(c, "clubOwner:club:settings:back", club),
+	)
+
+	var (
+		user *entity.User
+		done bool
+	)
+	for {
+		response, errGet := h.input.Get(context.Background(), c.Sender().ID, 0)
+		if response.Message != nil {
+			inputCollector.Collect(response.Message)
+		}
+		switch {
+		case response.Canceled:
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true, ExcludeLast: true})
+			return nil
+		case errGet != nil:
+			_ = inputCollector.Send(c,
+				banner.Menu.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "input_transfer_user_id"))),
+				h.layout.Markup(c, "clubOwner:club:settings:back", club),
+			)
+		case response.Message == nil:
+			_ = inputCollector.Send(c,
+				banner.Menu.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "input_transfer_user_id"))),
+				h.layout.Markup(c, "clubOwner:club:settings:back", club),
+			)
+		default:
+			userID, err := strconv.ParseInt(response.Message.Text, 10, 64)
+			if err != nil {
+				_ = inputCollector.Send(c,
+					banner.Menu.Caption(h.layout.Text(c, "input_transfer_user_id")),
+					h.layout.Markup(c, "clubOwner:club:settings:back", club),
+				)
+				break
+			}
+
+			if userID == c.Sender().ID {
+				_ = inputCollector.Send(c,
+					banner.Menu.Caption(h.layout.Text(c, "club_transfer_self")),
+					h.layout.Markup(c, "clubOwner:club:settings:back", club),
+				)
+				break
+			}
+
+			user, err = h.userService.Get(context.Background(), userID)
+			if err != nil {
+				_ = inputCollector.Send(c,
+					banner.Menu.Caption(h.layout.Text(c, "user_not_found", struct {
+						ID   int64
+						Text string
+					}{
+						ID:   userID,
+						Text: h.layout.Text(c, "input_transfer_user_id"),
+					})),
+					h.layout.Markup(c, "clubOwner:club:settings:back", club),
+				)
+				break
+			}
+			done = true
+		}
+		if done {
+			break
+		}
+	}
+
+	sender, err := h.userService.Get(context.Background(), c.Sender().ID)
+	if err == nil {
+		var callbackID string
+		callbackID, err = h.callbacksStorage.Set(fmt.Sprintf("%s %d %d", club.ID, c.Sender().ID, user.ID), clubTransferTTL)
+		if err == nil {
+			err = h.outboxService.Enqueue(context.Background(), user.ID,
+				h.layout.Text(c, "club_transfer_offer", struct {
+					FIO  string
+					Club string
+				}{
+					FIO:  sender.FIO.String(),
+					Club: club.Name,
+				}),
+				h.layout.Markup(c, "clubOwner:transfer", struct {
+					ID string
+				}{
+					ID: callbackID,
+				}),
+			)
+		}
+	}
+	if err != nil {
+		_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
+		h.logger.Errorf(
+			"(user: %d) error while send club transfer offer (club_id=%s, user_id=%d): %v",
+			c.Sender().ID,
+			clubID,
+			user.ID,
+			err,
+		)
+		return c.Send(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:club:settings:back", club),
+		)
+	}
+
+	h.logger.Infof(
+		"(user: %d) club transfer offered (club_id=%s, user_id=%d)",
+		c.Sender().ID,
+		clubID,
+		user.ID,
+	)
+
+	_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
+	return c.Send(
+		banner.Menu.Caption(h.layout.Text(c, "club_transfer_requested", struct {
+			FIO  string
+			Club string
+		}{
+			FIO:  user.FIO.String(),
+			Club: club.Name,
+		})),
+		h.layout.Markup(c, "clubOwner:club:settings:back", club),
+	)
+}
+
+// acceptClubTransfer makes the recipient a co-owner of the club and removes the former owner from the staff
+func (h Handler) acceptClubTransfer(c tele.Context) error {
+	clubID, fromUserID, toUserID, err := h.clubTransferByCallback(c.Callback().Data)
+	if err != nil || toUserID != c.Sender().ID {
+		h.logger.Infof("(user: %d) club transfer expired: %v", c.Sender().ID, err)
+		return c.Edit(
+			h.layout.Text(c, "club_transfer_expired"),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+	h.logger.Infof("(user: %d) accept club transfer (club_id=%s, from_user_id=%d)", c.Sender().ID, clubID, fromUserID)
+
+	// the offer is void once its sender is no longer a co-owner
+	role, err := h.clubOwnerService.GetRole(context.Background(), clubID, fromUserID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		h.logger.Errorf("(user: %d) error while get club owner role (club_id=%s): %v", c.Sender().ID, clubID, err)
+		return c.Edit(
+			h.layout.Text(c, "technical_issues", err.Error()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+	if role != valueobject.ClubCoOwner {
+		h.callbacksStorage.Delete(c.Callback().Data)
+		return c.Edit(
+			h.layout.Text(c, "club_transfer_expired"),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	club, err := h.clubService.Get(context.Background(), clubID)
+	if err == nil {
+		err = h.clubOwnerService.Transfer(context.Background(), clubID, fromUserID, toUserID)
+	}
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while transfer club (club_id=%s, from_user_id=%d): %v", c.Sender().ID, clubID, fromUserID, err)
+		return c.Edit(
+			h.layout.Text(c, "technical_issues", err.Error()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+	h.callbacksStorage.Delete(c.Callback().Data)
+
+	h.logger.Infof("(user: %d) club transferred (club_id=%s, from_user_id=%d)", c.Sender().ID, clubID, fromUserID)
+	h.auditService.Record(context.Background(), fromUserID, entity.AuditActionClubTransferred, entity.AuditTargetClub, clubID, struct {
+		UserID int64
+	}{
+		UserID: fromUserID,
+	}, struct {
+		UserID int64
+	}{
+		UserID: toUserID,
+	})
+
+	from, errFrom := h.userService.Get(context.Background(), fromUserID)
+	to, errTo := h.userService.Get(context.Background(), toUserID)
+	if errFrom != nil || errTo != nil {
+		h.logger.Errorf("(user: %d) error while get club transfer users: %v", c.Sender().ID, errors.Join(errFrom, errTo))
+	} else {
+		notification := h.layout.Text(c, "club_transferred_notification", struct {
+			From string
+			To   string
+			Club string
+		}{
+			From: from.FIO.String(),
+			To:   to.FIO.String(),
+			Club: club.Name,
+		})
+		h.notifyClubStaff(c, club, notification)
+
+		errSend := h.outboxService.Enqueue(context.Background(), fromUserID, notification, h.layout.Markup(c, "core:hide"))
+		if errSend != nil {
+			h.logger.Errorf("(user: %d) error while send club transfer notification to former owner: %v", c.Sender().ID, errSend)
+		}
+	}
+
+	return c.Edit(
+		h.layout.Text(c, "club_transfer_accepted", club),
+		h.layout.Markup(c, "core:hide"),
+	)
+}
+
+// declineClubTransfer rejects the club ownership transfer and notifies its sender
+func (h Handler) declineClubTransfer(c tele.Context) error {
+	clubID, fromUserID, toUserID, err := h.clubTransferByCallback(c.Callback().Data)
+	if err != nil || toUserID != c.Sender().ID {
+		h.logger.Infof("(user: %d) club transfer expired: %v", c.Sender().ID, err)
+		return c.Edit(
+			h.layout.Text(c, "club_transfer_expired"),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+	h.callbacksStorage.Delete(c.Callback().Data)
+	h.logger.Infof("(user: %d) decline club transfer (club_id=%s, from_user_id=%d)", c.Sender().ID, clubID, fromUserID)
+
+	club, err := h.clubService.Get(context.Background(), clubID)
+	if err == nil {
+		var user *entity.User
+		user, err = h.userService.Get(context.Background(), c.Sender().ID)
+		if err == nil {
+			err = h.outboxService.Enqueue(context.Background(), fromUserID,
+				h.layout.Text(c, "club_transfer_declined", struct {
+					FIO  string
+					Club string
+				}{
+					FIO:  user.FIO.String(),
+					Club: club.Name,
+				}),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+	}
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while send club transfer decline (club_id=%s): %v", c.Sender().ID, clubID, err)
+	}
+
+	return c.Edit(
+		h.layout.Text(c, "club_transfer_declined_text"),
+		h.layout.Markup(c, "core:hide"),
+	)
+}
+
+// clubTransferByCallback returns the club, the sender and the recipient of the transfer offer stored by the callback ID
+func (h Handler) clubTransferByCallback(callbackID string) (string, int64, int64, error) {
+	callbackData, err := h.callbacksStorage.Get(callbackID)
+	if err != nil {
+		return "", 0, 0, err
+	}
+
+	data := strings.Split(callbackData, " ")
+	if len(data) != 3 {
+		return "", 0, 0, errorz.ErrInvalidCallbackData
+	}
+
+	fromUserID, err := strconv.ParseInt(data[1], 10, 64)
+	if err != nil {
+		return "", 0, 0, errorz.ErrInvalidCallbackData
+	}
+	toUserID, err := strconv.ParseInt(data[2], 10, 64)
+	if err != nil {
+		return "", 0, 0, errorz.ErrInvalidCallbackData
+	}
+
+	return data[0], fromUserID, toUserID, nil
+}
+
+// notifyClubStaff sends the notification about the staff change to the club staff members
+func (h Handler) notifyClubStaff(c tele.Context, club *entity.Club, text string) {
+	err := h.notificationService.SendClubStaffUpdate(club.ID, text, h.layout.Markup(c, "core:hide"))
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while send club staff update (club_id=%s): %v", c.Sender().ID, club.ID, err)
+	}
+}
+
+// OwnershipSetup registers the handlers of the club transfer offers, which are answered by users outside the club staff
+func (h Handler) OwnershipSetup(group *tele.Group) {
+	group.Handle(h.layout.Callback("clubOwner:transfer:accept"), h.acceptClubTransfer)
+	group.Handle(h.layout.Callback("clubOwner:transfer:decline"), h.declineClubTransfer)
+}
@@ -0,0 +1,181 @@
+package clubowner
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/valueobject"
+)
+
+// clubRoleKey is the context key of the club staff role set by can
+const clubRoleKey = "club_owner_role"
+
+// buttonPermissions are the permissions required by the buttons of the club and event menus
+var buttonPermissions = map[string]valueobject.ClubPermission{
+	"clubOwner:club:events":                      valueobject.ClubScanPermission,
+	"clubOwner:club:create_event":                valueobject.ClubEventsPermission,
+	"clubOwner:club:mailing":                     valueobject.ClubManagePermission,
+	"clubOwner:club:scheduled_mailings":          valueobject.ClubManagePermission,
+	"clubOwner:club:settings":                    valueobject.ClubManagePermission,
+	"clubOwner:event:qr":                         valueobject.ClubScanPermission,
+	"clubOwner:event:settings":                   valueobject.ClubEventsPermission,
+	"clubOwner:event:mailing":                    valueobject.ClubEventsPermission,
+	"clubOwner:event:users":                      valueobject.ClubEventsPermission,
+	"clubOwner:event:guests":                     valueobject.ClubEventsPermission,
+	"clubOwner:event:cancel":                     valueobject.ClubManagePermission,
+	"clubOwner:event:settings:apply_to_series":   valueobject.ClubEventsPermission,
+	"clubOwner:event:settings:cancel_occurrence": valueobject.ClubManagePermission,
+}
+
+// clubResolver returns the ID of the club the callback belongs to
+type clubResolver func(c tele.Context) (string, error)
+
+// clubFromCallback takes the club ID from the first field of the callback data
+func (h Handler) clubFromCallback(c tele.Context) (string, error) {
+	data := strings.Fields(c.Callback().Data)
+	if len(data) == 0 {
+		return "", errorz.ErrInvalidCallbackData
+	}
+
+	return data[0], nil
+}
+
+// clubOfEvent finds the club of the event whose ID is the first field of the callback data
+func (h Handler) clubOfEvent(c tele.Context) (string, error) {
+	data := strings.Fields(c.Callback().Data)
+	if len(data) == 0 {
+		return "", errorz.ErrInvalidCallbackData
+	}
+
+	event, err := h.eventService.Get(context.Background(), data[0])
+	if err != nil {
+		return "", err
+	}
+
+	return event.ClubID, nil
+}
+
+// clubOfMailing finds the club of the mailing whose ID is the callback data
+func (h Handler) clubOfMailing(c tele.Context) (string, error) {
+	if c.Callback().Data == "" {
+		return "", errorz.ErrInvalidCallbackData
+	}
+
+	mailing, err := h.mailingService.Get(context.Background(), c.Callback().Data)
+	if err != nil {
+		return "", err
+	}
+
+	return mailing.ClubID, nil
+}
+
+// clubOfGuest finds the club of the event of the guest pass whose ID is the first field of the callback data
+func (h Handler) clubOfGuest(c tele.Context) (string, error) {
+	data := strings.Fields(c.Callback().Data)
+	if len(data) == 0 {
+		return "", errorz.ErrInvalidCallbackData
+	}
+
+	pass, err := h.passService.GetPass(context.Background(), data[0])
+	if err != nil {
+		return "", err
+	}
+
+	event, err := h.eventService.Get(context.Background(), pass.EventID)
+	if err != nil {
+		return "", err
+	}
+
+	return event.ClubID, nil
+}
+
+// clubOfDraft finds the club of the event edited by the user
+func (h Handler) clubOfDraft(c tele.Context) (string, error) {
+	event, err := h.eventsStorage.GetDraft(c.Sender().ID)
+	if err != nil {
+		return "", err
+	}
+
+	return event.ClubID, nil
+}
+
+// can middleware lets through the club staff members whose role grants the permission
+// and stores their role by clubRoleKey
+func (h Handler) can(resolve clubResolver, permission valueobject.ClubPermission) tele.MiddlewareFunc {
+	return func(next tele.HandlerFunc) tele.HandlerFunc {
+		return func(c tele.Context) error {
+			clubID, err := resolve(c)
+			if err != nil {
+				if errors.Is(err, errorz.ErrInvalidCallbackData) {
+					return err
+				}
+
+				h.logger.Errorf("(user: %d) error while get club of callback: %v", c.Sender().ID, err)
+				return c.Respond(&tele.CallbackResponse{
+					Text:      h.layout.Text(c, "technical_issues", err.Error()),
+					ShowAlert: true,
+				})
+			}
+
+			role, err := h.clubOwnerService.GetRole(context.Background(), clubID, c.Sender().ID)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				h.logger.Errorf("(user: %d) error while get club owner role (club_id=%s): %v", c.Sender().ID, clubID, err)
+				return c.Respond(&tele.CallbackResponse{
+					Text:      h.layout.Text(c, "technical_issues", err.Error()),
+					ShowAlert: true,
+				})
+			}
+
+			if role.Can(permission) {
+				c.Set(clubRoleKey, role)
+				return next(c)
+			}
+
+			h.logger.Infof("(user: %d) club permission denied (club_id=%s, role=%s, permission=%s)", c.Sender().ID, clubID, role, permission)
+			return c.Respond(&tele.CallbackResponse{
+				Text:      h.layout.Text(c, "club_permission_denied"),
+				ShowAlert: true,
+			})
+		}
+	}
+}
+
+// clubRole returns the club staff role of the sender stored by can
+func (h Handler) clubRole(c tele.Context) valueobject.ClubOwnerRole {
+	role, _ := c.Get(clubRoleKey).(valueobject.ClubOwnerRole)
+	return role
+}
+
+// withoutForbidden removes the buttons the role has no permission for
+func (h Handler) withoutForbidden(markup *tele.ReplyMarkup, role valueobject.ClubOwnerRole) *tele.ReplyMarkup {
+	hidden := make(map[string]struct{}, len(buttonPermissions))
+	for callback, permission := range buttonPermissions {
+		if !role.Can(permission) {
+			hidden[h.layout.Callback(callback).CallbackUnique()] = struct{}{}
+		}
+	}
+	if len(hidden) == 0 {
+		return markup
+	}
+
+	keyboard := make([][]tele.InlineButton, 0, len(markup.InlineKeyboard))
+	for _, row := range markup.InlineKeyboard {
+		buttons := make([]tele.InlineButton, 0, len(row))
+		for _, button := range row {
+			if _, ok := hidden[button.CallbackUnique()]; !ok {
+				buttons = append(buttons, button)
+			}
+		}
+		if len(buttons) > 0 {
+			keyboard = append(keyboard, buttons)
+		}
+	}
+	markup.InlineKeyboard = keyboard
+
+	return markup
+}
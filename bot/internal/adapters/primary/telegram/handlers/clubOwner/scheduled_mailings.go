@@ -0,0 +1,261 @@
+package clubowner
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	tele "gopkg.in/telebot.v3"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/validator"
+)
+
+// scheduledMailings shows mailings of the club waiting to be sent
+func (h Handler) scheduledMailings(c tele.Context) error {
+	if c.Callback().Data == "" {
+		return errorz.ErrInvalidCallbackData
+	}
+	clubID := c.Callback().Data
+	h.logger.Infof("(user: %d) edit scheduled mailings list (club_id=%s)", c.Sender().ID, clubID)
+
+	backMarkup := h.layout.Markup(c, "clubOwner:club:back", struct {
+		ID string
+	}{
+		ID: clubID,
+	})
+
+	mailings, err := h.mailingService.GetScheduledByClubID(context.Background(), clubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get scheduled mailings: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	markup := c.Bot().NewMarkup()
+	var rows []tele.Row
+	for _, mailing := range mailings {
+		rows = append(rows, markup.Row(*h.layout.Button(c, "clubOwner:scheduled_mailings:mailing", struct {
+			ID          string
+			ScheduledAt string
+			Audience    string
+		}{
+			ID:          mailing.ID,
+			ScheduledAt: mailing.ScheduledAt.In(location.Location()).Format(eventTimeLayout),
+			Audience:    h.layout.Text(c, "mailing_audience_"+string(mailing.Audience)),
+		})))
+	}
+	rows = append(rows, markup.Row(*h.layout.Button(c, "clubOwner:club:back", struct {
+		ID string
+	}{
+		ID: clubID,
+	})))
+	markup.Inline(rows...)
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "scheduled_mailings_text", len(mailings))),
+		markup,
+	)
+}
+
+// scheduledMailing shows the scheduled mailing with the edit menu
+func (h Handler) scheduledMailing(c tele.Context) error {
+	if c.Callback().Data == "" {
+		return errorz.ErrInvalidCallbackData
+	}
+	mailingID := c.Callback().Data
+	h.logger.Infof("(user: %d) edit scheduled mailing (mailing_id=%s)", c.Sender().ID, mailingID)
+
+	caption, markup := h.scheduledMailingMenu(c, mailingID)
+	return c.Edit(caption, markup)
+}
+
+// scheduledMailingMenu returns the scheduled mailing text and the edit menu
+func (h Handler) scheduledMailingMenu(c tele.Context, mailingID string) (interface{}, *tele.ReplyMarkup) {
+	mailing, err := h.mailingService.Get(context.Background(), mailingID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get mailing: %v", c.Sender().ID, err)
+		return banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "mainMenu:back")
+	}
+
+	backMarkup := h.layout.Markup(c, "clubOwner:scheduled_mailing:back", struct {
+		ClubID string
+	}{
+		ClubID: mailing.ClubID,
+	})
+	if !mailing.IsScheduled() {
+		return banner.ClubOwner.Caption(h.layout.Text(c, "mailing_not_scheduled")), backMarkup
+	}
+
+	return banner.ClubOwner.Caption(h.layout.Text(c, "scheduled_mailing_text", struct {
+			ScheduledAt string
+			Audience    string
+			IsMedia     bool
+			Text        string
+		}{
+			ScheduledAt: mailing.ScheduledAt.In(location.Location()).Format(eventTimeLayout),
+			Audience:    h.layout.Text(c, "mailing_audience_"+string(mailing.Audience)),
+			IsMedia:     mailing.Kind != entity.MessageText,
+			Text:        mailing.Text,
+		})),
+		h.layout.Markup(c, "clubOwner:scheduled_mailing", struct {
+			ID     string
+			ClubID string
+		}{
+			ID:     mailing.ID,
+			ClubID: mailing.ClubID,
+		})
+}
+
+func (h Handler) editScheduledMailingTime(c tele.Context) error {
+	if c.Callback().Data == "" {
+		return errorz.ErrInvalidCallbackData
+	}
+	mailingID := c.Callback().Data
+	h.logger.Infof("(user: %d) edit scheduled mailing time (mailing_id=%s)", c.Sender().ID, mailingID)
+
+	backMarkup := h.layout.Markup(c, "clubOwner:scheduled_mailing:edit:back", struct {
+		ID string
+	}{
+		ID: mailingID,
+	})
+
+	value, ok := h.inputText(c, backMarkup, "input_mailing_time", "invalid_mailing_time", validator.MailingScheduledAt, nil, nil)
+	if !ok {
+		return nil
+	}
+
+	scheduledAt, _ := time.ParseInLocation(eventTimeLayout, value, location.Location())
+	if _, err := h.mailingService.Reschedule(context.Background(), mailingID, scheduledAt); err != nil {
+		return h.scheduledMailingError(c, err, backMarkup)
+	}
+
+	caption, markup := h.scheduledMailingMenu(c, mailingID)
+	return c.Send(caption, markup)
+}
+
+func (h Handler) editScheduledMailingText(c tele.Context) error {
+	if c.Callback().Data == "" {
+		return errorz.ErrInvalidCallbackData
+	}
+	mailingID := c.Callback().Data
+	h.logger.Infof("(user: %d) edit scheduled mailing text (mailing_id=%s)", c.Sender().ID, mailingID)
+
+	backMarkup := h.layout.Markup(c, "clubOwner:scheduled_mailing:edit:back", struct {
+		ID string
+	}{
+		ID: mailingID,
+	})
+
+	mailing, err := h.mailingService.Get(context.Background(), mailingID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get mailing: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+
+	value, ok := h.inputText(c, backMarkup, "input_scheduled_mailing_text", "invalid_mailing_text", validator.MailingText, nil, nil)
+	if !ok {
+		return nil
+	}
+
+	text, err := h.mailingText(c, mailing, value)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while render mailing text: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	if _, err = h.mailingService.UpdateText(context.Background(), mailingID, text); err != nil {
+		return h.scheduledMailingError(c, err, backMarkup)
+	}
+
+	caption, markup := h.scheduledMailingMenu(c, mailingID)
+	return c.Send(caption, markup)
+}
+
+func (h Handler) cancelScheduledMailing(c tele.Context) error {
+	if c.Callback().Data == "" {
+		return errorz.ErrInvalidCallbackData
+	}
+	mailingID := c.Callback().Data
+	h.logger.Infof("(user: %d) cancel scheduled mailing (mailing_id=%s)", c.Sender().ID, mailingID)
+
+	mailing, err := h.mailingService.CancelScheduled(context.Background(), mailingID)
+	if err != nil {
+		return h.scheduledMailingError(c, err, h.layout.Markup(c, "clubOwner:scheduled_mailing:edit:back", struct {
+			ID string
+		}{
+			ID: mailingID,
+		}))
+	}
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "mailing_canceled")),
+		h.layout.Markup(c, "clubOwner:scheduled_mailing:back", struct {
+			ClubID string
+		}{
+			ClubID: mailing.ClubID,
+		}),
+	)
+}
+
+// mailingText renders the new text of the mailing with the template it was created with
+func (h Handler) mailingText(c tele.Context, mailing *entity.Mailing, text string) (string, error) {
+	club, err := h.clubService.Get(context.Background(), mailing.ClubID)
+	if err != nil {
+		return "", err
+	}
+
+	if mailing.EventID == nil {
+		return h.layout.Text(c, "club_mailing", struct {
+			ClubName string
+			Text     string
+		}{
+			ClubName: club.Name,
+			Text:     text,
+		}), nil
+	}
+
+	event, err := h.eventService.Get(context.Background(), *mailing.EventID)
+	if err != nil {
+		return "", err
+	}
+
+	return h.layout.Text(c, "event_mailing", struct {
+		ClubName  string
+		EventName string
+		Text      string
+	}{
+		ClubName:  club.Name,
+		EventName: event.Name,
+		Text:      text,
+	}), nil
+}
+
+// scheduledMailingError shows the error of the scheduled mailing change,
+// the mailing may have already been sent or cancelled in the meantime
+func (h Handler) scheduledMailingError(c tele.Context, err error, backMarkup *tele.ReplyMarkup) error {
+	if errors.Is(err, errorz.ErrMailingNotScheduled) {
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "mailing_not_scheduled")),
+			backMarkup,
+		)
+	}
+
+	h.logger.Errorf("(user: %d) error while update scheduled mailing: %v", c.Sender().ID, err)
+	return c.Send(
+		banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+		backMarkup,
+	)
+}
@@ -0,0 +1,313 @@
+package clubowner
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/valueobject"
+)
+
+// staffRoleButtons are the buttons of the member menu setting the club staff roles
+var staffRoleButtons = map[string]valueobject.ClubOwnerRole{
+	"clubOwner:club:settings:staff:co_owner": valueobject.ClubCoOwner,
+	"clubOwner:club:settings:staff:editor":   valueobject.ClubEditor,
+	"clubOwner:club:settings:staff:scanner":  valueobject.ClubScanner,
+}
+
+// staff lists the club staff members
+func (h Handler) staff(c tele.Context) error {
+	clubID := c.Callback().Data
+	if clubID == "" {
+		return errorz.ErrInvalidCallbackData
+	}
+	h.logger.Infof("(user: %d) edit club staff (club_id=%s)", c.Sender().ID, clubID)
+
+	club, err := h.clubService.Get(context.Background(), clubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:club:settings:back", struct {
+				ID string
+			}{
+				ID: clubID,
+			}),
+		)
+	}
+
+	owners, err := h.clubOwnerService.GetByClubID(context.Background(), clubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club owners (club_id=%s): %v", c.Sender().ID, clubID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:club:settings:back", struct {
+				ID string
+			}{
+				ID: clubID,
+			}),
+		)
+	}
+
+	staffMarkup := h.layout.Markup(c, "clubOwner:club:settings:staff", struct {
+		ID string
+	}{
+		ID: clubID,
+	})
+	rows := make([][]tele.InlineButton, 0, len(owners)+len(staffMarkup.InlineKeyboard))
+	for _, owner := range owners {
+		rows = append(rows, []tele.InlineButton{*h.layout.Button(c, "clubOwner:club:settings:staff:member", owner).Inline()})
+	}
+	staffMarkup.InlineKeyboard = append(rows, staffMarkup.InlineKeyboard...)
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "club_staff_text", club)),
+		staffMarkup,
+	)
+}
+
+// staffMember shows the club staff member with the buttons to change their role and remove them
+func (h Handler) staffMember(c tele.Context) error {
+	clubID, userID, err := parseStaffCallback(c.Callback().Data)
+	if err != nil {
+		return err
+	}
+	h.logger.Infof("(user: %d) edit club staff member (club_id=%s, user_id=%d)", c.Sender().ID, clubID, userID)
+
+	if userID == c.Sender().ID {
+		return c.Respond(&tele.CallbackResponse{
+			Text:      h.layout.Text(c, "club_staff_self"),
+			ShowAlert: true,
+		})
+	}
+
+	member, err := h.staffMemberByID(clubID, userID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club staff member (club_id=%s, user_id=%d): %v", c.Sender().ID, clubID, userID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:club:settings:staff:back", struct {
+				ClubID string
+			}{
+				ClubID: clubID,
+			}),
+		)
+	}
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "club_staff_member_text", member)),
+		h.layout.Markup(c, "clubOwner:club:settings:staff:member", member),
+	)
+}
+
+// setStaffRole changes the role of the club staff member to the role of the pressed button
+func (h Handler) setStaffRole(c tele.Context) error {
+	clubID, userID, err := parseStaffCallback(c.Callback().Data)
+	if err != nil {
+		return err
+	}
+
+	var role valueobject.ClubOwnerRole
+	for callback, buttonRole := range staffRoleButtons {
+		if h.layout.Callback(callback).CallbackUnique() == "\f"+c.Callback().Unique {
+			role = buttonRole
+		}
+	}
+	if !role.IsValid() {
+		return errorz.ErrInvalidCallbackData
+	}
+	h.logger.Infof("(user: %d) set club staff role (club_id=%s, user_id=%d, role=%s)", c.Sender().ID, clubID, userID, role)
+
+	if userID == c.Sender().ID {
+		return c.Respond(&tele.CallbackResponse{
+			Text:      h.layout.Text(c, "club_staff_self"),
+			ShowAlert: true,
+		})
+	}
+
+	member, err := h.staffMemberByID(clubID, userID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club staff member (club_id=%s, user_id=%d): %v", c.Sender().ID, clubID, userID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:club:settings:staff:back", struct {
+				ClubID string
+			}{
+				ClubID: clubID,
+			}),
+		)
+	}
+	if member.OwnerRole == role {
+		return c.Respond()
+	}
+
+	_, err = h.clubOwnerService.SetRole(context.Background(), clubID, userID, role)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while set club staff role (club_id=%s, user_id=%d): %v", c.Sender().ID, clubID, userID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:club:settings:staff:back", struct {
+				ClubID string
+			}{
+				ClubID: clubID,
+			}),
+		)
+	}
+
+	h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionClubOwnerRoleChanged, entity.AuditTargetClub, clubID, struct {
+		UserID int64
+		Role   valueobject.ClubOwnerRole
+	}{
+		UserID: userID,
+		Role:   member.OwnerRole,
+	}, struct {
+		UserID int64
+		Role   valueobject.ClubOwnerRole
+	}{
+		UserID: userID,
+		Role:   role,
+	})
+
+	member.OwnerRole = role
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "club_staff_member_text", member)),
+		h.layout.Markup(c, "clubOwner:club:settings:staff:member", member),
+	)
+}
+
+// removeStaff asks to confirm the removal of the club staff member
+func (h Handler) removeStaff(c tele.Context) error {
+	clubID, userID, err := parseStaffCallback(c.Callback().Data)
+	if err != nil {
+		return err
+	}
+	h.logger.Infof("(user: %d) remove club staff member (club_id=%s, user_id=%d)", c.Sender().ID, clubID, userID)
+
+	if userID == c.Sender().ID {
+		return c.Respond(&tele.CallbackResponse{
+			Text:      h.layout.Text(c, "club_staff_self"),
+			ShowAlert: true,
+		})
+	}
+
+	member, err := h.staffMemberByID(clubID, userID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club staff member (club_id=%s, user_id=%d): %v", c.Sender().ID, clubID, userID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:club:settings:staff:back", struct {
+				ClubID string
+			}{
+				ClubID: clubID,
+			}),
+		)
+	}
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "remove_club_staff_text", member)),
+		h.layout.Markup(c, "clubOwner:club:settings:staff:remove", member),
+	)
+}
+
+// acceptStaffRemoval removes the member from the club staff
+func (h Handler) acceptStaffRemoval(c tele.Context) error {
+	clubID, userID, err := parseStaffCallback(c.Callback().Data)
+	if err != nil {
+		return err
+	}
+	h.logger.Infof("(user: %d) accept club staff member removal (club_id=%s, user_id=%d)", c.Sender().ID, clubID, userID)
+
+	if userID == c.Sender().ID {
+		return c.Respond(&tele.CallbackResponse{
+			Text:      h.layout.Text(c, "club_staff_self"),
+			ShowAlert: true,
+		})
+	}
+
+	member, err := h.staffMemberByID(clubID, userID)
+	if err == nil {
+		err = h.clubOwnerService.Remove(context.Background(), userID, clubID)
+	}
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while remove club staff member (club_id=%s, user_id=%d): %v", c.Sender().ID, clubID, userID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:club:settings:staff:back", struct {
+				ClubID string
+			}{
+				ClubID: clubID,
+			}),
+		)
+	}
+
+	h.logger.Infof("(user: %d) club staff member removed (club_id=%s, user_id=%d)", c.Sender().ID, clubID, userID)
+	h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionClubOwnerRemoved, entity.AuditTargetClub, clubID, struct {
+		UserID int64
+		Role   valueobject.ClubOwnerRole
+	}{
+		UserID: userID,
+		Role:   member.OwnerRole,
+	}, nil)
+
+	club, err := h.clubService.Get(context.Background(), clubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club: %v", c.Sender().ID, err)
+	} else {
+		errSend := h.outboxService.Enqueue(context.Background(), userID,
+			h.layout.Text(c, "removed_from_club_staff", club),
+			h.layout.Markup(c, "core:hide"),
+		)
+		if errSend != nil {
+			h.logger.Errorf("(user: %d) error while send club staff removal to user %d: %v", c.Sender().ID, userID, errSend)
+		}
+		h.notifyClubStaff(c, club, h.layout.Text(c, "club_staff_removed_notification", struct {
+			FIO  string
+			Club string
+		}{
+			FIO:  member.FIO.String(),
+			Club: club.Name,
+		}))
+	}
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "club_staff_removed", member)),
+		h.layout.Markup(c, "clubOwner:club:settings:staff:back", member),
+	)
+}
+
+// staffMemberByID finds the member among the club staff
+func (h Handler) staffMemberByID(clubID string, userID int64) (*dto.ClubOwner, error) {
+	owners, err := h.clubOwnerService.GetByClubID(context.Background(), clubID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, owner := range owners {
+		if owner.UserID == userID {
+			return &owner, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func parseStaffCallback(callbackData string) (string, int64, error) {
+	data := strings.Split(callbackData, " ")
+	if len(data) != 2 {
+		return "", 0, errorz.ErrInvalidCallbackData
+	}
+
+	userID, err := strconv.ParseInt(data[1], 10, 64)
+	if err != nil {
+		return "", 0, errorz.ErrInvalidCallbackData
+	}
+
+	return data[0], userID, nil
+}
@@ -2,41 +2,52 @@ package menu
 
 import (
 	"context"
+	"errors"
 
 	tele "gopkg.in/telebot.v3"
 	"gopkg.in/telebot.v3/layout"
 
-	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/primary"
 	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
 )
 
 type Handler struct {
-	clubService primary.ClubService
+	clubService  primary.ClubService
+	adminService primary.AdminService
 
-	layout   *layout.Layout
-	logger   *types.Logger
-	adminIDs []int64
+	layout *layout.Layout
+	logger *types.Logger
 }
 
 func New(
 	clubSvc primary.ClubService,
 	lg *types.Logger,
 	lt *layout.Layout,
-	adminIDs []int64,
+	adminSvc primary.AdminService,
 ) *Handler {
 	return &Handler{
-		clubService: clubSvc,
+		clubService:  clubSvc,
+		adminService: adminSvc,
 
-		logger:   lg,
-		layout:   lt,
-		adminIDs: adminIDs,
+		logger: lg,
+		layout: lt,
 	}
 }
 
+// isAdmin checks whether the user has any admin role
+func (h Handler) isAdmin(c tele.Context) bool {
+	_, err := h.adminService.GetRole(context.Background(), c.Sender().ID)
+	if err != nil && !errors.Is(err, errorz.ErrNotAdmin) {
+		h.logger.Errorf("(user: %d) error while getting admin role: %v", c.Sender().ID, err)
+	}
+
+	return err == nil
+}
+
 func (h Handler) SendMenu(c tele.Context) error {
-	isAdmin := utils.IsAdmin(c.Sender().ID, h.adminIDs)
+	isAdmin := h.isAdmin(c)
 
 	menuMarkup := h.layout.Markup(c, "mainMenu:menu")
 
@@ -74,7 +85,7 @@ func (h Handler) SendMenu(c tele.Context) error {
 }
 
 func (h Handler) EditMenu(c tele.Context) error {
-	isAdmin := utils.IsAdmin(c.Sender().ID, h.adminIDs)
+	isAdmin := h.isAdmin(c)
 
 	menuMarkup := h.layout.Markup(c, "mainMenu:menu")
 
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"strings"
 
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/valueobject"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/primary"
 
 	"github.com/nlypage/intele"
@@ -17,30 +19,36 @@ import (
 	"gopkg.in/telebot.v3/layout"
 )
 
+// AdminRoleKey is the context key of the admin role set by IsAdmin
+const AdminRoleKey = "admin_role"
+
 type Handler struct {
-	bot         *tele.Bot
-	layout      *layout.Layout
-	logger      *types.Logger
-	userService primary.UserService
-	clubService primary.ClubService
-	input       *intele.InputManager
+	bot          *tele.Bot
+	layout       *layout.Layout
+	logger       *types.Logger
+	userService  primary.UserService
+	clubService  primary.ClubService
+	adminService primary.AdminService
+	input        *intele.InputManager
 }
 
 func New(
 	userSvc primary.UserService,
 	clubSvc primary.ClubService,
+	adminSvc primary.AdminService,
 	b *tele.Bot,
 	lt *layout.Layout,
 	lg *types.Logger,
 	in *intele.InputManager,
 ) *Handler {
 	return &Handler{
-		bot:         b,
-		layout:      lt,
-		logger:      lg,
-		userService: userSvc,
-		clubService: clubSvc,
-		input:       in,
+		bot:          b,
+		layout:       lt,
+		logger:       lg,
+		userService:  userSvc,
+		clubService:  clubSvc,
+		adminService: adminSvc,
+		input:        in,
 	}
 }
 
@@ -73,6 +81,18 @@ func (h Handler) Authorized(next tele.HandlerFunc) tele.HandlerFunc {
 			}
 		}
 
+		if user.IsBotBlocked() {
+			h.logger.Infof("(user: %d) user unblocked the bot", c.Sender().ID)
+			user.BotBlockedAt = nil
+			_, err = h.userService.Update(context.Background(), user)
+			if err != nil {
+				return c.Send(
+					banner.Auth.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+					h.layout.Markup(c, "core:hide"),
+				)
+			}
+		}
+
 		if user.IsBanned {
 			return c.Send(
 				banner.Auth.Caption(h.layout.TextLocale(user.Localisation, "banned")),
@@ -115,6 +135,50 @@ func (h Handler) IsClubOwner(next tele.HandlerFunc) tele.HandlerFunc {
 	}
 }
 
+// IsAdmin middleware lets only the admins through and stores their role by AdminRoleKey,
+// updates of other users are ignored
+func (h Handler) IsAdmin(next tele.HandlerFunc) tele.HandlerFunc {
+	return func(c tele.Context) error {
+		role, err := h.adminService.GetRole(context.Background(), c.Sender().ID)
+		if err != nil {
+			if !errors.Is(err, errorz.ErrNotAdmin) {
+				h.logger.Errorf("(user: %d) error while getting admin role: %v", c.Sender().ID, err)
+			}
+			return nil
+		}
+
+		c.Set(AdminRoleKey, role)
+		return next(c)
+	}
+}
+
+// AdminCan middleware lets through the admins whose role grants any of the permissions,
+// it must follow IsAdmin
+func (h Handler) AdminCan(permissions ...valueobject.AdminPermission) tele.MiddlewareFunc {
+	return func(next tele.HandlerFunc) tele.HandlerFunc {
+		return func(c tele.Context) error {
+			role, _ := c.Get(AdminRoleKey).(valueobject.AdminRole)
+			if role.CanAny(permissions...) {
+				return next(c)
+			}
+
+			h.logger.Infof("(user: %d) admin permission denied (role=%s)", c.Sender().ID, role)
+			if c.Callback() != nil {
+				return c.Respond(&tele.CallbackResponse{
+					Text:      h.layout.Text(c, "admin_permission_denied"),
+					ShowAlert: true,
+				})
+			}
+
+			_ = c.Delete()
+			return c.Send(
+				h.layout.Text(c, "admin_permission_denied"),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+	}
+}
+
 // func (h Handler) Localisation(next tele.HandlerFunc) tele.HandlerFunc {
 //	return func(c tele.Context) error {
 //		user, err := h.userService.Get(context.Background(), c.Sender().ID)
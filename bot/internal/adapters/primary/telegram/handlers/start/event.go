@@ -8,6 +8,7 @@ import (
 	tele "gopkg.in/telebot.v3"
 	"gorm.io/gorm"
 
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
@@ -245,6 +246,23 @@ func (h Handler) eventRegister(c tele.Context) error {
 			if (event.MaxParticipants == 0 || participantsCount < event.MaxParticipants || isShadowBanned) && registrationActive && roleAllowed && userSubscribed {
 				_, err = h.eventParticipantService.Register(context.Background(), eventID, c.Sender().ID)
 				if err != nil {
+					switch {
+					case errors.Is(err, errorz.ErrEventFull):
+						return c.Respond(&tele.CallbackResponse{
+							Text:      h.layout.Text(c, "max_participants_reached"),
+							ShowAlert: true,
+						})
+					case errors.Is(err, errorz.ErrRegistrationEnded):
+						return c.Respond(&tele.CallbackResponse{
+							Text:      h.layout.Text(c, "registration_ended"),
+							ShowAlert: true,
+						})
+					case errors.Is(err, errorz.ErrEventCancelled):
+						return c.Respond(&tele.CallbackResponse{
+							Text:      h.layout.Text(c, "event_cancelled_alert"),
+							ShowAlert: true,
+						})
+					}
 					h.logger.Errorf("(user: %d) error while register to event: %v", c.Sender().ID, err)
 					return c.Edit(
 						banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
@@ -4,13 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	tele "gopkg.in/telebot.v3"
 	"gorm.io/gorm"
 
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/valueobject"
 )
 
 func (h Handler) userQR(c tele.Context, qrCodeID string) error {
@@ -34,7 +38,7 @@ func (h Handler) userQR(c tele.Context, qrCodeID string) error {
 		)
 	}
 
-	userClubs, err := h.clubService.GetByOwnerID(context.Background(), c.Sender().ID)
+	userClubs, err := h.scannerClubs(c.Sender().ID)
 	if err != nil {
 		h.logger.Errorf("(user: %d) error while getting user's clubs from db: %v", c.Sender().ID, err)
 		return c.Send(
@@ -107,7 +111,7 @@ func (h Handler) backToClubsList(c tele.Context) error {
 		)
 	}
 
-	userClubs, err := h.clubService.GetByOwnerID(context.Background(), c.Sender().ID)
+	userClubs, err := h.scannerClubs(c.Sender().ID)
 	if err != nil {
 		h.logger.Errorf("(user: %d) error while getting user's clubs from db: %v", c.Sender().ID, err)
 		return c.Edit(
@@ -181,6 +185,21 @@ func (h Handler) qrEventsList(c tele.Context) error {
 
 	h.logger.Infof("(user: %d) qr events list (club_id=%s, qr_id=%s, qr_owner_id=%d)", c.Sender().ID, clubID, qrCodeID, user.ID)
 
+	canScan, err := h.canScan(clubID, c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club owner role: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+	if !canScan {
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "club_permission_denied")),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
 	events, err := h.eventService.GetFutureByClubID(
 		context.Background(),
 		-1,
@@ -268,6 +287,30 @@ func (h Handler) activateUserQR(c tele.Context) error {
 		)
 	}
 
+	canScan, err := h.canScan(event.ClubID, c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club owner role: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+	if !canScan {
+		h.logger.Infof("(user: %d) scan of user qr is not allowed (club_id=%s)", c.Sender().ID, event.ClubID)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "club_permission_denied")),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	if event.IsCancelled() {
+		h.logger.Infof("(user: %d) event is cancelled (event_id=%s)", c.Sender().ID, eventID)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "event_cancelled_alert")),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
 	if event.IsOver(time.Hour * 24) {
 		h.logger.Infof("(user: %d) event already started (event_id=%s)", c.Sender().ID, eventID)
 		return c.Edit(
@@ -280,8 +323,22 @@ func (h Handler) activateUserQR(c tele.Context) error {
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			h.logger.Infof("(user: %d) participant not found (event_id=%s)", c.Sender().ID, eventID)
-			eventParticipant, err = h.eventParticipantService.Register(context.Background(), eventID, user.ID)
+			eventParticipant, err = h.eventParticipantService.RegisterOnSite(context.Background(), eventID, user.ID)
 			if err != nil {
+				if errors.Is(err, errorz.ErrEventFull) {
+					h.logger.Infof("(user: %d) event is full (event_id=%s)", c.Sender().ID, eventID)
+					return c.Edit(
+						banner.ClubOwner.Caption(h.layout.Text(c, "max_participants_reached")),
+						h.layout.Markup(c, "core:hide"),
+					)
+				}
+				if errors.Is(err, errorz.ErrEventCancelled) {
+					h.logger.Infof("(user: %d) event is cancelled (event_id=%s)", c.Sender().ID, eventID)
+					return c.Edit(
+						banner.ClubOwner.Caption(h.layout.Text(c, "event_cancelled_alert")),
+						h.layout.Markup(c, "core:hide"),
+					)
+				}
 				h.logger.Errorf("(user: %d) error while registering participant: %v", c.Sender().ID, err)
 				return c.Edit(
 					banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
@@ -318,6 +375,7 @@ func (h Handler) activateUserQR(c tele.Context) error {
 	}
 
 	h.logger.Infof("(user: %d) user qr activated (event_id=%s, user_id=%d)", c.Sender().ID, eventID, user.ID)
+	h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionQRScanned, entity.AuditTargetUser, strconv.FormatInt(user.ID, 10), nil, eventParticipant)
 
 	return c.Edit(
 		banner.ClubOwner.Caption(h.layout.Text(c, "qr_activated", struct {
@@ -331,6 +389,45 @@ func (h Handler) activateUserQR(c tele.Context) error {
 	)
 }
 
+// scannerClubs returns the clubs where the role of the user allows to scan QR codes of users
+func (h Handler) scannerClubs(userID int64) ([]entity.Club, error) {
+	clubs, err := h.clubService.GetByOwnerID(context.Background(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	memberships, err := h.clubOwnerService.GetByUserID(context.Background(), userID)
+	if err != nil {
+		return nil, err
+	}
+	roles := make(map[string]valueobject.ClubOwnerRole, len(memberships))
+	for _, membership := range memberships {
+		roles[membership.ClubID] = membership.OwnerRole
+	}
+
+	scannerClubs := make([]entity.Club, 0, len(clubs))
+	for _, club := range clubs {
+		if roles[club.ID].Can(valueobject.ClubScanPermission) {
+			scannerClubs = append(scannerClubs, club)
+		}
+	}
+
+	return scannerClubs, nil
+}
+
+// canScan reports whether the role of the user in the club allows to scan QR codes of users
+func (h Handler) canScan(clubID string, userID int64) (bool, error) {
+	role, err := h.clubOwnerService.GetRole(context.Background(), clubID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return role.Can(valueobject.ClubScanPermission), nil
+}
+
 func (h Handler) SetupUserQR(group *tele.Group) {
 	group.Handle(h.layout.Callback("clubOwner:activateQR:clubs:back"), h.backToClubsList)
 	group.Handle(h.layout.Callback("clubOwner:activateQR:club"), h.qrEventsList)
@@ -350,6 +447,14 @@ func (h Handler) eventQR(c tele.Context, qrCodeID string) error {
 		)
 	}
 
+	if event.IsCancelled() {
+		h.logger.Infof("(user: %d) event is cancelled (event_id=%s)", c.Sender().ID, event.ID)
+		return c.Send(
+			banner.Events.Caption(h.layout.Text(c, "event_cancelled_alert")),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
 	if event.IsOver(time.Hour * 24) {
 		h.logger.Infof("(user: %d) event already started (event_id=%s)", c.Sender().ID, event.ID)
 		return c.Edit(
@@ -368,8 +473,22 @@ func (h Handler) eventQR(c tele.Context, qrCodeID string) error {
 			)
 		}
 		h.logger.Infof("(user: %d) participant not found (event_id=%s)", c.Sender().ID, event.ID)
-		eventParticipant, err = h.eventParticipantService.Register(context.Background(), event.ID, c.Sender().ID)
+		eventParticipant, err = h.eventParticipantService.RegisterOnSite(context.Background(), event.ID, c.Sender().ID)
 		if err != nil {
+			if errors.Is(err, errorz.ErrEventFull) {
+				h.logger.Infof("(user: %d) event is full (event_id=%s)", c.Sender().ID, event.ID)
+				return c.Send(
+					banner.Events.Caption(h.layout.Text(c, "max_participants_reached")),
+					h.layout.Markup(c, "core:hide"),
+				)
+			}
+			if errors.Is(err, errorz.ErrEventCancelled) {
+				h.logger.Infof("(user: %d) event is cancelled (event_id=%s)", c.Sender().ID, event.ID)
+				return c.Send(
+					banner.Events.Caption(h.layout.Text(c, "event_cancelled_alert")),
+					h.layout.Markup(c, "core:hide"),
+				)
+			}
 			h.logger.Errorf("(user: %d) error while registering participant: %v", c.Sender().ID, err)
 			return c.Edit(
 				banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
@@ -389,6 +508,7 @@ func (h Handler) eventQR(c tele.Context, qrCodeID string) error {
 		)
 	}
 	h.logger.Infof("(user: %d) event qr activated (event_id=%s, user_id=%d)", c.Sender().ID, event.ID, c.Sender().ID)
+	h.auditService.Record(context.Background(), c.Sender().ID, entity.AuditActionQRScanned, entity.AuditTargetEvent, event.ID, nil, eventParticipant)
 
 	return c.Send(
 		banner.Events.Caption(h.layout.Text(c, "event_qr_activated", struct {
@@ -27,10 +27,12 @@ import (
 type Handler struct {
 	userService             primary.UserService
 	clubService             primary.ClubService
+	clubOwnerService        primary.ClubOwnerService
 	eventService            primary.EventService
 	eventParticipantService primary.EventParticipantService
 	qrService               primary.QrService
 	notificationService     primary.NotifyService
+	auditService            primary.AuditService
 
 	callbacksStorage callbacks.CallbackStorage
 
@@ -48,10 +50,12 @@ type Handler struct {
 func New(
 	userSvc primary.UserService,
 	clubSvc primary.ClubService,
+	clubOwnerSvc primary.ClubOwnerService,
 	eventSvc primary.EventService,
 	eventParticipantSvc primary.EventParticipantService,
 	qrSvc primary.QrService,
 	notifySvc primary.NotifyService,
+	auditSvc primary.AuditService,
 	callbacksStorage callbacks.CallbackStorage,
 	menuHandler *menu.Handler,
 	codesStorage *codes.Storage,
@@ -65,10 +69,12 @@ func New(
 	return &Handler{
 		userService:             userSvc,
 		clubService:             clubSvc,
+		clubOwnerService:        clubOwnerSvc,
 		eventService:            eventSvc,
 		eventParticipantService: eventParticipantSvc,
 		qrService:               qrSvc,
 		notificationService:     notifySvc,
+		auditService:            auditSvc,
 		callbacksStorage:        callbacksStorage,
 		menuHandler:             menuHandler,
 		codesStorage:            codesStorage,
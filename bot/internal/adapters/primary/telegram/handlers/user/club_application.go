@@ -0,0 +1,304 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/nlypage/intele/collector"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/validator"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/valueobject"
+)
+
+// clubApplication asks for the fields of the club application and shows its draft
+func (h Handler) clubApplication(c tele.Context) error {
+	h.logger.Infof("(user: %d) create club application", c.Sender().ID)
+
+	pending, err := h.clubApplicationService.HasPending(context.Background(), c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while check pending club application: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.PersonalAccount.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "personalAccount:back"),
+		)
+	}
+	if pending {
+		return c.Edit(
+			banner.PersonalAccount.Caption(h.layout.Text(c, "club_application_pending")),
+			h.layout.Markup(c, "personalAccount:back"),
+		)
+	}
+
+	backMarkup := h.layout.Markup(c, "personalAccount:back")
+	name, ok := h.inputApplicationField(c, backMarkup, "input_club_application_name", "invalid_club_name", validator.ClubName, nil)
+	if !ok {
+		return nil
+	}
+	description, ok := h.inputApplicationField(c, backMarkup, "input_club_application_description", "invalid_club_description", validator.ClubDescription, nil)
+	if !ok {
+		return nil
+	}
+	link, ok := h.inputApplicationField(c, backMarkup, "input_club_application_link", "invalid_club_link", validator.ClubLink, nil)
+	if !ok {
+		return nil
+	}
+
+	var (
+		owners    []entity.User
+		promptKey = "input_club_application_owners"
+		notFound  int64
+	)
+	for {
+		value, ok := h.inputApplicationField(c, backMarkup, promptKey, "invalid_club_application_owners", validator.ClubApplicationOwners, struct {
+			ID  int64
+			Max int
+		}{
+			ID:  notFound,
+			Max: validator.ClubApplicationMaxOwners,
+		})
+		if !ok {
+			return nil
+		}
+
+		owners, notFound, err = h.applicationOwners(validator.ParseUserIDs(value))
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			h.logger.Errorf("(user: %d) error while get proposed club owners: %v", c.Sender().ID, err)
+			return c.Send(
+				banner.PersonalAccount.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+				backMarkup,
+			)
+		}
+		promptKey = "club_application_owner_not_found"
+	}
+
+	ownerIDs := make([]int64, 0, len(owners))
+	for _, owner := range owners {
+		ownerIDs = append(ownerIDs, owner.ID)
+	}
+
+	application, err := h.clubApplicationService.Create(context.Background(), &entity.ClubApplication{
+		ApplicantID: c.Sender().ID,
+		Name:        name,
+		Description: description,
+		Link:        link,
+		OwnerIDs:    ownerIDs,
+	})
+	if err != nil {
+		if errors.Is(err, errorz.ErrClubApplicationPending) {
+			return c.Send(
+				banner.PersonalAccount.Caption(h.layout.Text(c, "club_application_pending")),
+				backMarkup,
+			)
+		}
+
+		h.logger.Errorf("(user: %d) error while create club application: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.PersonalAccount.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	h.logger.Infof("(user: %d) club application draft created (application_id=%s)", c.Sender().ID, application.ID)
+	return c.Send(h.clubApplicationDraft(c, application, owners))
+}
+
+// toggleClubApplicationRole switches the role targeted by the club application draft
+func (h Handler) toggleClubApplicationRole(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+	applicationID, role := data[0], valueobject.Role(data[1])
+	h.logger.Infof("(user: %d) toggle club application role (application_id=%s, role=%s)", c.Sender().ID, applicationID, role)
+
+	application, err := h.clubApplicationService.Get(context.Background(), applicationID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club application: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.PersonalAccount.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "personalAccount:back"),
+		)
+	}
+	if application.ApplicantID != c.Sender().ID {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	application, err = h.clubApplicationService.ToggleRole(context.Background(), applicationID, role)
+	if err != nil {
+		if errors.Is(err, errorz.ErrClubApplicationSubmitted) {
+			return c.Edit(
+				banner.PersonalAccount.Caption(h.layout.Text(c, "club_application_already_submitted")),
+				h.layout.Markup(c, "personalAccount:back"),
+			)
+		}
+
+		h.logger.Errorf("(user: %d) error while toggle club application role: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.PersonalAccount.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "personalAccount:back"),
+		)
+	}
+
+	owners, _, err := h.applicationOwners(application.OwnerIDs)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get proposed club owners: %v", c.Sender().ID, err)
+	}
+
+	return c.Edit(h.clubApplicationDraft(c, application, owners))
+}
+
+// submitClubApplication sends the club application draft to the admins
+func (h Handler) submitClubApplication(c tele.Context) error {
+	applicationID := c.Callback().Data
+	if applicationID == "" {
+		return errorz.ErrInvalidCallbackData
+	}
+	h.logger.Infof("(user: %d) submit club application (application_id=%s)", c.Sender().ID, applicationID)
+
+	application, err := h.clubApplicationService.Get(context.Background(), applicationID)
+	if err == nil && application.ApplicantID != c.Sender().ID {
+		return errorz.ErrInvalidCallbackData
+	}
+	if err == nil {
+		application, err = h.clubApplicationService.Submit(context.Background(), applicationID)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, errorz.ErrClubApplicationSubmitted):
+			return c.Edit(
+				banner.PersonalAccount.Caption(h.layout.Text(c, "club_application_already_submitted")),
+				h.layout.Markup(c, "personalAccount:back"),
+			)
+		case errors.Is(err, errorz.ErrClubApplicationPending):
+			return c.Edit(
+				banner.PersonalAccount.Caption(h.layout.Text(c, "club_application_pending")),
+				h.layout.Markup(c, "personalAccount:back"),
+			)
+		}
+
+		h.logger.Errorf("(user: %d) error while submit club application: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.PersonalAccount.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "personalAccount:back"),
+		)
+	}
+
+	errSendWarning := h.notificationService.SendAdminWarning(
+		h.layout.Text(c, "new_club_application_warning", application),
+		h.layout.Markup(c, "admin:club_application:open", application),
+	)
+	if errSendWarning != nil {
+		h.logger.Errorf("(user: %d) error while send new club application warning: %v", c.Sender().ID, errSendWarning)
+	}
+
+	return c.Edit(
+		banner.PersonalAccount.Caption(h.layout.Text(c, "club_application_submitted", application)),
+		h.layout.Markup(c, "personalAccount:back"),
+	)
+}
+
+// clubApplicationDraft returns the draft of the club application with the buttons choosing its roles
+func (h Handler) clubApplicationDraft(c tele.Context, application *entity.ClubApplication, owners []entity.User) (interface{}, *tele.ReplyMarkup) {
+	markup := h.layout.Markup(c, "personalAccount:club_application", application)
+
+	rows := make([][]tele.InlineButton, 0, len(valueobject.AllRoles())+len(markup.InlineKeyboard))
+	for _, role := range valueobject.AllRoles() {
+		rows = append(rows, []tele.InlineButton{*h.layout.Button(c, "personalAccount:club_application:role", struct {
+			ID       string
+			Role     string
+			RoleText string
+			On       bool
+		}{
+			ID:       application.ID,
+			Role:     role.String(),
+			RoleText: h.layout.Text(c, role.String()),
+			On:       slices.Contains(application.AllowedRoles, role.String()),
+		}).Inline()})
+	}
+	markup.InlineKeyboard = append(rows, markup.InlineKeyboard...)
+
+	return banner.PersonalAccount.Caption(h.layout.Text(c, "club_application_draft_text", struct {
+		Application *entity.ClubApplication
+		Owners      []entity.User
+	}{
+		Application: application,
+		Owners:      owners,
+	})), markup
+}
+
+// applicationOwners returns the users proposed as the club owners,
+// gorm.ErrRecordNotFound is returned with the ID of the first missing user
+func (h Handler) applicationOwners(ids []int64) ([]entity.User, int64, error) {
+	owners := make([]entity.User, 0, len(ids))
+	for _, id := range ids {
+		user, err := h.userService.Get(context.Background(), id)
+		if err != nil {
+			return nil, id, err
+		}
+		owners = append(owners, *user)
+	}
+
+	return owners, 0, nil
+}
+
+// inputApplicationField asks the user for a field of the club application until it passes the validation,
+// returns false if the input was canceled
+func (h Handler) inputApplicationField(
+	c tele.Context,
+	backMarkup *tele.ReplyMarkup,
+	promptKey, errorKey string,
+	validate func(string, map[string]interface{}) bool,
+	textData interface{},
+) (string, bool) {
+	inputCollector := collector.New()
+	// the previous message is already cleared when the value is not the first one in a row
+	if err := c.Edit(banner.PersonalAccount.Caption(h.layout.Text(c, promptKey, textData)), backMarkup); err != nil {
+		_ = inputCollector.Send(c,
+			banner.PersonalAccount.Caption(h.layout.Text(c, promptKey, textData)),
+			backMarkup,
+		)
+	} else {
+		inputCollector.Collect(c.Message())
+	}
+
+	for {
+		response, errGet := h.input.Get(context.Background(), c.Sender().ID, 0)
+		if response.Message != nil {
+			inputCollector.Collect(response.Message)
+		}
+		switch {
+		case response.Canceled:
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true, ExcludeLast: true})
+			return "", false
+		case errGet != nil:
+			h.logger.Errorf("(user: %d) error while input (%s): %v", c.Sender().ID, promptKey, errGet)
+			_ = inputCollector.Send(c,
+				banner.PersonalAccount.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, promptKey, textData))),
+				backMarkup,
+			)
+		case response.Message == nil:
+			_ = inputCollector.Send(c,
+				banner.PersonalAccount.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, promptKey, textData))),
+				backMarkup,
+			)
+		case !validate(response.Message.Text, nil):
+			_ = inputCollector.Send(c,
+				banner.PersonalAccount.Caption(h.layout.Text(c, errorKey, textData)),
+				backMarkup,
+			)
+		default:
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
+			return response.Message.Text, true
+		}
+	}
+}
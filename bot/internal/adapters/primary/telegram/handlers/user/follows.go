@@ -0,0 +1,98 @@
+package user
+
+import (
+	"context"
+	"strings"
+
+	tele "gopkg.in/telebot.v3"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+)
+
+// followClub follows or unfollows the club from the club about page
+func (h Handler) followClub(c tele.Context) error {
+	callbackData := strings.Split(c.Callback().Data, " ")
+	if len(callbackData) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+	clubID := callbackData[0]
+
+	following, err := h.clubFollowerService.IsFollowing(context.Background(), c.Sender().ID, clubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while check club follow: %v", c.Sender().ID, err)
+		return c.Respond(&tele.CallbackResponse{Text: h.layout.Text(c, "technical_issues", err.Error())})
+	}
+
+	if following {
+		h.logger.Infof("(user: %d) unfollow club (club_id=%s)", c.Sender().ID, clubID)
+		err = h.clubFollowerService.Unfollow(context.Background(), c.Sender().ID, clubID)
+	} else {
+		h.logger.Infof("(user: %d) follow club (club_id=%s)", c.Sender().ID, clubID)
+		err = h.clubFollowerService.Follow(context.Background(), c.Sender().ID, clubID)
+	}
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while switch club follow: %v", c.Sender().ID, err)
+		return c.Respond(&tele.CallbackResponse{Text: h.layout.Text(c, "technical_issues", err.Error())})
+	}
+
+	if following {
+		_ = c.Respond(&tele.CallbackResponse{Text: h.layout.Text(c, "club_unfollowed")})
+	} else {
+		_ = c.Respond(&tele.CallbackResponse{Text: h.layout.Text(c, "club_followed")})
+	}
+
+	return h.clubAbout(c)
+}
+
+// followedClubs shows clubs followed by the user
+func (h Handler) followedClubs(c tele.Context) error {
+	h.logger.Infof("(user: %d) edit followed clubs", c.Sender().ID)
+
+	clubs, err := h.clubFollowerService.GetFollowedClubs(context.Background(), c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get followed clubs: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.PersonalAccount.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "personalAccount:back"),
+		)
+	}
+
+	markup := c.Bot().NewMarkup()
+	var rows []tele.Row
+	for _, club := range clubs {
+		rows = append(rows, markup.Row(*h.layout.Button(c, "personalAccount:followed_clubs:unfollow", struct {
+			ID   string
+			Name string
+		}{
+			ID:   club.ID,
+			Name: club.Name,
+		})))
+	}
+	rows = append(rows, markup.Row(*h.layout.Button(c, "personalAccount:back")))
+	markup.Inline(rows...)
+
+	return c.Edit(
+		banner.PersonalAccount.Caption(h.layout.Text(c, "followed_clubs_text", len(clubs))),
+		markup,
+	)
+}
+
+func (h Handler) unfollowClub(c tele.Context) error {
+	if c.Callback().Data == "" {
+		return errorz.ErrInvalidCallbackData
+	}
+	clubID := c.Callback().Data
+	h.logger.Infof("(user: %d) unfollow club (club_id=%s)", c.Sender().ID, clubID)
+
+	if err := h.clubFollowerService.Unfollow(context.Background(), c.Sender().ID, clubID); err != nil {
+		h.logger.Errorf("(user: %d) error while unfollow club: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.PersonalAccount.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "personalAccount:back"),
+		)
+	}
+
+	_ = c.Respond(&tele.CallbackResponse{Text: h.layout.Text(c, "club_unfollowed")})
+	return h.followedClubs(c)
+}
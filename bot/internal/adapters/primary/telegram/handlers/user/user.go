@@ -41,7 +41,10 @@ type Handler struct {
 	userService             primary.UserService
 	eventService            primary.EventService
 	clubService             primary.ClubService
+	clubFollowerService     primary.ClubFollowerService
+	clubApplicationService  primary.ClubApplicationService
 	eventParticipantService primary.EventParticipantService
+	eventSeriesService      primary.EventSeriesService
 	qrService               primary.QrService
 	notificationService     primary.NotifyService
 
@@ -67,7 +70,10 @@ func New(
 	userSvc primary.UserService,
 	eventSvc primary.EventService,
 	clubSvc primary.ClubService,
+	clubFollowerSvc primary.ClubFollowerService,
+	clubApplicationSvc primary.ClubApplicationService,
 	eventParticipantSvc primary.EventParticipantService,
+	eventSeriesSvc primary.EventSeriesService,
 	qrSvc primary.QrService,
 	notifySvc primary.NotifyService,
 	menuHandler *menu.Handler,
@@ -89,7 +95,10 @@ func New(
 		userService:             userSvc,
 		eventService:            eventSvc,
 		eventParticipantService: eventParticipantSvc,
+		eventSeriesService:      eventSeriesSvc,
 		clubService:             clubSvc,
+		clubFollowerService:     clubFollowerSvc,
+		clubApplicationService:  clubApplicationSvc,
 		qrService:               qrSvc,
 		notificationService:     notifySvc,
 		menuHandler:             menuHandler,
@@ -397,10 +406,27 @@ func (h Handler) clubAbout(c tele.Context) error {
 		)
 	}
 
-	menuMarkup := h.layout.Markup(c, "cuClubs:back", struct {
-		Page int
+	following, err := h.clubFollowerService.IsFollowing(context.Background(), c.Sender().ID, club.ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while check club follow: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.Clubs.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "cuClubs:back", struct {
+				Page int
+			}{
+				Page: page,
+			}),
+		)
+	}
+
+	menuMarkup := h.layout.Markup(c, "cuClubs:club:about:menu", struct {
+		ID        string
+		Page      int
+		Following bool
 	}{
-		Page: page,
+		ID:        club.ID,
+		Page:      page,
+		Following: following,
 	})
 
 	if clubAvatar != nil {
@@ -575,11 +601,13 @@ func (h Handler) eventsList(c tele.Context) error {
 			Name         string
 			Page         int
 			IsRegistered bool
+			IsRecurring  bool
 		}{
 			ID:           event.ID,
 			Name:         event.Name,
 			Page:         p,
 			IsRegistered: event.IsRegistered,
+			IsRecurring:  event.IsRecurring,
 		})))
 	}
 	h.logger.Debugf("Events: %+v", rows)
@@ -771,6 +799,23 @@ func (h Handler) event(c tele.Context) error {
 			if (event.MaxParticipants == 0 || participantsCount < event.MaxParticipants || isShadowBanned) && registrationActive && roleAllowed && userSubscribed {
 				_, err = h.eventParticipantService.Register(context.Background(), eventID, c.Sender().ID)
 				if err != nil {
+					switch {
+					case errors.Is(err, errorz.ErrEventFull):
+						return c.Respond(&tele.CallbackResponse{
+							Text:      h.layout.Text(c, "max_participants_reached_waitlist"),
+							ShowAlert: true,
+						})
+					case errors.Is(err, errorz.ErrRegistrationEnded):
+						return c.Respond(&tele.CallbackResponse{
+							Text:      h.layout.Text(c, "registration_ended"),
+							ShowAlert: true,
+						})
+					case errors.Is(err, errorz.ErrEventCancelled):
+						return c.Respond(&tele.CallbackResponse{
+							Text:      h.layout.Text(c, "event_cancelled_alert"),
+							ShowAlert: true,
+						})
+					}
 					h.logger.Errorf("(user: %d) error while register to event: %v", c.Sender().ID, err)
 					return c.Edit(
 						banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
@@ -822,8 +867,8 @@ func (h Handler) event(c tele.Context) error {
 						ShowAlert: true,
 					})
 				case !isShadowBanned && event.MaxParticipants > 0 && participantsCount >= event.MaxParticipants:
-					return c.Respond(&tele.CallbackResponse{
-						Text:      h.layout.Text(c, "max_participants_reached"),
+					_ = c.Respond(&tele.CallbackResponse{
+						Text:      h.layout.Text(c, "max_participants_reached_waitlist"),
 						ShowAlert: true,
 					})
 				case !roleAllowed:
@@ -893,14 +938,21 @@ func (h Handler) event(c tele.Context) error {
 			)
 		}
 	} else {
+		registrationButton, err := h.registrationButton(c, event, participantsCount, page)
+		if err != nil {
+			h.logger.Errorf("(user: %d) error while get waitlist position: %v", c.Sender().ID, err)
+			return c.Edit(
+				banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+				h.layout.Markup(c, "user:events:back", struct {
+					Page string
+				}{
+					Page: page,
+				}),
+			)
+		}
+
 		markup.InlineKeyboard = append(
-			[][]tele.InlineButton{{*h.layout.Button(c, "user:events:event:register", struct {
-				ID   string
-				Page string
-			}{
-				ID:   eventID,
-				Page: page,
-			}).Inline()}},
+			[][]tele.InlineButton{{*registrationButton}},
 			markup.InlineKeyboard...,
 		)
 	}
@@ -1175,14 +1227,21 @@ func (h Handler) eventCancelRegistration(c tele.Context) error {
 			markup.InlineKeyboard...,
 		)
 	} else {
+		registrationButton, err := h.registrationButton(c, event, participantsCount, page)
+		if err != nil {
+			h.logger.Errorf("(user: %d) error while get waitlist position: %v", c.Sender().ID, err)
+			return c.Edit(
+				banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+				h.layout.Markup(c, "user:events:back", struct {
+					Page string
+				}{
+					Page: page,
+				}),
+			)
+		}
+
 		markup.InlineKeyboard = append(
-			[][]tele.InlineButton{{*h.layout.Button(c, "user:events:event:register", struct {
-				ID   string
-				Page string
-			}{
-				ID:   eventID,
-				Page: page,
-			}).Inline()}},
+			[][]tele.InlineButton{{*registrationButton}},
 			markup.InlineKeyboard...,
 		)
 	}
@@ -1229,6 +1288,240 @@ func (h Handler) eventCancelRegistration(c tele.Context) error {
 	return nil
 }
 
+// registrationButton returns the register button or, if the event is full, the waitlist button
+func (h Handler) registrationButton(c tele.Context, event *entity.Event, participantsCount int, page string) (*tele.InlineButton, error) {
+	if event.MaxParticipants == 0 || participantsCount < event.MaxParticipants {
+		return h.layout.Button(c, "user:events:event:register", struct {
+			ID   string
+			Page string
+		}{
+			ID:   event.ID,
+			Page: page,
+		}).Inline(), nil
+	}
+
+	position, err := h.eventParticipantService.GetWaitlistPosition(context.Background(), event.ID, c.Sender().ID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+
+		return h.layout.Button(c, "user:events:event:join_waitlist", struct {
+			ID   string
+			Page string
+		}{
+			ID:   event.ID,
+			Page: page,
+		}).Inline(), nil
+	}
+
+	return h.layout.Button(c, "user:events:event:leave_waitlist", struct {
+		ID       string
+		Page     string
+		Position int
+	}{
+		ID:       event.ID,
+		Page:     page,
+		Position: position,
+	}).Inline(), nil
+}
+
+func (h Handler) eventJoinWaitlist(c tele.Context) error {
+	callbackData := strings.Split(c.Callback().Data, " ")
+	if len(callbackData) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+	eventID := callbackData[0]
+	page := callbackData[1]
+	h.logger.Infof("(user: %d) join event waitlist (event_id=%s)", c.Sender().ID, eventID)
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "user:events:back", struct {
+				Page string
+			}{
+				Page: page,
+			}),
+		)
+	}
+
+	user, err := h.userService.Get(context.Background(), c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get user: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "user:events:back", struct {
+				Page string
+			}{
+				Page: page,
+			}),
+		)
+	}
+
+	if !slices.Contains(event.AllowedRoles, string(user.Role)) {
+		return c.Respond(&tele.CallbackResponse{
+			Text:      h.layout.Text(c, "not_allowed_role"),
+			ShowAlert: true,
+		})
+	}
+
+	isShadowBanned, err := h.eventParticipantService.IsShadowBanned(context.Background(), c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while checking shadow ban: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "user:events:back", struct {
+				Page string
+			}{
+				Page: page,
+			}),
+		)
+	}
+
+	participantsCount, err := h.eventParticipantService.CountByEventID(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get participants count: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "user:events:back", struct {
+				Page string
+			}{
+				Page: page,
+			}),
+		)
+	}
+
+	// A seat has been freed in the meantime, the event page will offer a regular registration
+	if event.MaxParticipants == 0 || participantsCount < event.MaxParticipants {
+		return h.event(c)
+	}
+
+	if isShadowBanned {
+		_, err = h.eventParticipantService.Register(context.Background(), eventID, c.Sender().ID)
+	} else {
+		_, err = h.eventParticipantService.JoinWaitlist(context.Background(), eventID, c.Sender().ID)
+	}
+	switch {
+	case errors.Is(err, errorz.ErrRegistrationEnded):
+		return c.Respond(&tele.CallbackResponse{
+			Text:      h.layout.Text(c, "registration_ended"),
+			ShowAlert: true,
+		})
+	case errors.Is(err, errorz.ErrEventCancelled):
+		return c.Respond(&tele.CallbackResponse{
+			Text:      h.layout.Text(c, "event_cancelled_alert"),
+			ShowAlert: true,
+		})
+	case errors.Is(err, errorz.ErrAlreadyRegistered):
+		return h.event(c)
+	}
+	if err != nil && !errors.Is(err, errorz.ErrAlreadyInWaitlist) {
+		h.logger.Errorf("(user: %d) error while join event waitlist: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "user:events:back", struct {
+				Page string
+			}{
+				Page: page,
+			}),
+		)
+	}
+
+	if !isShadowBanned {
+		position, err := h.eventParticipantService.GetWaitlistPosition(context.Background(), eventID, c.Sender().ID)
+		if err == nil {
+			_ = c.Respond(&tele.CallbackResponse{
+				Text: h.layout.Text(c, "waitlist_joined", struct {
+					Position int
+				}{
+					Position: position,
+				}),
+				ShowAlert: true,
+			})
+		}
+	}
+
+	return h.event(c)
+}
+
+func (h Handler) eventLeaveWaitlist(c tele.Context) error {
+	callbackData := strings.Split(c.Callback().Data, " ")
+	if len(callbackData) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+	eventID := callbackData[0]
+	page := callbackData[1]
+	h.logger.Infof("(user: %d) leave event waitlist (event_id=%s)", c.Sender().ID, eventID)
+
+	err := h.eventParticipantService.LeaveWaitlist(context.Background(), eventID, c.Sender().ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		h.logger.Errorf("(user: %d) error while leave event waitlist: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "user:events:back", struct {
+				Page string
+			}{
+				Page: page,
+			}),
+		)
+	}
+
+	return h.event(c)
+}
+
+func (h Handler) waitlistConfirm(c tele.Context) error {
+	eventID := c.Callback().Data
+	h.logger.Infof("(user: %d) confirm waitlist offer (event_id=%s)", c.Sender().ID, eventID)
+
+	err := h.eventParticipantService.ConfirmWaitlistOffer(context.Background(), eventID, c.Sender().ID)
+	if err != nil {
+		if errors.Is(err, errorz.ErrWaitlistOfferExpired) {
+			return c.Edit(
+				h.layout.Text(c, "waitlist_offer_expired"),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+		h.logger.Errorf("(user: %d) error while confirm waitlist offer: %v", c.Sender().ID, err)
+		return c.Edit(
+			h.layout.Text(c, "technical_issues", err.Error()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	return c.Edit(
+		h.layout.Text(c, "waitlist_offer_confirmed"),
+		h.layout.Markup(c, "core:hide"),
+	)
+}
+
+func (h Handler) waitlistDecline(c tele.Context) error {
+	eventID := c.Callback().Data
+	h.logger.Infof("(user: %d) decline waitlist offer (event_id=%s)", c.Sender().ID, eventID)
+
+	err := h.eventParticipantService.DeclineWaitlistOffer(context.Background(), eventID, c.Sender().ID)
+	if err != nil {
+		if errors.Is(err, errorz.ErrWaitlistOfferExpired) {
+			return c.Edit(
+				h.layout.Text(c, "waitlist_offer_expired"),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+		h.logger.Errorf("(user: %d) error while decline waitlist offer: %v", c.Sender().ID, err)
+		return c.Edit(
+			h.layout.Text(c, "technical_issues", err.Error()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	return c.Edit(
+		h.layout.Text(c, "waitlist_offer_declined"),
+		h.layout.Markup(c, "core:hide"),
+	)
+}
+
 func (h Handler) eventExportToICS(c tele.Context) error {
 	eventID := c.Callback().Data
 	h.logger.Infof("(user: %d) export event to ics (event_id=%s)", c.Sender().ID, eventID)
@@ -1242,7 +1535,19 @@ func (h Handler) eventExportToICS(c tele.Context) error {
 		)
 	}
 
-	ics, err := calendar.ExportEventToICS(*event)
+	var series *entity.EventSeries
+	if event.IsRecurring() {
+		series, err = h.eventSeriesService.Get(context.Background(), *event.SeriesID)
+		if err != nil {
+			h.logger.Errorf("(user: %d) error while get event series: %v", c.Sender().ID, err)
+			return c.Edit(
+				banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+	}
+
+	ics, err := calendar.ExportEventToICS(*event, series)
 	if err != nil {
 		h.logger.Errorf("(user: %d) error while export event to ics: %v", c.Sender().ID, err)
 		return c.Edit(
@@ -1325,17 +1630,19 @@ func (h Handler) myEvents(c tele.Context) error {
 	markup := c.Bot().NewMarkup()
 	for _, event := range events {
 		rows = append(rows, markup.Row(*h.layout.Button(c, "user:myEvents:event", struct {
-			ID        string
-			Name      string
-			Page      int
-			IsOver    bool
-			IsVisited bool
+			ID          string
+			Name        string
+			Page        int
+			IsOver      bool
+			IsVisited   bool
+			IsCancelled bool
 		}{
-			ID:        event.ID,
-			Name:      event.Name,
-			Page:      p,
-			IsOver:    event.IsOver(0),
-			IsVisited: event.IsVisited,
+			ID:          event.ID,
+			Name:        event.Name,
+			Page:        p,
+			IsOver:      event.IsOver(0),
+			IsVisited:   event.IsVisited,
+			IsCancelled: event.IsCancelled(),
 		})))
 	}
 
@@ -1500,9 +1807,25 @@ func (h Handler) myEvent(c tele.Context) error {
 		)
 	}
 
+	var passStatus, passTime string
+	if !event.IsOver(0) && !event.IsCancelled() {
+		passStatus, passTime, err = h.userPassStatus(event, user)
+		if err != nil {
+			h.logger.Errorf("(user: %d) error while get user pass: %v", c.Sender().ID, err)
+			return c.Edit(
+				banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+				h.layout.Markup(c, "user:myEvents:back", struct {
+					Page string
+				}{
+					Page: page,
+				}),
+			)
+		}
+	}
+
 	markup := c.Bot().NewMarkup()
 	var rows []tele.Row
-	if canCancelRegistration {
+	if canCancelRegistration && !event.IsCancelled() {
 		rows = append(rows, markup.Row(*h.layout.Button(c, "user:myEvents:event:cancel_registration", struct {
 			ID   string
 			Page string
@@ -1539,6 +1862,10 @@ func (h Handler) myEvent(c tele.Context) error {
 			AfterRegistrationText string
 			IsOver                bool
 			IsVisited             bool
+			IsCancelled           bool
+			CancellationReason    string
+			PassStatus            string
+			PassTime              string
 		}{
 			Name:                  event.Name,
 			ClubName:              club.Name,
@@ -1552,11 +1879,40 @@ func (h Handler) myEvent(c tele.Context) error {
 			AfterRegistrationText: event.AfterRegistrationText,
 			IsOver:                event.IsOver(0),
 			IsVisited:             eventParticipant.IsEventQr || eventParticipant.IsUserQr,
+			IsCancelled:           event.IsCancelled(),
+			CancellationReason:    event.CancellationReason,
+			PassStatus:            passStatus,
+			PassTime:              passTime,
 		})),
 		markup)
 	return nil
 }
 
+// userPassStatus returns the pass status of the user for the event shown in my events:
+// not_required, pending, sent or cancelled, and the time the pass is going to be or was sent at.
+// The time of a pending pass is empty if its scheduled time has passed and it is sent with the next correction
+func (h Handler) userPassStatus(event *entity.Event, user *entity.User) (string, string, error) {
+	pass, err := h.eventParticipantService.GetUserPass(context.Background(), event, user)
+	if err != nil {
+		switch {
+		case errors.Is(err, errorz.ErrPassNotRequired):
+			return "not_required", "", nil
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return "cancelled", "", nil
+		}
+		return "", "", err
+	}
+
+	switch {
+	case pass.Status == entity.PassStatusSent && pass.SentAt != nil:
+		return "sent", pass.SentAt.In(location.Location()).Format("02.01.2006 15:04"), nil
+	case pass.ScheduledAt.After(time.Now()):
+		return "pending", pass.ScheduledAt.In(location.Location()).Format("02.01.2006 15:04"), nil
+	default:
+		return "pending", "", nil
+	}
+}
+
 func (h Handler) myEventCancelRegistration(c tele.Context) error {
 	callbackData := strings.Split(c.Callback().Data, " ")
 	if len(callbackData) != 2 {
@@ -2056,6 +2412,7 @@ func (h Handler) UserSetup(group *tele.Group) {
 	group.Handle(h.layout.Callback("cuClubs:club:intro"), h.clubIntro)
 	group.Handle(h.layout.Callback("cuClubs:club:intro:back"), h.clubIntro)
 	group.Handle(h.layout.Callback("cuClubs:club:about"), h.clubAbout)
+	group.Handle(h.layout.Callback("cuClubs:club:follow"), h.followClub)
 
 	group.Handle(h.layout.Callback("mainMenu:events"), h.digest)
 	group.Handle(h.layout.Callback("digest:events"), h.eventsList)
@@ -2068,6 +2425,10 @@ func (h Handler) UserSetup(group *tele.Group) {
 	group.Handle(h.layout.Callback("user:myEvents:event:export"), h.eventExportToICS)
 	group.Handle(h.layout.Callback("user:myEvents:event:cancel_registration"), h.myEventCancelRegistration)
 	group.Handle(h.layout.Callback("user:events:event:register"), h.event)
+	group.Handle(h.layout.Callback("user:events:event:join_waitlist"), h.eventJoinWaitlist)
+	group.Handle(h.layout.Callback("user:events:event:leave_waitlist"), h.eventLeaveWaitlist)
+	group.Handle(h.layout.Callback("waitlist:confirm"), h.waitlistConfirm)
+	group.Handle(h.layout.Callback("waitlist:decline"), h.waitlistDecline)
 
 	group.Handle(h.layout.Callback("mainMenu:personalAccount"), h.personalAccount)
 	group.Handle(h.layout.Callback("personalAccount:my_events"), h.myEvents)
@@ -2075,6 +2436,11 @@ func (h Handler) UserSetup(group *tele.Group) {
 	group.Handle(h.layout.Callback("user:myEvents:next_page"), h.myEvents)
 	group.Handle(h.layout.Callback("user:myEvents:event"), h.myEvent)
 	group.Handle(h.layout.Callback("user:myEvents:back"), h.myEvents)
+	group.Handle(h.layout.Callback("personalAccount:followed_clubs"), h.followedClubs)
+	group.Handle(h.layout.Callback("personalAccount:followed_clubs:unfollow"), h.unfollowClub)
+	group.Handle(h.layout.Callback("personalAccount:club_application"), h.clubApplication)
+	group.Handle(h.layout.Callback("personalAccount:club_application:role"), h.toggleClubApplicationRole)
+	group.Handle(h.layout.Callback("personalAccount:club_application:submit"), h.submitClubApplication)
 	group.Handle(h.layout.Callback("personalAccount:change_role"), h.changeRole)
 	group.Handle(h.layout.Callback("changeRole:student:resend_email"), h.resendChangeRoleEmailConfirmationCode)
 	group.Handle(h.layout.Callback("personalAccount:back"), h.personalAccount)
@@ -2117,6 +2483,16 @@ func (h Handler) digest(c tele.Context) error {
 		return c.Send("На этой неделе мероприятий нет.", h.layout.Markup(c, "digest:menu"))
 	}
 
+	followedClubs, err := h.clubFollowerService.GetFollowedClubs(context.Background(), c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error getting followed clubs: %v", c.Sender().ID, err)
+		return c.Send(h.layout.Text(c, "technical_issues", err.Error()))
+	}
+	followedClubIDs := make(map[string]bool, len(followedClubs))
+	for _, club := range followedClubs {
+		followedClubIDs[club.ID] = true
+	}
+
 	// Generate digest images
 	images, err := h.eventService.GenerateWeeklyDigestImage(filteredEvents)
 	if err != nil {
@@ -2128,7 +2504,7 @@ func (h Handler) digest(c tele.Context) error {
 	imageBytes := images[0]
 	photo := &tele.Photo{
 		File:    tele.FromReader(bytes.NewReader(imageBytes)),
-		Caption: h.generateDigestText(filteredEvents, botUsername),
+		Caption: h.generateDigestText(filteredEvents, followedClubIDs, botUsername),
 	}
 	markup := h.layout.Markup(c, "digest:menu")
 
@@ -2136,7 +2512,8 @@ func (h Handler) digest(c tele.Context) error {
 	return c.Send(photo, markup)
 }
 
-func (h Handler) generateDigestText(events []entity.Event, botUsername string) string {
+// generateDigestText generates the digest caption, events of followed clubs are marked with a star
+func (h Handler) generateDigestText(events []entity.Event, followedClubIDs map[string]bool, botUsername string) string {
 	// Group events by day
 	eventsByDay := make(map[time.Time][]entity.Event)
 	for _, event := range events {
@@ -2179,11 +2556,16 @@ func (h Handler) generateDigestText(events []entity.Event, botUsername string) s
 			text += "<i>В этот день нет мероприятий</i>\n\n"
 		} else {
 			for _, event := range dayEvents {
+				marker := "➡️"
+				if followedClubIDs[event.ClubID] {
+					marker = "⭐️"
+				}
+
 				if time.Now().In(location.Location()).After(event.RegistrationEnd) {
-					text += fmt.Sprintf("➡️ %s\n", event.Name)
+					text += fmt.Sprintf("%s %s\n", marker, event.Name)
 				} else {
 					link := fmt.Sprintf("https://t.me/%s?start=event_%s", botUsername, event.ID)
-					text += fmt.Sprintf("➡️ <a href=\"%s\">%s</a>\n", link, event.Name)
+					text += fmt.Sprintf("%s <a href=\"%s\">%s</a>\n", marker, link, event.Name)
 				}
 			}
 			text += "\n"
@@ -22,7 +22,6 @@ func Setup(
 	menuHandler *menu.Handler,
 	adminHandler *admin.Handler,
 	debug bool,
-	adminIDs []int64,
 ) {
 	// Pre-setup and global middlewares
 	bot.Use(middle.PrivateChatOnly)
@@ -57,9 +56,9 @@ func Setup(
 	startHandler.SetupURLEvent(bot.Group())
 
 	// ClubOwner:
+	clubOwnerHandler.OwnershipSetup(bot.Group())
 	clubOwnerHandler.ClubOwnerSetup(bot.Group(), middle)
 
 	// Admin:
-	bot.Use(middleware.Whitelist(adminIDs...))
-	adminHandler.AdminSetup(bot.Group())
+	adminHandler.AdminSetup(bot.Group(), middle)
 }
@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+type AdminRepository struct {
+	db *gorm.DB
+}
+
+func NewAdminRepository(db *gorm.DB) *AdminRepository {
+	return &AdminRepository{
+		db: db,
+	}
+}
+
+func (s *AdminRepository) Get(ctx context.Context, userID int64) (*entity.Admin, error) {
+	var admin entity.Admin
+	err := s.db.WithContext(ctx).Where("user_id = ?", userID).First(&admin).Error
+	return &admin, err
+}
+
+func (s *AdminRepository) GetAll(ctx context.Context) ([]entity.Admin, error) {
+	var admins []entity.Admin
+	err := s.db.WithContext(ctx).Order("created_at").Find(&admins).Error
+	return admins, err
+}
+
+// Save creates the admin or updates the role of the existing one
+func (s *AdminRepository) Save(ctx context.Context, admin *entity.Admin) (*entity.Admin, error) {
+	err := s.db.WithContext(ctx).Save(admin).Error
+	return admin, err
+}
+
+// Delete removes the admin, returns gorm.ErrRecordNotFound if the user is not an admin
+func (s *AdminRepository) Delete(ctx context.Context, userID int64) error {
+	result := s.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entity.Admin{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+type APITokenRepository struct {
+	db *gorm.DB
+}
+
+func NewAPITokenRepository(db *gorm.DB) *APITokenRepository {
+	return &APITokenRepository{
+		db: db,
+	}
+}
+
+func (s *APITokenRepository) Create(ctx context.Context, token *entity.APIToken) (*entity.APIToken, error) {
+	err := s.db.WithContext(ctx).Omit("Club").Create(token).Error
+	return token, err
+}
+
+func (s *APITokenRepository) Get(ctx context.Context, id string) (*entity.APIToken, error) {
+	var token entity.APIToken
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&token).Error
+	return &token, err
+}
+
+// GetByHash returns the token with the given hash, revoked tokens are returned as well
+func (s *APITokenRepository) GetByHash(ctx context.Context, hash string) (*entity.APIToken, error) {
+	var token entity.APIToken
+	err := s.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error
+	return &token, err
+}
+
+func (s *APITokenRepository) GetByClubID(ctx context.Context, clubID string) ([]entity.APIToken, error) {
+	var tokens []entity.APIToken
+	err := s.db.WithContext(ctx).
+		Where("club_id = ?", clubID).
+		Order("created_at ASC").
+		Find(&tokens).Error
+	return tokens, err
+}
+
+func (s *APITokenRepository) Update(ctx context.Context, token *entity.APIToken) (*entity.APIToken, error) {
+	err := s.db.WithContext(ctx).Omit("Club").Save(token).Error
+	return token, err
+}
+
+// UpdateLastUsedAt updates only the last usage time, so concurrent requests don't overwrite other fields
+func (s *APITokenRepository) UpdateLastUsedAt(ctx context.Context, id string, lastUsedAt time.Time) error {
+	return s.db.WithContext(ctx).
+		Model(&entity.APIToken{}).
+		Where("id = ?", id).
+		Update("last_used_at", lastUsedAt).Error
+}
+
+type APIRequestLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIRequestLogRepository(db *gorm.DB) *APIRequestLogRepository {
+	return &APIRequestLogRepository{
+		db: db,
+	}
+}
+
+func (s *APIRequestLogRepository) Create(ctx context.Context, log *entity.APIRequestLog) error {
+	return s.db.WithContext(ctx).Create(log).Error
+}
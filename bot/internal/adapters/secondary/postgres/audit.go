@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{
+		db: db,
+	}
+}
+
+func (s *AuditRepository) Create(ctx context.Context, entry *entity.AuditEntry) (*entity.AuditEntry, error) {
+	err := s.db.WithContext(ctx).Create(entry).Error
+	return entry, err
+}
+
+// Search returns the newest entries matching the filter
+func (s *AuditRepository) Search(ctx context.Context, filter dto.AuditFilter) ([]entity.AuditEntry, error) {
+	query := s.db.WithContext(ctx).Model(&entity.AuditEntry{})
+	if filter.ActorID != 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var entries []entity.AuditEntry
+	err := query.Order("created_at DESC").Find(&entries).Error
+	return entries, err
+}
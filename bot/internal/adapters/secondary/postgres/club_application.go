@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+type ClubApplicationRepository struct {
+	db *gorm.DB
+}
+
+func NewClubApplicationRepository(db *gorm.DB) *ClubApplicationRepository {
+	return &ClubApplicationRepository{
+		db: db,
+	}
+}
+
+func (s *ClubApplicationRepository) Create(ctx context.Context, application *entity.ClubApplication) (*entity.ClubApplication, error) {
+	err := s.db.WithContext(ctx).Create(application).Error
+	return application, err
+}
+
+func (s *ClubApplicationRepository) Get(ctx context.Context, id string) (*entity.ClubApplication, error) {
+	var application entity.ClubApplication
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&application).Error
+	return &application, err
+}
+
+func (s *ClubApplicationRepository) Update(ctx context.Context, application *entity.ClubApplication) (*entity.ClubApplication, error) {
+	err := s.db.WithContext(ctx).Save(application).Error
+	return application, err
+}
+
+// GetPending returns the submitted applications waiting for the review, the oldest first
+func (s *ClubApplicationRepository) GetPending(ctx context.Context) ([]entity.ClubApplication, error) {
+	var applications []entity.ClubApplication
+	err := s.db.WithContext(ctx).
+		Where("status = ?", entity.ClubApplicationPending).
+		Order("created_at").
+		Find(&applications).Error
+	return applications, err
+}
+
+// CountPendingByApplicantID returns the number of the submitted applications of the user waiting for the review
+func (s *ClubApplicationRepository) CountPendingByApplicantID(ctx context.Context, applicantID int64) (int64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).
+		Model(&entity.ClubApplication{}).
+		Where("applicant_id = ? AND status = ?", applicantID, entity.ClubApplicationPending).
+		Count(&count).Error
+	return count, err
+}
+
+// Approve marks the application approved and creates the club with its owners in one transaction.
+// Returns errorz.ErrClubApplicationReviewed if the application is not waiting for the review anymore.
+func (s *ClubApplicationRepository) Approve(
+	ctx context.Context,
+	application *entity.ClubApplication,
+	club *entity.Club,
+	owners []entity.ClubOwner,
+) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// the status is changed first: it locks the application row, so concurrent reviews wait
+		// for the transaction and then find the application already reviewed
+		if err := s.review(tx, application); err != nil {
+			return err
+		}
+
+		if err := tx.Create(club).Error; err != nil {
+			return err
+		}
+
+		for i := range owners {
+			owners[i].ClubID = club.ID
+		}
+		if len(owners) > 0 {
+			if err := tx.Create(&owners).Error; err != nil {
+				return err
+			}
+		}
+
+		application.ClubID = &club.ID
+		return tx.Model(application).Update("club_id", club.ID).Error
+	})
+}
+
+// Reject saves the rejected application.
+// Returns errorz.ErrClubApplicationReviewed if the application is not waiting for the review anymore.
+func (s *ClubApplicationRepository) Reject(ctx context.Context, application *entity.ClubApplication) error {
+	return s.review(s.db.WithContext(ctx), application)
+}
+
+// review saves the result of the review only if the application is still pending
+func (s *ClubApplicationRepository) review(tx *gorm.DB, application *entity.ClubApplication) error {
+	result := tx.Model(&entity.ClubApplication{}).
+		Where("id = ? AND status = ?", application.ID, entity.ClubApplicationPending).
+		Updates(map[string]interface{}{
+			"status":      application.Status,
+			"reviewer_id": application.ReviewerID,
+			"reviewed_at": application.ReviewedAt,
+			"comment":     application.Comment,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errorz.ErrClubApplicationReviewed
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+type ClubFollowerRepository struct {
+	db *gorm.DB
+}
+
+func NewClubFollowerRepository(db *gorm.DB) *ClubFollowerRepository {
+	return &ClubFollowerRepository{
+		db: db,
+	}
+}
+
+// Create is a function that makes the user a follower of the club, following twice is not an error.
+func (s *ClubFollowerRepository) Create(ctx context.Context, follower *entity.ClubFollower) error {
+	return s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(follower).Error
+}
+
+func (s *ClubFollowerRepository) Delete(ctx context.Context, userID int64, clubID string) error {
+	return s.db.WithContext(ctx).
+		Where("user_id = ? AND club_id = ?", userID, clubID).
+		Delete(&entity.ClubFollower{}).Error
+}
+
+func (s *ClubFollowerRepository) Exists(ctx context.Context, userID int64, clubID string) (bool, error) {
+	var count int64
+	err := s.db.WithContext(ctx).
+		Model(&entity.ClubFollower{}).
+		Where("user_id = ? AND club_id = ?", userID, clubID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetClubsByUserID is a function that returns clubs followed by the user.
+func (s *ClubFollowerRepository) GetClubsByUserID(ctx context.Context, userID int64) ([]entity.Club, error) {
+	var clubs []entity.Club
+	err := s.db.WithContext(ctx).
+		Joins("inner join club_followers on club_followers.club_id = clubs.id").
+		Where("club_followers.user_id = ?", userID).
+		Order("clubs.name").
+		Find(&clubs).Error
+	return clubs, err
+}
+
+func (s *ClubFollowerRepository) CountByClubID(ctx context.Context, clubID string) (int64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).
+		Model(&entity.ClubFollower{}).
+		Where("club_id = ?", clubID).
+		Count(&count).Error
+	return count, err
+}
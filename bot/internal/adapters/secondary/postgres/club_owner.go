@@ -5,7 +5,9 @@ import (
 	"fmt"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/valueobject"
@@ -64,22 +66,75 @@ func (s *ClubOwnerRepository) Update(ctx context.Context, clubOwner *entity.Club
 	return clubOwner, err
 }
 
+// Transfer makes the recipient a co-owner of the club and removes the former owner in one transaction
+func (s *ClubOwnerRepository) Transfer(ctx context.Context, clubID string, fromUserID, toUserID int64) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "club_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"role": valueobject.ClubCoOwner}),
+		}).Create(&entity.ClubOwner{UserID: toUserID, ClubID: clubID, Role: valueobject.ClubCoOwner}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("club_id = ? AND user_id = ?", clubID, fromUserID).Delete(&entity.ClubOwner{}).Error
+	})
+}
+
+// Leave removes the user from the club staff, the staff rows of the club are locked,
+// so concurrent leaves never remove the last co-owner. Returns errorz.ErrLastClubOwner for the last co-owner
+func (s *ClubOwnerRepository) Leave(ctx context.Context, clubID string, userID int64) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var owners []entity.ClubOwner
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("club_id = ?", clubID).
+			Find(&owners).Error
+		if err != nil {
+			return err
+		}
+
+		var (
+			found     bool
+			coOwners  int
+			isCoOwner bool
+		)
+		for _, owner := range owners {
+			if owner.Role == valueobject.ClubCoOwner {
+				coOwners++
+			}
+			if owner.UserID == userID {
+				found = true
+				isCoOwner = owner.Role == valueobject.ClubCoOwner
+			}
+		}
+		if !found {
+			return gorm.ErrRecordNotFound
+		}
+		if isCoOwner && coOwners == 1 {
+			return errorz.ErrLastClubOwner
+		}
+
+		return tx.Where("club_id = ? AND user_id = ?", clubID, userID).Delete(&entity.ClubOwner{}).Error
+	})
+}
+
 func (s *ClubOwnerRepository) GetByClubID(ctx context.Context, clubID string) ([]dto.ClubOwner, error) {
 	type RawClubOwner struct {
-		ClubID   string `gorm:"column:club_id"`
-		UserID   int64  `gorm:"column:user_id"`
-		Username string `gorm:"column:username"`
-		Warnings bool   `gorm:"column:warnings"`
-		FIO      string `gorm:"column:fio"`
-		Email    string `gorm:"column:email"`
-		Role     string `gorm:"column:role"`
-		IsBanned bool   `gorm:"column:is_banned"`
+		ClubID    string `gorm:"column:club_id"`
+		UserID    int64  `gorm:"column:user_id"`
+		Username  string `gorm:"column:username"`
+		Warnings  bool   `gorm:"column:warnings"`
+		FIO       string `gorm:"column:fio"`
+		Email     string `gorm:"column:email"`
+		Role      string `gorm:"column:role"`
+		OwnerRole string `gorm:"column:owner_role"`
+		IsBanned  bool   `gorm:"column:is_banned"`
 	}
 
 	var rawResult []RawClubOwner
 	err := s.db.WithContext(ctx).
 		Table("club_owners").
-		Select("club_owners.club_id, club_owners.user_id, users.username, club_owners.warnings, users.fio, users.email, users.role, users.is_banned").
+		Select("club_owners.club_id, club_owners.user_id, users.username, club_owners.warnings, users.fio, users.email, users.role, club_owners.role AS owner_role, users.is_banned").
 		Joins("LEFT JOIN users ON users.id = club_owners.user_id").
 		Where("club_owners.club_id = ?", clubID).
 		Scan(&rawResult).Error
@@ -106,14 +161,15 @@ func (s *ClubOwnerRepository) GetByClubID(ctx context.Context, clubID string) ([
 		role := valueobject.Role(raw.Role)
 
 		result[i] = dto.ClubOwner{
-			ClubID:   raw.ClubID,
-			UserID:   raw.UserID,
-			Username: raw.Username,
-			FIO:      fio,
-			Email:    email,
-			Role:     role,
-			IsBanned: raw.IsBanned,
-			Warnings: raw.Warnings,
+			ClubID:    raw.ClubID,
+			UserID:    raw.UserID,
+			Username:  raw.Username,
+			FIO:       fio,
+			Email:     email,
+			Role:      role,
+			OwnerRole: valueobject.ClubOwnerRole(raw.OwnerRole),
+			IsBanned:  raw.IsBanned,
+			Warnings:  raw.Warnings,
 		}
 	}
 
@@ -122,20 +178,21 @@ func (s *ClubOwnerRepository) GetByClubID(ctx context.Context, clubID string) ([
 
 func (s *ClubOwnerRepository) GetByUserID(ctx context.Context, userID int64) ([]dto.ClubOwner, error) {
 	type RawClubOwner struct {
-		ClubID   string `gorm:"column:club_id"`
-		UserID   int64  `gorm:"column:user_id"`
-		Username string `gorm:"column:username"`
-		Warnings bool   `gorm:"column:warnings"`
-		FIO      string `gorm:"column:fio"`
-		Email    string `gorm:"column:email"`
-		Role     string `gorm:"column:role"`
-		IsBanned bool   `gorm:"column:is_banned"`
+		ClubID    string `gorm:"column:club_id"`
+		UserID    int64  `gorm:"column:user_id"`
+		Username  string `gorm:"column:username"`
+		Warnings  bool   `gorm:"column:warnings"`
+		FIO       string `gorm:"column:fio"`
+		Email     string `gorm:"column:email"`
+		Role      string `gorm:"column:role"`
+		OwnerRole string `gorm:"column:owner_role"`
+		IsBanned  bool   `gorm:"column:is_banned"`
 	}
 
 	var rawResult []RawClubOwner
 	err := s.db.WithContext(ctx).
 		Table("club_owners").
-		Select("club_owners.club_id, club_owners.user_id, users.username, club_owners.warnings, users.fio, users.email, users.role, users.is_banned").
+		Select("club_owners.club_id, club_owners.user_id, users.username, club_owners.warnings, users.fio, users.email, users.role, club_owners.role AS owner_role, users.is_banned").
 		Joins("LEFT JOIN users ON users.id = club_owners.user_id").
 		Where("club_owners.user_id = ?", userID).
 		Scan(&rawResult).Error
@@ -162,14 +219,15 @@ func (s *ClubOwnerRepository) GetByUserID(ctx context.Context, userID int64) ([]
 		role := valueobject.Role(raw.Role)
 
 		result[i] = dto.ClubOwner{
-			ClubID:   raw.ClubID,
-			UserID:   raw.UserID,
-			Username: raw.Username,
-			FIO:      fio,
-			Email:    email,
-			Role:     role,
-			IsBanned: raw.IsBanned,
-			Warnings: raw.Warnings,
+			ClubID:    raw.ClubID,
+			UserID:    raw.UserID,
+			Username:  raw.Username,
+			FIO:       fio,
+			Email:     email,
+			Role:      role,
+			OwnerRole: valueobject.ClubOwnerRole(raw.OwnerRole),
+			IsBanned:  raw.IsBanned,
+			Warnings:  raw.Warnings,
 		}
 	}
 
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/valueobject"
+)
+
+func TestClubOwnerRepository_Leave_Concurrent(t *testing.T) {
+	const coOwners = 2
+
+	db := testDB(t)
+	ctx := context.Background()
+
+	club := &entity.Club{Name: "test-club-" + uuid.NewString()}
+	if err := db.Create(club).Error; err != nil {
+		t.Fatalf("create club: %v", err)
+	}
+
+	repo := NewClubOwnerRepository(db)
+	fio, _ := valueobject.NewFIO("Test", "User", "")
+	firstUserID := time.Now().UnixNano() % 1_000_000_000_000
+	userIDs := make([]int64, 0, coOwners)
+	for i := 0; i < coOwners; i++ {
+		user := &entity.User{ID: firstUserID + int64(i), Role: valueobject.Student, FIO: fio}
+		if err := db.Create(user).Error; err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+		userIDs = append(userIDs, user.ID)
+
+		_, err := repo.Create(ctx, &entity.ClubOwner{ClubID: club.ID, UserID: user.ID, Role: valueobject.ClubCoOwner})
+		if err != nil {
+			t.Fatalf("create club owner: %v", err)
+		}
+	}
+
+	t.Cleanup(func() {
+		db.Where("club_id = ?", club.ID).Delete(&entity.ClubOwner{})
+		db.Where("id IN ?", userIDs).Delete(&entity.User{})
+		db.Unscoped().Delete(club)
+	})
+
+	errs := make([]error, coOwners)
+	var wg sync.WaitGroup
+	for i, userID := range userIDs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = repo.Leave(ctx, club.ID, userID)
+		}()
+	}
+	wg.Wait()
+
+	last := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+		case errors.Is(err, errorz.ErrLastClubOwner):
+			last++
+		default:
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if last != 1 {
+		t.Errorf("got %d ErrLastClubOwner, want 1", last)
+	}
+
+	owners, err := repo.GetByClubID(ctx, club.ID)
+	if err != nil {
+		t.Fatalf("get club owners: %v", err)
+	}
+	if len(owners) != 1 {
+		t.Errorf("got %d club owners, want 1", len(owners))
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
 )
@@ -112,7 +114,7 @@ func (s *EventRepository) GetByClubID(ctx context.Context, limit, offset int, cl
 
 // GetFutureByClubID retrieves future events for a specific club from the database.
 // The events are filtered by club ID and a start time greater than the current time
-// minus the additional time parameter. The results are ordered and paginated
+// minus the additional time parameter, cancelled events are skipped. The results are ordered and paginated
 // according to the provided parameters.
 //
 // Parameters:
@@ -137,6 +139,7 @@ func (s *EventRepository) GetFutureByClubID(
 	var events []entity.Event
 	err := s.db.WithContext(ctx).
 		Where("club_id = ? AND start_time > ?", clubID, time.Now().In(location.Location()).Add(-additionalTime)).
+		Where("cancelled_at IS NULL").
 		Order(order).
 		Limit(limit).
 		Offset(offset).
@@ -144,11 +147,12 @@ func (s *EventRepository) GetFutureByClubID(
 	return events, err
 }
 
-// GetUpcomingEvents returns all events that start before the given time
+// GetUpcomingEvents returns all not cancelled events that start before the given time
 func (s *EventRepository) GetUpcomingEvents(ctx context.Context, before time.Time) ([]entity.Event, error) {
 	var events []entity.Event
 	err := s.db.WithContext(ctx).
 		Where("start_time <= ? AND start_time > ?", before.In(location.Location()), time.Now().In(location.Location())).
+		Where("cancelled_at IS NULL").
 		Find(&events).Error
 	return events, err
 }
@@ -167,23 +171,81 @@ func (s *EventRepository) Update(ctx context.Context, event *entity.Event) (*ent
 	return event, err
 }
 
+// Cancel marks the event as cancelled and cancels the active entries of its waitlist in one transaction.
+//
+// The event row is locked like in EventParticipantRepository.CreateWithCheck, so registrations
+// either complete before the cancellation or see the event cancelled.
+// Returns errorz.ErrEventCancelled with the event if it is already cancelled.
+func (s *EventRepository) Cancel(ctx context.Context, id string, reason string) (*entity.Event, error) {
+	var event entity.Event
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&event).Error; err != nil {
+			return err
+		}
+		if event.IsCancelled() {
+			return errorz.ErrEventCancelled
+		}
+
+		event.Cancel(reason)
+		if err := tx.Save(&event).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&entity.WaitlistEntry{}).
+			Where("event_id = ? AND status IN ?", id, []entity.WaitlistStatus{entity.WaitlistStatusWaiting, entity.WaitlistStatusOffered}).
+			Update("status", entity.WaitlistStatusCancelled).Error
+	})
+
+	return &event, err
+}
+
 // Delete is a function that deletes an event from the database.
 func (s *EventRepository) Delete(ctx context.Context, id string) error {
 	err := s.db.WithContext(ctx).Where("id = ?", id).Delete(&entity.Event{}).Error
 	return err
 }
 
+// GetFutureBySeriesID returns not cancelled occurrences of the series that start after the given time
+func (s *EventRepository) GetFutureBySeriesID(ctx context.Context, seriesID string, after time.Time) ([]entity.Event, error) {
+	var events []entity.Event
+	err := s.db.WithContext(ctx).
+		Where("series_id = ? AND start_time > ? AND cancelled_at IS NULL", seriesID, after).
+		Order("start_time ASC").
+		Find(&events).Error
+	return events, err
+}
+
 // Count is a function that gets the count of events from the database.
+// Every series is counted once (see openEventsQuery).
 func (s *EventRepository) Count(ctx context.Context, role string) (int64, error) {
 	var count int64
-	query := s.db.WithContext(ctx).Model(&entity.Event{}).
-		Where("registration_end > ?", time.Now().In(location.Location())).
-		Where("? = ANY(allowed_roles)", role)
+	query := s.openEventsQuery(ctx, role)
 
 	err := query.Count(&count).Error
 	return count, err
 }
 
+// openEventsQuery builds a query for not cancelled events open for registration.
+// Only the nearest open occurrence of every series is returned, so a series takes one line in lists.
+// If role is empty, it will return events with any role.
+func (s *EventRepository) openEventsQuery(ctx context.Context, role string) *gorm.DB {
+	filter := func(query *gorm.DB) *gorm.DB {
+		query = query.Where("registration_end > ? AND cancelled_at IS NULL", time.Now().In(location.Location()))
+		if role != "" {
+			query = query.Where("? = ANY(allowed_roles)", role)
+		}
+		return query
+	}
+
+	nearestOccurrences := filter(s.db.WithContext(ctx).Model(&entity.Event{})).
+		Select("DISTINCT ON (series_id) id").
+		Where("series_id IS NOT NULL").
+		Order("series_id, start_time")
+
+	return filter(s.db.WithContext(ctx).Model(&entity.Event{})).
+		Where("series_id IS NULL OR id IN (?)", nearestOccurrences)
+}
+
 func (s *EventRepository) CountByClubID(ctx context.Context, clubID string) (int64, error) {
 	var count int64
 
@@ -197,14 +259,7 @@ func (s *EventRepository) CountByClubID(ctx context.Context, clubID string) (int
 // If role is empty, it will return events with any role.
 func (s *EventRepository) GetWithPagination(ctx context.Context, limit, offset int, order string, role string, userID int64) ([]dto.Event, error) {
 	// Create the base query with all conditions
-	baseQuery := s.db.WithContext(ctx).
-		Model(&entity.Event{}). // Use Model() to ensure deleted_at IS NULL filter
-		Where("registration_end > ?", time.Now().In(location.Location()))
-
-	// Apply role filtering if specified
-	if role != "" {
-		baseQuery = baseQuery.Where("? = ANY(allowed_roles)", role)
-	}
+	baseQuery := s.openEventsQuery(ctx, role)
 
 	// Apply ordering and pagination to get the correct subset of events
 	var eventIDs []string
@@ -2,13 +2,16 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/secondary"
 )
 
 type EventParticipantRepository struct {
@@ -48,6 +51,47 @@ func (s *EventParticipantRepository) Create(ctx context.Context, eventParticipan
 	return eventParticipant, err
 }
 
+// CreateWithCheck creates a participant while holding a row lock on the event.
+//
+// Concurrent registrations for the same event are serialized, so check always sees
+// the actual list of participants and capacity can't be exceeded.
+func (s *EventParticipantRepository) CreateWithCheck(ctx context.Context, eventParticipant *entity.EventParticipant, check secondary.RegistrationCheck) (*entity.EventParticipant, error) {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var event entity.Event
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", eventParticipant.EventID).First(&event).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("event with id %s not found", eventParticipant.EventID)
+			}
+			return err
+		}
+
+		var user entity.User
+		if err := tx.Where("id = ?", eventParticipant.UserID).First(&user).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("user with id %d not found", eventParticipant.UserID)
+			}
+			return err
+		}
+
+		var participants []entity.User
+		if err := tx.
+			Joins("JOIN event_participants ON event_participants.user_id = users.id").
+			Where("event_participants.event_id = ?", eventParticipant.EventID).
+			Find(&participants).Error; err != nil {
+			return err
+		}
+
+		if err := check(&event, &user, participants); err != nil {
+			return err
+		}
+
+		return tx.Create(&eventParticipant).Error
+	})
+
+	return eventParticipant, err
+}
+
 func (s *EventParticipantRepository) Get(ctx context.Context, eventID string, userID int64) (*entity.EventParticipant, error) {
 	var eventParticipant entity.EventParticipant
 	err := s.db.WithContext(ctx).Where("event_id = ? AND user_id = ?", eventID, userID).First(&eventParticipant).Error
@@ -64,6 +108,17 @@ func (s *EventParticipantRepository) Delete(ctx context.Context, eventID string,
 	return err
 }
 
+// GetUpcomingEventIDs returns the IDs of the events the user is registered for which start after the given time
+func (s *EventParticipantRepository) GetUpcomingEventIDs(ctx context.Context, userID int64, after time.Time) ([]string, error) {
+	var eventIDs []string
+	err := s.db.WithContext(ctx).
+		Model(&entity.EventParticipant{}).
+		Joins("JOIN events ON events.id = event_participants.event_id AND events.deleted_at IS NULL").
+		Where("event_participants.user_id = ? AND events.start_time > ?", userID, after).
+		Pluck("event_participants.event_id", &eventIDs).Error
+	return eventIDs, err
+}
+
 func (s *EventParticipantRepository) GetByEventID(ctx context.Context, eventID string) ([]entity.EventParticipant, error) {
 	var eventParticipants []entity.EventParticipant
 	err := s.db.WithContext(ctx).Where("event_id = ?", eventID).Find(&eventParticipants).Error
@@ -82,6 +137,17 @@ func (s *EventParticipantRepository) CountVisitedByEventID(ctx context.Context,
 	return count, err
 }
 
+// CountByUserAndClubID returns the number of registrations of the user for events of the club
+func (s *EventParticipantRepository) CountByUserAndClubID(ctx context.Context, userID int64, clubID string) (int64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).
+		Model(&entity.EventParticipant{}).
+		Joins("inner join events on events.id = event_participants.event_id").
+		Where("event_participants.user_id = ? AND events.club_id = ?", userID, clubID).
+		Count(&count).Error
+	return count, err
+}
+
 // GetUserEvents returns events that user with given id has registered on, with pagination.
 // It returns events in the order of start_time (upcoming first, then past).
 // If user has registered on more events than limit, it returns only first limit events.
@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	postgresDriver "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/valueobject"
+)
+
+// testDB connects to the database from TEST_POSTGRES_DSN and runs the migrations,
+// the test is skipped if the variable is not set
+func testDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+
+	db, err := gorm.Open(postgresDriver.Open(dsn), &gorm.Config{
+		Logger:         logger.Default.LogMode(logger.Silent),
+		TranslateError: true,
+	})
+	if err != nil {
+		t.Fatalf("connect to postgres: %v", err)
+	}
+	if err := Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	return db
+}
+
+func TestEventParticipantRepository_CreateWithCheck_Concurrent(t *testing.T) {
+	const (
+		registrations   = 20
+		maxParticipants = 3
+	)
+
+	db := testDB(t)
+	ctx := context.Background()
+
+	club := &entity.Club{Name: "test-club-" + uuid.NewString()}
+	if err := db.Create(club).Error; err != nil {
+		t.Fatalf("create club: %v", err)
+	}
+	event := &entity.Event{
+		ClubID:          club.ID,
+		Name:            "test event",
+		Description:     "test event",
+		Location:        "test location",
+		StartTime:       time.Now().Add(48 * time.Hour),
+		RegistrationEnd: time.Now().Add(24 * time.Hour),
+		MaxParticipants: maxParticipants,
+	}
+	if err := db.Create(event).Error; err != nil {
+		t.Fatalf("create event: %v", err)
+	}
+
+	fio, _ := valueobject.NewFIO("Test", "User", "")
+	firstUserID := time.Now().UnixNano() % 1_000_000_000_000
+	userIDs := make([]int64, 0, registrations)
+	for i := 0; i < registrations; i++ {
+		user := &entity.User{ID: firstUserID + int64(i), Role: valueobject.Student, FIO: fio}
+		if err := db.Create(user).Error; err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+		userIDs = append(userIDs, user.ID)
+	}
+
+	t.Cleanup(func() {
+		db.Where("event_id = ?", event.ID).Delete(&entity.EventParticipant{})
+		db.Unscoped().Delete(event)
+		db.Where("id IN ?", userIDs).Delete(&entity.User{})
+		db.Unscoped().Delete(club)
+	})
+
+	check := func(event *entity.Event, _ *entity.User, participants []entity.User) error {
+		if len(participants) >= event.MaxParticipants {
+			return errorz.ErrEventFull
+		}
+		return nil
+	}
+
+	repo := NewEventParticipantRepository(db)
+	errs := make([]error, registrations)
+	var wg sync.WaitGroup
+	for i, userID := range userIDs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = repo.CreateWithCheck(ctx, &entity.EventParticipant{
+				EventID: event.ID,
+				UserID:  userID,
+			}, check)
+		}()
+	}
+	wg.Wait()
+
+	full := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+		case errors.Is(err, errorz.ErrEventFull):
+			full++
+		default:
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if full != registrations-maxParticipants {
+		t.Errorf("got %d ErrEventFull, want %d", full, registrations-maxParticipants)
+	}
+
+	count, err := repo.CountByEventID(ctx, event.ID)
+	if err != nil {
+		t.Fatalf("count participants: %v", err)
+	}
+	if count != maxParticipants {
+		t.Errorf("got %d participants, want %d", count, maxParticipants)
+	}
+}
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+type EventSeriesRepository struct {
+	db *gorm.DB
+}
+
+func NewEventSeriesRepository(db *gorm.DB) *EventSeriesRepository {
+	return &EventSeriesRepository{
+		db: db,
+	}
+}
+
+func (s *EventSeriesRepository) Create(ctx context.Context, series *entity.EventSeries) (*entity.EventSeries, error) {
+	err := s.db.WithContext(ctx).Create(series).Error
+	return series, err
+}
+
+func (s *EventSeriesRepository) Get(ctx context.Context, id string) (*entity.EventSeries, error) {
+	var series entity.EventSeries
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&series).Error
+	return &series, err
+}
+
+// Update saves the series template, LastOccurrenceAt is only saved by CreateOccurrences,
+// so a stale copy never moves the materialisation back
+func (s *EventSeriesRepository) Update(ctx context.Context, series *entity.EventSeries) (*entity.EventSeries, error) {
+	err := s.db.WithContext(ctx).Omit("LastOccurrenceAt").Save(series).Error
+	return series, err
+}
+
+// GetActive returns weekly and biweekly series that still have occurrences to materialise
+func (s *EventSeriesRepository) GetActive(ctx context.Context, now time.Time) ([]entity.EventSeries, error) {
+	var series []entity.EventSeries
+	err := s.db.WithContext(ctx).
+		Where("rule IN ? AND until > ?", []entity.RecurrenceRule{entity.RecurrenceWeekly, entity.RecurrenceBiweekly}, now).
+		Find(&series).Error
+	return series, err
+}
+
+// CreateOccurrences creates the occurrences and saves the start of the last one as LastOccurrenceAt
+// in one transaction, so a failed run never leaves occurrences that would be created again
+func (s *EventSeriesRepository) CreateOccurrences(ctx context.Context, series *entity.EventSeries, occurrences []entity.Event) error {
+	if len(occurrences) == 0 {
+		return nil
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&occurrences).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&entity.EventSeries{}).
+			Where("id = ?", series.ID).
+			Update("last_occurrence_at", occurrences[len(occurrences)-1].StartTime).Error
+	})
+}
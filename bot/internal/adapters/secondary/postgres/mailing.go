@@ -0,0 +1,189 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+type MailingRepository struct {
+	db *gorm.DB
+}
+
+func NewMailingRepository(db *gorm.DB) *MailingRepository {
+	return &MailingRepository{
+		db: db,
+	}
+}
+
+func (s *MailingRepository) Create(ctx context.Context, mailing *entity.Mailing) (*entity.Mailing, error) {
+	err := s.db.WithContext(ctx).Omit("Recipients").Create(mailing).Error
+	return mailing, err
+}
+
+// UpdateScheduled saves the text, the send time and the cancellation of the mailing
+// if it is still scheduled, otherwise errorz.ErrMailingNotScheduled is returned
+func (s *MailingRepository) UpdateScheduled(ctx context.Context, mailing *entity.Mailing) (*entity.Mailing, error) {
+	result := s.db.WithContext(ctx).
+		Model(&entity.Mailing{}).
+		Where("id = ? AND sent_at IS NULL AND cancelled_at IS NULL", mailing.ID).
+		Updates(map[string]interface{}{
+			"text":         mailing.Text,
+			"scheduled_at": mailing.ScheduledAt,
+			"cancelled_at": mailing.CancelledAt,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errorz.ErrMailingNotScheduled
+	}
+
+	return mailing, nil
+}
+
+// Dispatch marks the mailing as sent and stores its recipients and outbox messages in one transaction.
+// The mailing is claimed only if it is neither sent nor cancelled, otherwise errorz.ErrMailingNotScheduled is returned,
+// so a mailing is never sent twice. Recipients and messages are inserted in batches,
+// so large audiences don't exceed the query parameters limit
+func (s *MailingRepository) Dispatch(
+	ctx context.Context,
+	mailing *entity.Mailing,
+	recipients []entity.MailingRecipient,
+	messages []entity.OutboxMessage,
+) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Mailing{}).
+			Where("id = ? AND sent_at IS NULL AND cancelled_at IS NULL", mailing.ID).
+			Update("sent_at", mailing.SentAt)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errorz.ErrMailingNotScheduled
+		}
+
+		if len(recipients) > 0 {
+			if err := tx.CreateInBatches(recipients, outboxBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		if len(messages) > 0 {
+			if err := tx.CreateInBatches(messages, outboxBatchSize).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *MailingRepository) Get(ctx context.Context, id string) (*entity.Mailing, error) {
+	var mailing entity.Mailing
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&mailing).Error
+	return &mailing, err
+}
+
+// GetScheduledByClubID returns mailings of the club waiting to be sent
+func (s *MailingRepository) GetScheduledByClubID(ctx context.Context, clubID string) ([]entity.Mailing, error) {
+	var mailings []entity.Mailing
+	err := s.db.WithContext(ctx).
+		Where("club_id = ? AND scheduled_at IS NOT NULL AND sent_at IS NULL AND cancelled_at IS NULL", clubID).
+		Order("scheduled_at ASC").
+		Find(&mailings).Error
+	return mailings, err
+}
+
+// GetDue returns scheduled mailings that should be sent before the given time
+func (s *MailingRepository) GetDue(ctx context.Context, before time.Time) ([]entity.Mailing, error) {
+	var mailings []entity.Mailing
+	err := s.db.WithContext(ctx).
+		Where("scheduled_at <= ? AND sent_at IS NULL AND cancelled_at IS NULL", before).
+		Order("scheduled_at ASC").
+		Find(&mailings).Error
+	return mailings, err
+}
+
+func (s *MailingRepository) GetByClubID(ctx context.Context, clubID string, limit, offset int) ([]entity.Mailing, error) {
+	var mailings []entity.Mailing
+	err := s.db.WithContext(ctx).
+		Where("club_id = ?", clubID).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&mailings).Error
+	return mailings, err
+}
+
+func (s *MailingRepository) CountByClubID(ctx context.Context, clubID string) (int64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&entity.Mailing{}).Where("club_id = ?", clubID).Count(&count).Error
+	return count, err
+}
+
+func (s *MailingRepository) GetStats(ctx context.Context, id string) (dto.MailingStats, error) {
+	var rows []struct {
+		Status entity.MailingRecipientStatus
+		Count  int
+	}
+	err := s.db.WithContext(ctx).
+		Model(&entity.MailingRecipient{}).
+		Select("status, COUNT(*) AS count").
+		Where("mailing_id = ?", id).
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return dto.MailingStats{}, err
+	}
+
+	var stats dto.MailingStats
+	for _, row := range rows {
+		stats.Total += row.Count
+		switch row.Status {
+		case entity.MailingRecipientPending:
+			stats.Pending = row.Count
+		case entity.MailingRecipientSent:
+			stats.Sent = row.Count
+		case entity.MailingRecipientBlocked:
+			stats.Blocked = row.Count
+		case entity.MailingRecipientError:
+			stats.Error = row.Count
+		case entity.MailingRecipientSkipped:
+			stats.Skipped = row.Count
+		}
+	}
+	return stats, nil
+}
+
+func (s *MailingRepository) GetRecipientsByStatus(
+	ctx context.Context,
+	id string,
+	statuses ...entity.MailingRecipientStatus,
+) ([]entity.MailingRecipient, error) {
+	var recipients []entity.MailingRecipient
+	err := s.db.WithContext(ctx).
+		Where("mailing_id = ? AND status IN ?", id, statuses).
+		Find(&recipients).Error
+	return recipients, err
+}
+
+func (s *MailingRepository) UpdateRecipientStatus(
+	ctx context.Context,
+	id string,
+	userIDs []int64,
+	status entity.MailingRecipientStatus,
+	errText string,
+) error {
+	return s.db.WithContext(ctx).
+		Model(&entity.MailingRecipient{}).
+		Where("mailing_id = ? AND user_id IN ?", id, userIDs).
+		Updates(map[string]interface{}{
+			"status": status,
+			"error":  errText,
+		}).Error
+}
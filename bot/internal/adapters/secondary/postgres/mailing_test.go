@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+func TestMailingRepository_DispatchOnce(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+
+	club := &entity.Club{Name: "test-club-" + uuid.NewString()}
+	if err := db.Create(club).Error; err != nil {
+		t.Fatalf("create club: %v", err)
+	}
+
+	repo := NewMailingRepository(db)
+	scheduledAt := time.Now().Add(-time.Minute)
+	mailing, err := repo.Create(ctx, &entity.Mailing{
+		ClubID:         club.ID,
+		Audience:       entity.MailingAudienceClub,
+		MessageContent: entity.MessageContent{Kind: entity.MessageText, Text: "test"},
+		ScheduledAt:    &scheduledAt,
+	})
+	if err != nil {
+		t.Fatalf("create mailing: %v", err)
+	}
+
+	t.Cleanup(func() {
+		db.Where("mailing_id = ?", mailing.ID).Delete(&entity.OutboxMessage{})
+		db.Delete(mailing)
+		db.Unscoped().Delete(club)
+	})
+
+	stale := *mailing
+	mailing.MarkSent()
+	if err = repo.Dispatch(ctx, mailing, nil, nil); err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+
+	if err = repo.Dispatch(ctx, mailing, nil, nil); !errors.Is(err, errorz.ErrMailingNotScheduled) {
+		t.Errorf("second dispatch: got %v, want ErrMailingNotScheduled", err)
+	}
+
+	stale.Text = "edited"
+	if _, err = repo.UpdateScheduled(ctx, &stale); !errors.Is(err, errorz.ErrMailingNotScheduled) {
+		t.Errorf("update of sent mailing: got %v, want ErrMailingNotScheduled", err)
+	}
+
+	stored, err := repo.Get(ctx, mailing.ID)
+	if err != nil {
+		t.Fatalf("get mailing: %v", err)
+	}
+	if stored.SentAt == nil || stored.Text != "test" {
+		t.Errorf("sent mailing was overwritten: sent_at=%v, text=%q", stored.SentAt, stored.Text)
+	}
+}
@@ -1,15 +1,75 @@
 package postgres
 
-import "github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+import (
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
 
 // Migrations is a list of all gorm migrations for the database.
 var Migrations = []interface{}{
 	&entity.User{},
 	&entity.Club{},
 	&entity.ClubOwner{},
+	&entity.ClubApplication{},
 	&entity.IgnoreMailing{},
+	&entity.ClubFollower{},
+	&entity.EventSeries{},
 	&entity.Event{},
 	&entity.EventParticipant{},
 	&entity.EventNotification{},
 	&entity.Pass{},
+	&entity.PassLocation{},
+	&entity.PassSchedule{},
+	&entity.PassBatch{},
+	&entity.PassBatchDelivery{},
+	&entity.WaitlistEntry{},
+	&entity.APIToken{},
+	&entity.APIRequestLog{},
+	&entity.OutboxMessage{},
+	&entity.Mailing{},
+	&entity.MailingRecipient{},
+	&entity.ShadowBan{},
+	&entity.UserBan{},
+	&entity.Admin{},
+	&entity.AuditEntry{},
+}
+
+// Migrate runs gorm migrations and fills the data of newly created tables.
+func Migrate(db *gorm.DB) error {
+	hasClubFollowers := db.Migrator().HasTable(&entity.ClubFollower{})
+
+	if err := db.AutoMigrate(Migrations...); err != nil {
+		return err
+	}
+
+	if !hasClubFollowers {
+		if err := backfillClubFollowers(db); err != nil {
+			return err
+		}
+	}
+
+	// recipients of the pass schedules were moved to pass locations
+	for _, column := range []string{"email_recipients", "telegram_chat_id"} {
+		if db.Migrator().HasColumn(&entity.PassSchedule{}, column) {
+			if err := db.Migrator().DropColumn(&entity.PassSchedule{}, column); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// backfillClubFollowers makes users who have registered for club events its followers,
+// so that clubs keep their mailing audience after follows were introduced
+func backfillClubFollowers(db *gorm.DB) error {
+	return db.Exec(`
+		INSERT INTO club_followers (user_id, club_id, created_at)
+		SELECT event_participants.user_id, events.club_id, MIN(event_participants.created_at)
+		FROM event_participants
+		INNER JOIN events ON events.id = event_participants.event_id
+		GROUP BY event_participants.user_id, events.club_id
+		ON CONFLICT DO NOTHING
+	`).Error
 }
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// outboxBatchSize limits the number of rows inserted by a single statement
+const outboxBatchSize = 500
+
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{
+		db: db,
+	}
+}
+
+func (s *OutboxRepository) CreateMany(ctx context.Context, messages []entity.OutboxMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	return s.db.WithContext(ctx).CreateInBatches(messages, outboxBatchSize).Error
+}
+
+// ClaimDue locks due messages with SKIP LOCKED, so concurrent workers never claim the same message
+func (s *OutboxRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]entity.OutboxMessage, error) {
+	var messages []entity.OutboxMessage
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", entity.OutboxMessagePending, now).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Find(&messages).Error
+		if err != nil || len(messages) == 0 {
+			return err
+		}
+
+		ids := make([]string, 0, len(messages))
+		for _, message := range messages {
+			ids = append(ids, message.ID)
+		}
+
+		return tx.Model(&entity.OutboxMessage{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", leaseUntil).Error
+	})
+	return messages, err
+}
+
+func (s *OutboxRepository) Update(ctx context.Context, message *entity.OutboxMessage) (*entity.OutboxMessage, error) {
+	err := s.db.WithContext(ctx).Save(message).Error
+	return message, err
+}
+
+func (s *OutboxRepository) DeleteSentBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := s.db.WithContext(ctx).
+		Where("status = ? AND sent_at < ?", entity.OutboxMessageSent, before).
+		Delete(&entity.OutboxMessage{})
+	return result.RowsAffected, result.Error
+}
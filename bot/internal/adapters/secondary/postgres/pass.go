@@ -41,7 +41,7 @@ func (s *PassRepository) UpdatePass(ctx context.Context, pass *entity.Pass) (*en
 // GetPassesByEventID is a function that gets passes by event id.
 func (s *PassRepository) GetPassesByEventID(ctx context.Context, eventID string) ([]entity.Pass, error) {
 	var passes []entity.Pass
-	err := s.db.WithContext(ctx).Where("event_id = ?", eventID).Find(&passes).Error
+	err := s.db.WithContext(ctx).Where("event_id = ?", eventID).Order("created_at").Find(&passes).Error
 	return passes, err
 }
 
@@ -71,11 +71,20 @@ func (s *PassRepository) MarkPassAsSent(ctx context.Context, id string, sentAt t
 	return err
 }
 
-// MarkPassesAsSent is a function that marks multiple passes as sent.
-func (s *PassRepository) MarkPassesAsSent(ctx context.Context, ids []string, sentAt time.Time, emailSent, telegramSent bool) error {
+// MarkPassesAsSent is a function that marks multiple passes as sent in the batch, batchID is nil if the passes are not sent separately.
+func (s *PassRepository) MarkPassesAsSent(ctx context.Context, ids []string, sentAt time.Time, batchID *string) error {
 	err := s.db.WithContext(ctx).Model(&entity.Pass{}).Where("id IN ?", ids).Updates(map[string]interface{}{
-		"status":        entity.PassStatusSent,
-		"sent_at":       sentAt,
+		"status":     entity.PassStatusSent,
+		"sent_at":    sentAt,
+		"batch_id":   batchID,
+		"updated_at": time.Now(),
+	}).Error
+	return err
+}
+
+// UpdateBatchDelivery is a function that sets the delivery channels of the passes sent in the batch.
+func (s *PassRepository) UpdateBatchDelivery(ctx context.Context, batchID string, emailSent, telegramSent bool) error {
+	err := s.db.WithContext(ctx).Model(&entity.Pass{}).Where("batch_id = ?", batchID).Updates(map[string]interface{}{
 		"email_sent":    emailSent,
 		"telegram_sent": telegramSent,
 		"updated_at":    time.Now(),
@@ -83,6 +92,25 @@ func (s *PassRepository) MarkPassesAsSent(ctx context.Context, ids []string, sen
 	return err
 }
 
+// GetUnnotifiedPassesByBatchID is a function that gets the sent passes of the batch whose holders are not notified yet, guest passes are skipped.
+func (s *PassRepository) GetUnnotifiedPassesByBatchID(ctx context.Context, batchID string) ([]entity.Pass, error) {
+	var passes []entity.Pass
+	err := s.db.WithContext(ctx).
+		Where("batch_id = ? AND status = ? AND user_id != 0 AND notified_at IS NULL", batchID, entity.PassStatusSent).
+		Order("created_at").
+		Find(&passes).Error
+	return passes, err
+}
+
+// MarkPassesAsNotified is a function that marks the holders of multiple passes as notified about the sending.
+func (s *PassRepository) MarkPassesAsNotified(ctx context.Context, ids []string, notifiedAt time.Time) error {
+	err := s.db.WithContext(ctx).Model(&entity.Pass{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+		"notified_at": notifiedAt,
+		"updated_at":  time.Now(),
+	}).Error
+	return err
+}
+
 // CreateBulkPasses is a function that creates multiple passes in bulk.
 func (s *PassRepository) CreateBulkPasses(ctx context.Context, passes []entity.Pass) error {
 	err := s.db.WithContext(ctx).Create(&passes).Error
@@ -115,6 +143,38 @@ func (s *PassRepository) CancelPassesByEventAndUser(ctx context.Context, eventID
 	return err
 }
 
+// CancelPendingPassesByUserID is a function that cancels all pending passes of a user.
+func (s *PassRepository) CancelPendingPassesByUserID(ctx context.Context, userID int64) error {
+	err := s.db.WithContext(ctx).Model(&entity.Pass{}).Where("user_id = ? AND status = ?", userID, entity.PassStatusPending).Updates(map[string]interface{}{
+		"status":     entity.PassStatusCancelled,
+		"updated_at": time.Now(),
+	}).Error
+	return err
+}
+
+// GetPassCorrections is a function that gets the passes missing in the sent consolidated reports of events starting after startAfter:
+// pending passes scheduled before scheduledBefore and sent passes cancelled afterwards.
+func (s *PassRepository) GetPassCorrections(ctx context.Context, scheduledBefore, startAfter time.Time) ([]entity.Pass, error) {
+	var passes []entity.Pass
+	err := s.db.WithContext(ctx).
+		Joins("JOIN events ON events.id = passes.event_id AND events.deleted_at IS NULL").
+		Where("events.start_time > ?", startAfter).
+		Where("(passes.status = ? AND passes.scheduled_at <= ?) OR (passes.status = ? AND passes.sent_at IS NOT NULL AND passes.cancellation_sent_at IS NULL)",
+			entity.PassStatusPending, scheduledBefore, entity.PassStatusCancelled).
+		Order("passes.created_at").
+		Find(&passes).Error
+	return passes, err
+}
+
+// MarkPassesCancellationSent is a function that marks the cancellation of multiple sent passes as reported.
+func (s *PassRepository) MarkPassesCancellationSent(ctx context.Context, ids []string, sentAt time.Time) error {
+	err := s.db.WithContext(ctx).Model(&entity.Pass{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+		"cancellation_sent_at": sentAt,
+		"updated_at":           time.Now(),
+	}).Error
+	return err
+}
+
 // GetPassesByRequester is a function that gets passes by requester type and ID with pagination.
 func (s *PassRepository) GetPassesByRequester(ctx context.Context, requesterType entity.PassRequesterType, requesterID string, limit, offset int) ([]entity.Pass, error) {
 	var passes []entity.Pass
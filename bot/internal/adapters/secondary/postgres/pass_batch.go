@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+type PassBatchRepository struct {
+	db *gorm.DB
+}
+
+func NewPassBatchRepository(db *gorm.DB) *PassBatchRepository {
+	return &PassBatchRepository{
+		db: db,
+	}
+}
+
+// Create creates the batch with its deliveries
+func (s *PassBatchRepository) Create(ctx context.Context, batch *entity.PassBatch) (*entity.PassBatch, error) {
+	err := s.db.WithContext(ctx).Create(batch).Error
+	return batch, err
+}
+
+func (s *PassBatchRepository) Get(ctx context.Context, id string) (*entity.PassBatch, error) {
+	var batch entity.PassBatch
+	err := s.db.WithContext(ctx).Preload("Deliveries").Where("id = ?", id).First(&batch).Error
+	return &batch, err
+}
+
+// GetRecent returns the latest batches without their files
+func (s *PassBatchRepository) GetRecent(ctx context.Context, limit int) ([]entity.PassBatch, error) {
+	var batches []entity.PassBatch
+	err := s.db.WithContext(ctx).
+		Omit("file").
+		Preload("Deliveries").
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&batches).Error
+	return batches, err
+}
+
+// GetUndelivered returns the batches with pending deliveries
+func (s *PassBatchRepository) GetUndelivered(ctx context.Context) ([]entity.PassBatch, error) {
+	var batches []entity.PassBatch
+	err := s.db.WithContext(ctx).
+		Preload("Deliveries").
+		Where("EXISTS (SELECT 1 FROM pass_batch_deliveries WHERE pass_batch_deliveries.batch_id = pass_batches.id AND pass_batch_deliveries.status = ?)",
+			entity.PassDeliveryStatusPending).
+		Order("created_at").
+		Find(&batches).Error
+	return batches, err
+}
+
+func (s *PassBatchRepository) Update(ctx context.Context, batch *entity.PassBatch) (*entity.PassBatch, error) {
+	err := s.db.WithContext(ctx).Omit("Deliveries").Save(batch).Error
+	return batch, err
+}
+
+func (s *PassBatchRepository) UpdateDelivery(ctx context.Context, delivery *entity.PassBatchDelivery) error {
+	return s.db.WithContext(ctx).Save(delivery).Error
+}
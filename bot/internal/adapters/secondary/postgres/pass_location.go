@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+type PassLocationRepository struct {
+	db *gorm.DB
+}
+
+func NewPassLocationRepository(db *gorm.DB) *PassLocationRepository {
+	return &PassLocationRepository{
+		db: db,
+	}
+}
+
+func (s *PassLocationRepository) Create(ctx context.Context, location *entity.PassLocation) (*entity.PassLocation, error) {
+	err := s.db.WithContext(ctx).Create(location).Error
+	return location, err
+}
+
+func (s *PassLocationRepository) Get(ctx context.Context, id string) (*entity.PassLocation, error) {
+	var location entity.PassLocation
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&location).Error
+	return &location, err
+}
+
+func (s *PassLocationRepository) GetAll(ctx context.Context) ([]entity.PassLocation, error) {
+	var locations []entity.PassLocation
+	err := s.db.WithContext(ctx).Order("created_at ASC").Find(&locations).Error
+	return locations, err
+}
+
+func (s *PassLocationRepository) Update(ctx context.Context, location *entity.PassLocation) (*entity.PassLocation, error) {
+	err := s.db.WithContext(ctx).Save(location).Error
+	return location, err
+}
+
+func (s *PassLocationRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&entity.PassLocation{}).Count(&count).Error
+	return count, err
+}
+
+// AssignUnlocated is a function that assigns the location to the schedules, events and event series
+// created before pass locations were introduced.
+func (s *PassLocationRepository) AssignUnlocated(ctx context.Context, locationID string) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.PassSchedule{}).
+			Where("location_id IS NULL").
+			Update("location_id", locationID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&entity.Event{}).
+			Where("pass_required = ? AND pass_location_id IS NULL", true).
+			Update("pass_location_id", locationID).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&entity.EventSeries{}).
+			Where("pass_required = ? AND pass_location_id IS NULL", true).
+			Update("pass_location_id", locationID).Error
+	})
+}
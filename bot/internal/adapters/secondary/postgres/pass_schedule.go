@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+type PassScheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewPassScheduleRepository(db *gorm.DB) *PassScheduleRepository {
+	return &PassScheduleRepository{
+		db: db,
+	}
+}
+
+func (s *PassScheduleRepository) Create(ctx context.Context, schedule *entity.PassSchedule) (*entity.PassSchedule, error) {
+	err := s.db.WithContext(ctx).Create(schedule).Error
+	return schedule, err
+}
+
+func (s *PassScheduleRepository) Get(ctx context.Context, id string) (*entity.PassSchedule, error) {
+	var schedule entity.PassSchedule
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&schedule).Error
+	return &schedule, err
+}
+
+func (s *PassScheduleRepository) GetAll(ctx context.Context) ([]entity.PassSchedule, error) {
+	var schedules []entity.PassSchedule
+	err := s.db.WithContext(ctx).Order("created_at ASC").Find(&schedules).Error
+	return schedules, err
+}
+
+func (s *PassScheduleRepository) GetByLocationID(ctx context.Context, locationID string) ([]entity.PassSchedule, error) {
+	var schedules []entity.PassSchedule
+	err := s.db.WithContext(ctx).
+		Where("location_id = ?", locationID).
+		Order("created_at ASC").
+		Find(&schedules).Error
+	return schedules, err
+}
+
+func (s *PassScheduleRepository) Update(ctx context.Context, schedule *entity.PassSchedule) (*entity.PassSchedule, error) {
+	err := s.db.WithContext(ctx).Save(schedule).Error
+	return schedule, err
+}
+
+func (s *PassScheduleRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&entity.PassSchedule{}).Count(&count).Error
+	return count, err
+}
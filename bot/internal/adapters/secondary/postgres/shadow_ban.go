@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+type ShadowBanRepository struct {
+	db *gorm.DB
+}
+
+func NewShadowBanRepository(db *gorm.DB) *ShadowBanRepository {
+	return &ShadowBanRepository{
+		db: db,
+	}
+}
+
+func (s *ShadowBanRepository) Create(ctx context.Context, ban *entity.ShadowBan) (*entity.ShadowBan, error) {
+	err := s.db.WithContext(ctx).Create(ban).Error
+	return ban, err
+}
+
+func (s *ShadowBanRepository) Get(ctx context.Context, id string) (*entity.ShadowBan, error) {
+	var ban entity.ShadowBan
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&ban).Error
+	return &ban, err
+}
+
+// GetActive is a function that gets the shadow bans which are not expired at now.
+func (s *ShadowBanRepository) GetActive(ctx context.Context, now time.Time) ([]entity.ShadowBan, error) {
+	var bans []entity.ShadowBan
+	err := s.db.WithContext(ctx).
+		Where("expires_at IS NULL OR expires_at > ?", now).
+		Order("created_at").
+		Find(&bans).Error
+	return bans, err
+}
+
+// GetActiveByUserID is a function that gets the shadow bans of the user which are not expired at now.
+func (s *ShadowBanRepository) GetActiveByUserID(ctx context.Context, userID int64, now time.Time) ([]entity.ShadowBan, error) {
+	var bans []entity.ShadowBan
+	err := s.db.WithContext(ctx).
+		Where("user_id = ? AND (expires_at IS NULL OR expires_at > ?)", userID, now).
+		Order("created_at").
+		Find(&bans).Error
+	return bans, err
+}
+
+func (s *ShadowBanRepository) Delete(ctx context.Context, ids []string) error {
+	return s.db.WithContext(ctx).Where("id IN ?", ids).Delete(&entity.ShadowBan{}).Error
+}
+
+// CountAll is a function that counts the shadow bans including the removed ones.
+func (s *ShadowBanRepository) CountAll(ctx context.Context) (int64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Unscoped().Model(&entity.ShadowBan{}).Count(&count).Error
+	return count, err
+}
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -59,6 +60,13 @@ func (s *UserRepository) GetByEmail(ctx context.Context, email valueobject.Email
 	return &user, err
 }
 
+// GetByUsername is a function that gets a user from the database by telegram username, case-insensitive.
+func (s *UserRepository) GetByUsername(ctx context.Context, username string) (*entity.User, error) {
+	var user entity.User
+	err := s.db.WithContext(ctx).Where("LOWER(username) = LOWER(?)", username).First(&user).Error
+	return &user, err
+}
+
 // GetAll is a function that gets all users from the database.
 func (s *UserRepository) GetAll(ctx context.Context) ([]entity.User, error) {
 	var users []entity.User
@@ -127,6 +135,21 @@ func (s *UserRepository) GetUsersByClubID(ctx context.Context, clubID string) ([
 	return users, err
 }
 
+// GetClubFollowers is a function that returns all users that follow the club
+func (s *UserRepository) GetClubFollowers(ctx context.Context, clubID string) ([]entity.User, error) {
+	var users []entity.User
+
+	err := s.db.
+		WithContext(ctx).
+		Table("club_followers").
+		Select("users.*").
+		Joins("inner join users on club_followers.user_id = users.id").
+		Where("club_followers.club_id = ?", clubID).
+		Preload("IgnoreMailing").
+		Find(&users).Error
+	return users, err
+}
+
 // GetManyUsersByEventIDs is a function that get users that registered on event by event ids without duplicates.
 func (s *UserRepository) GetManyUsersByEventIDs(ctx context.Context, eventIDs []string) ([]entity.User, error) {
 	var users []entity.User
@@ -147,6 +170,14 @@ func (s *UserRepository) Update(ctx context.Context, user *entity.User) (*entity
 	return user, err
 }
 
+// SetBotBlocked is a function that updates the time the user blocked the bot (nil if the bot is not blocked).
+func (s *UserRepository) SetBotBlocked(ctx context.Context, userID int64, blockedAt *time.Time) error {
+	return s.db.WithContext(ctx).
+		Model(&entity.User{}).
+		Where("id = ?", userID).
+		Update("bot_blocked_at", blockedAt).Error
+}
+
 // Count is a function that gets the count of users from the database.
 func (s *UserRepository) Count(ctx context.Context) (int64, error) {
 	var count int64
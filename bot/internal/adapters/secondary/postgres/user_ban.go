@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+type UserBanRepository struct {
+	db *gorm.DB
+}
+
+func NewUserBanRepository(db *gorm.DB) *UserBanRepository {
+	return &UserBanRepository{
+		db: db,
+	}
+}
+
+func (s *UserBanRepository) Create(ctx context.Context, ban *entity.UserBan) (*entity.UserBan, error) {
+	err := s.db.WithContext(ctx).Create(ban).Error
+	return ban, err
+}
+
+func (s *UserBanRepository) Update(ctx context.Context, ban *entity.UserBan) (*entity.UserBan, error) {
+	err := s.db.WithContext(ctx).Save(ban).Error
+	return ban, err
+}
+
+// GetActiveByUserID returns the latest ban of the user which is neither lifted nor expired at now
+func (s *UserBanRepository) GetActiveByUserID(ctx context.Context, userID int64, now time.Time) (*entity.UserBan, error) {
+	var ban entity.UserBan
+	err := s.db.WithContext(ctx).
+		Where("user_id = ? AND lifted_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", userID, now).
+		Order("created_at DESC").
+		First(&ban).Error
+	return &ban, err
+}
+
+// GetActive returns the bans which are neither lifted nor expired at now
+func (s *UserBanRepository) GetActive(ctx context.Context, now time.Time) ([]entity.UserBan, error) {
+	var bans []entity.UserBan
+	err := s.db.WithContext(ctx).
+		Where("lifted_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", now).
+		Order("created_at").
+		Find(&bans).Error
+	return bans, err
+}
+
+// GetExpired returns the bans which have expired by now but are not lifted yet
+func (s *UserBanRepository) GetExpired(ctx context.Context, now time.Time) ([]entity.UserBan, error) {
+	var bans []entity.UserBan
+	err := s.db.WithContext(ctx).
+		Where("lifted_at IS NULL AND expires_at <= ?", now).
+		Find(&bans).Error
+	return bans, err
+}
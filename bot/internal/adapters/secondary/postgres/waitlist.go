@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+type WaitlistRepository struct {
+	db *gorm.DB
+}
+
+func NewWaitlistRepository(db *gorm.DB) *WaitlistRepository {
+	return &WaitlistRepository{
+		db: db,
+	}
+}
+
+func (s *WaitlistRepository) Create(ctx context.Context, entry *entity.WaitlistEntry) (*entity.WaitlistEntry, error) {
+	err := s.db.WithContext(ctx).Create(entry).Error
+	return entry, err
+}
+
+func (s *WaitlistRepository) Update(ctx context.Context, entry *entity.WaitlistEntry) (*entity.WaitlistEntry, error) {
+	err := s.db.WithContext(ctx).Omit("Event", "User").Save(entry).Error
+	return entry, err
+}
+
+// Transition updates the entry with a conditional UPDATE, so concurrent promotions never take the same entry
+func (s *WaitlistRepository) Transition(ctx context.Context, entry *entity.WaitlistEntry, from entity.WaitlistStatus) (bool, error) {
+	result := s.db.WithContext(ctx).
+		Model(&entity.WaitlistEntry{}).
+		Where("id = ? AND status = ?", entry.ID, from).
+		Updates(map[string]interface{}{
+			"status":           entry.Status,
+			"offered_at":       entry.OfferedAt,
+			"confirm_deadline": entry.ConfirmDeadline,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// GetActive returns the waiting or offered entry of the user for the event
+func (s *WaitlistRepository) GetActive(ctx context.Context, eventID string, userID int64) (*entity.WaitlistEntry, error) {
+	var entry entity.WaitlistEntry
+	err := s.db.WithContext(ctx).
+		Where("event_id = ? AND user_id = ? AND status IN ?", eventID, userID, []entity.WaitlistStatus{
+			entity.WaitlistStatusWaiting,
+			entity.WaitlistStatusOffered,
+		}).
+		First(&entry).Error
+	return &entry, err
+}
+
+// GetNextWaiting returns the oldest waiting entry for the event
+func (s *WaitlistRepository) GetNextWaiting(ctx context.Context, eventID string) (*entity.WaitlistEntry, error) {
+	var entry entity.WaitlistEntry
+	err := s.db.WithContext(ctx).
+		Where("event_id = ? AND status = ?", eventID, entity.WaitlistStatusWaiting).
+		Order("created_at ASC").
+		First(&entry).Error
+	return &entry, err
+}
+
+func (s *WaitlistRepository) CountWaiting(ctx context.Context, eventID string) (int64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).
+		Model(&entity.WaitlistEntry{}).
+		Where("event_id = ? AND status = ?", eventID, entity.WaitlistStatusWaiting).
+		Count(&count).Error
+	return count, err
+}
+
+// CountWaitingBefore returns the number of waiting entries created before the given time
+func (s *WaitlistRepository) CountWaitingBefore(ctx context.Context, eventID string, createdAt time.Time) (int64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).
+		Model(&entity.WaitlistEntry{}).
+		Where("event_id = ? AND status = ? AND created_at < ?", eventID, entity.WaitlistStatusWaiting, createdAt).
+		Count(&count).Error
+	return count, err
+}
+
+// GetExpiredOffers returns offered entries whose confirmation deadline has passed
+func (s *WaitlistRepository) GetExpiredOffers(ctx context.Context, now time.Time) ([]entity.WaitlistEntry, error) {
+	var entries []entity.WaitlistEntry
+	err := s.db.WithContext(ctx).
+		Where("status = ? AND confirm_deadline < ?", entity.WaitlistStatusOffered, now).
+		Find(&entries).Error
+	return entries, err
+}
+
+// CancelWaitingByUserID cancels the waiting entries of the user in all waitlists
+func (s *WaitlistRepository) CancelWaitingByUserID(ctx context.Context, userID int64) error {
+	return s.db.WithContext(ctx).
+		Model(&entity.WaitlistEntry{}).
+		Where("user_id = ? AND status = ?", userID, entity.WaitlistStatusWaiting).
+		Update("status", entity.WaitlistStatusCancelled).Error
+}
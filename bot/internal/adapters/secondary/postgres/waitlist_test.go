@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/valueobject"
+)
+
+func TestWaitlistRepository_Transition_Concurrent(t *testing.T) {
+	const promotions = 10
+
+	db := testDB(t)
+	ctx := context.Background()
+
+	club := &entity.Club{Name: "test-club-" + uuid.NewString()}
+	if err := db.Create(club).Error; err != nil {
+		t.Fatalf("create club: %v", err)
+	}
+	event := &entity.Event{
+		ClubID:          club.ID,
+		Name:            "test event",
+		Description:     "test event",
+		Location:        "test location",
+		StartTime:       time.Now().Add(48 * time.Hour),
+		RegistrationEnd: time.Now().Add(24 * time.Hour),
+		MaxParticipants: 1,
+	}
+	if err := db.Create(event).Error; err != nil {
+		t.Fatalf("create event: %v", err)
+	}
+	fio, _ := valueobject.NewFIO("Test", "User", "")
+	user := &entity.User{ID: time.Now().UnixNano() % 1_000_000_000_000, Role: valueobject.Student, FIO: fio}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	repo := NewWaitlistRepository(db)
+	entry, err := repo.Create(ctx, &entity.WaitlistEntry{
+		EventID: event.ID,
+		UserID:  user.ID,
+		Status:  entity.WaitlistStatusWaiting,
+	})
+	if err != nil {
+		t.Fatalf("create waitlist entry: %v", err)
+	}
+
+	t.Cleanup(func() {
+		db.Delete(entry)
+		db.Unscoped().Delete(event)
+		db.Delete(user)
+		db.Unscoped().Delete(club)
+	})
+
+	var (
+		claimed atomic.Int32
+		wg      sync.WaitGroup
+	)
+	for i := 0; i < promotions; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stale := *entry
+			now := time.Now()
+			stale.Offer(now, now.Add(time.Hour))
+			ok, err := repo.Transition(ctx, &stale, entity.WaitlistStatusWaiting)
+			if err != nil {
+				t.Errorf("transition: %v", err)
+				return
+			}
+			if ok {
+				claimed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if claimed.Load() != 1 {
+		t.Errorf("entry claimed %d times, want 1", claimed.Load())
+	}
+
+	stored, err := repo.GetActive(ctx, event.ID, user.ID)
+	if err != nil {
+		t.Fatalf("get waitlist entry: %v", err)
+	}
+	if stored.Status != entity.WaitlistStatusOffered {
+		t.Errorf("got status %s, want %s", stored.Status, entity.WaitlistStatusOffered)
+	}
+}
@@ -56,3 +56,65 @@ func (s *Storage) SetEventID(userID int64, key, eventID string, expiration time.
 func (s *Storage) Clear(userID int64) {
 	s.redis.Del(context.Background(), fmt.Sprintf("%d", userID))
 }
+
+// GetSeries returns the recurrence draft of the event being created by the user
+func (s *Storage) GetSeries(userID int64) (entity.EventSeries, error) {
+	seriesBytes, err := s.redis.Get(context.Background(), fmt.Sprintf("series:%d", userID)).Result()
+	if err != nil {
+		return entity.EventSeries{}, err
+	}
+
+	var series entity.EventSeries
+	if err = json.Unmarshal([]byte(seriesBytes), &series); err != nil {
+		return entity.EventSeries{}, err
+	}
+
+	return series, nil
+}
+
+func (s *Storage) SetSeries(userID int64, series entity.EventSeries, expiration time.Duration) {
+	seriesBytes, _ := json.Marshal(series)
+	s.redis.Set(context.Background(), fmt.Sprintf("series:%d", userID), seriesBytes, expiration)
+}
+
+func (s *Storage) ClearSeries(userID int64) {
+	s.redis.Del(context.Background(), fmt.Sprintf("series:%d", userID))
+}
+
+// GetDraft returns the unsaved copy of an existing event edited by the user
+func (s *Storage) GetDraft(userID int64) (entity.Event, error) {
+	eventBytes, err := s.redis.Get(context.Background(), fmt.Sprintf("draft:%d", userID)).Result()
+	if err != nil {
+		return entity.Event{}, err
+	}
+
+	var event entity.Event
+	if err = json.Unmarshal([]byte(eventBytes), &event); err != nil {
+		return entity.Event{}, err
+	}
+
+	return event, nil
+}
+
+func (s *Storage) SetDraft(userID int64, event entity.Event, expiration time.Duration) {
+	eventBytes, _ := json.Marshal(event)
+	s.redis.Set(context.Background(), fmt.Sprintf("draft:%d", userID), eventBytes, expiration)
+}
+
+func (s *Storage) ClearDraft(userID int64) {
+	s.redis.Del(context.Background(), fmt.Sprintf("draft:%d", userID))
+}
+
+// IsAnnouncementDisabled reports whether the user has turned off the announcement of the event being created
+func (s *Storage) IsAnnouncementDisabled(userID int64) bool {
+	count, err := s.redis.Exists(context.Background(), fmt.Sprintf("no_announcement:%d", userID)).Result()
+	return err == nil && count > 0
+}
+
+func (s *Storage) SetAnnouncementDisabled(userID int64, disabled bool, expiration time.Duration) {
+	if !disabled {
+		s.redis.Del(context.Background(), fmt.Sprintf("no_announcement:%d", userID))
+		return
+	}
+	s.redis.Set(context.Background(), fmt.Sprintf("no_announcement:%d", userID), true, expiration)
+}
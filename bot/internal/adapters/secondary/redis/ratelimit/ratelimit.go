@@ -0,0 +1,36 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Storage counts requests in fixed time windows
+type Storage struct {
+	redis *redis.Client
+}
+
+func NewStorage(client *redis.Client) *Storage {
+	return &Storage{
+		redis: client,
+	}
+}
+
+// Allow registers a hit for the key and reports whether the number of hits
+// in the current window does not exceed the limit
+func (s *Storage) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error) {
+	windowStart := time.Now().UnixNano() / int64(window)
+	redisKey := fmt.Sprintf("ratelimit:%s:%d", key, windowStart)
+
+	pipe := s.redis.TxPipeline()
+	hits := pipe.Incr(ctx, redisKey)
+	pipe.ExpireNX(ctx, redisKey, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+
+	return hits.Val() <= int64(limit), nil
+}
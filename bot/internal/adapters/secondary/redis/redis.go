@@ -10,6 +10,7 @@ import (
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/secondary/redis/codes"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/secondary/redis/emails"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/secondary/redis/events"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/secondary/redis/ratelimit"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/secondary/redis/states"
 )
 
@@ -19,6 +20,7 @@ type Client struct {
 	Emails    *emails.Storage
 	Events    *events.Storage
 	Callbacks *callbacks.Storage
+	RateLimit *ratelimit.Storage
 }
 
 type Options struct {
@@ -70,6 +72,14 @@ func New(opts Options) (*Client, error) {
 	if err := callbacksRedis.Ping(context.Background()).Err(); err != nil {
 		return nil, fmt.Errorf("failed to ping callbacks storage: %w", err)
 	}
+	rateLimitRedis := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%s", opts.Host, opts.Port),
+		Password: opts.Password,
+		DB:       5,
+	})
+	if err := rateLimitRedis.Ping(context.Background()).Err(); err != nil {
+		return nil, fmt.Errorf("failed to ping rate limit storage: %w", err)
+	}
 
 	return &Client{
 		States:    states.NewStorage(stateRedis),
@@ -77,5 +87,6 @@ func New(opts Options) (*Client, error) {
 		Emails:    emails.NewStorage(emailsRedis),
 		Events:    events.NewStorage(eventsRedis),
 		Callbacks: callbacks.NewStorage(callbacksRedis),
+		RateLimit: ratelimit.NewStorage(rateLimitRedis),
 	}, nil
 }
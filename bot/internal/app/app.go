@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -16,6 +17,9 @@ import (
 	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger"
 )
 
+// apiShutdownTimeout is how long active API requests are awaited on shutdown
+const apiShutdownTimeout = 10 * time.Second
+
 // App represents the main application structure.
 type App struct {
 	serviceProvider *serviceProvider
@@ -50,7 +54,6 @@ func (a *App) Run() {
 
 	// Setup bot handlers
 	debug := a.serviceProvider.Cfg().Logger.Debug()
-	adminIDs := a.serviceProvider.Cfg().Bot.AdminIDs()
 	setupBot.Setup(
 		a.serviceProvider.Bot(),
 		a.serviceProvider.MiddlewaresHandler(),
@@ -60,7 +63,6 @@ func (a *App) Run() {
 		a.serviceProvider.MenuHandler(),
 		a.serviceProvider.AdminHandler(),
 		debug,
-		adminIDs,
 	)
 
 	// Start bot in goroutine to catch panics
@@ -74,6 +76,24 @@ func (a *App) Run() {
 		a.serviceProvider.Bot().Start()
 	}()
 
+	// Import shadow bans from the config on the first start
+	if err := a.serviceProvider.ShadowBanService().Seed(context.Background()); err != nil {
+		logger.Log.Errorf("failed to import shadow bans: %v", err)
+	}
+
+	// Start outbox workers
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Log.Error("Panic in Start outbox", zap.Any("panic", r))
+			}
+		}()
+		err := a.serviceProvider.OutboxService().Start()
+		if err != nil {
+			logger.Log.Errorf("failed to start outbox: %v", err)
+		}
+	}()
+
 	// Start notification scheduler
 	func() {
 		defer func() {
@@ -97,6 +117,42 @@ func (a *App) Run() {
 		}
 	}()
 
+	// Start waitlist scheduler
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Log.Error("Panic in StartWaitlistScheduler", zap.Any("panic", r))
+			}
+		}()
+		a.serviceProvider.EventParticipantService().StartWaitlistScheduler()
+	}()
+
+	// Start event series scheduler
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Log.Error("Panic in StartScheduler event series", zap.Any("panic", r))
+			}
+		}()
+		err := a.serviceProvider.EventSeriesService().StartScheduler()
+		if err != nil {
+			logger.Log.Errorf("failed to start event series scheduler: %v", err)
+		}
+	}()
+
+	// Start mailing scheduler
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Log.Error("Panic in StartScheduler mailing", zap.Any("panic", r))
+			}
+		}()
+		err := a.serviceProvider.MailingService().StartScheduler()
+		if err != nil {
+			logger.Log.Errorf("failed to start mailing scheduler: %v", err)
+		}
+	}()
+
 	// Start club owner reminder scheduler
 	func() {
 		defer func() {
@@ -110,6 +166,30 @@ func (a *App) Run() {
 		}
 	}()
 
+	// Start ban scheduler
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Log.Error("Panic in StartScheduler ban", zap.Any("panic", r))
+			}
+		}()
+		a.serviceProvider.BanService().StartScheduler()
+	}()
+
+	// Start HTTP API
+	if a.serviceProvider.Cfg().API.Enabled() {
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					errChan <- fmt.Errorf("api server panic: %v", r)
+				}
+			}()
+			if err := a.serviceProvider.APIServer().Start(); err != nil {
+				errChan <- fmt.Errorf("api server: %w", err)
+			}
+		}()
+	}
+
 	// Wait for shutdown signal or error
 	select {
 	case err := <-errChan:
@@ -133,6 +213,27 @@ func (a *App) gracefulShutdown() {
 			logger.Log.Info("Pass scheduler stopped")
 		}
 
+		// Stop waitlist scheduler
+		if a.serviceProvider.eventParticipantService != nil {
+			logger.Log.Info("Stopping waitlist scheduler...")
+			a.serviceProvider.eventParticipantService.StopWaitlistScheduler()
+			logger.Log.Info("Waitlist scheduler stopped")
+		}
+
+		// Stop event series scheduler
+		if a.serviceProvider.eventSeriesService != nil {
+			logger.Log.Info("Stopping event series scheduler...")
+			a.serviceProvider.eventSeriesService.StopScheduler()
+			logger.Log.Info("Event series scheduler stopped")
+		}
+
+		// Stop mailing scheduler
+		if a.serviceProvider.mailingService != nil {
+			logger.Log.Info("Stopping mailing scheduler...")
+			a.serviceProvider.mailingService.StopScheduler()
+			logger.Log.Info("Mailing scheduler stopped")
+		}
+
 		// Stop club owner reminder scheduler
 		if a.serviceProvider.notifyService != nil {
 			logger.Log.Info("Stopping club owner reminder scheduler...")
@@ -140,6 +241,25 @@ func (a *App) gracefulShutdown() {
 			logger.Log.Info("Club owner reminder scheduler stopped")
 		}
 
+		// Stop ban scheduler
+		if a.serviceProvider.banService != nil {
+			logger.Log.Info("Stopping ban scheduler...")
+			a.serviceProvider.banService.StopScheduler()
+			logger.Log.Info("Ban scheduler stopped")
+		}
+
+		// Stop HTTP API
+		if a.serviceProvider.apiServer != nil {
+			logger.Log.Info("Stopping API server...")
+			ctx, cancel := context.WithTimeout(context.Background(), apiShutdownTimeout)
+			if err := a.serviceProvider.apiServer.Stop(ctx); err != nil {
+				logger.Log.Errorf("Error stopping API server: %v", err)
+			} else {
+				logger.Log.Info("API server stopped")
+			}
+			cancel()
+		}
+
 		// Stop the bot
 		if a.serviceProvider.Bot() != nil {
 			logger.Log.Info("Stopping bot...")
@@ -147,6 +267,13 @@ func (a *App) gracefulShutdown() {
 			logger.Log.Info("Bot stopped")
 		}
 
+		// Stop outbox after the bot, so messages enqueued by the last updates are saved
+		if a.serviceProvider.outboxService != nil {
+			logger.Log.Info("Stopping outbox...")
+			a.serviceProvider.outboxService.Stop()
+			logger.Log.Info("Outbox stopped")
+		}
+
 		if a.serviceProvider.db != nil {
 			logger.Log.Info("Closing database connection...")
 			sqlDB, err := a.serviceProvider.db.DB()
@@ -17,6 +17,7 @@ import (
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/secondary"
 
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/config"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/primary/api"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/primary/telegram/bot"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/primary/telegram/handlers/admin"
 	clubowner "github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/primary/telegram/handlers/clubOwner"
@@ -51,19 +52,44 @@ type serviceProvider struct {
 	eventRepo            secondary.EventRepository
 	eventParticipantRepo secondary.EventParticipantRepository
 	passRepo             secondary.PassRepository
+	passLocationRepo     secondary.PassLocationRepository
+	passScheduleRepo     secondary.PassScheduleRepository
+	passBatchRepo        secondary.PassBatchRepository
 	clubOwnerRepo        secondary.ClubOwnerRepository
 	notificationRepo     secondary.NotificationRepository
+	waitlistRepo         secondary.WaitlistRepository
+	eventSeriesRepo      secondary.EventSeriesRepository
+	apiTokenRepo         secondary.APITokenRepository
+	apiRequestLogRepo    secondary.APIRequestLogRepository
+	outboxRepo           secondary.OutboxRepository
+	mailingRepo          secondary.MailingRepository
+	clubFollowerRepo     secondary.ClubFollowerRepository
+	shadowBanRepo        secondary.ShadowBanRepository
+	userBanRepo          secondary.UserBanRepository
+	adminRepo            secondary.AdminRepository
+	auditRepo            secondary.AuditRepository
+	clubApplicationRepo  secondary.ClubApplicationRepository
 
 	// Service layer
 	userService             primary.UserService
 	clubService             primary.ClubService
 	eventService            primary.EventService
 	eventParticipantService primary.EventParticipantService
+	eventSeriesService      primary.EventSeriesService
 	passService             primary.PassService
 	clubOwnerService        primary.ClubOwnerService
 	notifyService           primary.NotifyService
 	qrService               primary.QrService
 	versionService          primary.VersionService
+	apiTokenService         primary.APITokenService
+	outboxService           primary.OutboxService
+	mailingService          primary.MailingService
+	clubFollowerService     primary.ClubFollowerService
+	shadowBanService        primary.ShadowBanService
+	banService              primary.BanService
+	adminService            primary.AdminService
+	auditService            primary.AuditService
+	clubApplicationService  primary.ClubApplicationService
 
 	// Handlers
 	adminHandler       *admin.Handler
@@ -72,6 +98,9 @@ type serviceProvider struct {
 	menuHandler        *menu.Handler
 	middlewaresHandler *middlewares.Handler
 	clubOwnerHandler   *clubowner.Handler
+
+	// HTTP API
+	apiServer *api.Server
 }
 
 func newServiceProvider() *serviceProvider {
@@ -142,7 +171,7 @@ func (s *serviceProvider) DB() *gorm.DB {
 
 		logger.Log.Info("Database connection pool configured")
 
-		errMigrate := database.AutoMigrate(postgres.Migrations...)
+		errMigrate := postgres.Migrate(database)
 		if errMigrate != nil {
 			panic(fmt.Errorf("failed to migrate database: %w", errMigrate))
 		}
@@ -237,6 +266,30 @@ func (s *serviceProvider) PassRepo() secondary.PassRepository {
 	return s.passRepo
 }
 
+func (s *serviceProvider) PassLocationRepo() secondary.PassLocationRepository {
+	if s.passLocationRepo == nil {
+		s.passLocationRepo = postgres.NewPassLocationRepository(s.DB())
+	}
+
+	return s.passLocationRepo
+}
+
+func (s *serviceProvider) PassScheduleRepo() secondary.PassScheduleRepository {
+	if s.passScheduleRepo == nil {
+		s.passScheduleRepo = postgres.NewPassScheduleRepository(s.DB())
+	}
+
+	return s.passScheduleRepo
+}
+
+func (s *serviceProvider) PassBatchRepo() secondary.PassBatchRepository {
+	if s.passBatchRepo == nil {
+		s.passBatchRepo = postgres.NewPassBatchRepository(s.DB())
+	}
+
+	return s.passBatchRepo
+}
+
 func (s *serviceProvider) ClubOwnerRepo() secondary.ClubOwnerRepository {
 	if s.clubOwnerRepo == nil {
 		s.clubOwnerRepo = postgres.NewClubOwnerRepository(s.DB())
@@ -253,6 +306,102 @@ func (s *serviceProvider) NotificationRepo() secondary.NotificationRepository {
 	return s.notificationRepo
 }
 
+func (s *serviceProvider) WaitlistRepo() secondary.WaitlistRepository {
+	if s.waitlistRepo == nil {
+		s.waitlistRepo = postgres.NewWaitlistRepository(s.DB())
+	}
+
+	return s.waitlistRepo
+}
+
+func (s *serviceProvider) EventSeriesRepo() secondary.EventSeriesRepository {
+	if s.eventSeriesRepo == nil {
+		s.eventSeriesRepo = postgres.NewEventSeriesRepository(s.DB())
+	}
+
+	return s.eventSeriesRepo
+}
+
+func (s *serviceProvider) APITokenRepo() secondary.APITokenRepository {
+	if s.apiTokenRepo == nil {
+		s.apiTokenRepo = postgres.NewAPITokenRepository(s.DB())
+	}
+
+	return s.apiTokenRepo
+}
+
+func (s *serviceProvider) APIRequestLogRepo() secondary.APIRequestLogRepository {
+	if s.apiRequestLogRepo == nil {
+		s.apiRequestLogRepo = postgres.NewAPIRequestLogRepository(s.DB())
+	}
+
+	return s.apiRequestLogRepo
+}
+
+func (s *serviceProvider) OutboxRepo() secondary.OutboxRepository {
+	if s.outboxRepo == nil {
+		s.outboxRepo = postgres.NewOutboxRepository(s.DB())
+	}
+
+	return s.outboxRepo
+}
+
+func (s *serviceProvider) MailingRepo() secondary.MailingRepository {
+	if s.mailingRepo == nil {
+		s.mailingRepo = postgres.NewMailingRepository(s.DB())
+	}
+
+	return s.mailingRepo
+}
+
+func (s *serviceProvider) ClubFollowerRepo() secondary.ClubFollowerRepository {
+	if s.clubFollowerRepo == nil {
+		s.clubFollowerRepo = postgres.NewClubFollowerRepository(s.DB())
+	}
+
+	return s.clubFollowerRepo
+}
+
+func (s *serviceProvider) ShadowBanRepo() secondary.ShadowBanRepository {
+	if s.shadowBanRepo == nil {
+		s.shadowBanRepo = postgres.NewShadowBanRepository(s.DB())
+	}
+
+	return s.shadowBanRepo
+}
+
+func (s *serviceProvider) UserBanRepo() secondary.UserBanRepository {
+	if s.userBanRepo == nil {
+		s.userBanRepo = postgres.NewUserBanRepository(s.DB())
+	}
+
+	return s.userBanRepo
+}
+
+func (s *serviceProvider) AdminRepo() secondary.AdminRepository {
+	if s.adminRepo == nil {
+		s.adminRepo = postgres.NewAdminRepository(s.DB())
+	}
+
+	return s.adminRepo
+}
+
+func (s *serviceProvider) AuditRepo() secondary.AuditRepository {
+	if s.auditRepo == nil {
+		s.auditRepo = postgres.NewAuditRepository(s.DB())
+	}
+
+	return s.auditRepo
+}
+
+func (s *serviceProvider) ClubApplicationRepo() secondary.ClubApplicationRepository {
+	if s.clubApplicationRepo == nil {
+		s.clubApplicationRepo = postgres.NewClubApplicationRepository(s.DB())
+	}
+
+	return s.clubApplicationRepo
+}
+
 // Service layer
 
 func (s *serviceProvider) UserService() primary.UserService {
@@ -261,8 +410,8 @@ func (s *serviceProvider) UserService() primary.UserService {
 			s.UserRepo(),
 			s.EventParticipantRepo(),
 			s.SMTPClient(),
+			s.ShadowBanRepo(),
 			s.cfg.App.EmailConfirmationTemplate(),
-			s.cfg.App.PassShadowBanNameSurnames(),
 		)
 	}
 
@@ -279,7 +428,12 @@ func (s *serviceProvider) ClubService() primary.ClubService {
 
 func (s *serviceProvider) EventService() primary.EventService {
 	if s.eventService == nil {
-		s.eventService = service.NewEventService(s.EventRepo())
+		eventLogger, err := logger.Named("event")
+		if err != nil {
+			panic(fmt.Errorf("failed to create event logger: %w", err))
+		}
+
+		s.eventService = service.NewEventService(eventLogger, s.EventRepo(), s.PassService())
 	}
 
 	return s.eventService
@@ -293,19 +447,42 @@ func (s *serviceProvider) EventParticipantService() primary.EventParticipantServ
 		}
 
 		s.eventParticipantService = service.NewEventParticipantService(
+			s.Bot().Layout,
 			botLogger,
 			s.EventParticipantRepo(),
 			s.EventRepo(),
 			s.PassRepo(),
 			s.UserRepo(),
+			s.WaitlistRepo(),
+			s.ClubFollowerRepo(),
+			s.ShadowBanRepo(),
+			s.PassService(),
+			s.OutboxService(),
 			s.cfg.App.PassExcludedRoles(),
-			s.cfg.App.PassShadowBanNameSurnames(),
+			s.cfg.App.FollowOnRegistration(),
 		)
 	}
 
 	return s.eventParticipantService
 }
 
+func (s *serviceProvider) EventSeriesService() primary.EventSeriesService {
+	if s.eventSeriesService == nil {
+		seriesLogger, err := logger.Named("event-series")
+		if err != nil {
+			panic(fmt.Errorf("failed to create event series logger: %w", err))
+		}
+
+		s.eventSeriesService = service.NewEventSeriesService(
+			seriesLogger,
+			s.EventSeriesRepo(),
+			s.EventRepo(),
+		)
+	}
+
+	return s.eventSeriesService
+}
+
 func (s *serviceProvider) PassService() primary.PassService {
 	if s.passService == nil {
 		botLogger, err := logger.Named("pass")
@@ -315,15 +492,21 @@ func (s *serviceProvider) PassService() primary.PassService {
 
 		s.passService = service.NewPassService(
 			s.Bot().Bot,
+			s.Bot().Layout,
 			botLogger,
 			s.PassRepo(),
+			s.PassLocationRepo(),
+			s.PassScheduleRepo(),
+			s.PassBatchRepo(),
 			s.EventRepo(),
 			s.UserRepo(),
 			s.ClubRepo(),
 			s.SMTPClient(),
+			s.ShadowBanRepo(),
+			s.OutboxService(),
+			s.cfg.App.PassLocationSubstrings(),
 			s.cfg.App.PassEmails(),
 			s.cfg.Bot.PassChannelID(),
-			s.cfg.App.PassShadowBanNameSurnames(),
 		)
 	}
 
@@ -332,9 +515,19 @@ func (s *serviceProvider) PassService() primary.PassService {
 
 func (s *serviceProvider) ClubOwnerService() primary.ClubOwnerService {
 	if s.clubOwnerService == nil {
+		clubOwnerLogger, err := logger.Named("club-owner")
+		if err != nil {
+			panic(fmt.Errorf("failed to create club owner logger: %w", err))
+		}
+
 		s.clubOwnerService = service.NewClubOwnerService(
+			s.Bot().Layout,
+			clubOwnerLogger,
 			s.ClubOwnerRepo(),
 			s.UserRepo(),
+			s.ClubRepo(),
+			s.AdminService(),
+			s.OutboxService(),
 		)
 	}
 
@@ -352,10 +545,15 @@ func (s *serviceProvider) NotifyService() primary.NotifyService {
 			s.Bot().Bot,
 			s.Bot().Layout,
 			notifyLogger,
+			s.OutboxService(),
 			s.ClubOwnerService(),
+			s.AdminService(),
 			s.EventRepo(),
 			s.NotificationRepo(),
 			s.EventParticipantRepo(),
+			s.ClubRepo(),
+			s.UserRepo(),
+			s.cfg.Bot.AnnouncementChannelID(),
 		)
 	}
 
@@ -410,6 +608,152 @@ func (s *serviceProvider) setBot(b *bot.Bot) {
 	s.bot = b
 }
 
+func (s *serviceProvider) APITokenService() primary.APITokenService {
+	if s.apiTokenService == nil {
+		s.apiTokenService = service.NewAPITokenService(s.APITokenRepo(), s.APIRequestLogRepo())
+	}
+
+	return s.apiTokenService
+}
+
+func (s *serviceProvider) ShadowBanService() primary.ShadowBanService {
+	if s.shadowBanService == nil {
+		shadowBanLogger, err := logger.Named("shadow-ban")
+		if err != nil {
+			panic(fmt.Errorf("failed to create shadow ban logger: %w", err))
+		}
+
+		s.shadowBanService = service.NewShadowBanService(
+			shadowBanLogger,
+			s.ShadowBanRepo(),
+			s.UserRepo(),
+			s.cfg.App.PassShadowBanNameSurnames(),
+		)
+	}
+
+	return s.shadowBanService
+}
+
+func (s *serviceProvider) BanService() primary.BanService {
+	if s.banService == nil {
+		banLogger, err := logger.Named("ban")
+		if err != nil {
+			panic(fmt.Errorf("failed to create ban logger: %w", err))
+		}
+
+		s.banService = service.NewBanService(
+			s.Bot().Layout,
+			banLogger,
+			s.UserBanRepo(),
+			s.UserRepo(),
+			s.EventParticipantService(),
+			s.ClubOwnerService(),
+			s.OutboxService(),
+		)
+	}
+
+	return s.banService
+}
+
+func (s *serviceProvider) AdminService() primary.AdminService {
+	if s.adminService == nil {
+		adminLogger, err := logger.Named("admin")
+		if err != nil {
+			panic(fmt.Errorf("failed to create admin logger: %w", err))
+		}
+
+		s.adminService = service.NewAdminService(
+			adminLogger,
+			s.AdminRepo(),
+			s.UserRepo(),
+			s.Cfg().Bot.AdminIDs(),
+		)
+	}
+
+	return s.adminService
+}
+
+func (s *serviceProvider) AuditService() primary.AuditService {
+	if s.auditService == nil {
+		auditLogger, err := logger.Named("audit")
+		if err != nil {
+			panic(fmt.Errorf("failed to create audit logger: %w", err))
+		}
+
+		s.auditService = service.NewAuditService(auditLogger, s.AuditRepo())
+	}
+
+	return s.auditService
+}
+
+func (s *serviceProvider) ClubApplicationService() primary.ClubApplicationService {
+	if s.clubApplicationService == nil {
+		clubApplicationLogger, err := logger.Named("club-application")
+		if err != nil {
+			panic(fmt.Errorf("failed to create club application logger: %w", err))
+		}
+
+		s.clubApplicationService = service.NewClubApplicationService(
+			s.Bot().Layout,
+			clubApplicationLogger,
+			s.ClubApplicationRepo(),
+			s.OutboxService(),
+		)
+	}
+
+	return s.clubApplicationService
+}
+
+func (s *serviceProvider) OutboxService() primary.OutboxService {
+	if s.outboxService == nil {
+		outboxLogger, err := logger.Named("outbox")
+		if err != nil {
+			panic(fmt.Errorf("failed to create outbox logger: %w", err))
+		}
+
+		s.outboxService = service.NewOutboxService(
+			s.Bot().Bot,
+			outboxLogger,
+			s.OutboxRepo(),
+			s.UserRepo(),
+			s.MailingRepo(),
+			s.cfg.Outbox.Workers(),
+			s.cfg.Outbox.RateLimit(),
+			s.cfg.Outbox.MaxAttempts(),
+		)
+	}
+
+	return s.outboxService
+}
+
+func (s *serviceProvider) MailingService() primary.MailingService {
+	if s.mailingService == nil {
+		mailingLogger, err := logger.Named("mailing")
+		if err != nil {
+			panic(fmt.Errorf("failed to create mailing logger: %w", err))
+		}
+
+		s.mailingService = service.NewMailingService(
+			s.MailingRepo(),
+			s.UserRepo(),
+			s.OutboxService(),
+			s.Bot().Layout,
+			mailingLogger,
+			s.cfg.Bot.MailingChannelID(),
+		)
+	}
+
+	return s.mailingService
+}
+
+func (s *serviceProvider) ClubFollowerService() primary.ClubFollowerService {
+	if s.clubFollowerService == nil {
+		s.clubFollowerService = service.NewClubFollowerService(s.ClubFollowerRepo())
+	}
+
+	return s.clubFollowerService
+}
+
 // Handlers
 
 func (s *serviceProvider) AdminHandler() *admin.Handler {
@@ -418,6 +762,15 @@ func (s *serviceProvider) AdminHandler() *admin.Handler {
 			s.UserService(),
 			s.ClubService(),
 			s.ClubOwnerService(),
+			s.APITokenService(),
+			s.MailingService(),
+			s.EventService(),
+			s.PassService(),
+			s.ShadowBanService(),
+			s.BanService(),
+			s.AdminService(),
+			s.AuditService(),
+			s.ClubApplicationService(),
 			s.Bot().Bot,
 			s.Bot().Layout,
 			s.Bot().Logger,
@@ -433,7 +786,10 @@ func (s *serviceProvider) UserHandler() *user.Handler {
 			s.UserService(),
 			s.EventService(),
 			s.ClubService(),
+			s.ClubFollowerService(),
+			s.ClubApplicationService(),
 			s.EventParticipantService(),
+			s.EventSeriesService(),
 			s.QrService(),
 			s.NotifyService(),
 			s.MenuHandler(),
@@ -460,10 +816,12 @@ func (s *serviceProvider) StartHandler() *start.Handler {
 		s.startHandler = start.New(
 			s.UserService(),
 			s.ClubService(),
+			s.ClubOwnerService(),
 			s.EventService(),
 			s.EventParticipantService(),
 			s.QrService(),
 			s.NotifyService(),
+			s.AuditService(),
 			s.Redis().Callbacks,
 			s.MenuHandler(),
 			s.Redis().Codes,
@@ -484,7 +842,7 @@ func (s *serviceProvider) MenuHandler() *menu.Handler {
 			s.ClubService(),
 			s.Bot().Logger,
 			s.Bot().Layout,
-			s.Cfg().Bot.AdminIDs(),
+			s.AdminService(),
 		)
 	}
 	return s.menuHandler
@@ -495,6 +853,7 @@ func (s *serviceProvider) MiddlewaresHandler() *middlewares.Handler {
 		s.middlewaresHandler = middlewares.New(
 			s.UserService(),
 			s.ClubService(),
+			s.AdminService(),
 			s.Bot().Bot,
 			s.Bot().Layout,
 			s.Bot().Logger,
@@ -512,22 +871,53 @@ func (s *serviceProvider) ClubOwnerHandler() *clubowner.Handler {
 			s.Bot().Logger,
 			s.Bot().Input,
 			s.Redis().Events,
+			s.Redis().Callbacks,
 			s.ClubService(),
 			s.ClubOwnerService(),
 			s.UserService(),
 			s.EventService(),
 			s.EventParticipantService(),
+			s.EventSeriesService(),
 			s.QrService(),
 			s.NotifyService(),
-			s.Cfg().Bot.MailingChannelID(),
+			s.OutboxService(),
+			s.MailingService(),
+			s.PassService(),
+			s.AuditService(),
 			s.Cfg().Bot.AvatarChannelID(),
 			s.Cfg().Bot.IntroChannelID(),
-			s.Cfg().App.PassLocationSubstrings(),
 		)
 	}
 	return s.clubOwnerHandler
 }
 
+// HTTP API
+
+func (s *serviceProvider) APIServer() *api.Server {
+	if s.apiServer == nil {
+		apiLogger, err := logger.Named("api")
+		if err != nil {
+			panic(fmt.Errorf("failed to create api logger: %w", err))
+		}
+
+		s.apiServer = api.New(
+			s.cfg.API.Address(),
+			s.APITokenService(),
+			s.ClubService(),
+			s.EventService(),
+			s.EventParticipantService(),
+			s.UserService(),
+			s.PassService(),
+			s.Redis().RateLimit,
+			s.cfg.API.RateLimit(),
+			s.cfg.API.RateLimitWindow(),
+			apiLogger,
+		)
+	}
+
+	return s.apiServer
+}
+
 // Cfg returns the config
 func (s *serviceProvider) Cfg() *config.Config {
 	return s.cfg
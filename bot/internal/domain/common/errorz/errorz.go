@@ -5,4 +5,39 @@ import "errors"
 var (
 	ErrInvalidCallbackData = errors.New("invalid callback data")
 	ErrInvalidCode         = errors.New("invalid code")
+
+	ErrEventFull            = errors.New("event is full")
+	ErrRegistrationEnded    = errors.New("event registration ended")
+	ErrAlreadyInWaitlist    = errors.New("user is already in the waitlist")
+	ErrAlreadyRegistered    = errors.New("user is already registered for the event")
+	ErrWaitlistOfferExpired = errors.New("waitlist offer expired")
+	ErrEventNotRecurring    = errors.New("event is not an occurrence of a series")
+	ErrEventCancelled       = errors.New("event is cancelled")
+
+	ErrInvalidAPIToken = errors.New("invalid api token")
+
+	ErrMailingNotScheduled = errors.New("mailing is not scheduled")
+
+	ErrInvalidPassSchedule     = errors.New("invalid pass schedule")
+	ErrPassSchedulesNotCovered = errors.New("events are not covered by pass schedules")
+	ErrPassNotRequired         = errors.New("event does not require passes")
+	ErrPassAlreadySent         = errors.New("pass is already sent")
+	ErrPassLocationNoRecipient = errors.New("pass location has no recipients")
+	ErrGuestAlreadyAdded       = errors.New("guest is already added to the event")
+
+	ErrAlreadyBanned       = errors.New("user is already banned")
+	ErrNotBanned           = errors.New("user is not banned")
+	ErrAlreadyShadowBanned = errors.New("user is already shadow banned")
+	ErrEmptyShadowBan      = errors.New("shadow ban has neither a user nor patterns")
+
+	ErrNotAdmin         = errors.New("user is not an admin")
+	ErrInvalidAdminRole = errors.New("invalid admin role")
+	ErrConfigAdmin      = errors.New("admin is set in the config")
+
+	ErrInvalidClubOwnerRole = errors.New("invalid club owner role")
+	ErrLastClubOwner        = errors.New("user is the last co-owner of the club")
+
+	ErrClubApplicationPending   = errors.New("user already has a pending club application")
+	ErrClubApplicationSubmitted = errors.New("club application is already submitted")
+	ErrClubApplicationReviewed  = errors.New("club application is already reviewed")
 )
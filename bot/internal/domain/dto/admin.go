@@ -0,0 +1,15 @@
+package dto
+
+import (
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/valueobject"
+)
+
+// Admin is the admin with the full name of the user
+type Admin struct {
+	UserID    int64
+	FIO       string
+	Role      valueobject.AdminRole
+	GrantedBy int64
+	// FromConfig is set for the admins of the config, they can't be revoked from the bot
+	FromConfig bool
+}
@@ -0,0 +1,14 @@
+package dto
+
+import (
+	"time"
+)
+
+// AuditFilter is the search of the audit log, zero fields are not filtered
+type AuditFilter struct {
+	ActorID  int64
+	TargetID string
+	From     *time.Time
+	To       *time.Time
+	Limit    int
+}
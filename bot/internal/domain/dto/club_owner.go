@@ -6,12 +6,13 @@ import (
 )
 
 type ClubOwner struct {
-	ClubID   string
-	UserID   int64
-	Username string
-	FIO      valueobject.FIO
-	Email    valueobject.Email
-	Role     entity.Role
-	IsBanned bool
-	Warnings bool
+	ClubID    string
+	UserID    int64
+	Username  string
+	FIO       valueobject.FIO
+	Email     valueobject.Email
+	Role      entity.Role
+	OwnerRole valueobject.ClubOwnerRole
+	IsBanned  bool
+	Warnings  bool
 }
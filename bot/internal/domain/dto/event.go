@@ -17,6 +17,7 @@ type Event struct {
 	StartTime       time.Time
 	EndTime         time.Time
 	IsRegistered    bool
+	IsRecurring     bool
 }
 
 func NewEventFromEntity(event entity.Event, isRegistered bool) Event {
@@ -31,5 +32,6 @@ func NewEventFromEntity(event entity.Event, isRegistered bool) Event {
 		StartTime:       event.StartTime,
 		EndTime:         event.EndTime,
 		IsRegistered:    isRegistered,
+		IsRecurring:     event.IsRecurring(),
 	}
 }
@@ -0,0 +1,16 @@
+package dto
+
+// MailingStats is the number of mailing recipients by delivery status
+type MailingStats struct {
+	Total   int
+	Pending int
+	Sent    int
+	Blocked int
+	Error   int
+	Skipped int
+}
+
+// Failed is the number of recipients the mailing may be retried for
+func (s MailingStats) Failed() int {
+	return s.Blocked + s.Error
+}
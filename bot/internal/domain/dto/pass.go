@@ -0,0 +1,11 @@
+package dto
+
+import (
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// EventPass is the pass of the event with the full name of the user or the guest it is issued to
+type EventPass struct {
+	Pass entity.Pass
+	FIO  string
+}
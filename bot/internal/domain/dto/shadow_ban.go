@@ -0,0 +1,11 @@
+package dto
+
+import (
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// ShadowBan is the shadow ban with the full name of the banned user, the name is empty for bans without a user
+type ShadowBan struct {
+	Ban entity.ShadowBan
+	FIO string
+}
@@ -0,0 +1,11 @@
+package dto
+
+import (
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// UserBan is the ban with the full name of the banned user
+type UserBan struct {
+	Ban entity.UserBan
+	FIO string
+}
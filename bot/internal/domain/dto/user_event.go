@@ -23,6 +23,7 @@ type UserEvent struct {
 	ExpectedParticipants  int
 	AllowedRoles          pq.StringArray
 	IsVisited             bool
+	CancelledAt           *time.Time
 }
 
 func NewUserEventFromEntity(event entity.Event, isVisited bool) UserEvent {
@@ -40,9 +41,14 @@ func NewUserEventFromEntity(event entity.Event, isVisited bool) UserEvent {
 		ExpectedParticipants:  event.ExpectedParticipants,
 		AllowedRoles:          event.AllowedRoles,
 		IsVisited:             isVisited,
+		CancelledAt:           event.CancelledAt,
 	}
 }
 
 func (e *UserEvent) IsOver(additionalTime time.Duration) bool {
 	return e.StartTime.Before(time.Now().In(location.Location()).Add(-additionalTime))
 }
+
+func (e *UserEvent) IsCancelled() bool {
+	return e.CancelledAt != nil
+}
@@ -0,0 +1,17 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/valueobject"
+)
+
+// Admin is the admin role granted to the user from the bot,
+// the admins of the config are superadmins without a record
+type Admin struct {
+	UserID    int64                 `gorm:"primaryKey;autoIncrement:false"`
+	Role      valueobject.AdminRole `gorm:"not null"`
+	GrantedBy int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,44 @@
+package entity
+
+import "time"
+
+// APIToken grants a club access to the HTTP API.
+//
+// Only the SHA-256 hash of the token is stored, the token itself is shown to the admin once when issued.
+type APIToken struct {
+	ID         string `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	ClubID     string `gorm:"type:uuid;not null;index"`
+	Name       string `gorm:"not null"`
+	TokenHash  string `gorm:"not null;uniqueIndex"`
+	CreatedBy  int64
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+
+	Club Club `gorm:"foreignKey:ClubID"`
+}
+
+// IsRevoked checks if the token was revoked by an admin
+func (t *APIToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+// Revoke marks the token as revoked, revoked tokens are kept for the audit log
+func (t *APIToken) Revoke() {
+	now := time.Now()
+	t.RevokedAt = &now
+}
+
+// APIRequestLog is an audit record of a single HTTP API request
+type APIRequestLog struct {
+	ID         string `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt  time.Time
+	TokenID    string `gorm:"type:uuid;not null;index"`
+	ClubID     string `gorm:"type:uuid;not null;index"`
+	Method     string `gorm:"not null"`
+	Path       string `gorm:"not null"`
+	StatusCode int
+	RemoteAddr string
+	DurationMs int64
+}
@@ -0,0 +1,68 @@
+package entity
+
+import (
+	"time"
+)
+
+type AuditAction string
+
+const (
+	AuditActionClubCreated             AuditAction = "club_created"
+	AuditActionClubUpdated             AuditAction = "club_updated"
+	AuditActionClubDeleted             AuditAction = "club_deleted"
+	AuditActionClubOwnerAdded          AuditAction = "club_owner_added"
+	AuditActionClubOwnerRemoved        AuditAction = "club_owner_removed"
+	AuditActionClubOwnerRoleChanged    AuditAction = "club_owner_role_changed"
+	AuditActionClubOwnerLeft           AuditAction = "club_owner_left"
+	AuditActionClubTransferred         AuditAction = "club_transferred"
+	AuditActionClubRolesChanged        AuditAction = "club_roles_changed"
+	AuditActionClubApplicationApproved AuditAction = "club_application_approved"
+	AuditActionClubApplicationRejected AuditAction = "club_application_rejected"
+	AuditActionUserBanned              AuditAction = "user_banned"
+	AuditActionUserUnbanned            AuditAction = "user_unbanned"
+	AuditActionShadowBanAdded          AuditAction = "shadow_ban_added"
+	AuditActionShadowBanRemoved        AuditAction = "shadow_ban_removed"
+	AuditActionAdminGranted            AuditAction = "admin_granted"
+	AuditActionAdminRevoked            AuditAction = "admin_revoked"
+	AuditActionMailingSent             AuditAction = "mailing_sent"
+	AuditActionEventCancelled          AuditAction = "event_cancelled"
+	AuditActionQRScanned               AuditAction = "qr_scanned"
+	AuditActionAPITokenIssued          AuditAction = "api_token_issued"
+	AuditActionAPITokenRevoked         AuditAction = "api_token_revoked"
+	AuditActionPassesAdded             AuditAction = "passes_added"
+	AuditActionPassCancelled           AuditAction = "pass_cancelled"
+	AuditActionPassLocationCreated     AuditAction = "pass_location_created"
+	AuditActionPassLocationUpdated     AuditAction = "pass_location_updated"
+	AuditActionPassScheduleCreated     AuditAction = "pass_schedule_created"
+	AuditActionPassScheduleUpdated     AuditAction = "pass_schedule_updated"
+)
+
+type AuditTarget string
+
+const (
+	AuditTargetClub            AuditTarget = "club"
+	AuditTargetClubApplication AuditTarget = "club_application"
+	AuditTargetUser            AuditTarget = "user"
+	AuditTargetEvent           AuditTarget = "event"
+	AuditTargetMailing         AuditTarget = "mailing"
+	AuditTargetShadowBan       AuditTarget = "shadow_ban"
+	AuditTargetAPIToken        AuditTarget = "api_token"
+	AuditTargetPass            AuditTarget = "pass"
+	AuditTargetPassLocation    AuditTarget = "pass_location"
+	AuditTargetPassSchedule    AuditTarget = "pass_schedule"
+)
+
+// AuditEntry is the record of a privileged action, the entries are never updated or deleted
+type AuditEntry struct {
+	ID        string    `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	CreatedAt time.Time `gorm:"index"`
+
+	ActorID    int64       `gorm:"not null;index"`
+	Action     AuditAction `gorm:"not null;index"`
+	TargetType AuditTarget `gorm:"not null"`
+	TargetID   string      `gorm:"not null;index"`
+
+	// Состояние цели до и после действия в JSON, nil если состояния нет
+	Before *string `gorm:"type:jsonb"`
+	After  *string `gorm:"type:jsonb"`
+}
@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type ClubApplicationStatus string
+
+const (
+	// ClubApplicationDraft means the applicant has not submitted the application yet
+	ClubApplicationDraft    ClubApplicationStatus = "draft"
+	ClubApplicationPending  ClubApplicationStatus = "pending"
+	ClubApplicationApproved ClubApplicationStatus = "approved"
+	ClubApplicationRejected ClubApplicationStatus = "rejected"
+)
+
+// ClubApplication is a request of the user to create a club, the club is created when an admin approves it
+type ClubApplication struct {
+	ID        string `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	ApplicantID int64  `gorm:"not null;index"`
+	Name        string `gorm:"not null"`
+	Description string
+	Link        string
+	// OwnerIDs - users proposed as the club owners besides the applicant
+	OwnerIDs pq.Int64Array `gorm:"type:bigint[]"`
+	// AllowedRoles - roles for which the club is going to create events
+	AllowedRoles pq.StringArray `gorm:"type:text[]"`
+
+	Status ClubApplicationStatus `gorm:"not null;default:'draft';index"`
+
+	// Рассмотрение заявки: ClubID заполняется при одобрении, Comment - причина отклонения
+	ReviewerID int64
+	ReviewedAt *time.Time
+	Comment    string
+	ClubID     *string `gorm:"type:uuid"`
+}
@@ -8,7 +8,9 @@ import (
 	"github.com/lib/pq"
 	"gorm.io/gorm"
 
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/valueobject"
 )
 
 type Event struct {
@@ -16,9 +18,10 @@ type Event struct {
 	CreatedAt             time.Time
 	UpdatedAt             time.Time
 	DeletedAt             gorm.DeletedAt
-	ClubID                string `gorm:"not null;type:uuid"`
-	Name                  string `gorm:"not null"`
-	Description           string `gorm:"not null"`
+	ClubID                string  `gorm:"not null;type:uuid"`
+	SeriesID              *string `gorm:"type:uuid;index"`
+	Name                  string  `gorm:"not null"`
+	Description           string  `gorm:"not null"`
 	AfterRegistrationText string
 	Location              string    `gorm:"not null"`
 	StartTime             time.Time `gorm:"not null"`
@@ -30,6 +33,9 @@ type Event struct {
 	QRFileID              string
 	AllowedRoles          pq.StringArray `gorm:"type:text[]"`
 	PassRequired          bool           `gorm:"default:false"`
+	PassLocationID        *string        `gorm:"type:uuid"`
+	CancelledAt           *time.Time
+	CancellationReason    string
 }
 
 // IsOver checks if the event is over, considering the additional time
@@ -42,6 +48,36 @@ func (e *Event) IsOver(additionalTime time.Duration) bool {
 	return e.StartTime.Before(time.Now().In(location.Location()).Add(-additionalTime))
 }
 
+// IsRegistrationActive checks if a user with the given role can still register for the event
+//
+// Students can register until RegistrationEnd, other roles also have to fit
+// into the pass deadline (see utils.GetMaxRegisteredEndTime)
+func (e *Event) IsRegistrationActive(role Role) bool {
+	now := time.Now().In(location.Location())
+	if role == valueobject.Student {
+		return e.RegistrationEnd.After(now)
+	}
+
+	return utils.GetMaxRegisteredEndTime(e.StartTime).After(now) && e.RegistrationEnd.After(now)
+}
+
+// IsCancelled checks if the event was cancelled by the club
+func (e *Event) IsCancelled() bool {
+	return e.CancelledAt != nil
+}
+
+// Cancel marks the event as cancelled, cancelled events stay in the club's history
+func (e *Event) Cancel(reason string) {
+	now := time.Now()
+	e.CancelledAt = &now
+	e.CancellationReason = reason
+}
+
+// IsRecurring checks if the event is an occurrence of an event series
+func (e *Event) IsRecurring() bool {
+	return e.SeriesID != nil
+}
+
 // Link generates a link to the event in the bot
 //
 // The link is in the format https://t.me/<botName>?start=event_<eventID>
@@ -64,44 +100,25 @@ func (e *Event) IsPassRequiredForUser(user *User, excludedRoles []string) bool {
 	return true
 }
 
-// CalculateScheduledAt calculates the scheduled time for pass sending based on cron schedule
+// SetPassLocation stores the pass location matched by the event location, nil means a pass is not required
+func (e *Event) SetPassLocation(location *PassLocation) {
+	e.PassRequired = location != nil
+	e.PassLocationID = nil
+	if location != nil {
+		e.PassLocationID = &location.ID
+	}
+}
+
+// CalculateScheduledAt calculates the scheduled time for pass sending based on the schedules of its pass location
 //
-// Правила отправки:
-// - События в Воскресенье или Понедельник → отправка в предыдущую Субботу 12:00
-// - События во Вторник-Пятницу → отправка в предыдущий день 16:00
-// - События в Субботу → отправка в Пятницу 16:00
-func (e *Event) CalculateScheduledAt() time.Time {
-	loc := location.Location()
-	eventStart := e.StartTime.In(loc)
-	eventWeekday := eventStart.Weekday()
-
-	var scheduledAt time.Time
-
-	switch eventWeekday {
-	case time.Sunday:
-		// Воскресенье → отправка в Субботу 12:00
-		daysToSubtract := 1
-		sendDay := eventStart.AddDate(0, 0, -daysToSubtract)
-		scheduledAt = time.Date(sendDay.Year(), sendDay.Month(), sendDay.Day(), 12, 0, 0, 0, loc)
-
-	case time.Monday:
-		// Понедельник → отправка в Субботу 12:00
-		daysToSubtract := 2
-		sendDay := eventStart.AddDate(0, 0, -daysToSubtract)
-		scheduledAt = time.Date(sendDay.Year(), sendDay.Month(), sendDay.Day(), 12, 0, 0, 0, loc)
-
-	case time.Saturday:
-		// Суббота → отправка в Пятницу 16:00
-		daysToSubtract := 1
-		sendDay := eventStart.AddDate(0, 0, -daysToSubtract)
-		scheduledAt = time.Date(sendDay.Year(), sendDay.Month(), sendDay.Day(), 16, 0, 0, 0, loc)
-
-	default:
-		// Вторник-Пятница → отправка в предыдущий день 16:00
-		daysToSubtract := 1
-		sendDay := eventStart.AddDate(0, 0, -daysToSubtract)
-		scheduledAt = time.Date(sendDay.Year(), sendDay.Month(), sendDay.Day(), 16, 0, 0, 0, loc)
+// Пропуски отправляются последним запуском активного расписания, после которого
+// до начала события остаётся не меньше его LeadTime (см. PassSchedule).
+// Если ни один запуск не покрывает событие, пропуски отправит первый запуск
+// не ранее чем за PassScheduleMaxAdvance до начала события
+func (e *Event) CalculateScheduledAt(schedules []PassSchedule) time.Time {
+	if scheduledAt, ok := CalculatePassScheduledAt(e.StartTime, schedules); ok {
+		return scheduledAt
 	}
 
-	return scheduledAt
+	return e.StartTime.In(location.Location()).Add(-PassScheduleMaxAdvance)
 }
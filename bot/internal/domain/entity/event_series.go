@@ -0,0 +1,194 @@
+package entity
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+)
+
+type RecurrenceRule string
+
+const (
+	RecurrenceWeekly   RecurrenceRule = "weekly"
+	RecurrenceBiweekly RecurrenceRule = "biweekly"
+	RecurrenceCustom   RecurrenceRule = "custom"
+)
+
+// EventSeriesHorizon is how far ahead weekly and biweekly occurrences are materialised
+const EventSeriesHorizon = 28 * 24 * time.Hour
+
+// EventSeries is a template for recurring events.
+//
+// Occurrences are regular Event rows linked through Event.SeriesID. Weekly and biweekly
+// series are materialised ahead of time up to EventSeriesHorizon, custom series are
+// materialised completely on creation.
+type EventSeries struct {
+	ID        string `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt
+	ClubID    string         `gorm:"not null;type:uuid"`
+	Rule      RecurrenceRule `gorm:"not null"`
+	// Until is the last moment an occurrence may start at (weekly and biweekly)
+	Until time.Time
+	// Dates are start times of custom occurrences in RFC3339
+	Dates pq.StringArray `gorm:"type:text[]"`
+	// CancelledDates are start times of cancelled occurrences in RFC3339
+	CancelledDates pq.StringArray `gorm:"type:text[]"`
+	// LastOccurrenceAt is the start time of the latest materialised occurrence
+	LastOccurrenceAt time.Time
+
+	Name                  string `gorm:"not null"`
+	Description           string
+	AfterRegistrationText string
+	Location              string `gorm:"not null"`
+	// Duration is the time between start and end of an occurrence, 0 if end time is not set
+	Duration time.Duration
+	// RegistrationOffset is the time between registration end and start of an occurrence
+	RegistrationOffset   time.Duration
+	MaxParticipants      int
+	ExpectedParticipants int
+	AllowedRoles         pq.StringArray `gorm:"type:text[]"`
+	PassRequired         bool           `gorm:"default:false"`
+	PassLocationID       *string        `gorm:"type:uuid"`
+}
+
+// NewEventSeries creates a series using the event as a template for all occurrences
+func NewEventSeries(event Event, rule RecurrenceRule, until time.Time, dates []time.Time) *EventSeries {
+	series := &EventSeries{
+		ClubID:                event.ClubID,
+		Rule:                  rule,
+		Until:                 until,
+		Name:                  event.Name,
+		Description:           event.Description,
+		AfterRegistrationText: event.AfterRegistrationText,
+		Location:              event.Location,
+		RegistrationOffset:    event.StartTime.Sub(event.RegistrationEnd),
+		MaxParticipants:       event.MaxParticipants,
+		ExpectedParticipants:  event.ExpectedParticipants,
+		AllowedRoles:          event.AllowedRoles,
+		PassRequired:          event.PassRequired,
+		PassLocationID:        event.PassLocationID,
+	}
+	if !event.EndTime.IsZero() {
+		series.Duration = event.EndTime.Sub(event.StartTime)
+	}
+	for _, date := range dates {
+		series.Dates = append(series.Dates, date.Format(time.RFC3339))
+	}
+
+	return series
+}
+
+// Interval returns the number of days between occurrences, 0 for custom series
+func (s *EventSeries) Interval() int {
+	switch s.Rule {
+	case RecurrenceWeekly:
+		return 7
+	case RecurrenceBiweekly:
+		return 14
+	default:
+		return 0
+	}
+}
+
+// Occurrence builds an event of the series starting at the given time
+func (s *EventSeries) Occurrence(startTime time.Time) Event {
+	event := Event{
+		ClubID:                s.ClubID,
+		SeriesID:              &s.ID,
+		Name:                  s.Name,
+		Description:           s.Description,
+		AfterRegistrationText: s.AfterRegistrationText,
+		Location:              s.Location,
+		StartTime:             startTime.UTC(),
+		RegistrationEnd:       startTime.Add(-s.RegistrationOffset).UTC(),
+		MaxParticipants:       s.MaxParticipants,
+		ExpectedParticipants:  s.ExpectedParticipants,
+		AllowedRoles:          s.AllowedRoles,
+		PassRequired:          s.PassRequired,
+		PassLocationID:        s.PassLocationID,
+	}
+	if s.Duration > 0 {
+		event.EndTime = startTime.Add(s.Duration).UTC()
+	}
+
+	return event
+}
+
+// NextOccurrences returns start times of occurrences that have to be materialised before the given time
+func (s *EventSeries) NextOccurrences(before time.Time) []time.Time {
+	interval := s.Interval()
+	if interval == 0 || s.LastOccurrenceAt.IsZero() {
+		return nil
+	}
+
+	var starts []time.Time
+	next := s.LastOccurrenceAt.In(location.Location()).AddDate(0, 0, interval)
+	for !next.After(s.Until) && next.Before(before) {
+		starts = append(starts, next)
+		next = next.AddDate(0, 0, interval)
+	}
+
+	return starts
+}
+
+// CustomDates returns parsed start times of custom occurrences
+func (s *EventSeries) CustomDates() []time.Time {
+	return parseDates(s.Dates)
+}
+
+// CancelledOccurrences returns parsed start times of cancelled occurrences
+func (s *EventSeries) CancelledOccurrences() []time.Time {
+	return parseDates(s.CancelledDates)
+}
+
+// CancelOccurrence remembers that the occurrence starting at the given time was cancelled
+func (s *EventSeries) CancelOccurrence(startTime time.Time) {
+	date := startTime.Format(time.RFC3339)
+	if slices.Contains(s.CancelledDates, date) {
+		return
+	}
+	s.CancelledDates = append(s.CancelledDates, date)
+}
+
+// ApplyTemplate copies editable fields of the event into the series template
+func (s *EventSeries) ApplyTemplate(event Event) {
+	s.Name = event.Name
+	s.Description = event.Description
+	s.AfterRegistrationText = event.AfterRegistrationText
+	s.Location = event.Location
+	s.MaxParticipants = event.MaxParticipants
+	s.ExpectedParticipants = event.ExpectedParticipants
+	s.AllowedRoles = event.AllowedRoles
+	s.PassRequired = event.PassRequired
+	s.PassLocationID = event.PassLocationID
+}
+
+// RRule returns the iCalendar recurrence rule of the series, empty for custom series
+func (s *EventSeries) RRule() string {
+	interval := s.Interval()
+	if interval == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("FREQ=WEEKLY;INTERVAL=%d;UNTIL=%s", interval/7, s.Until.UTC().Format("20060102T150405Z"))
+}
+
+func parseDates(values []string) []time.Time {
+	dates := make([]time.Time, 0, len(values))
+	for _, value := range values {
+		date, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			continue
+		}
+		dates = append(dates, date)
+	}
+
+	return dates
+}
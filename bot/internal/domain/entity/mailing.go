@@ -0,0 +1,77 @@
+package entity
+
+import "time"
+
+type MailingAudience string
+
+const (
+	MailingAudienceClub            MailingAudience = "club"
+	MailingAudienceEventRegistered MailingAudience = "event_registered"
+	MailingAudienceEventVisited    MailingAudience = "event_visited"
+)
+
+type MailingRecipientStatus string
+
+const (
+	MailingRecipientPending MailingRecipientStatus = "pending"
+	MailingRecipientSent    MailingRecipientStatus = "sent"
+	// MailingRecipientBlocked means the user has blocked the bot
+	MailingRecipientBlocked MailingRecipientStatus = "blocked"
+	MailingRecipientError   MailingRecipientStatus = "error"
+	// MailingRecipientSkipped means the user has disabled mailings from the club
+	MailingRecipientSkipped MailingRecipientStatus = "skipped"
+)
+
+// Mailing is a message sent by a club owner to the club or event audience.
+//
+// Scheduled mailings have ScheduledAt set, their recipients are resolved when the mailing is sent.
+type Mailing struct {
+	ID        string `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	ClubID    string  `gorm:"type:uuid;not null;index"`
+	EventID   *string `gorm:"type:uuid"`
+	AuthorID  int64
+	Audience  MailingAudience `gorm:"not null"`
+	MessageContent
+	ScheduledAt *time.Time `gorm:"index"`
+	SentAt      *time.Time
+	CancelledAt *time.Time
+
+	Recipients []MailingRecipient `gorm:"foreignKey:MailingID"`
+}
+
+// IsScheduled checks if the mailing is waiting to be sent
+func (m *Mailing) IsScheduled() bool {
+	return m.ScheduledAt != nil && m.SentAt == nil && m.CancelledAt == nil
+}
+
+// IsDue checks if the scheduled mailing should be sent at t
+func (m *Mailing) IsDue(t time.Time) bool {
+	return m.IsScheduled() && !m.ScheduledAt.After(t)
+}
+
+// Cancel cancels the scheduled mailing
+func (m *Mailing) Cancel() {
+	now := time.Now()
+	m.CancelledAt = &now
+}
+
+// MarkSent marks the mailing as sent
+func (m *Mailing) MarkSent() {
+	now := time.Now()
+	m.SentAt = &now
+}
+
+type MailingRecipient struct {
+	MailingID string `gorm:"primaryKey;type:uuid"`
+	UserID    int64  `gorm:"primaryKey"`
+	UpdatedAt time.Time
+	Status    MailingRecipientStatus `gorm:"not null;index"`
+	Error     string
+}
+
+// IsFailed checks if the message was not delivered to the recipient and may be retried
+func (r *MailingRecipient) IsFailed() bool {
+	return r.Status == MailingRecipientError || r.Status == MailingRecipientBlocked
+}
@@ -0,0 +1,20 @@
+package entity
+
+type MessageKind string
+
+const (
+	MessageText     MessageKind = "text"
+	MessagePhoto    MessageKind = "photo"
+	MessageVideo    MessageKind = "video"
+	MessageAudio    MessageKind = "audio"
+	MessageDocument MessageKind = "document"
+)
+
+// MessageContent is a Telegram message stored to be sent later.
+//
+// Media messages store only the Telegram file ID, Text is the message text or the media caption.
+type MessageContent struct {
+	Kind   MessageKind `gorm:"not null"`
+	FileID string
+	Text   string
+}
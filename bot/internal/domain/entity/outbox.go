@@ -0,0 +1,51 @@
+package entity
+
+import "time"
+
+type OutboxMessageStatus string
+
+const (
+	OutboxMessagePending OutboxMessageStatus = "pending"
+	OutboxMessageSent    OutboxMessageStatus = "sent"
+	OutboxMessageFailed  OutboxMessageStatus = "failed"
+)
+
+// OutboxMessage is a Telegram message waiting to be delivered by the outbox workers.
+//
+// Markup is the JSON encoded inline keyboard of the message.
+// MailingID is set for messages of club mailings, their delivery status is reported to the mailing.
+type OutboxMessage struct {
+	ID        string `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	MessageContent
+	ChatID        int64   `gorm:"not null;index"`
+	MailingID     *string `gorm:"type:uuid;index"`
+	ParseMode     string
+	Markup        string
+	Status        OutboxMessageStatus `gorm:"not null;default:pending;index:idx_outbox_messages_due,priority:1"`
+	Attempts      int
+	NextAttemptAt time.Time `gorm:"not null;index:idx_outbox_messages_due,priority:2"`
+	LastError     string
+	SentAt        *time.Time
+}
+
+// MarkSent marks the message as delivered
+func (m *OutboxMessage) MarkSent() {
+	now := time.Now()
+	m.Status = OutboxMessageSent
+	m.SentAt = &now
+	m.LastError = ""
+}
+
+// MarkFailed marks the message as undeliverable, it won't be retried anymore
+func (m *OutboxMessage) MarkFailed(err error) {
+	m.Status = OutboxMessageFailed
+	m.LastError = err.Error()
+}
+
+// Retry schedules the next delivery attempt of the message
+func (m *OutboxMessage) Retry(at time.Time, err error) {
+	m.NextAttemptAt = at
+	m.LastError = err.Error()
+}
@@ -35,16 +35,25 @@ type Pass struct {
 	UpdatedAt time.Time
 
 	EventID string     `gorm:"type:uuid;not null;index"`
-	UserID  int64      `gorm:"not null;index"`
+	UserID  int64      `gorm:"not null;index"` // 0 для гостя без аккаунта в боте
 	Type    PassType   `gorm:"not null;default:'event'"`
 	Status  PassStatus `gorm:"not null;default:'pending'"`
 
 	RequesterType PassRequesterType `gorm:"type:varchar(20);not null;default:'user';index"`
 	RequesterID   string            `gorm:"not null;index"` // ID запросчика (int64 как string для user/admin, UUID для club)
 
+	// Гость мероприятия без аккаунта в боте (спикер, приглашённый)
+	GuestFIO   string
+	GuestPhone string
+
 	// Расписание и отправка
 	ScheduledAt time.Time
 	SentAt      *time.Time
+	BatchID     *string    `gorm:"type:uuid;index"` // Сводка или поправка, в которой отправлен пропуск
+	NotifiedAt  *time.Time // Время, когда владельцу пропуска сообщили об отправке охране
+
+	// Поправка к сводке: время, когда охране сообщили об отмене уже отправленного пропуска
+	CancellationSentAt *time.Time
 
 	// Дополнительная информация
 	Reason       string // Причина создания пропуска
@@ -53,6 +62,11 @@ type Pass struct {
 	TelegramSent bool   `gorm:"default:false"`
 }
 
+// IsGuest checks if the pass is issued to a guest without a bot account
+func (p *Pass) IsGuest() bool {
+	return p.GuestFIO != ""
+}
+
 func (p *Pass) IsExpired() bool {
 	if p.Status == PassStatusSent {
 		return false
@@ -73,6 +87,12 @@ func (p *Pass) MarkAsSent(emailSent, telegramSent bool) {
 	p.UpdatedAt = now
 }
 
+// IsCancelledAfterSend checks if the pass was cancelled after it had been sent to the security
+// and the security still doesn't know about it
+func (p *Pass) IsCancelledAfterSend() bool {
+	return p.Status == PassStatusCancelled && p.SentAt != nil && p.CancellationSentAt == nil
+}
+
 func (p *Pass) Cancel() {
 	p.Status = PassStatusCancelled
 	p.UpdatedAt = time.Now()
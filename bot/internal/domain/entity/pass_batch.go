@@ -0,0 +1,160 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+type PassBatchKind string
+
+const (
+	PassBatchKindReport     PassBatchKind = "report"
+	PassBatchKindCorrection PassBatchKind = "correction"
+)
+
+type PassDeliveryChannel string
+
+const (
+	PassDeliveryChannelTelegram PassDeliveryChannel = "telegram"
+	PassDeliveryChannelEmail    PassDeliveryChannel = "email"
+)
+
+type PassDeliveryStatus string
+
+const (
+	PassDeliveryStatusPending PassDeliveryStatus = "pending"
+	PassDeliveryStatusSent    PassDeliveryStatus = "sent"
+	// PassDeliveryStatusFailed means the delivery is not retried anymore, the events of the batch have started
+	PassDeliveryStatusFailed PassDeliveryStatus = "failed"
+)
+
+// errPassBatchDeadline is the error of the deliveries which were not attempted before the deadline
+var errPassBatchDeadline = errors.New("deadline passed")
+
+// PassBatch is a consolidated pass report or a correction sent to the security of the location
+//
+// The message and the file are stored, so failed deliveries are retried with the same content
+// until the Deadline and an admin can resend the batch
+type PassBatch struct {
+	ID        string `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	LocationID *string       `gorm:"type:uuid;index"`
+	Kind       PassBatchKind `gorm:"not null"`
+	Subject    string        `gorm:"not null"`
+	Message    string        `gorm:"not null"`
+	EmailText  string
+	FileName   string
+	File       []byte
+
+	// Deadline is the start of the earliest event of the batch
+	Deadline  time.Time `gorm:"not null"`
+	AlertedAt *time.Time
+
+	Deliveries []PassBatchDelivery `gorm:"foreignKey:BatchID"`
+}
+
+// PassBatchDelivery is the delivery of the batch to a single Telegram chat or email recipient
+type PassBatchDelivery struct {
+	ID        string `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	BatchID       string              `gorm:"type:uuid;not null;index"`
+	Channel       PassDeliveryChannel `gorm:"not null"`
+	Recipient     string              `gorm:"not null"`
+	Status        PassDeliveryStatus  `gorm:"not null;default:'pending';index"`
+	Attempts      int                 `gorm:"not null;default:0"`
+	LastError     string
+	NextAttemptAt time.Time
+	SentAt        *time.Time
+}
+
+// PassDeliveryBackoff returns the delay before the next attempt after the given number of failed attempts:
+// a minute after the first failure, doubled after every next one, but not more than an hour
+func PassDeliveryBackoff(attempts int) time.Duration {
+	backoff := time.Minute
+	for i := 1; i < attempts && backoff < time.Hour; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, time.Hour)
+}
+
+// IsDue checks if the delivery should be attempted at t
+func (d *PassBatchDelivery) IsDue(t time.Time) bool {
+	return d.Status == PassDeliveryStatusPending && !d.NextAttemptAt.After(t)
+}
+
+// MarkSent marks the delivery as successful
+func (d *PassBatchDelivery) MarkSent() {
+	now := time.Now()
+	d.Status = PassDeliveryStatusSent
+	d.Attempts++
+	d.LastError = ""
+	d.SentAt = &now
+}
+
+// Fail records the failed attempt and schedules the next one,
+// the delivery is given up if the next attempt would be after the deadline
+func (d *PassBatchDelivery) Fail(err error, deadline time.Time) {
+	d.Attempts++
+	d.LastError = err.Error()
+	d.NextAttemptAt = time.Now().Add(PassDeliveryBackoff(d.Attempts))
+	if !d.NextAttemptAt.Before(deadline) {
+		d.Status = PassDeliveryStatusFailed
+	}
+}
+
+// Expire gives up the delivery which was not attempted before the deadline
+func (d *PassBatchDelivery) Expire() {
+	d.Status = PassDeliveryStatusFailed
+	if d.LastError == "" {
+		d.LastError = errPassBatchDeadline.Error()
+	}
+}
+
+// Reset makes the delivery pending again to send it once more right away
+func (d *PassBatchDelivery) Reset() {
+	d.Status = PassDeliveryStatusPending
+	d.NextAttemptAt = time.Now()
+}
+
+// IsDelivered checks if the batch was delivered to every recipient, a batch without recipients is never delivered
+func (b *PassBatch) IsDelivered() bool {
+	if len(b.Deliveries) == 0 {
+		return false
+	}
+
+	for _, delivery := range b.Deliveries {
+		if delivery.Status != PassDeliveryStatusSent {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsDeliveredTo checks if the batch was delivered to at least one recipient of the channel
+func (b *PassBatch) IsDeliveredTo(channel PassDeliveryChannel) bool {
+	for _, delivery := range b.Deliveries {
+		if delivery.Channel == channel && delivery.Status == PassDeliveryStatusSent {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CountDelivered returns the number of recipients the batch was delivered to
+func (b *PassBatch) CountDelivered() int {
+	count := 0
+	for _, delivery := range b.Deliveries {
+		if delivery.Status == PassDeliveryStatusSent {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// PassLocation is a building with its own security desk
+//
+// Events whose location matches one of the patterns require a pass,
+// their passes are sent to the recipients of the building by its schedules
+type PassLocation struct {
+	ID        string `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	Name            string         `gorm:"not null;unique"`
+	Patterns        pq.StringArray `gorm:"type:text[]"`
+	EmailRecipients pq.StringArray `gorm:"type:text[]"`
+	TelegramChatID  int64
+}
+
+// HasRecipients checks if the location has a Telegram chat or an email to send the passes to
+func (l *PassLocation) HasRecipients() bool {
+	return l.TelegramChatID != 0 || len(l.EmailRecipients) > 0
+}
+
+// Matches checks if the event location contains one of the patterns,
+// a location without patterns matches every event
+func (l *PassLocation) Matches(eventLocation string) bool {
+	if len(l.Patterns) == 0 {
+		return true
+	}
+
+	for _, pattern := range l.Patterns {
+		if strings.Contains(strings.ToLower(eventLocation), strings.ToLower(pattern)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MatchPassLocation returns the pass location of the event location or nil if a pass is not required,
+// locations with patterns are checked before the ones matching every event
+func MatchPassLocation(eventLocation string, locations []PassLocation) *PassLocation {
+	var fallback *PassLocation
+	for i := range locations {
+		if len(locations[i].Patterns) == 0 {
+			if fallback == nil {
+				fallback = &locations[i]
+			}
+			continue
+		}
+
+		if locations[i].Matches(eventLocation) {
+			return &locations[i]
+		}
+	}
+
+	return fallback
+}
+
+// PassLocationOf returns the location the passes of the event are sent to,
+// passes of events without a stored location (e.g. requested via API) go to the first location
+func PassLocationOf(event *Event, locations []PassLocation) *PassLocation {
+	if len(locations) == 0 {
+		return nil
+	}
+
+	if event.PassLocationID != nil {
+		for i := range locations {
+			if locations[i].ID == *event.PassLocationID {
+				return &locations[i]
+			}
+		}
+	}
+
+	return &locations[0]
+}
@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+)
+
+// PassScheduleMaxAdvance is the longest time between the pass report run and the event start,
+// earlier runs are not considered to cover the event
+const PassScheduleMaxAdvance = 72 * time.Hour
+
+// PassSchedule is a run of the consolidated pass report sent to the security desk of the location
+//
+// The run covers the events of the location which start not earlier than LeadTime after it,
+// the passes of the event are sent by the latest run covering it
+type PassSchedule struct {
+	ID        string `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	LocationID   *string       `gorm:"type:uuid;index"`
+	Name         string        `gorm:"not null;unique"`
+	CronSchedule string        `gorm:"not null"`
+	LeadTime     time.Duration `gorm:"not null"`
+	IsActive     bool          `gorm:"not null"`
+}
+
+// BelongsTo checks if the schedule sends the passes of the location
+func (s *PassSchedule) BelongsTo(passLocation *PassLocation) bool {
+	return passLocation != nil && s.LocationID != nil && *s.LocationID == passLocation.ID
+}
+
+// Validate checks the cron expression and the lead time of the schedule
+func (s *PassSchedule) Validate() error {
+	if _, err := cron.ParseStandard(s.CronSchedule); err != nil {
+		return fmt.Errorf("invalid cron schedule %q: %w", s.CronSchedule, err)
+	}
+
+	if s.LeadTime < 0 || s.LeadTime >= PassScheduleMaxAdvance {
+		return fmt.Errorf("lead time must be between 0 and %s", PassScheduleMaxAdvance)
+	}
+
+	return nil
+}
+
+// LastRunFor returns the latest run of the schedule which covers the event starting at startTime
+func (s *PassSchedule) LastRunFor(startTime time.Time) (time.Time, bool) {
+	schedule, err := cron.ParseStandard(s.CronSchedule)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	startTime = startTime.In(location.Location())
+	deadline := startTime.Add(-s.LeadTime)
+
+	var last time.Time
+	for run := schedule.Next(startTime.Add(-PassScheduleMaxAdvance - time.Second)); !run.IsZero() && !run.After(deadline); run = schedule.Next(run) {
+		last = run
+	}
+
+	return last, !last.IsZero()
+}
+
+// CalculatePassScheduledAt returns the latest run of the active schedules covering the event starting at startTime
+func CalculatePassScheduledAt(startTime time.Time, schedules []PassSchedule) (time.Time, bool) {
+	var scheduledAt time.Time
+	for i := range schedules {
+		if !schedules[i].IsActive {
+			continue
+		}
+
+		run, ok := schedules[i].LastRunFor(startTime)
+		if ok && run.After(scheduledAt) {
+			scheduledAt = run
+		}
+	}
+
+	return scheduledAt, !scheduledAt.IsZero()
+}
+
+// FindUncoveredPassTime checks that every event starting within the week after from is covered
+// by some run of the active schedules, the first start time which is not covered is returned
+func FindUncoveredPassTime(schedules []PassSchedule, from time.Time) (time.Time, bool) {
+	const step = 30 * time.Minute
+
+	from = from.In(location.Location()).Truncate(step)
+	for startTime := from; startTime.Before(from.AddDate(0, 0, 7)); startTime = startTime.Add(step) {
+		if _, ok := CalculatePassScheduledAt(startTime, schedules); !ok {
+			return startTime, true
+		}
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// ShadowBan hides the user from the participants lists and counts without telling the user,
+// the security is told not to let the user in
+//
+// The ban matches the user by ID, the FIO patterns ("Имя Фамилия") match the guests without a bot account.
+// A ban without a user matches everyone with the name and surname of its patterns
+type ShadowBan struct {
+	ID        string `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+
+	UserID    int64          `gorm:"index"` // 0 для бана только по ФИО
+	Patterns  pq.StringArray `gorm:"type:text[]"`
+	Reason    string
+	AuthorID  int64
+	ExpiresAt *time.Time // nil для бессрочного бана
+}
+
+// IsActive checks if the ban is not expired at t
+func (b *ShadowBan) IsActive(t time.Time) bool {
+	return b.ExpiresAt == nil || b.ExpiresAt.After(t)
+}
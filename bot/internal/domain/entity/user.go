@@ -22,14 +22,16 @@ type User struct {
 	FIO           valueobject.FIO   `gorm:"not null"`
 	QRCodeID      string
 	QRFileID      string
+	BotBlockedAt  *time.Time
 	IsBanned      bool            `gorm:"default:false"`
 	Clubs         []Club          `gorm:"many2many:club_owners;foreignKey:ID;joinForeignKey:UserID;References:ID;JoinReferences:ClubID"`
 	IgnoreMailing []IgnoreMailing `gorm:"foreignKey:UserID;references:ID"`
 }
 
 type ClubOwner struct {
-	UserID    int64  `gorm:"primaryKey"`
-	ClubID    string `gorm:"primaryKey;type:uuid"`
+	UserID    int64                     `gorm:"primaryKey"`
+	ClubID    string                    `gorm:"primaryKey;type:uuid"`
+	Role      valueobject.ClubOwnerRole `gorm:"not null;default:'co_owner'"`
 	Warnings  bool
 	CreatedAt time.Time
 }
@@ -49,6 +51,13 @@ type IgnoreMailing struct {
 	CreatedAt time.Time
 }
 
+// ClubFollower is a user who follows the club and receives its mailings and announcements
+type ClubFollower struct {
+	UserID    int64  `gorm:"primaryKey"`
+	ClubID    string `gorm:"primaryKey;type:uuid;index"`
+	CreatedAt time.Time
+}
+
 type FIO = valueobject.FIO
 
 func (u *User) GetFIO() FIO {
@@ -59,6 +68,11 @@ func (u *User) GetEmail() valueobject.Email {
 	return u.Email
 }
 
+// IsBotBlocked checks if the user has blocked the bot, messages to such users are not delivered
+func (u *User) IsBotBlocked() bool {
+	return u.BotBlockedAt != nil
+}
+
 func (u *User) IsMailingAllowed(clubID string) bool {
 	for _, ignoreMailing := range u.IgnoreMailing {
 		if ignoreMailing.ClubID == clubID {
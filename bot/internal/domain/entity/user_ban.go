@@ -0,0 +1,32 @@
+package entity
+
+import "time"
+
+// UserBan is the record of the user ban, User.IsBanned is set while the ban is active
+type UserBan struct {
+	ID        string `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	UserID    int64 `gorm:"not null;index"`
+	AdminID   int64 `gorm:"not null"`
+	Reason    string
+	StartedAt time.Time  `gorm:"not null"`
+	ExpiresAt *time.Time `gorm:"index"` // nil для бессрочного бана
+
+	// Снятие бана: LiftedBy равен 0, если бан снят автоматически по истечении срока
+	LiftedAt *time.Time `gorm:"index"`
+	LiftedBy int64
+}
+
+// IsActive checks if the ban is neither lifted nor expired at t
+func (b *UserBan) IsActive(t time.Time) bool {
+	return b.LiftedAt == nil && (b.ExpiresAt == nil || b.ExpiresAt.After(t))
+}
+
+// Lift lifts the ban, adminID is 0 when the ban expires
+func (b *UserBan) Lift(adminID int64) {
+	now := time.Now()
+	b.LiftedAt = &now
+	b.LiftedBy = adminID
+}
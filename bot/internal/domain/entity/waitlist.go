@@ -0,0 +1,48 @@
+package entity
+
+import "time"
+
+type WaitlistStatus string
+
+const (
+	WaitlistStatusWaiting   WaitlistStatus = "waiting"
+	WaitlistStatusOffered   WaitlistStatus = "offered"
+	WaitlistStatusConfirmed WaitlistStatus = "confirmed"
+	WaitlistStatusExpired   WaitlistStatus = "expired"
+	WaitlistStatusCancelled WaitlistStatus = "cancelled"
+)
+
+// WaitlistEntry represents a user waiting for a free seat on a full event.
+//
+// When a seat frees up the oldest waiting entry is promoted: the user is registered,
+// the entry becomes offered and the user has to confirm the seat before ConfirmDeadline.
+type WaitlistEntry struct {
+	ID              string `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	EventID         string         `gorm:"type:uuid;not null;index"`
+	UserID          int64          `gorm:"not null;index"`
+	Status          WaitlistStatus `gorm:"not null;default:waiting;index"`
+	OfferedAt       *time.Time
+	ConfirmDeadline *time.Time
+
+	Event Event `gorm:"foreignKey:EventID"`
+	User  User  `gorm:"foreignKey:UserID"`
+}
+
+// IsActive reports whether the entry still holds a place in the waitlist
+func (w *WaitlistEntry) IsActive() bool {
+	return w.Status == WaitlistStatusWaiting || w.Status == WaitlistStatusOffered
+}
+
+// IsOfferExpired reports whether the seat offer was not confirmed in time
+func (w *WaitlistEntry) IsOfferExpired(now time.Time) bool {
+	return w.Status == WaitlistStatusOffered && w.ConfirmDeadline != nil && now.After(*w.ConfirmDeadline)
+}
+
+// Offer marks the entry as promoted with the given confirmation deadline
+func (w *WaitlistEntry) Offer(now, deadline time.Time) {
+	w.Status = WaitlistStatusOffered
+	w.OfferedAt = &now
+	w.ConfirmDeadline = &deadline
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/valueobject"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/secondary"
+	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
+)
+
+/*
+AdminService - сервис ролей администраторов.
+
+Основные принципы работы:
+- Роли выдаются из бота и хранятся в базе (entity.Admin), права роли описаны в valueobject.AdminRole
+- Администраторы из bot.admin-ids всегда суперадмины, их роль нельзя изменить или отозвать из бота
+- Роль проверяется при каждом действии администратора, выданная или отозванная роль действует сразу
+*/
+type AdminService struct {
+	logger *types.Logger
+
+	repo     secondary.AdminRepository
+	userRepo secondary.UserRepository
+
+	// configAdminIDs are the superadmins of the config
+	configAdminIDs []int64
+}
+
+func NewAdminService(
+	logger *types.Logger,
+	repo secondary.AdminRepository,
+	userRepo secondary.UserRepository,
+	configAdminIDs []int64,
+) *AdminService {
+	return &AdminService{
+		logger:         logger,
+		repo:           repo,
+		userRepo:       userRepo,
+		configAdminIDs: configAdminIDs,
+	}
+}
+
+// GetRole returns the admin role of the user, errorz.ErrNotAdmin if the user is not an admin
+func (s *AdminService) GetRole(ctx context.Context, userID int64) (valueobject.AdminRole, error) {
+	if utils.IsAdmin(userID, s.configAdminIDs) {
+		return valueobject.SuperAdmin, nil
+	}
+
+	admin, err := s.repo.Get(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", errorz.ErrNotAdmin
+		}
+		return "", err
+	}
+
+	return admin.Role, nil
+}
+
+// Grant grants the role to the user or changes the role of the admin
+func (s *AdminService) Grant(ctx context.Context, userID int64, role valueobject.AdminRole, grantedBy int64) (*entity.User, error) {
+	if !role.IsValid() {
+		return nil, errorz.ErrInvalidAdminRole
+	}
+	if utils.IsAdmin(userID, s.configAdminIDs) {
+		return nil, errorz.ErrConfigAdmin
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.repo.Save(ctx, &entity.Admin{
+		UserID:    userID,
+		Role:      role,
+		GrantedBy: grantedBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("Admin role %s granted to %d by %d", role, userID, grantedBy)
+	return user, nil
+}
+
+// Revoke revokes the admin role of the user, errorz.ErrNotAdmin if the user is not an admin
+func (s *AdminService) Revoke(ctx context.Context, userID int64) (*entity.User, error) {
+	if utils.IsAdmin(userID, s.configAdminIDs) {
+		return nil, errorz.ErrConfigAdmin
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.repo.Delete(ctx, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorz.ErrNotAdmin
+		}
+		return nil, err
+	}
+
+	s.logger.Infof("Admin role of %d revoked", userID)
+	return user, nil
+}
+
+// GetAll returns the admins of the config followed by the admins granted from the bot
+func (s *AdminService) GetAll(ctx context.Context) ([]dto.Admin, error) {
+	admins, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]int64, 0, len(s.configAdminIDs)+len(admins))
+	userIDs = append(userIDs, s.configAdminIDs...)
+	for _, admin := range admins {
+		userIDs = append(userIDs, admin.UserID)
+	}
+
+	users, err := s.userRepo.GetMany(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get admin users: %w", err)
+	}
+
+	names := make(map[int64]string, len(users))
+	for _, user := range users {
+		names[user.ID] = user.FIO.String()
+	}
+
+	result := make([]dto.Admin, 0, len(userIDs))
+	for _, userID := range s.configAdminIDs {
+		result = append(result, dto.Admin{
+			UserID:     userID,
+			FIO:        names[userID],
+			Role:       valueobject.SuperAdmin,
+			FromConfig: true,
+		})
+	}
+	for _, admin := range admins {
+		if utils.IsAdmin(admin.UserID, s.configAdminIDs) {
+			continue
+		}
+		result = append(result, dto.Admin{
+			UserID:    admin.UserID,
+			FIO:       names[admin.UserID],
+			Role:      admin.Role,
+			GrantedBy: admin.GrantedBy,
+		})
+	}
+
+	return result, nil
+}
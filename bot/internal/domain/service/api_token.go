@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/secondary"
+)
+
+const (
+	apiTokenPrefix = "cuc_"
+	apiTokenBytes  = 32
+
+	// apiTokenTouchInterval limits how often the last usage time of a token is written to the database
+	apiTokenTouchInterval = time.Minute
+)
+
+type APITokenService struct {
+	repo    secondary.APITokenRepository
+	logRepo secondary.APIRequestLogRepository
+}
+
+func NewAPITokenService(repo secondary.APITokenRepository, logRepo secondary.APIRequestLogRepository) *APITokenService {
+	return &APITokenService{
+		repo:    repo,
+		logRepo: logRepo,
+	}
+}
+
+// Issue creates a new token for the club. The returned plain token is not stored and can't be shown again.
+func (s *APITokenService) Issue(ctx context.Context, clubID, name string, createdBy int64) (string, *entity.APIToken, error) {
+	secret := make([]byte, apiTokenBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, fmt.Errorf("generate token: %w", err)
+	}
+	plain := apiTokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	token, err := s.repo.Create(ctx, &entity.APIToken{
+		ClubID:    clubID,
+		Name:      name,
+		TokenHash: hashAPIToken(plain),
+		CreatedBy: createdBy,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return plain, token, nil
+}
+
+// Authenticate returns the active token matching the plain token.
+// Returns errorz.ErrInvalidAPIToken if the token is unknown or revoked.
+func (s *APITokenService) Authenticate(ctx context.Context, plain string) (*entity.APIToken, error) {
+	token, err := s.repo.GetByHash(ctx, hashAPIToken(plain))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorz.ErrInvalidAPIToken
+		}
+		return nil, err
+	}
+	if token.IsRevoked() {
+		return nil, errorz.ErrInvalidAPIToken
+	}
+
+	now := time.Now()
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > apiTokenTouchInterval {
+		if err = s.repo.UpdateLastUsedAt(ctx, token.ID, now); err != nil {
+			return nil, fmt.Errorf("update token usage: %w", err)
+		}
+		token.LastUsedAt = &now
+	}
+
+	return token, nil
+}
+
+func (s *APITokenService) Get(ctx context.Context, id string) (*entity.APIToken, error) {
+	return s.repo.Get(ctx, id)
+}
+
+func (s *APITokenService) GetByClubID(ctx context.Context, clubID string) ([]entity.APIToken, error) {
+	return s.repo.GetByClubID(ctx, clubID)
+}
+
+// Revoke revokes the token, requests with it are rejected afterwards
+func (s *APITokenService) Revoke(ctx context.Context, id string) (*entity.APIToken, error) {
+	token, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if token.IsRevoked() {
+		return token, nil
+	}
+
+	token.Revoke()
+	return s.repo.Update(ctx, token)
+}
+
+// LogRequest writes the request to the API audit log
+func (s *APITokenService) LogRequest(ctx context.Context, log *entity.APIRequestLog) error {
+	return s.logRepo.Create(ctx, log)
+}
+
+func hashAPIToken(plain string) string {
+	hash := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/secondary"
+	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
+)
+
+// auditSearchLimit caps the entries returned by one search
+const auditSearchLimit = 10000
+
+/*
+AuditService - журнал привилегированных действий.
+
+Основные принципы работы:
+- Записи только добавляются, журнал не изменяется и не очищается из бота
+- Запись хранит исполнителя, действие, цель и состояние цели до и после действия в JSON
+- Ошибка записи в журнал логируется и не прерывает само действие
+*/
+type AuditService struct {
+	logger *types.Logger
+
+	repo secondary.AuditRepository
+}
+
+func NewAuditService(logger *types.Logger, repo secondary.AuditRepository) *AuditService {
+	return &AuditService{
+		logger: logger,
+		repo:   repo,
+	}
+}
+
+// Record appends the action to the audit log, before and after are marshaled to JSON unless nil
+func (s *AuditService) Record(
+	ctx context.Context,
+	actorID int64,
+	action entity.AuditAction,
+	targetType entity.AuditTarget,
+	targetID string,
+	before, after interface{},
+) {
+	entry := &entity.AuditEntry{
+		ActorID:    actorID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Before:     s.marshal(before),
+		After:      s.marshal(after),
+	}
+
+	if _, err := s.repo.Create(ctx, entry); err != nil {
+		s.logger.Errorf("Failed to record %s of %s %s by %d: %v", action, targetType, targetID, actorID, err)
+	}
+}
+
+// Search returns the newest entries matching the filter
+func (s *AuditService) Search(ctx context.Context, filter dto.AuditFilter) ([]entity.AuditEntry, error) {
+	if filter.Limit <= 0 || filter.Limit > auditSearchLimit {
+		filter.Limit = auditSearchLimit
+	}
+
+	return s.repo.Search(ctx, filter)
+}
+
+func (s *AuditService) marshal(state interface{}) *string {
+	if state == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		s.logger.Errorf("Failed to marshal audit state: %v", err)
+		return nil
+	}
+
+	result := string(data)
+	return &result
+}
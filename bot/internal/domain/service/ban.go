@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gopkg.in/telebot.v3/layout"
+	"gorm.io/gorm"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/valueobject"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/primary"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/secondary"
+	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
+)
+
+// banExpirySchedule is how often the expired bans are lifted
+const banExpirySchedule = time.Minute
+
+/*
+BanService - сервис банов пользователей.
+
+Основные принципы работы:
+- Каждый бан сохраняется (entity.UserBan) с причиной, администратором, временем начала и необязательным сроком
+- Пока бан действует, у пользователя установлен User.IsBanned, по нему бот не пускает пользователя
+- При бане пользователь снимается с предстоящих мероприятий и листов ожидания, ожидающие пропуски отменяются
+- Пользователь получает сообщение о бане с причиной и сроком и сообщение о снятии бана
+- Планировщик снимает баны по истечении срока
+*/
+type BanService struct {
+	layout *layout.Layout
+	logger *types.Logger
+
+	repo     secondary.UserBanRepository
+	userRepo secondary.UserRepository
+
+	eventParticipantService primary.EventParticipantService
+	clubOwnerService        primary.ClubOwnerService
+	outboxService           primary.OutboxService
+
+	ticker *time.Ticker
+}
+
+func NewBanService(
+	layout *layout.Layout,
+	logger *types.Logger,
+	repo secondary.UserBanRepository,
+	userRepo secondary.UserRepository,
+	eventParticipantService primary.EventParticipantService,
+	clubOwnerService primary.ClubOwnerService,
+	outboxService primary.OutboxService,
+) *BanService {
+	return &BanService{
+		layout:                  layout,
+		logger:                  logger,
+		repo:                    repo,
+		userRepo:                userRepo,
+		eventParticipantService: eventParticipantService,
+		clubOwnerService:        clubOwnerService,
+		outboxService:           outboxService,
+	}
+}
+
+// Ban bans the user for the duration, zero duration bans the user permanently.
+// Returns errorz.ErrAlreadyBanned if the user has an active ban.
+func (s *BanService) Ban(ctx context.Context, userID, adminID int64, reason string, duration time.Duration) (*entity.UserBan, *entity.User, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, err = s.repo.GetActiveByUserID(ctx, userID, time.Now())
+	switch {
+	case err == nil:
+		return nil, nil, errorz.ErrAlreadyBanned
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, nil, fmt.Errorf("failed to get active ban: %w", err)
+	}
+
+	ban := &entity.UserBan{
+		UserID:    userID,
+		AdminID:   adminID,
+		Reason:    reason,
+		StartedAt: time.Now(),
+	}
+	if duration > 0 {
+		expiresAt := ban.StartedAt.Add(duration)
+		ban.ExpiresAt = &expiresAt
+	}
+
+	ban, err = s.repo.Create(ctx, ban)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user.IsBanned = true
+	user, err = s.userRepo.Update(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.logger.Infof("User %d banned by %d until %v: %s", userID, adminID, ban.ExpiresAt, reason)
+
+	removed, err := s.eventParticipantService.RemoveFromUpcomingEvents(ctx, userID)
+	if err != nil {
+		s.logger.Errorf("Failed to remove banned user %d from upcoming events: %v", userID, err)
+	} else if removed > 0 {
+		s.logger.Infof("Banned user %d removed from %d upcoming events", userID, removed)
+	}
+
+	// banned co-owners don't count as active owners of their clubs
+	clubs, err := s.clubOwnerService.GetByUserID(ctx, userID)
+	if err != nil {
+		s.logger.Errorf("Failed to get clubs of banned user %d: %v", userID, err)
+	}
+	for _, club := range clubs {
+		if club.OwnerRole == valueobject.ClubCoOwner {
+			s.clubOwnerService.WarnIfOrphaned(ctx, club.ClubID)
+		}
+	}
+
+	s.notify(ctx, userID, "ban_notification", newBanNotification(ban))
+	return ban, user, nil
+}
+
+// Unban lifts the active ban of the user.
+// Returns errorz.ErrNotBanned if the user has no active ban.
+func (s *BanService) Unban(ctx context.Context, userID, adminID int64) (*entity.User, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ban, err := s.repo.GetActiveByUserID(ctx, userID, time.Now())
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get active ban: %w", err)
+	}
+
+	// users banned before the bans were recorded have no ban record
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if !user.IsBanned {
+			return nil, errorz.ErrNotBanned
+		}
+		ban = nil
+	}
+
+	if err = s.lift(ctx, ban, user, adminID); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// GetActiveBan returns the active ban of the user
+func (s *BanService) GetActiveBan(ctx context.Context, userID int64) (*entity.UserBan, error) {
+	return s.repo.GetActiveByUserID(ctx, userID, time.Now())
+}
+
+// GetActive returns the active bans with the names of the banned users
+func (s *BanService) GetActive(ctx context.Context) ([]dto.UserBan, error) {
+	bans, err := s.repo.GetActive(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]int64, 0, len(bans))
+	for _, ban := range bans {
+		userIDs = append(userIDs, ban.UserID)
+	}
+
+	users, err := s.userRepo.GetMany(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get banned users: %w", err)
+	}
+
+	names := make(map[int64]string, len(users))
+	for _, user := range users {
+		names[user.ID] = user.FIO.String()
+	}
+
+	result := make([]dto.UserBan, 0, len(bans))
+	for _, ban := range bans {
+		result = append(result, dto.UserBan{Ban: ban, FIO: names[ban.UserID]})
+	}
+
+	return result, nil
+}
+
+// StartScheduler starts the scheduler that lifts the expired bans
+func (s *BanService) StartScheduler() {
+	s.logger.Debug("Starting ban scheduler")
+	s.ticker = time.NewTicker(banExpirySchedule)
+	go func() {
+		for range s.ticker.C {
+			s.liftExpiredBans(context.Background())
+		}
+	}()
+	s.logger.Info("Ban scheduler started")
+}
+
+// StopScheduler stops the ban scheduler
+func (s *BanService) StopScheduler() {
+	if s.ticker != nil {
+		s.ticker.Stop()
+		s.logger.Info("Ban scheduler stopped")
+	}
+}
+
+func (s *BanService) liftExpiredBans(ctx context.Context) {
+	bans, err := s.repo.GetExpired(ctx, time.Now())
+	if err != nil {
+		s.logger.Errorf("Failed to get expired bans: %v", err)
+		return
+	}
+
+	for i := range bans {
+		user, err := s.userRepo.GetUserByID(ctx, bans[i].UserID)
+		if err != nil {
+			s.logger.Errorf("Failed to get user %d of the expired ban: %v", bans[i].UserID, err)
+			continue
+		}
+
+		if err = s.lift(ctx, &bans[i], user, 0); err != nil {
+			s.logger.Errorf("Failed to lift expired ban of user %d: %v", bans[i].UserID, err)
+		}
+	}
+}
+
+// lift lifts the ban and unbans the user unless another ban of the user is active,
+// ban is nil for users banned before the bans were recorded
+func (s *BanService) lift(ctx context.Context, ban *entity.UserBan, user *entity.User, adminID int64) error {
+	if ban != nil {
+		ban.Lift(adminID)
+		if _, err := s.repo.Update(ctx, ban); err != nil {
+			return err
+		}
+	}
+
+	if _, err := s.repo.GetActiveByUserID(ctx, user.ID, time.Now()); err == nil {
+		return nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to get active ban: %w", err)
+	}
+
+	user.IsBanned = false
+	if _, err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+	s.logger.Infof("User %d unbanned by %d", user.ID, adminID)
+
+	s.notify(ctx, user.ID, "unban_notification", nil)
+	return nil
+}
+
+// banNotification is the ban shown to the banned user
+type banNotification struct {
+	Reason    string
+	ExpiresAt string
+}
+
+func newBanNotification(ban *entity.UserBan) banNotification {
+	notification := banNotification{Reason: ban.Reason}
+	if ban.ExpiresAt != nil {
+		notification.ExpiresAt = ban.ExpiresAt.In(location.Location()).Format("02.01.2006 15:04")
+	}
+
+	return notification
+}
+
+func (s *BanService) notify(ctx context.Context, userID int64, key string, data interface{}) {
+	err := s.outboxService.Enqueue(ctx, userID,
+		s.layout.TextLocale("ru", key, data),
+		s.layout.MarkupLocale("ru", "core:hide"),
+	)
+	if err != nil {
+		s.logger.Errorf("Failed to enqueue %s to user %d: %v", key, userID, err)
+	}
+}
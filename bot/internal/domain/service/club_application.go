@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"gopkg.in/telebot.v3/layout"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/valueobject"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/primary"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/secondary"
+	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
+)
+
+/*
+ClubApplicationService - сервис заявок на создание клубов.
+
+Основные принципы работы:
+- Пользователь заполняет заявку (entity.ClubApplication): название, описание, ссылку, организаторов и роли
+- Пока заявка не отправлена, она остаётся черновиком, у пользователя может быть только одна заявка на рассмотрении
+- Администратор одобряет заявку, при этом создаются клуб и его совладельцы: заявитель и предложенные организаторы
+- Либо администратор отклоняет заявку с комментарием
+- Заявитель получает сообщение о результате рассмотрения
+*/
+type ClubApplicationService struct {
+	layout *layout.Layout
+	logger *types.Logger
+
+	repo secondary.ClubApplicationRepository
+
+	outboxService primary.OutboxService
+}
+
+func NewClubApplicationService(
+	layout *layout.Layout,
+	logger *types.Logger,
+	repo secondary.ClubApplicationRepository,
+	outboxService primary.OutboxService,
+) *ClubApplicationService {
+	return &ClubApplicationService{
+		layout:        layout,
+		logger:        logger,
+		repo:          repo,
+		outboxService: outboxService,
+	}
+}
+
+// Create saves the draft of the application.
+// Returns errorz.ErrClubApplicationPending if the applicant has an application waiting for the review.
+func (s *ClubApplicationService) Create(ctx context.Context, application *entity.ClubApplication) (*entity.ClubApplication, error) {
+	if err := s.checkNoPending(ctx, application.ApplicantID); err != nil {
+		return nil, err
+	}
+
+	application.Status = entity.ClubApplicationDraft
+	return s.repo.Create(ctx, application)
+}
+
+func (s *ClubApplicationService) Get(ctx context.Context, id string) (*entity.ClubApplication, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// HasPending checks if the applicant has an application waiting for the review
+func (s *ClubApplicationService) HasPending(ctx context.Context, applicantID int64) (bool, error) {
+	count, err := s.repo.CountPendingByApplicantID(ctx, applicantID)
+	return count > 0, err
+}
+
+// ToggleRole adds the role to the roles targeted by the draft or removes it from them
+func (s *ClubApplicationService) ToggleRole(ctx context.Context, id string, role valueobject.Role) (*entity.ClubApplication, error) {
+	if !role.IsValid() {
+		return nil, errorz.ErrInvalidCallbackData
+	}
+
+	application, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if application.Status != entity.ClubApplicationDraft {
+		return nil, errorz.ErrClubApplicationSubmitted
+	}
+
+	if i := slices.Index(application.AllowedRoles, role.String()); i >= 0 {
+		application.AllowedRoles = slices.Delete(application.AllowedRoles, i, i+1)
+	} else {
+		application.AllowedRoles = append(application.AllowedRoles, role.String())
+	}
+
+	return s.repo.Update(ctx, application)
+}
+
+// Submit sends the draft to the admins for the review
+func (s *ClubApplicationService) Submit(ctx context.Context, id string) (*entity.ClubApplication, error) {
+	application, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if application.Status != entity.ClubApplicationDraft {
+		return nil, errorz.ErrClubApplicationSubmitted
+	}
+	if err = s.checkNoPending(ctx, application.ApplicantID); err != nil {
+		return nil, err
+	}
+
+	application.Status = entity.ClubApplicationPending
+	application, err = s.repo.Update(ctx, application)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Infof("Club application %s submitted by %d", application.ID, application.ApplicantID)
+
+	return application, nil
+}
+
+// GetPending returns the applications waiting for the review, the oldest first
+func (s *ClubApplicationService) GetPending(ctx context.Context) ([]entity.ClubApplication, error) {
+	return s.repo.GetPending(ctx)
+}
+
+// Approve creates the club of the application, the applicant and the proposed owners become its co-owners.
+// Returns errorz.ErrClubApplicationReviewed if the application is not waiting for the review,
+// the status is checked again by the repository, so concurrent reviews can't both succeed.
+func (s *ClubApplicationService) Approve(ctx context.Context, id string, adminID int64) (*entity.ClubApplication, *entity.Club, error) {
+	application, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if application.Status != entity.ClubApplicationPending {
+		return nil, nil, errorz.ErrClubApplicationReviewed
+	}
+
+	club := &entity.Club{
+		Name:         application.Name,
+		Description:  application.Description,
+		Link:         application.Link,
+		AllowedRoles: application.AllowedRoles,
+	}
+
+	ownerIDs := append([]int64{application.ApplicantID}, application.OwnerIDs...)
+	owners := make([]entity.ClubOwner, 0, len(ownerIDs))
+	for _, ownerID := range ownerIDs {
+		if slices.ContainsFunc(owners, func(owner entity.ClubOwner) bool { return owner.UserID == ownerID }) {
+			continue
+		}
+		owners = append(owners, entity.ClubOwner{UserID: ownerID, Role: valueobject.ClubCoOwner})
+	}
+
+	s.review(application, entity.ClubApplicationApproved, adminID, "")
+	if err = s.repo.Approve(ctx, application, club, owners); err != nil {
+		return nil, nil, err
+	}
+	s.logger.Infof("Club application %s approved by %d, club %s created", application.ID, adminID, club.ID)
+
+	s.notify(ctx, application.ApplicantID, "club_application_approved_notification", application)
+	return application, club, nil
+}
+
+// Reject rejects the application with the comment shown to the applicant.
+// Returns errorz.ErrClubApplicationReviewed if the application is not waiting for the review.
+func (s *ClubApplicationService) Reject(ctx context.Context, id string, adminID int64, comment string) (*entity.ClubApplication, error) {
+	application, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if application.Status != entity.ClubApplicationPending {
+		return nil, errorz.ErrClubApplicationReviewed
+	}
+
+	s.review(application, entity.ClubApplicationRejected, adminID, comment)
+	if err = s.repo.Reject(ctx, application); err != nil {
+		return nil, err
+	}
+	s.logger.Infof("Club application %s rejected by %d: %s", application.ID, adminID, comment)
+
+	s.notify(ctx, application.ApplicantID, "club_application_rejected_notification", application)
+	return application, nil
+}
+
+func (s *ClubApplicationService) checkNoPending(ctx context.Context, applicantID int64) error {
+	pending, err := s.HasPending(ctx, applicantID)
+	if err != nil {
+		return err
+	}
+	if pending {
+		return errorz.ErrClubApplicationPending
+	}
+
+	return nil
+}
+
+func (s *ClubApplicationService) review(application *entity.ClubApplication, status entity.ClubApplicationStatus, adminID int64, comment string) {
+	now := time.Now()
+	application.Status = status
+	application.ReviewerID = adminID
+	application.ReviewedAt = &now
+	application.Comment = comment
+}
+
+func (s *ClubApplicationService) notify(ctx context.Context, userID int64, key string, data interface{}) {
+	err := s.outboxService.Enqueue(ctx, userID,
+		s.layout.TextLocale("ru", key, data),
+		s.layout.MarkupLocale("ru", "core:hide"),
+	)
+	if err != nil {
+		s.logger.Errorf("Failed to enqueue %s to user %d: %v", key, userID, err)
+	}
+}
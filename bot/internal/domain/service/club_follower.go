@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/secondary"
+)
+
+// ClubFollowerService manages club followers, followers are the audience of club mailings
+type ClubFollowerService struct {
+	repo secondary.ClubFollowerRepository
+}
+
+func NewClubFollowerService(repo secondary.ClubFollowerRepository) *ClubFollowerService {
+	return &ClubFollowerService{
+		repo: repo,
+	}
+}
+
+func (s *ClubFollowerService) Follow(ctx context.Context, userID int64, clubID string) error {
+	return s.repo.Create(ctx, &entity.ClubFollower{
+		UserID: userID,
+		ClubID: clubID,
+	})
+}
+
+func (s *ClubFollowerService) Unfollow(ctx context.Context, userID int64, clubID string) error {
+	return s.repo.Delete(ctx, userID, clubID)
+}
+
+func (s *ClubFollowerService) IsFollowing(ctx context.Context, userID int64, clubID string) (bool, error) {
+	return s.repo.Exists(ctx, userID, clubID)
+}
+
+func (s *ClubFollowerService) GetFollowedClubs(ctx context.Context, userID int64) ([]entity.Club, error) {
+	return s.repo.GetClubsByUserID(ctx, userID)
+}
+
+func (s *ClubFollowerService) CountFollowers(ctx context.Context, clubID string) (int64, error) {
+	return s.repo.CountByClubID(ctx, clubID)
+}
@@ -3,36 +3,170 @@ package service
 import (
 	"context"
 
+	"gopkg.in/telebot.v3/layout"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/valueobject"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/primary"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/secondary"
+	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
 
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
 )
 
 type ClubOwnerService struct {
+	layout *layout.Layout
+	logger *types.Logger
+
 	repo     secondary.ClubOwnerRepository
 	userRepo secondary.UserRepository
+	clubRepo secondary.ClubRepository
+
+	adminService  primary.AdminService
+	outboxService primary.OutboxService
 }
 
-func NewClubOwnerService(storage secondary.ClubOwnerRepository, userStorage secondary.UserRepository) *ClubOwnerService {
+func NewClubOwnerService(
+	layout *layout.Layout,
+	logger *types.Logger,
+	storage secondary.ClubOwnerRepository,
+	userStorage secondary.UserRepository,
+	clubStorage secondary.ClubRepository,
+	adminService primary.AdminService,
+	outboxService primary.OutboxService,
+) *ClubOwnerService {
 	return &ClubOwnerService{
-		repo:     storage,
-		userRepo: userStorage,
+		layout:        layout,
+		logger:        logger,
+		repo:          storage,
+		userRepo:      userStorage,
+		clubRepo:      clubStorage,
+		adminService:  adminService,
+		outboxService: outboxService,
 	}
 }
 
-func (s *ClubOwnerService) Add(ctx context.Context, userID int64, clubID string) (*entity.ClubOwner, error) {
-	return s.repo.Create(ctx, &entity.ClubOwner{UserID: userID, ClubID: clubID})
+func (s *ClubOwnerService) Add(ctx context.Context, userID int64, clubID string, role valueobject.ClubOwnerRole) (*entity.ClubOwner, error) {
+	if !role.IsValid() {
+		return nil, errorz.ErrInvalidClubOwnerRole
+	}
+
+	return s.repo.Create(ctx, &entity.ClubOwner{UserID: userID, ClubID: clubID, Role: role})
 }
 
+// Remove removes the user from the club staff, the admins are warned if the club is left without active owners
 func (s *ClubOwnerService) Remove(ctx context.Context, userID int64, clubID string) error {
-	return s.repo.Delete(ctx, userID, clubID)
+	if err := s.repo.Delete(ctx, userID, clubID); err != nil {
+		return err
+	}
+
+	s.WarnIfOrphaned(ctx, clubID)
+	return nil
 }
 
 func (s *ClubOwnerService) Get(ctx context.Context, clubID string, userID int64) (*entity.ClubOwner, error) {
 	return s.repo.Get(ctx, clubID, userID)
 }
 
+// GetRole returns the role of the user in the club staff,
+// gorm.ErrRecordNotFound is returned if the user is not a member of the club staff
+func (s *ClubOwnerService) GetRole(ctx context.Context, clubID string, userID int64) (valueobject.ClubOwnerRole, error) {
+	clubOwner, err := s.repo.Get(ctx, clubID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	return clubOwner.Role, nil
+}
+
+// SetRole changes the role of the club staff member
+func (s *ClubOwnerService) SetRole(ctx context.Context, clubID string, userID int64, role valueobject.ClubOwnerRole) (*entity.ClubOwner, error) {
+	if !role.IsValid() {
+		return nil, errorz.ErrInvalidClubOwnerRole
+	}
+
+	clubOwner, err := s.repo.Get(ctx, clubID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	clubOwner.Role = role
+	return s.repo.Update(ctx, clubOwner)
+}
+
+// Leave removes the user from the club staff, the last co-owner has to transfer the club instead
+func (s *ClubOwnerService) Leave(ctx context.Context, clubID string, userID int64) error {
+	if err := s.repo.Leave(ctx, clubID, userID); err != nil {
+		return err
+	}
+
+	s.WarnIfOrphaned(ctx, clubID)
+	return nil
+}
+
+// Transfer hands the club over to the recipient, who becomes a co-owner, and removes the former owner from the staff
+func (s *ClubOwnerService) Transfer(ctx context.Context, clubID string, fromUserID, toUserID int64) error {
+	return s.repo.Transfer(ctx, clubID, fromUserID, toUserID)
+}
+
+// HasActiveOwners reports whether the club has a co-owner who is not banned
+func (s *ClubOwnerService) HasActiveOwners(ctx context.Context, clubID string) (bool, error) {
+	owners, err := s.repo.GetByClubID(ctx, clubID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, owner := range owners {
+		if owner.OwnerRole == valueobject.ClubCoOwner && !owner.IsBanned {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// WarnIfOrphaned warns the admins who manage clubs if the club has no active co-owners,
+// it is called after the staff of the club has lost a co-owner
+func (s *ClubOwnerService) WarnIfOrphaned(ctx context.Context, clubID string) {
+	hasOwners, err := s.HasActiveOwners(ctx, clubID)
+	if err != nil {
+		s.logger.Errorf("Failed to check active owners of club %s: %v", clubID, err)
+		return
+	}
+	if hasOwners {
+		return
+	}
+
+	club, err := s.clubRepo.Get(ctx, clubID)
+	if err != nil {
+		s.logger.Errorf("Failed to get club %s left without owners: %v", clubID, err)
+		return
+	}
+	s.logger.Warnf("Club %s left without active owners", clubID)
+
+	admins, err := s.adminService.GetAll(ctx)
+	if err != nil {
+		s.logger.Errorf("Failed to get admins to warn about club %s without owners: %v", clubID, err)
+		return
+	}
+
+	chatIDs := make([]int64, 0, len(admins))
+	for _, admin := range admins {
+		if admin.Role.Can(valueobject.ClubsPermission) {
+			chatIDs = append(chatIDs, admin.UserID)
+		}
+	}
+
+	err = s.outboxService.EnqueueMany(ctx, chatIDs,
+		s.layout.TextLocale("ru", "club_without_owners_warning", club),
+		s.layout.MarkupLocale("ru", "core:hide"),
+	)
+	if err != nil {
+		s.logger.Errorf("Failed to enqueue club without owners warning (club_id=%s): %v", clubID, err)
+	}
+}
+
 func (s *ClubOwnerService) Update(ctx context.Context, clubOwner *entity.ClubOwner) (*entity.ClubOwner, error) {
 	return s.repo.Update(ctx, clubOwner)
 }
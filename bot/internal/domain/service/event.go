@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"io"
@@ -11,20 +12,27 @@ import (
 	"sort"
 	"time"
 
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/primary"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/secondary"
+	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
 
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
 )
 
 type EventService struct {
-	repo secondary.EventRepository
+	logger      *types.Logger
+	repo        secondary.EventRepository
+	passService primary.PassService
 }
 
-func NewEventService(storage secondary.EventRepository) *EventService {
+func NewEventService(logger *types.Logger, storage secondary.EventRepository, passService primary.PassService) *EventService {
 	return &EventService{
-		repo: storage,
+		logger:      logger,
+		repo:        storage,
+		passService: passService,
 	}
 }
 
@@ -79,6 +87,31 @@ func (s *EventService) Delete(ctx context.Context, id string) error {
 	return s.repo.Delete(ctx, id)
 }
 
+// Cancel marks the event as cancelled with the given reason, cancels its waitlist and its passes.
+// The event stays in the database, so the club keeps its history.
+//
+// The event is marked cancelled first, so no passes are created for it afterwards.
+// Once the event is cancelled, a failed pass cancellation is only logged:
+// the pass corrections and the batch retries deliver it later.
+// If the event is already cancelled, its passes are cancelled again to finish a failed cancellation
+// and the event is returned with errorz.ErrEventCancelled.
+func (s *EventService) Cancel(ctx context.Context, id string, reason string) (*entity.Event, error) {
+	event, err := s.repo.Cancel(ctx, id, reason)
+	if err != nil && !errors.Is(err, errorz.ErrEventCancelled) {
+		return nil, fmt.Errorf("cancel event: %w", err)
+	}
+	alreadyCancelled := err != nil
+
+	if err = s.passService.CancelEventPasses(ctx, event); err != nil {
+		s.logger.Errorf("Failed to cancel passes of the cancelled event %s: %v", event.ID, err)
+	}
+	if alreadyCancelled {
+		return event, errorz.ErrEventCancelled
+	}
+
+	return event, nil
+}
+
 func (s *EventService) Count(ctx context.Context, role entity.Role) (int64, error) {
 	return s.repo.Count(ctx, string(role))
 }
@@ -108,6 +141,9 @@ func (s *EventService) GetWeeklyEvents(ctx context.Context) ([]entity.Event, err
 
 	var weeklyEvents []entity.Event
 	for _, event := range allEvents {
+		if event.IsCancelled() {
+			continue
+		}
 		if event.StartTime.After(startOfWeek.AddDate(0, 0, -1)) && event.StartTime.Before(endOfWeek) {
 			weeklyEvents = append(weeklyEvents, event)
 		}
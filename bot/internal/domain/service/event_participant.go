@@ -3,13 +3,19 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
+	"gopkg.in/telebot.v3/layout"
 	"gorm.io/gorm"
 
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/primary"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/secondary"
 
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/shadowban"
 	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
 )
@@ -21,45 +27,95 @@ EventParticipantService - сервис для управления участн
 - Автоматическое создание пропусков при регистрации на события, требующие пропуск
 - Управление статусом посещения через QR-коды
 - Статистика участников и их активности
+- Лист ожидания с автоматическим повышением и подтверждением участия до дедлайна
+- Автоматическая подписка на клуб при первой регистрации на его мероприятие (если включена)
 */
 type EventParticipantService struct {
-	logger        *types.Logger
-	storage       secondary.EventParticipantRepository
-	eventStorage  secondary.EventRepository
-	passStorage   secondary.PassRepository
-	userStorage   secondary.UserRepository
-	excludedRoles []string
-	shadowMatcher *shadowban.Matcher
+	layout *layout.Layout
+	logger *types.Logger
+
+	storage          secondary.EventParticipantRepository
+	eventStorage     secondary.EventRepository
+	passStorage      secondary.PassRepository
+	userStorage      secondary.UserRepository
+	waitlistStorage  secondary.WaitlistRepository
+	followerStorage  secondary.ClubFollowerRepository
+	passService      primary.PassService
+	outboxService    primary.OutboxService
+	shadowBanStorage secondary.ShadowBanRepository
+	excludedRoles    []string
+
+	followOnRegistration bool
+
+	waitlistTicker *time.Ticker
 }
 
+// waitlistConfirmTimeout is the time a promoted user has to confirm the offered seat
+const waitlistConfirmTimeout = 12 * time.Hour
+
 func NewEventParticipantService(
+	layout *layout.Layout,
 	logger *types.Logger,
 	repo secondary.EventParticipantRepository,
 	eventRepo secondary.EventRepository,
 	passRepo secondary.PassRepository,
 	userRepo secondary.UserRepository,
+	waitlistRepo secondary.WaitlistRepository,
+	followerRepo secondary.ClubFollowerRepository,
+	shadowBanRepo secondary.ShadowBanRepository,
+	passService primary.PassService,
+	outboxService primary.OutboxService,
 	excludedRoles []string,
-	shadowBanNameSurnames []string,
+	followOnRegistration bool,
 ) *EventParticipantService {
 	return &EventParticipantService{
-		logger:        logger,
-		storage:       repo,
-		eventStorage:  eventRepo,
-		passStorage:   passRepo,
-		userStorage:   userRepo,
-		excludedRoles: excludedRoles,
-		shadowMatcher: shadowban.NewMatcher(shadowBanNameSurnames),
+		layout:           layout,
+		logger:           logger,
+		storage:          repo,
+		eventStorage:     eventRepo,
+		passStorage:      passRepo,
+		userStorage:      userRepo,
+		waitlistStorage:  waitlistRepo,
+		followerStorage:  followerRepo,
+		shadowBanStorage: shadowBanRepo,
+		passService:      passService,
+		outboxService:    outboxService,
+		excludedRoles:    excludedRoles,
+
+		followOnRegistration: followOnRegistration,
 	}
 }
 
+// Register registers the user for the event checking capacity and the registration window.
+// Returns errorz.ErrEventFull, errorz.ErrRegistrationEnded or errorz.ErrEventCancelled if the registration is not possible.
 func (s *EventParticipantService) Register(ctx context.Context, eventID string, userID int64) (*entity.EventParticipant, error) {
+	return s.register(ctx, eventID, userID, true)
+}
+
+// RegisterOnSite registers the user at the event entrance (QR activation),
+// the registration window is not checked but capacity is.
+func (s *EventParticipantService) RegisterOnSite(ctx context.Context, eventID string, userID int64) (*entity.EventParticipant, error) {
+	return s.register(ctx, eventID, userID, false)
+}
+
+func (s *EventParticipantService) register(ctx context.Context, eventID string, userID int64, checkRegistrationWindow bool) (*entity.EventParticipant, error) {
 	s.logger.Debugf("Registering user %d for event %s", userID, eventID)
 
-	participant, err := s.storage.Create(ctx, &entity.EventParticipant{
+	shadowMatcher, err := shadowban.Load(ctx, s.shadowBanStorage)
+	if err != nil {
+		s.logger.Errorf("Failed to get shadow bans for registration of user %d for event %s: %v", userID, eventID, err)
+		return nil, err
+	}
+
+	participant, err := s.storage.CreateWithCheck(ctx, &entity.EventParticipant{
 		UserID:  userID,
 		EventID: eventID,
-	})
+	}, s.registrationCheck(checkRegistrationWindow, shadowMatcher))
 	if err != nil {
+		if errors.Is(err, errorz.ErrEventFull) || errors.Is(err, errorz.ErrRegistrationEnded) || errors.Is(err, errorz.ErrEventCancelled) {
+			s.logger.Debugf("Registration of user %d for event %s rejected: %v", userID, eventID, err)
+			return nil, err
+		}
 		s.logger.Errorf("Failed to register user %d for event %s: %v", userID, eventID, err)
 		return nil, err
 	}
@@ -68,10 +124,68 @@ func (s *EventParticipantService) Register(ctx context.Context, eventID string,
 		s.logger.Errorf("Failed to create pass for user %d, event %s: %v", userID, eventID, err)
 	}
 
+	if s.followOnRegistration {
+		if err := s.followOnFirstRegistration(ctx, eventID, userID); err != nil {
+			s.logger.Errorf("Failed to follow club of event %s by user %d: %v", eventID, userID, err)
+		}
+	}
+
 	s.logger.Debugf("Successfully registered user %d for event %s", userID, eventID)
 	return participant, nil
 }
 
+// registrationCheck returns a check that runs under the event lock.
+// Shadow-banned users are neither counted nor limited by capacity.
+func (s *EventParticipantService) registrationCheck(checkRegistrationWindow bool, shadowMatcher *shadowban.Matcher) secondary.RegistrationCheck {
+	return func(event *entity.Event, user *entity.User, participants []entity.User) error {
+		if event.IsCancelled() {
+			return errorz.ErrEventCancelled
+		}
+
+		if checkRegistrationWindow && !event.IsRegistrationActive(user.Role) {
+			return errorz.ErrRegistrationEnded
+		}
+
+		if event.MaxParticipants == 0 || shadowMatcher.MatchUser(*user) {
+			return nil
+		}
+
+		count := 0
+		for _, participant := range participants {
+			if !shadowMatcher.MatchUser(participant) {
+				count++
+			}
+		}
+		if count >= event.MaxParticipants {
+			return errorz.ErrEventFull
+		}
+
+		return nil
+	}
+}
+
+// followOnFirstRegistration makes the user a follower of the club if it is the first registration
+// of the user for events of the club. Users who unfollowed the club are not followed again.
+func (s *EventParticipantService) followOnFirstRegistration(ctx context.Context, eventID string, userID int64) error {
+	event, err := s.eventStorage.GetEventByID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	count, err := s.storage.CountByUserAndClubID(ctx, userID, event.ClubID)
+	if err != nil {
+		return err
+	}
+	if count != 1 {
+		return nil
+	}
+
+	return s.followerStorage.Create(ctx, &entity.ClubFollower{
+		UserID: userID,
+		ClubID: event.ClubID,
+	})
+}
+
 func (s *EventParticipantService) createPassIfRequired(ctx context.Context, eventID string, userID int64) error {
 	event, err := s.eventStorage.GetEventByID(ctx, eventID)
 	if err != nil {
@@ -83,6 +197,11 @@ func (s *EventParticipantService) createPassIfRequired(ctx context.Context, even
 		return err
 	}
 
+	if event.IsCancelled() {
+		s.logger.Debugf("Event %s is cancelled, pass is not created for user %d", eventID, userID)
+		return nil
+	}
+
 	if !event.IsPassRequiredForUser(user, s.excludedRoles) {
 		s.logger.Debugf("Pass not required for user %d, event %s", userID, eventID)
 		return nil
@@ -99,7 +218,11 @@ func (s *EventParticipantService) createPassIfRequired(ctx context.Context, even
 		return nil
 	}
 
-	scheduledAt := event.CalculateScheduledAt()
+	scheduledAt, err := s.passService.CalculateScheduledAt(ctx, event)
+	if err != nil {
+		s.logger.Errorf("Failed to calculate pass schedule for event %s: %v", eventID, err)
+		return err
+	}
 
 	pass := &entity.Pass{
 		EventID:     eventID,
@@ -121,6 +244,58 @@ func (s *EventParticipantService) createPassIfRequired(ctx context.Context, even
 	return nil
 }
 
+// SyncPasses приводит пропуски мероприятия в соответствие с его текущими параметрами
+//
+// Вызывается после изменения мероприятия:
+// - Если пропуск больше не требуется, ожидающие пропуски по регистрации отменяются
+// - Ожидающие пропуски переносятся на новое время отправки (CalculateScheduledAt)
+// - Если пропуск стал требоваться, он создаётся для уже зарегистрированных участников
+func (s *EventParticipantService) SyncPasses(ctx context.Context, event *entity.Event) error {
+	passes, err := s.passStorage.GetPassesByEventID(ctx, event.ID)
+	if err != nil {
+		return fmt.Errorf("get passes: %w", err)
+	}
+
+	scheduledAt, err := s.passService.CalculateScheduledAt(ctx, event)
+	if err != nil {
+		return fmt.Errorf("calculate pass schedule: %w", err)
+	}
+
+	for i := range passes {
+		pass := &passes[i]
+		if pass.Status != entity.PassStatusPending {
+			continue
+		}
+
+		if !event.PassRequired && pass.Type == entity.PassTypeEvent {
+			pass.Cancel()
+		} else {
+			pass.ScheduledAt = scheduledAt
+		}
+
+		if _, err = s.passStorage.UpdatePass(ctx, pass); err != nil {
+			s.logger.Errorf("Failed to update pass %s for event %s: %v", pass.ID, event.ID, err)
+		}
+	}
+
+	if !event.PassRequired {
+		return nil
+	}
+
+	participants, err := s.storage.GetByEventID(ctx, event.ID)
+	if err != nil {
+		return fmt.Errorf("get participants: %w", err)
+	}
+
+	for _, participant := range participants {
+		if err = s.createPassIfRequired(ctx, event.ID, participant.UserID); err != nil {
+			s.logger.Errorf("Failed to create pass for user %d, event %s: %v", participant.UserID, event.ID, err)
+		}
+	}
+
+	return nil
+}
+
 func (s *EventParticipantService) Get(ctx context.Context, eventID string, userID int64) (*entity.EventParticipant, error) {
 	return s.storage.Get(ctx, eventID, userID)
 }
@@ -135,6 +310,52 @@ func (s *EventParticipantService) Update(ctx context.Context, eventParticipant *
 }
 
 func (s *EventParticipantService) Delete(ctx context.Context, eventID string, userID int64) error {
+	if err := s.removeParticipant(ctx, eventID, userID); err != nil {
+		return err
+	}
+
+	entry, err := s.waitlistStorage.GetActive(ctx, eventID, userID)
+	if err == nil && entry.Status == entity.WaitlistStatusOffered {
+		entry.Status = entity.WaitlistStatusCancelled
+		if _, err := s.waitlistStorage.Update(ctx, entry); err != nil {
+			s.logger.Errorf("Failed to cancel waitlist offer for user %d, event %s: %v", userID, eventID, err)
+		}
+	}
+
+	s.promoteFromWaitlist(ctx, eventID)
+	return nil
+}
+
+// RemoveFromUpcomingEvents removes the registrations of the user for the events which have not started yet,
+// leaves the waitlists and cancels the pending passes of the user. Returns the number of removed registrations
+func (s *EventParticipantService) RemoveFromUpcomingEvents(ctx context.Context, userID int64) (int, error) {
+	if err := s.waitlistStorage.CancelWaitingByUserID(ctx, userID); err != nil {
+		return 0, fmt.Errorf("cancel waitlist entries: %w", err)
+	}
+
+	eventIDs, err := s.storage.GetUpcomingEventIDs(ctx, userID, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("get upcoming events: %w", err)
+	}
+
+	removed := 0
+	for _, eventID := range eventIDs {
+		if err = s.Delete(ctx, eventID, userID); err != nil {
+			s.logger.Errorf("Failed to remove user %d from upcoming event %s: %v", userID, eventID, err)
+			continue
+		}
+		removed++
+	}
+
+	if err = s.passStorage.CancelPendingPassesByUserID(ctx, userID); err != nil {
+		return removed, fmt.Errorf("cancel pending passes: %w", err)
+	}
+
+	return removed, nil
+}
+
+// removeParticipant cancels user's passes and removes the registration without touching the waitlist
+func (s *EventParticipantService) removeParticipant(ctx context.Context, eventID string, userID int64) error {
 	if err := s.passStorage.CancelPassesByEventAndUser(ctx, eventID, userID); err != nil {
 		s.logger.Errorf("Failed to cancel passes for user %d, event %s: %v", userID, eventID, err)
 	}
@@ -158,9 +379,14 @@ func (s *EventParticipantService) CountByEventID(ctx context.Context, eventID st
 		return 0, err
 	}
 
+	shadowMatcher, err := shadowban.Load(ctx, s.shadowBanStorage)
+	if err != nil {
+		return 0, err
+	}
+
 	count := 0
 	for _, user := range users {
-		if s.shadowMatcher.MatchUser(user) {
+		if shadowMatcher.MatchUser(user) {
 			continue
 		}
 		count++
@@ -236,7 +462,24 @@ func (s *EventParticipantService) IsShadowBanned(ctx context.Context, userID int
 		return false, err
 	}
 
-	return s.shadowMatcher.MatchUser(*user), nil
+	shadowMatcher, err := shadowban.Load(ctx, s.shadowBanStorage)
+	if err != nil {
+		return false, err
+	}
+
+	return shadowMatcher.MatchUser(*user), nil
+}
+
+// GetUserPass returns the active pass of the user for the event.
+// Returns errorz.ErrPassNotRequired if the user has no pass and doesn't need it
+// and gorm.ErrRecordNotFound if the pass is required but was cancelled.
+func (s *EventParticipantService) GetUserPass(ctx context.Context, event *entity.Event, user *entity.User) (*entity.Pass, error) {
+	pass, err := s.passStorage.GetActivePassForUser(ctx, event.ID, user.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) && !event.IsPassRequiredForUser(user, s.excludedRoles) {
+		return nil, errorz.ErrPassNotRequired
+	}
+
+	return pass, err
 }
 
 func (s *EventParticipantService) CanCancelRegistration(ctx context.Context, eventID string) (bool, error) {
@@ -322,9 +565,14 @@ func (s *EventParticipantService) visibleUserIDs(ctx context.Context, participan
 		return nil, err
 	}
 
+	shadowMatcher, err := shadowban.Load(ctx, s.shadowBanStorage)
+	if err != nil {
+		return nil, err
+	}
+
 	visibleUserIDs := make(map[int64]struct{}, len(users))
 	for _, user := range users {
-		if s.shadowMatcher.MatchUser(user) {
+		if shadowMatcher.MatchUser(user) {
 			continue
 		}
 		visibleUserIDs[user.ID] = struct{}{}
@@ -332,3 +580,307 @@ func (s *EventParticipantService) visibleUserIDs(ctx context.Context, participan
 
 	return visibleUserIDs, nil
 }
+
+// JoinWaitlist puts the user into the event waitlist.
+// Returns errorz.ErrEventCancelled, errorz.ErrRegistrationEnded or errorz.ErrAlreadyRegistered if the user can't wait for a seat.
+func (s *EventParticipantService) JoinWaitlist(ctx context.Context, eventID string, userID int64) (*entity.WaitlistEntry, error) {
+	event, err := s.eventStorage.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userStorage.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case event.IsCancelled():
+		return nil, errorz.ErrEventCancelled
+	case !event.IsRegistrationActive(user.Role):
+		return nil, errorz.ErrRegistrationEnded
+	}
+
+	if _, err = s.storage.Get(ctx, eventID, userID); err == nil {
+		return nil, errorz.ErrAlreadyRegistered
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if _, err := s.waitlistStorage.GetActive(ctx, eventID, userID); err == nil {
+		return nil, errorz.ErrAlreadyInWaitlist
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	entry, err := s.waitlistStorage.Create(ctx, &entity.WaitlistEntry{
+		EventID: eventID,
+		UserID:  userID,
+		Status:  entity.WaitlistStatusWaiting,
+	})
+	if err != nil {
+		s.logger.Errorf("Failed to add user %d to waitlist of event %s: %v", userID, eventID, err)
+		return nil, err
+	}
+
+	s.logger.Debugf("User %d joined waitlist of event %s", userID, eventID)
+	return entry, nil
+}
+
+// LeaveWaitlist removes the user from the event waitlist
+func (s *EventParticipantService) LeaveWaitlist(ctx context.Context, eventID string, userID int64) error {
+	entry, err := s.waitlistStorage.GetActive(ctx, eventID, userID)
+	if err != nil {
+		return err
+	}
+
+	if entry.Status == entity.WaitlistStatusOffered {
+		return s.DeclineWaitlistOffer(ctx, eventID, userID)
+	}
+
+	entry.Status = entity.WaitlistStatusCancelled
+	_, err = s.waitlistStorage.Update(ctx, entry)
+	return err
+}
+
+// GetWaitlistEntry returns the active waitlist entry of the user
+func (s *EventParticipantService) GetWaitlistEntry(ctx context.Context, eventID string, userID int64) (*entity.WaitlistEntry, error) {
+	return s.waitlistStorage.GetActive(ctx, eventID, userID)
+}
+
+// GetWaitlistPosition returns the 1-based position of the user in the event waitlist
+func (s *EventParticipantService) GetWaitlistPosition(ctx context.Context, eventID string, userID int64) (int, error) {
+	entry, err := s.waitlistStorage.GetActive(ctx, eventID, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	before, err := s.waitlistStorage.CountWaitingBefore(ctx, eventID, entry.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(before) + 1, nil
+}
+
+func (s *EventParticipantService) CountWaitlist(ctx context.Context, eventID string) (int, error) {
+	count, err := s.waitlistStorage.CountWaiting(ctx, eventID)
+	return int(count), err
+}
+
+// ConfirmWaitlistOffer confirms the seat offered to the user after promotion from the waitlist
+func (s *EventParticipantService) ConfirmWaitlistOffer(ctx context.Context, eventID string, userID int64) error {
+	entry, err := s.waitlistStorage.GetActive(ctx, eventID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errorz.ErrWaitlistOfferExpired
+		}
+		return err
+	}
+
+	if entry.Status != entity.WaitlistStatusOffered || entry.IsOfferExpired(time.Now()) {
+		return errorz.ErrWaitlistOfferExpired
+	}
+
+	entry.Status = entity.WaitlistStatusConfirmed
+	confirmed, err := s.waitlistStorage.Transition(ctx, entry, entity.WaitlistStatusOffered)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return errorz.ErrWaitlistOfferExpired
+	}
+
+	return nil
+}
+
+// DeclineWaitlistOffer releases the seat offered to the user and promotes the next one
+func (s *EventParticipantService) DeclineWaitlistOffer(ctx context.Context, eventID string, userID int64) error {
+	entry, err := s.waitlistStorage.GetActive(ctx, eventID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errorz.ErrWaitlistOfferExpired
+		}
+		return err
+	}
+
+	if entry.Status != entity.WaitlistStatusOffered {
+		return errorz.ErrWaitlistOfferExpired
+	}
+
+	return s.releaseOffer(ctx, entry, entity.WaitlistStatusCancelled)
+}
+
+// StartWaitlistScheduler starts the scheduler that releases unconfirmed waitlist offers
+func (s *EventParticipantService) StartWaitlistScheduler() {
+	s.logger.Debug("Starting waitlist scheduler")
+	s.waitlistTicker = time.NewTicker(1 * time.Minute)
+	go func() {
+		for range s.waitlistTicker.C {
+			s.expireWaitlistOffers(context.Background())
+		}
+	}()
+	s.logger.Info("Waitlist scheduler started")
+}
+
+// StopWaitlistScheduler stops the waitlist scheduler
+func (s *EventParticipantService) StopWaitlistScheduler() {
+	if s.waitlistTicker != nil {
+		s.waitlistTicker.Stop()
+		s.logger.Info("Waitlist scheduler stopped")
+	}
+}
+
+func (s *EventParticipantService) expireWaitlistOffers(ctx context.Context) {
+	entries, err := s.waitlistStorage.GetExpiredOffers(ctx, time.Now())
+	if err != nil {
+		s.logger.Errorf("Failed to get expired waitlist offers: %v", err)
+		return
+	}
+
+	for i := range entries {
+		s.logger.Infof("Waitlist offer expired (user_id=%d, event_id=%s)", entries[i].UserID, entries[i].EventID)
+		err := s.releaseOffer(ctx, &entries[i], entity.WaitlistStatusExpired)
+		if err != nil && !errors.Is(err, errorz.ErrWaitlistOfferExpired) {
+			s.logger.Errorf("Failed to release expired waitlist offer %s: %v", entries[i].ID, err)
+		}
+	}
+}
+
+// releaseOffer removes the registration created for the offer and passes the seat to the next user
+func (s *EventParticipantService) releaseOffer(ctx context.Context, entry *entity.WaitlistEntry, status entity.WaitlistStatus) error {
+	entry.Status = status
+	released, err := s.waitlistStorage.Transition(ctx, entry, entity.WaitlistStatusOffered)
+	if err != nil {
+		return err
+	}
+	if !released {
+		// the offer was confirmed or released concurrently
+		return errorz.ErrWaitlistOfferExpired
+	}
+
+	if err := s.removeParticipant(ctx, entry.EventID, entry.UserID); err != nil {
+		return err
+	}
+
+	s.promoteFromWaitlist(ctx, entry.EventID)
+	return nil
+}
+
+// promoteFromWaitlist registers waiting users while the event has free seats
+//
+// NOTE: localisation is hardcoded for now (ru)
+func (s *EventParticipantService) promoteFromWaitlist(ctx context.Context, eventID string) {
+	event, err := s.eventStorage.GetEventByID(ctx, eventID)
+	if err != nil {
+		s.logger.Errorf("Failed to get event %s for waitlist promotion: %v", eventID, err)
+		return
+	}
+
+	now := time.Now()
+	if !event.StartTime.After(now) {
+		return
+	}
+
+	for {
+		participantsCount, err := s.CountByEventID(ctx, eventID)
+		if err != nil {
+			s.logger.Errorf("Failed to count participants of event %s: %v", eventID, err)
+			return
+		}
+		if event.MaxParticipants > 0 && participantsCount >= event.MaxParticipants {
+			return
+		}
+
+		entry, err := s.waitlistStorage.GetNextWaiting(ctx, eventID)
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				s.logger.Errorf("Failed to get next waitlist entry for event %s: %v", eventID, err)
+			}
+			return
+		}
+
+		// The entry is claimed before the registration, so a concurrent promotion skips it
+		deadline := now.Add(waitlistConfirmTimeout)
+		if deadline.After(event.StartTime) {
+			deadline = event.StartTime
+		}
+		entry.Offer(now, deadline)
+		claimed, err := s.waitlistStorage.Transition(ctx, entry, entity.WaitlistStatusWaiting)
+		if err != nil {
+			s.logger.Errorf("Failed to claim waitlist entry %s: %v", entry.ID, err)
+			return
+		}
+		if !claimed {
+			continue
+		}
+
+		if _, err := s.register(ctx, eventID, entry.UserID, false); err != nil {
+			switch {
+			case errors.Is(err, gorm.ErrDuplicatedKey):
+				// the user has registered in the meantime, the entry is done without an offer
+				s.logger.Infof("User %d from waitlist of event %s is already registered", entry.UserID, eventID)
+				s.finishClaimedEntry(ctx, entry, entity.WaitlistStatusConfirmed)
+				continue
+			case isPermanentRegistrationError(err):
+				s.logger.Infof("Waitlist entry %s of user %d cancelled: %v", entry.ID, entry.UserID, err)
+				s.finishClaimedEntry(ctx, entry, entity.WaitlistStatusCancelled)
+				continue
+			default:
+				// the seat stays free and the entry gets its place back, the next trigger retries the promotion
+				if !errors.Is(err, errorz.ErrEventFull) {
+					s.logger.Errorf("Failed to promote user %d from waitlist of event %s: %v", entry.UserID, eventID, err)
+				}
+				s.finishClaimedEntry(ctx, entry, entity.WaitlistStatusWaiting)
+				return
+			}
+		}
+
+		s.logger.Infof("User %d promoted from waitlist of event %s (deadline: %s)", entry.UserID, eventID, deadline.Format("2006-01-02 15:04:05"))
+		s.sendWaitlistOffer(ctx, *event, entry.UserID, deadline)
+	}
+}
+
+// finishClaimedEntry moves the entry claimed by the promotion to the given status,
+// the waiting status returns the entry to its place without the offer
+func (s *EventParticipantService) finishClaimedEntry(ctx context.Context, entry *entity.WaitlistEntry, status entity.WaitlistStatus) {
+	entry.Status = status
+	if status == entity.WaitlistStatusWaiting {
+		entry.OfferedAt = nil
+		entry.ConfirmDeadline = nil
+	}
+
+	if _, err := s.waitlistStorage.Transition(ctx, entry, entity.WaitlistStatusOffered); err != nil {
+		s.logger.Errorf("Failed to update claimed waitlist entry %s: %v", entry.ID, err)
+	}
+}
+
+// isPermanentRegistrationError reports whether the waiting user can never be registered for the event
+func isPermanentRegistrationError(err error) bool {
+	return errors.Is(err, errorz.ErrEventCancelled) ||
+		errors.Is(err, errorz.ErrRegistrationEnded)
+}
+
+// sendWaitlistOffer enqueues the offer to confirm the seat, the outbox retries it
+// so the user doesn't lose the seat because of a failed send
+func (s *EventParticipantService) sendWaitlistOffer(ctx context.Context, event entity.Event, userID int64, deadline time.Time) {
+	err := s.outboxService.Enqueue(ctx, userID,
+		s.layout.TextLocale("ru", "waitlist_promoted", struct {
+			Name      string
+			StartTime string
+			Deadline  string
+		}{
+			Name:      event.Name,
+			StartTime: event.StartTime.In(location.Location()).Format("02.01.2006 15:04"),
+			Deadline:  deadline.In(location.Location()).Format("02.01.2006 15:04"),
+		}),
+		s.layout.MarkupLocale("ru", "waitlist:offer", struct {
+			ID string
+		}{
+			ID: event.ID,
+		}),
+	)
+	if err != nil {
+		s.logger.Errorf("Failed to enqueue waitlist offer to user %d: %v", userID, err)
+	}
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/secondary"
+	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
+)
+
+type EventSeriesService struct {
+	logger *types.Logger
+
+	repo      secondary.EventSeriesRepository
+	eventRepo secondary.EventRepository
+
+	cron *cron.Cron
+}
+
+func NewEventSeriesService(
+	logger *types.Logger,
+	repo secondary.EventSeriesRepository,
+	eventRepo secondary.EventRepository,
+) *EventSeriesService {
+	return &EventSeriesService{
+		logger:    logger,
+		repo:      repo,
+		eventRepo: eventRepo,
+		cron:      cron.New(cron.WithLocation(location.Location())),
+	}
+}
+
+// Create creates a series using the event as its first occurrence and materialises the following occurrences
+//
+// until is used by weekly and biweekly series, dates are start times of the following occurrences of custom series
+func (s *EventSeriesService) Create(
+	ctx context.Context,
+	event entity.Event,
+	rule entity.RecurrenceRule,
+	until time.Time,
+	dates []time.Time,
+) (*entity.EventSeries, error) {
+	if rule == entity.RecurrenceCustom {
+		dates = append([]time.Time{event.StartTime}, dates...)
+		slices.SortFunc(dates, func(a, b time.Time) int {
+			return a.Compare(b)
+		})
+	}
+
+	series, err := s.repo.Create(ctx, entity.NewEventSeries(event, rule, until, dates))
+	if err != nil {
+		return nil, fmt.Errorf("create series: %w", err)
+	}
+
+	starts := []time.Time{event.StartTime}
+	if rule == entity.RecurrenceCustom {
+		starts = series.CustomDates()
+	}
+
+	if err = s.createOccurrences(ctx, series, starts); err != nil {
+		return nil, err
+	}
+
+	if err = s.materialize(ctx, series); err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
+
+func (s *EventSeriesService) Get(ctx context.Context, id string) (*entity.EventSeries, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// GetFutureOccurrences returns not cancelled occurrences of the series that have not started yet
+func (s *EventSeriesService) GetFutureOccurrences(ctx context.Context, id string) ([]entity.Event, error) {
+	return s.eventRepo.GetFutureBySeriesID(ctx, id, time.Now())
+}
+
+// UpdateFutureOccurrences applies editable fields of the occurrence to the series template
+// and to all occurrences that start after it. It returns the updated occurrences.
+func (s *EventSeriesService) UpdateFutureOccurrences(ctx context.Context, eventID string) ([]entity.Event, error) {
+	event, err := s.eventRepo.Get(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if !event.IsRecurring() {
+		return nil, errorz.ErrEventNotRecurring
+	}
+
+	series, err := s.repo.Get(ctx, *event.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	series.ApplyTemplate(*event)
+	if _, err = s.repo.Update(ctx, series); err != nil {
+		return nil, fmt.Errorf("update series: %w", err)
+	}
+
+	occurrences, err := s.eventRepo.GetFutureBySeriesID(ctx, series.ID, event.StartTime)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range occurrences {
+		occurrences[i].Name = series.Name
+		occurrences[i].Description = series.Description
+		occurrences[i].AfterRegistrationText = series.AfterRegistrationText
+		occurrences[i].Location = series.Location
+		occurrences[i].MaxParticipants = series.MaxParticipants
+		occurrences[i].ExpectedParticipants = series.ExpectedParticipants
+		occurrences[i].AllowedRoles = series.AllowedRoles
+		occurrences[i].PassRequired = series.PassRequired
+		occurrences[i].PassLocationID = series.PassLocationID
+
+		if _, err = s.eventRepo.Update(ctx, &occurrences[i]); err != nil {
+			return nil, fmt.Errorf("update occurrence %s: %w", occurrences[i].ID, err)
+		}
+	}
+
+	return occurrences, nil
+}
+
+// CancelOccurrence remembers the date of a single occurrence of the series as cancelled,
+// so that it is excluded from calendar exports. The occurrence itself must be cancelled by EventService.Cancel first.
+// A repeated call doesn't add the date twice
+func (s *EventSeriesService) CancelOccurrence(ctx context.Context, eventID string) (*entity.Event, error) {
+	event, err := s.eventRepo.Get(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if !event.IsRecurring() {
+		return nil, errorz.ErrEventNotRecurring
+	}
+
+	series, err := s.repo.Get(ctx, *event.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	series.CancelOccurrence(event.StartTime)
+	if _, err = s.repo.Update(ctx, series); err != nil {
+		return nil, fmt.Errorf("update series: %w", err)
+	}
+
+	return event, nil
+}
+
+// Materialize creates occurrences of all active series up to entity.EventSeriesHorizon
+func (s *EventSeriesService) Materialize(ctx context.Context) error {
+	series, err := s.repo.GetActive(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for i := range series {
+		if err = s.materialize(ctx, &series[i]); err != nil {
+			s.logger.Errorf("failed to materialize series %s: %v", series[i].ID, err)
+		}
+	}
+
+	return nil
+}
+
+// StartScheduler starts the daily materialisation of event series
+func (s *EventSeriesService) StartScheduler() error {
+	s.logger.Debug("Initializing event series scheduler...")
+
+	// Every day at 03:00
+	_, err := s.cron.AddFunc("0 3 * * *", func() {
+		if err := s.Materialize(context.Background()); err != nil {
+			s.logger.Errorf("failed to materialize event series: %v", err)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	s.cron.Start()
+	s.logger.Info("Event series scheduler initialized")
+	return nil
+}
+
+// StopScheduler stops the event series scheduler
+func (s *EventSeriesService) StopScheduler() {
+	if s.cron != nil {
+		s.cron.Stop()
+		s.logger.Info("Event series scheduler stopped")
+	}
+}
+
+func (s *EventSeriesService) materialize(ctx context.Context, series *entity.EventSeries) error {
+	return s.createOccurrences(ctx, series, series.NextOccurrences(time.Now().Add(entity.EventSeriesHorizon)))
+}
+
+// createOccurrences creates the occurrences of the series starting at starts together with the series progress
+func (s *EventSeriesService) createOccurrences(ctx context.Context, series *entity.EventSeries, starts []time.Time) error {
+	if len(starts) == 0 {
+		return nil
+	}
+
+	occurrences := make([]entity.Event, 0, len(starts))
+	for _, start := range starts {
+		occurrences = append(occurrences, series.Occurrence(start))
+	}
+
+	if err := s.repo.CreateOccurrences(ctx, series, occurrences); err != nil {
+		return fmt.Errorf("create occurrences: %w", err)
+	}
+	series.LastOccurrenceAt = starts[len(starts)-1]
+
+	return nil
+}
@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	tele "gopkg.in/telebot.v3"
+	"gopkg.in/telebot.v3/layout"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/primary"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/secondary"
+	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
+)
+
+type MailingService struct {
+	repo          secondary.MailingRepository
+	userRepo      secondary.UserRepository
+	outboxService primary.OutboxService
+
+	layout *layout.Layout
+	logger *types.Logger
+
+	mailingChannelID int64
+
+	cron *cron.Cron
+}
+
+func NewMailingService(
+	repo secondary.MailingRepository,
+	userRepo secondary.UserRepository,
+	outboxService primary.OutboxService,
+	layout *layout.Layout,
+	logger *types.Logger,
+	mailingChannelID int64,
+) *MailingService {
+	return &MailingService{
+		repo:             repo,
+		userRepo:         userRepo,
+		outboxService:    outboxService,
+		layout:           layout,
+		logger:           logger,
+		mailingChannelID: mailingChannelID,
+		cron:             cron.New(cron.WithLocation(location.Location())),
+	}
+}
+
+// Send records the mailing and sends it to the audience, or keeps it until mailing.ScheduledAt if it is set.
+//
+// what may be a string, *tele.Photo, *tele.Video, *tele.Audio or *tele.Document.
+// Users who disabled mailings from the club are recorded as skipped, users who blocked the bot as blocked.
+func (s *MailingService) Send(ctx context.Context, mailing *entity.Mailing, what interface{}) (*entity.Mailing, error) {
+	content, err := newMessageContent(what)
+	if err != nil {
+		return nil, err
+	}
+	mailing.MessageContent = content
+
+	mailing, err = s.repo.Create(ctx, mailing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mailing: %w", err)
+	}
+
+	if mailing.IsScheduled() && !mailing.IsDue(time.Now()) {
+		return mailing, nil
+	}
+
+	if err = s.dispatch(ctx, mailing); err != nil {
+		return nil, err
+	}
+	return mailing, nil
+}
+
+func (s *MailingService) Get(ctx context.Context, id string) (*entity.Mailing, error) {
+	return s.repo.Get(ctx, id)
+}
+
+func (s *MailingService) GetByClubID(ctx context.Context, clubID string, limit, offset int) ([]entity.Mailing, error) {
+	return s.repo.GetByClubID(ctx, clubID, limit, offset)
+}
+
+func (s *MailingService) GetScheduledByClubID(ctx context.Context, clubID string) ([]entity.Mailing, error) {
+	return s.repo.GetScheduledByClubID(ctx, clubID)
+}
+
+func (s *MailingService) CountByClubID(ctx context.Context, clubID string) (int64, error) {
+	return s.repo.CountByClubID(ctx, clubID)
+}
+
+func (s *MailingService) GetStats(ctx context.Context, id string) (dto.MailingStats, error) {
+	return s.repo.GetStats(ctx, id)
+}
+
+// Reschedule changes the send time of the scheduled mailing
+func (s *MailingService) Reschedule(ctx context.Context, id string, scheduledAt time.Time) (*entity.Mailing, error) {
+	mailing, err := s.getScheduled(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	mailing.ScheduledAt = &scheduledAt
+	return s.repo.UpdateScheduled(ctx, mailing)
+}
+
+// UpdateText replaces the text (or the media caption) of the scheduled mailing
+func (s *MailingService) UpdateText(ctx context.Context, id string, text string) (*entity.Mailing, error) {
+	mailing, err := s.getScheduled(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	mailing.Text = text
+	return s.repo.UpdateScheduled(ctx, mailing)
+}
+
+// CancelScheduled cancels the scheduled mailing, cancelled mailings are kept in the history
+func (s *MailingService) CancelScheduled(ctx context.Context, id string) (*entity.Mailing, error) {
+	mailing, err := s.getScheduled(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	mailing.Cancel()
+	return s.repo.UpdateScheduled(ctx, mailing)
+}
+
+// RetryFailed enqueues the mailing again for recipients it was not delivered to.
+//
+// Recipients who still block the bot are left as is. It returns the number of enqueued messages
+func (s *MailingService) RetryFailed(ctx context.Context, id string) (int, error) {
+	mailing, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	recipients, err := s.repo.GetRecipientsByStatus(ctx, id, entity.MailingRecipientError, entity.MailingRecipientBlocked)
+	if err != nil {
+		return 0, err
+	}
+	if len(recipients) == 0 {
+		return 0, nil
+	}
+
+	userIDs := make([]int64, 0, len(recipients))
+	for _, recipient := range recipients {
+		userIDs = append(userIDs, recipient.UserID)
+	}
+
+	users, err := s.userRepo.GetMany(ctx, userIDs)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get recipients: %w", err)
+	}
+
+	var chatIDs []int64
+	for _, user := range users {
+		if !user.IsBotBlocked() {
+			chatIDs = append(chatIDs, user.ID)
+		}
+	}
+	if len(chatIDs) == 0 {
+		return 0, nil
+	}
+
+	what, err := messageSendable(mailing.MessageContent)
+	if err != nil {
+		return 0, err
+	}
+
+	if err = s.repo.UpdateRecipientStatus(ctx, id, chatIDs, entity.MailingRecipientPending, ""); err != nil {
+		return 0, fmt.Errorf("failed to update recipients: %w", err)
+	}
+
+	if err = s.outboxService.EnqueueMailing(ctx, id, chatIDs, what, s.markup(mailing.ClubID)); err != nil {
+		return 0, err
+	}
+
+	return len(chatIDs), nil
+}
+
+// DispatchDue sends scheduled mailings whose time has come
+func (s *MailingService) DispatchDue(ctx context.Context) error {
+	mailings, err := s.repo.GetDue(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for i := range mailings {
+		s.logger.Infof("Sending scheduled mailing (mailing_id=%s, club_id=%s)", mailings[i].ID, mailings[i].ClubID)
+		err = s.dispatch(ctx, &mailings[i])
+		switch {
+		case errors.Is(err, errorz.ErrMailingNotScheduled):
+			s.logger.Infof("Scheduled mailing %s was sent or cancelled concurrently", mailings[i].ID)
+		case err != nil:
+			s.logger.Errorf("failed to send scheduled mailing %s: %v", mailings[i].ID, err)
+		}
+	}
+
+	return nil
+}
+
+// StartScheduler starts sending scheduled mailings every minute
+func (s *MailingService) StartScheduler() error {
+	s.logger.Debug("Initializing mailing scheduler...")
+
+	_, err := s.cron.AddFunc("* * * * *", func() {
+		if err := s.DispatchDue(context.Background()); err != nil {
+			s.logger.Errorf("failed to send scheduled mailings: %v", err)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	s.cron.Start()
+	s.logger.Info("Mailing scheduler initialized")
+	return nil
+}
+
+// StopScheduler stops the mailing scheduler
+func (s *MailingService) StopScheduler() {
+	if s.cron != nil {
+		s.cron.Stop()
+		s.logger.Info("Mailing scheduler stopped")
+	}
+}
+
+func (s *MailingService) getScheduled(ctx context.Context, id string) (*entity.Mailing, error) {
+	mailing, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !mailing.IsScheduled() {
+		return nil, errorz.ErrMailingNotScheduled
+	}
+	return mailing, nil
+}
+
+// dispatch resolves the audience of the mailing and enqueues the message for it and for the mailing channel
+func (s *MailingService) dispatch(ctx context.Context, mailing *entity.Mailing) error {
+	users, err := s.audience(ctx, mailing)
+	if err != nil {
+		return fmt.Errorf("failed to get mailing audience: %w", err)
+	}
+
+	var chatIDs []int64
+	recipients := make([]entity.MailingRecipient, 0, len(users))
+	for _, user := range users {
+		recipient := entity.MailingRecipient{
+			MailingID: mailing.ID,
+			UserID:    user.ID,
+			Status:    entity.MailingRecipientPending,
+		}
+		switch {
+		case !user.IsMailingAllowed(mailing.ClubID):
+			recipient.Status = entity.MailingRecipientSkipped
+		case user.IsBotBlocked():
+			recipient.Status = entity.MailingRecipientBlocked
+		default:
+			chatIDs = append(chatIDs, user.ID)
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	what, err := messageSendable(mailing.MessageContent)
+	if err != nil {
+		return err
+	}
+
+	messages, err := newOutboxMessages(&mailing.ID, chatIDs, what, s.markup(mailing.ClubID))
+	if err != nil {
+		return err
+	}
+
+	// The mailing is marked as sent together with its recipients and messages,
+	// so it is never sent twice and its recipients are never left pending without a message
+	mailing.MarkSent()
+	if err = s.repo.Dispatch(ctx, mailing, recipients, messages); err != nil {
+		return fmt.Errorf("failed to dispatch mailing: %w", err)
+	}
+
+	if s.mailingChannelID != 0 {
+		if err = s.outboxService.Enqueue(ctx, s.mailingChannelID, what); err != nil {
+			s.logger.Errorf("failed to enqueue mailing %s to mailing channel: %v", mailing.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// audience returns users the mailing is addressed to at the moment
+func (s *MailingService) audience(ctx context.Context, mailing *entity.Mailing) ([]entity.User, error) {
+	switch mailing.Audience {
+	case entity.MailingAudienceClub:
+		return s.userRepo.GetClubFollowers(ctx, mailing.ClubID)
+	case entity.MailingAudienceEventRegistered:
+		return s.userRepo.GetUsersByEventID(ctx, *mailing.EventID)
+	case entity.MailingAudienceEventVisited:
+		eventUsers, err := s.userRepo.GetEventUsers(ctx, *mailing.EventID)
+		if err != nil {
+			return nil, err
+		}
+
+		var users []entity.User
+		for _, eventUser := range eventUsers {
+			if eventUser.UserVisit {
+				users = append(users, eventUser.User)
+			}
+		}
+		return users, nil
+	default:
+		return nil, fmt.Errorf("unknown mailing audience %q", mailing.Audience)
+	}
+}
+
+// markup returns the keyboard attached to mailing messages
+//
+// NOTE: localisation is hardcoded for now (ru)
+func (s *MailingService) markup(clubID string) *tele.ReplyMarkup {
+	return s.layout.MarkupLocale("ru", "mailing", struct {
+		ClubID  string
+		Allowed bool
+	}{
+		ClubID:  clubID,
+		Allowed: true,
+	})
+}
@@ -2,10 +2,12 @@ package service
 
 import (
 	"context"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/valueobject"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/primary"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/secondary"
 
@@ -20,15 +22,21 @@ import (
 )
 
 type NotifyService struct {
+	outboxService        primary.OutboxService
 	clubOwnerService     primary.ClubOwnerService
+	adminService         primary.AdminService
 	eventRepo            secondary.EventRepository
 	notificationRepo     secondary.NotificationRepository
 	eventParticipantRepo secondary.EventParticipantRepository
+	clubRepo             secondary.ClubRepository
+	userRepo             secondary.UserRepository
 
 	bot    *tele.Bot
 	layout *layout.Layout
 	logger *types.Logger
 
+	announcementChannelID int64
+
 	cron *cron.Cron
 }
 
@@ -36,20 +44,32 @@ func NewNotifyService(
 	bot *tele.Bot,
 	layout *layout.Layout,
 	logger *types.Logger,
+	outboxService primary.OutboxService,
 	clubOwnerService primary.ClubOwnerService,
+	adminService primary.AdminService,
 	eventRepo secondary.EventRepository,
 	notificationRepo secondary.NotificationRepository,
 	notifyEventParticipantRepo secondary.EventParticipantRepository,
+	clubRepo secondary.ClubRepository,
+	userRepo secondary.UserRepository,
+	announcementChannelID int64,
 ) *NotifyService {
 	return &NotifyService{
+		outboxService:        outboxService,
 		clubOwnerService:     clubOwnerService,
+		adminService:         adminService,
 		eventRepo:            eventRepo,
 		notificationRepo:     notificationRepo,
 		eventParticipantRepo: notifyEventParticipantRepo,
+		clubRepo:             clubRepo,
+		userRepo:             userRepo,
 		bot:                  bot,
 		layout:               layout,
 		logger:               logger,
-		cron:                 cron.New(cron.WithLocation(location.Location())),
+
+		announcementChannelID: announcementChannelID,
+
+		cron: cron.New(cron.WithLocation(location.Location())),
 	}
 }
 
@@ -81,47 +101,124 @@ func (s *NotifyService) SendClubWarning(clubID string, what interface{}, opts ..
 		return err
 	}
 
-	var errors []error
+	var chatIDs []int64
 	for _, owner := range clubOwners {
 		if owner.Warnings {
-			chat, errGetChat := s.bot.ChatByID(owner.UserID)
-			if errGetChat != nil {
-				errors = append(errors, errGetChat)
-			}
-			_, errSend := s.bot.Send(chat, what, opts...)
-			if errSend != nil {
-				errors = append(errors, errSend)
-			}
+			chatIDs = append(chatIDs, owner.UserID)
 		}
 	}
 
-	if len(errors) > 0 {
-		return errors[0]
+	return s.outboxService.EnqueueMany(context.Background(), chatIDs, what, opts...)
+}
+
+// SendClubStaffUpdate sends the message to all club staff members who are not banned,
+// unlike SendClubWarning it ignores the notification settings
+func (s *NotifyService) SendClubStaffUpdate(clubID string, what interface{}, opts ...interface{}) error {
+	clubOwners, err := s.clubOwnerService.GetByClubID(context.Background(), clubID)
+	if err != nil {
+		return err
 	}
-	return nil
+
+	chatIDs := make([]int64, 0, len(clubOwners))
+	for _, owner := range clubOwners {
+		if !owner.IsBanned {
+			chatIDs = append(chatIDs, owner.UserID)
+		}
+	}
+
+	return s.outboxService.EnqueueMany(context.Background(), chatIDs, what, opts...)
 }
 
+// SendAdminWarning sends the message to the admins who manage clubs
+func (s *NotifyService) SendAdminWarning(what interface{}, opts ...interface{}) error {
+	admins, err := s.adminService.GetAll(context.Background())
+	if err != nil {
+		return err
+	}
+
+	chatIDs := make([]int64, 0, len(admins))
+	for _, admin := range admins {
+		if admin.Role.Can(valueobject.ClubsPermission) {
+			chatIDs = append(chatIDs, admin.UserID)
+		}
+	}
+
+	return s.outboxService.EnqueueMany(context.Background(), chatIDs, what, opts...)
+}
+
+// SendEventUpdate enqueues the message for all participants of the event
 func (s *NotifyService) SendEventUpdate(eventID string, what interface{}, opts ...interface{}) error {
 	participants, err := s.eventParticipantRepo.GetByEventID(context.Background(), eventID)
 	if err != nil {
 		return err
 	}
 
-	var errors []error
+	chatIDs := make([]int64, 0, len(participants))
 	for _, participant := range participants {
-		chat, errGetChat := s.bot.ChatByID(participant.UserID)
-		if errGetChat != nil {
-			errors = append(errors, errGetChat)
+		chatIDs = append(chatIDs, participant.UserID)
+	}
+
+	return s.outboxService.EnqueueMany(context.Background(), chatIDs, what, opts...)
+}
+
+// SendEventAnnouncement announces the new event to followers of the club who can register for it
+// and to the public announcement channel if it is configured
+//
+// NOTE: localisation is hardcoded for now (ru)
+func (s *NotifyService) SendEventAnnouncement(event *entity.Event) error {
+	ctx := context.Background()
+
+	club, err := s.clubRepo.Get(ctx, event.ClubID)
+	if err != nil {
+		return err
+	}
+
+	followers, err := s.userRepo.GetClubFollowers(ctx, event.ClubID)
+	if err != nil {
+		return err
+	}
+
+	var chatIDs []int64
+	for _, follower := range followers {
+		if follower.IsBotBlocked() || !follower.IsMailingAllowed(event.ClubID) {
+			continue
 		}
-		_, errSend := s.bot.Send(chat, what, opts...)
-		if errSend != nil {
-			errors = append(errors, errSend)
+		if len(event.AllowedRoles) > 0 && !slices.Contains(event.AllowedRoles, follower.Role.String()) {
+			continue
 		}
+		chatIDs = append(chatIDs, follower.ID)
+	}
+
+	text := s.layout.TextLocale("ru", "event_announcement", struct {
+		ClubName    string
+		Name        string
+		Description string
+		Location    string
+		StartTime   string
+	}{
+		ClubName:    club.Name,
+		Name:        event.Name,
+		Description: event.Description,
+		Location:    event.Location,
+		StartTime:   event.StartTime.In(location.Location()).Format("02.01.2006 15:04"),
+	})
+	markup := s.layout.MarkupLocale("ru", "event:announcement", struct {
+		Link string
+	}{
+		Link: event.Link(s.bot.Me.Username),
+	})
+
+	s.logger.Infof("Announcing event (event_id=%s, club_id=%s, followers=%d)", event.ID, event.ClubID, len(chatIDs))
+	if err = s.outboxService.EnqueueMany(ctx, chatIDs, text, markup); err != nil {
+		return err
 	}
 
-	if len(errors) > 0 {
-		return errors[0]
+	if s.announcementChannelID != 0 {
+		if err = s.outboxService.Enqueue(ctx, s.announcementChannelID, text, markup); err != nil {
+			return err
+		}
 	}
+
 	return nil
 }
 
@@ -178,31 +275,25 @@ func (s *NotifyService) sendClubOwnerReminder(ctx context.Context) {
 
 	s.logger.Infof("Found %d unique club owners to send reminder", len(clubOwners))
 
+	var chatIDs []int64
 	for _, owner := range clubOwners {
 		if owner.IsBanned {
 			s.logger.Debugf("Skipping banned user %d", owner.UserID)
 			continue
 		}
+		chatIDs = append(chatIDs, owner.UserID)
+	}
 
-		chat, errGetChat := s.bot.ChatByID(owner.UserID)
-		if errGetChat != nil {
-			s.logger.Errorf("Failed to get chat for user %d: %v", owner.UserID, errGetChat)
-			continue
-		}
-
-		_, errSend := s.bot.Send(chat,
-			s.layout.TextLocale("ru", "club_owner_weekly_reminder"),
-			s.layout.MarkupLocale("ru", "core:hide"),
-		)
-		if errSend != nil {
-			s.logger.Errorf("Failed to send mailing to user %d: %v", owner.UserID, errSend)
-			continue
-		}
-
-		s.logger.Infof("Sent weekly reminder to club owner %d (%s)", owner.UserID, owner.FIO.String())
+	err = s.outboxService.EnqueueMany(ctx, chatIDs,
+		s.layout.TextLocale("ru", "club_owner_weekly_reminder"),
+		s.layout.MarkupLocale("ru", "core:hide"),
+	)
+	if err != nil {
+		s.logger.Errorf("Failed to enqueue weekly reminder: %v", err)
+		return
 	}
 
-	s.logger.Info("Weekly reminder to club owners completed")
+	s.logger.Infof("Weekly reminder to %d club owners enqueued", len(chatIDs))
 }
 
 // checkAndNotify checks for events starting in the next 25 hours (to cover both day and hour notifications)
@@ -255,13 +346,6 @@ func (s *NotifyService) sendNotifications(ctx context.Context, event entity.Even
 			notificationType,
 		)
 
-		// Send notification
-		chat, errGetChat := s.bot.ChatByID(participant.UserID)
-		if errGetChat != nil {
-			s.logger.Errorf("failed to get chat for user %d: %v", participant.UserID, errGetChat)
-			continue
-		}
-
 		var messageKey string
 		switch notificationType {
 		case entity.NotificationTypeDay:
@@ -270,12 +354,12 @@ func (s *NotifyService) sendNotifications(ctx context.Context, event entity.Even
 			messageKey = "event_notification_hour"
 		}
 
-		_, errSend := s.bot.Send(chat,
+		errSend := s.outboxService.Enqueue(ctx, participant.UserID,
 			s.layout.TextLocale("ru", messageKey, event),
 			s.layout.MarkupLocale("ru", "core:hide"),
 		)
 		if errSend != nil {
-			s.logger.Errorf("failed to send notification to user %d: %v", participant.UserID, errSend)
+			s.logger.Errorf("failed to enqueue notification to user %d: %v", participant.UserID, errSend)
 			continue
 		}
 
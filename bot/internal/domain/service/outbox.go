@@ -0,0 +1,485 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	tele "gopkg.in/telebot.v3"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/secondary"
+	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
+)
+
+const (
+	defaultOutboxWorkers     = 4
+	defaultOutboxRateLimit   = 25
+	defaultOutboxMaxAttempts = 8
+
+	// outboxPollInterval is how often the dispatcher looks for due messages when nothing was enqueued
+	outboxPollInterval = 5 * time.Second
+	// outboxLease is how long a claimed message is hidden from other dispatchers,
+	// messages of a crashed instance are picked up again after it expires
+	outboxLease = 5 * time.Minute
+	// outboxPrivateChatInterval and outboxGroupChatInterval are Telegram limits for a single chat
+	outboxPrivateChatInterval = time.Second
+	outboxGroupChatInterval   = 3 * time.Second
+	outboxMaxBackoff          = time.Hour
+	// outboxRetention is how long delivered messages are kept
+	outboxRetention = 7 * 24 * time.Hour
+)
+
+// OutboxService delivers Telegram messages enqueued by other services.
+//
+// Messages are stored in the database first, so they survive restarts. Workers send them
+// within Telegram's global and per-chat limits, retry transient failures with exponential backoff
+// and mark users who blocked the bot.
+type OutboxService struct {
+	bot         *tele.Bot
+	logger      *types.Logger
+	repo        secondary.OutboxRepository
+	userRepo    secondary.UserRepository
+	mailingRepo secondary.MailingRepository
+
+	workers     int
+	maxAttempts int
+	limiter     *time.Ticker
+
+	mu          sync.Mutex
+	lastSent    map[int64]time.Time
+	pausedUntil time.Time
+
+	wakeup chan struct{}
+	jobs   chan entity.OutboxMessage
+	stop   chan struct{}
+	wg     sync.WaitGroup
+
+	cron *cron.Cron
+}
+
+func NewOutboxService(
+	bot *tele.Bot,
+	logger *types.Logger,
+	repo secondary.OutboxRepository,
+	userRepo secondary.UserRepository,
+	mailingRepo secondary.MailingRepository,
+	workers int,
+	rateLimit int,
+	maxAttempts int,
+) *OutboxService {
+	if workers < 1 {
+		workers = defaultOutboxWorkers
+	}
+	if rateLimit < 1 {
+		rateLimit = defaultOutboxRateLimit
+	}
+	if maxAttempts < 1 {
+		maxAttempts = defaultOutboxMaxAttempts
+	}
+
+	return &OutboxService{
+		bot:         bot,
+		logger:      logger,
+		repo:        repo,
+		userRepo:    userRepo,
+		mailingRepo: mailingRepo,
+		workers:     workers,
+		maxAttempts: maxAttempts,
+		limiter:     time.NewTicker(time.Second / time.Duration(rateLimit)),
+		lastSent:    make(map[int64]time.Time),
+		wakeup:      make(chan struct{}, 1),
+		jobs:        make(chan entity.OutboxMessage),
+		stop:        make(chan struct{}),
+		cron:        cron.New(cron.WithLocation(location.Location())),
+	}
+}
+
+// Enqueue stores the message for delivery to the chat.
+//
+// what may be a string, *tele.Photo, *tele.Video, *tele.Audio or *tele.Document,
+// opts may contain *tele.ReplyMarkup, *tele.SendOptions and tele.ParseMode
+func (s *OutboxService) Enqueue(ctx context.Context, chatID int64, what interface{}, opts ...interface{}) error {
+	return s.EnqueueMany(ctx, []int64{chatID}, what, opts...)
+}
+
+// EnqueueMany stores the same message for delivery to every chat
+func (s *OutboxService) EnqueueMany(ctx context.Context, chatIDs []int64, what interface{}, opts ...interface{}) error {
+	return s.enqueue(ctx, nil, chatIDs, what, opts...)
+}
+
+// EnqueueMailing stores the mailing message for delivery to the recipients,
+// the delivery status of every message is saved to the mailing recipient
+func (s *OutboxService) EnqueueMailing(
+	ctx context.Context,
+	mailingID string,
+	chatIDs []int64,
+	what interface{},
+	opts ...interface{},
+) error {
+	return s.enqueue(ctx, &mailingID, chatIDs, what, opts...)
+}
+
+func (s *OutboxService) enqueue(ctx context.Context, mailingID *string, chatIDs []int64, what interface{}, opts ...interface{}) error {
+	if len(chatIDs) == 0 {
+		return nil
+	}
+
+	messages, err := newOutboxMessages(mailingID, chatIDs, what, opts...)
+	if err != nil {
+		return err
+	}
+
+	if err = s.repo.CreateMany(ctx, messages); err != nil {
+		return fmt.Errorf("failed to enqueue messages: %w", err)
+	}
+
+	select {
+	case s.wakeup <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Start starts the dispatcher, the workers and the daily cleanup of delivered messages
+func (s *OutboxService) Start() error {
+	s.logger.Debug("Starting outbox workers...")
+
+	// Every day at 04:00
+	_, err := s.cron.AddFunc("0 4 * * *", func() {
+		deleted, err := s.repo.DeleteSentBefore(context.Background(), time.Now().Add(-outboxRetention))
+		if err != nil {
+			s.logger.Errorf("failed to delete delivered outbox messages: %v", err)
+			return
+		}
+		s.logger.Infof("Deleted %d delivered outbox messages", deleted)
+	})
+	if err != nil {
+		return err
+	}
+	s.cron.Start()
+
+	for i := 0; i < s.workers; i++ {
+		s.wg.Add(1)
+		go s.work()
+	}
+
+	s.wg.Add(1)
+	go s.dispatch()
+
+	s.logger.Infof("Outbox started (workers: %d)", s.workers)
+	return nil
+}
+
+// Stop stops the outbox and waits for messages being sent, claimed but unsent messages are sent after restart
+func (s *OutboxService) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+	s.limiter.Stop()
+	s.cron.Stop()
+	s.logger.Info("Outbox stopped")
+}
+
+func (s *OutboxService) dispatch() {
+	defer s.wg.Done()
+	defer close(s.jobs)
+
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		now := time.Now()
+		messages, err := s.repo.ClaimDue(context.Background(), now, now.Add(outboxLease), s.workers*10)
+		if err != nil {
+			s.logger.Errorf("failed to claim outbox messages: %v", err)
+		}
+
+		for _, message := range messages {
+			select {
+			case s.jobs <- message:
+			case <-s.stop:
+				return
+			}
+		}
+
+		// A full batch means more messages are probably due
+		if len(messages) == s.workers*10 {
+			continue
+		}
+
+		select {
+		case <-s.stop:
+			return
+		case <-s.wakeup:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *OutboxService) work() {
+	defer s.wg.Done()
+
+	for message := range s.jobs {
+		s.deliver(context.Background(), message)
+	}
+}
+
+func (s *OutboxService) deliver(ctx context.Context, message entity.OutboxMessage) {
+	if wait := s.chatWait(message.ChatID); wait > 0 {
+		message.NextAttemptAt = time.Now().Add(wait)
+		s.save(ctx, &message)
+		return
+	}
+
+	s.waitLimiter()
+
+	what, opts, err := outboxSendable(message)
+	if err == nil {
+		_, err = s.bot.Send(&tele.Chat{ID: message.ChatID}, what, opts...)
+	}
+	message.Attempts++
+
+	var floodErr tele.FloodError
+	switch {
+	case err == nil:
+		message.MarkSent()
+		s.reportMailing(ctx, message, entity.MailingRecipientSent)
+	case errors.As(err, &floodErr):
+		retryAfter := time.Duration(floodErr.RetryAfter) * time.Second
+		s.logger.Warnf("telegram flood limit reached, pausing outbox for %s", retryAfter)
+		s.pause(retryAfter)
+		// Flood errors are not the message's fault, so the attempt is not counted
+		message.Attempts--
+		message.Retry(time.Now().Add(retryAfter), err)
+	case errors.Is(err, tele.ErrBlockedByUser), errors.Is(err, tele.ErrUserIsDeactivated):
+		message.MarkFailed(err)
+		s.markBotBlocked(ctx, message.ChatID)
+		s.reportMailing(ctx, message, entity.MailingRecipientBlocked)
+	case errors.Is(err, tele.ErrChatNotFound), errors.Is(err, tele.ErrNotStartedByUser), errors.Is(err, tele.ErrKickedFromGroup):
+		message.MarkFailed(err)
+		s.reportMailing(ctx, message, entity.MailingRecipientError)
+	case message.Attempts >= s.maxAttempts:
+		s.logger.Errorf("failed to deliver outbox message %s to chat %d: %v", message.ID, message.ChatID, err)
+		message.MarkFailed(err)
+		s.reportMailing(ctx, message, entity.MailingRecipientError)
+	default:
+		message.Retry(time.Now().Add(outboxBackoff(message.Attempts)), err)
+	}
+
+	s.save(ctx, &message)
+}
+
+// reportMailing saves the final delivery status of the mailing message to its recipient
+func (s *OutboxService) reportMailing(ctx context.Context, message entity.OutboxMessage, status entity.MailingRecipientStatus) {
+	if message.MailingID == nil {
+		return
+	}
+
+	err := s.mailingRepo.UpdateRecipientStatus(ctx, *message.MailingID, []int64{message.ChatID}, status, message.LastError)
+	if err != nil {
+		s.logger.Errorf("failed to update recipient %d of mailing %s: %v", message.ChatID, *message.MailingID, err)
+	}
+}
+
+func (s *OutboxService) save(ctx context.Context, message *entity.OutboxMessage) {
+	if _, err := s.repo.Update(ctx, message); err != nil {
+		s.logger.Errorf("failed to update outbox message %s: %v", message.ID, err)
+	}
+}
+
+// chatWait reserves the chat for the message and returns how long to wait if the chat limit is reached
+func (s *OutboxService) chatWait(chatID int64) time.Duration {
+	interval := outboxPrivateChatInterval
+	if chatID < 0 {
+		interval = outboxGroupChatInterval
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if last, ok := s.lastSent[chatID]; ok && now.Sub(last) < interval {
+		return interval - now.Sub(last)
+	}
+	s.lastSent[chatID] = now
+
+	// Forget chats that are no longer limited
+	if len(s.lastSent) > 10000 {
+		for id, last := range s.lastSent {
+			if now.Sub(last) > outboxGroupChatInterval {
+				delete(s.lastSent, id)
+			}
+		}
+	}
+
+	return 0
+}
+
+// waitLimiter blocks until sending is allowed by the global rate limit
+func (s *OutboxService) waitLimiter() {
+	for {
+		s.mu.Lock()
+		wait := time.Until(s.pausedUntil)
+		s.mu.Unlock()
+		if wait <= 0 {
+			break
+		}
+		time.Sleep(wait)
+	}
+	<-s.limiter.C
+}
+
+func (s *OutboxService) pause(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(s.pausedUntil) {
+		s.pausedUntil = until
+	}
+}
+
+func (s *OutboxService) markBotBlocked(ctx context.Context, chatID int64) {
+	if chatID < 0 {
+		return
+	}
+
+	now := time.Now()
+	if err := s.userRepo.SetBotBlocked(ctx, chatID, &now); err != nil {
+		s.logger.Errorf("failed to mark user %d as blocked the bot: %v", chatID, err)
+		return
+	}
+	s.logger.Infof("User %d blocked the bot", chatID)
+}
+
+// outboxBackoff returns the delay before the next attempt: 10s, 20s, 40s ... up to outboxMaxBackoff
+func outboxBackoff(attempts int) time.Duration {
+	backoff := 10 * time.Second
+	for i := 1; i < attempts && backoff < outboxMaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, outboxMaxBackoff)
+}
+
+// newOutboxMessage converts the arguments of tele.Bot.Send to an outbox message without chat
+// newOutboxMessages returns the pending messages for every chat, ready to be stored
+func newOutboxMessages(mailingID *string, chatIDs []int64, what interface{}, opts ...interface{}) ([]entity.OutboxMessage, error) {
+	template, err := newOutboxMessage(what, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	messages := make([]entity.OutboxMessage, 0, len(chatIDs))
+	for _, chatID := range chatIDs {
+		message := template
+		message.ChatID = chatID
+		message.MailingID = mailingID
+		message.Status = entity.OutboxMessagePending
+		message.NextAttemptAt = now
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+func newOutboxMessage(what interface{}, opts ...interface{}) (entity.OutboxMessage, error) {
+	var (
+		message entity.OutboxMessage
+		err     error
+	)
+	message.MessageContent, err = newMessageContent(what)
+	if err != nil {
+		return message, err
+	}
+
+	var markup *tele.ReplyMarkup
+	for _, opt := range opts {
+		switch v := opt.(type) {
+		case *tele.ReplyMarkup:
+			markup = v
+		case *tele.SendOptions:
+			if v != nil {
+				message.ParseMode = string(v.ParseMode)
+				markup = v.ReplyMarkup
+			}
+		case tele.ParseMode:
+			message.ParseMode = string(v)
+		default:
+			return message, fmt.Errorf("unsupported outbox message option %T", opt)
+		}
+	}
+
+	if markup != nil && len(markup.InlineKeyboard) > 0 {
+		data, err := json.Marshal(markup.InlineKeyboard)
+		if err != nil {
+			return message, fmt.Errorf("failed to encode markup: %w", err)
+		}
+		message.Markup = string(data)
+	}
+
+	return message, nil
+}
+
+// outboxSendable converts the outbox message back to the arguments of tele.Bot.Send
+func outboxSendable(message entity.OutboxMessage) (interface{}, []interface{}, error) {
+	what, err := messageSendable(message.MessageContent)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var opts []interface{}
+	if message.ParseMode != "" {
+		opts = append(opts, tele.ParseMode(message.ParseMode))
+	}
+	if message.Markup != "" {
+		var keyboard [][]tele.InlineButton
+		if err = json.Unmarshal([]byte(message.Markup), &keyboard); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode markup: %w", err)
+		}
+		opts = append(opts, &tele.ReplyMarkup{InlineKeyboard: keyboard})
+	}
+
+	return what, opts, nil
+}
+
+// newMessageContent converts a string, *tele.Photo, *tele.Video, *tele.Audio or *tele.Document to a stored message
+func newMessageContent(what interface{}) (entity.MessageContent, error) {
+	switch v := what.(type) {
+	case string:
+		return entity.MessageContent{Kind: entity.MessageText, Text: v}, nil
+	case *tele.Photo:
+		return entity.MessageContent{Kind: entity.MessagePhoto, FileID: v.FileID, Text: v.Caption}, nil
+	case *tele.Video:
+		return entity.MessageContent{Kind: entity.MessageVideo, FileID: v.FileID, Text: v.Caption}, nil
+	case *tele.Audio:
+		return entity.MessageContent{Kind: entity.MessageAudio, FileID: v.FileID, Text: v.Caption}, nil
+	case *tele.Document:
+		return entity.MessageContent{Kind: entity.MessageDocument, FileID: v.FileID, Text: v.Caption}, nil
+	default:
+		return entity.MessageContent{}, fmt.Errorf("unsupported message type %T", what)
+	}
+}
+
+// messageSendable converts the stored message back to a value accepted by tele.Bot.Send
+func messageSendable(content entity.MessageContent) (interface{}, error) {
+	file := tele.File{FileID: content.FileID}
+	switch content.Kind {
+	case entity.MessageText:
+		return content.Text, nil
+	case entity.MessagePhoto:
+		return &tele.Photo{File: file, Caption: content.Text}, nil
+	case entity.MessageVideo:
+		return &tele.Video{File: file, Caption: content.Text}, nil
+	case entity.MessageAudio:
+		return &tele.Audio{File: file, Caption: content.Text}, nil
+	case entity.MessageDocument:
+		return &tele.Document{File: file, Caption: content.Text}, nil
+	default:
+		return nil, fmt.Errorf("unsupported message kind %q", content.Kind)
+	}
+}
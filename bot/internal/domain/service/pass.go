@@ -4,18 +4,28 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"html"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/robfig/cron/v3"
 	"github.com/xuri/excelize/v2"
 	tele "gopkg.in/telebot.v3"
+	"gopkg.in/telebot.v3/layout"
+	"gorm.io/gorm"
 
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/primary"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/secondary"
 
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/shadowban"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/valueobject"
 	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
 )
 
@@ -26,99 +36,186 @@ PassService - сервис управления пропусками для со
 - Пропуски создаются автоматически при регистрации пользователя на событие, которое требует пропуск
 - Планировщик отправляет уже созданные пропуски согласно расписанию
 - Поддерживается отправка через email и Telegram
-- Гибкая система конфигураций для разных типов отправки (будни/выходные)
+- Пропуски разделены по корпусам (entity.PassLocation): у каждого корпуса свои шаблоны локаций,
+  получатели и расписания, сводка отправляется отдельно для каждого корпуса
+- Расписания отправки (entity.PassSchedule) хранятся в базе и меняются администраторами из бота,
+  время отправки пропуска вычисляется по расписаниям его корпуса (Event.CalculateScheduledAt)
+- Изменения после отправки сводки (поздние регистрации, ручные пропуски, отмены уже отправленных пропусков)
+  отправляются охране отдельной поправкой, не дожидаясь следующей сводки
+- Сводки и поправки сохраняются (entity.PassBatch) с доставкой по каждому получателю: неудачные отправки
+  повторяются до начала мероприятия, администраторы могут отправить сводку повторно
+- Владельцы клубов и администраторы добавляют гостей без аккаунта в боте (entity.Pass.GuestFIO),
+  гости попадают в сводку с пометкой и могут быть удалены, пока пропуск не отправлен
+- Пользователь получает сообщение, когда его пропуск доставлен охране хотя бы одному получателю
 */
 
-type PassConfig struct {
-	Name            string
-	EmailRecipients []string
-	TelegramChatID  int64
-	IsActive        bool
-	CronSchedule    string
-}
+// passCorrectionSchedule is how often the changes of the passes after the sent reports are checked
+const passCorrectionSchedule = "@every 5m"
+
+// passCorrectionGrace is the time a pending pass waits for the run it is scheduled for,
+// after that the pass is sent as a correction
+const passCorrectionGrace = 10 * time.Minute
+
+// passDeliveryRetrySchedule is how often the failed deliveries of pass batches are retried
+const passDeliveryRetrySchedule = "@every 1m"
+
+// passBatchAlertBefore is the time before the deadline of an undelivered batch when the admins are alerted
+const passBatchAlertBefore = 6 * time.Hour
+
+// passBatchEmptyDeadline is how long the deliveries of a batch without events are retried
+const passBatchEmptyDeadline = 24 * time.Hour
+
+// telegramCaptionLimit is the maximum length of a document caption in Telegram
+const telegramCaptionLimit = 1024
 
 type EventWithPasses struct {
 	Event  entity.Event
 	Passes []entity.Pass
 }
 
+// EventPassCorrection is the change of the event passes after its consolidated report was sent
+type EventPassCorrection struct {
+	Event   entity.Event
+	Added   []entity.Pass
+	Removed []entity.Pass
+}
+
+// newEventPassCorrection splits the changed passes of the event into additions and removals,
+// a user whose sent pass was cancelled and then created again is not reported at all
+func newEventPassCorrection(eventWithPasses EventWithPasses) EventPassCorrection {
+	added := make(map[int64]bool)
+	removed := make(map[int64]bool)
+	for _, pass := range eventWithPasses.Passes {
+		if pass.IsGuest() {
+			continue
+		}
+		if pass.IsCancelledAfterSend() {
+			removed[pass.UserID] = true
+		} else {
+			added[pass.UserID] = true
+		}
+	}
+
+	correction := EventPassCorrection{Event: eventWithPasses.Event}
+	for _, pass := range eventWithPasses.Passes {
+		if !pass.IsGuest() && added[pass.UserID] && removed[pass.UserID] {
+			continue
+		}
+		if pass.IsCancelledAfterSend() {
+			correction.Removed = append(correction.Removed, pass)
+		} else {
+			correction.Added = append(correction.Added, pass)
+		}
+	}
+
+	return correction
+}
+
 type PassService struct {
 	bot    *tele.Bot
+	layout *layout.Layout
 	logger *types.Logger
 
-	passRepo   secondary.PassRepository
-	eventRepo  secondary.EventRepository
-	userRepo   secondary.UserRepository
-	clubRepo   secondary.ClubRepository
-	smtpClient secondary.SMTPClient
+	passRepo      secondary.PassRepository
+	locationRepo  secondary.PassLocationRepository
+	scheduleRepo  secondary.PassScheduleRepository
+	batchRepo     secondary.PassBatchRepository
+	eventRepo     secondary.EventRepository
+	userRepo      secondary.UserRepository
+	clubRepo      secondary.ClubRepository
+	smtpClient    secondary.SMTPClient
+	shadowBanRepo secondary.ShadowBanRepository
+
+	outboxService primary.OutboxService
 
 	cron             *cron.Cron
-	configs          map[string]*PassConfig
+	cronMu           sync.Mutex
+	cronEntries      []cron.EntryID
 	schedulerStarted bool
-	shadowMatcher    *shadowban.Matcher
+
+	// defaultPatterns, defaultEmails and defaultTelegramChatID are the settings
+	// of the default location, created on the first start
+	defaultPatterns       []string
+	defaultEmails         []string
+	defaultTelegramChatID int64
 }
 
 func NewPassService(
 	bot *tele.Bot,
+	layout *layout.Layout,
 	logger *types.Logger,
 	passRepo secondary.PassRepository,
+	locationRepo secondary.PassLocationRepository,
+	scheduleRepo secondary.PassScheduleRepository,
+	batchRepo secondary.PassBatchRepository,
 	eventRepo secondary.EventRepository,
 	userRepo secondary.UserRepository,
 	clubRepo secondary.ClubRepository,
 	smtpClient secondary.SMTPClient,
+	shadowBanRepo secondary.ShadowBanRepository,
+	outboxService primary.OutboxService,
+	passLocationSubstrings []string,
 	passEmails []string,
 	telegramChatID int64,
-	shadowBanNameSurnames []string,
 ) *PassService {
-	ps := &PassService{
-		bot:              bot,
-		logger:           logger,
-		passRepo:         passRepo,
-		eventRepo:        eventRepo,
-		userRepo:         userRepo,
-		clubRepo:         clubRepo,
-		smtpClient:       smtpClient,
-		cron:             cron.New(cron.WithLocation(location.Location())),
-		configs:          make(map[string]*PassConfig),
-		schedulerStarted: false,
-		shadowMatcher:    shadowban.NewMatcher(shadowBanNameSurnames),
-	}
-
-	weekdayConfig := &PassConfig{
-		Name:            "weekday",
-		EmailRecipients: passEmails,
-		TelegramChatID:  telegramChatID,
-		IsActive:        true,
-		CronSchedule:    "0 16 * * 1-5",
+	return &PassService{
+		bot:                   bot,
+		layout:                layout,
+		logger:                logger,
+		passRepo:              passRepo,
+		locationRepo:          locationRepo,
+		scheduleRepo:          scheduleRepo,
+		batchRepo:             batchRepo,
+		eventRepo:             eventRepo,
+		userRepo:              userRepo,
+		clubRepo:              clubRepo,
+		smtpClient:            smtpClient,
+		shadowBanRepo:         shadowBanRepo,
+		outboxService:         outboxService,
+		cron:                  cron.New(cron.WithLocation(location.Location())),
+		schedulerStarted:      false,
+		defaultPatterns:       passLocationSubstrings,
+		defaultEmails:         passEmails,
+		defaultTelegramChatID: telegramChatID,
 	}
-	ps.configs["weekday"] = weekdayConfig
+}
 
-	weekendConfig := &PassConfig{
-		Name:            "weekend",
-		EmailRecipients: passEmails,
-		TelegramChatID:  telegramChatID,
-		IsActive:        true,
-		CronSchedule:    "0 12 * * 6",
+// defaultPassLocation returns the location created on the first start from the pass settings,
+// the location is named after its first pattern
+func defaultPassLocation(patterns, emails []string, telegramChatID int64) entity.PassLocation {
+	name := "default"
+	if len(patterns) > 0 {
+		name = patterns[0]
 	}
-	ps.configs["weekend"] = weekendConfig
 
-	//testConfig := &PassConfig{
-	//	Name:            "test",
-	//	EmailRecipients: passEmails,
-	//	TelegramChatID:  telegramChatID,
-	//	IsActive:        true,
-	//	CronSchedule:    "* * * * *",
-	//}
-	//ps.configs["test"] = testConfig
-
-	return ps
+	return entity.PassLocation{
+		Name:            name,
+		Patterns:        patterns,
+		EmailRecipients: emails,
+		TelegramChatID:  telegramChatID,
+	}
 }
 
-func (s *PassService) getConfig(name string) *PassConfig {
-	if config, exists := s.configs[name]; exists {
-		return config
+// defaultPassSchedules returns the schedules of the default location created on the first start:
+// events on Tuesday-Saturday are sent the previous weekday at 16:00,
+// events on Sunday-Monday are sent on Saturday at 12:00
+func defaultPassSchedules(locationID string) []entity.PassSchedule {
+	return []entity.PassSchedule{
+		{
+			LocationID:   &locationID,
+			Name:         "weekday",
+			CronSchedule: "0 16 * * 1-5",
+			LeadTime:     8 * time.Hour,
+			IsActive:     true,
+		},
+		{
+			LocationID:   &locationID,
+			Name:         "weekend",
+			CronSchedule: "0 12 * * 6",
+			LeadTime:     12 * time.Hour,
+			IsActive:     true,
+		},
 	}
-	return nil
 }
 
 // CreatePassForUser создает пропуск для пользователя с проверкой на дублирование
@@ -202,111 +299,931 @@ func (s *PassService) CreatePassesByClub(
 			errors = append(errors, fmt.Errorf("user %d: %w", userID, err))
 			continue
 		}
-		passes = append(passes, *pass)
-	}
+		passes = append(passes, *pass)
+	}
+
+	return passes, errors
+}
+
+// CreateManualPasses создает пропуски, выданные администратором вручную
+//
+// Если sendNow, пропуски сразу отправляются охране отдельной поправкой,
+// иначе они уйдут со следующей сводкой корпуса (или поправкой, если сводка уже отправлена)
+func (s *PassService) CreateManualPasses(
+	ctx context.Context,
+	event *entity.Event,
+	userIDs []int64,
+	adminID int64,
+	reason string,
+	sendNow bool,
+) ([]entity.Pass, []error) {
+	switch {
+	case event.IsCancelled():
+		return nil, []error{errorz.ErrEventCancelled}
+	case !event.PassRequired:
+		return nil, []error{errorz.ErrPassNotRequired}
+	case !event.StartTime.After(time.Now()):
+		return nil, []error{errorz.ErrRegistrationEnded}
+	}
+
+	scheduledAt, err := s.CalculateScheduledAt(ctx, event)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var (
+		passes []entity.Pass
+		errs   []error
+	)
+	for _, userID := range userIDs {
+		pass, err := s.CreatePassForUser(ctx, event.ID, userID, entity.PassRequesterTypeAdmin, adminID, entity.PassTypeManual, reason, scheduledAt)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("user %d: %w", userID, err))
+			continue
+		}
+		passes = append(passes, *pass)
+	}
+
+	if sendNow && len(passes) > 0 {
+		if err = s.sendPassesNow(ctx, event, passes); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return passes, errs
+}
+
+// sendPassesNow sends the passes of the event to the security right away as an addition to the reports.
+// The passes go to the location the event was created with, like its reports.
+func (s *PassService) sendPassesNow(ctx context.Context, event *entity.Event, passes []entity.Pass) error {
+	locations, err := s.locationRepo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get pass locations: %w", err)
+	}
+
+	passLocation := entity.PassLocationOf(event, locations)
+	if passLocation == nil {
+		return errorz.ErrPassNotRequired
+	}
+
+	batch, err := s.newPassCorrectionsBatch(ctx, []EventPassCorrection{{Event: *event, Added: passes}}, passLocation)
+	if err != nil {
+		return err
+	}
+
+	passIDs := make([]string, 0, len(passes))
+	for _, pass := range passes {
+		passIDs = append(passIDs, pass.ID)
+	}
+
+	return s.sendBatch(ctx, batch, passIDs)
+}
+
+// GetEventPasses returns all passes of the event with the names of their holders
+func (s *PassService) GetEventPasses(ctx context.Context, eventID string) ([]dto.EventPass, error) {
+	passes, err := s.passRepo.GetPassesByEventID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event passes: %w", err)
+	}
+
+	holders, err := s.passHolders(ctx, passes)
+	if err != nil {
+		return nil, err
+	}
+
+	eventPasses := make([]dto.EventPass, 0, len(passes))
+	for _, pass := range passes {
+		fio := strconv.FormatInt(pass.UserID, 10)
+		if holder, ok := holders.Get(pass); ok {
+			fio = holder.Title()
+		}
+		eventPasses = append(eventPasses, dto.EventPass{Pass: pass, FIO: fio})
+	}
+
+	return eventPasses, nil
+}
+
+func (s *PassService) GetPass(ctx context.Context, id string) (*entity.Pass, error) {
+	return s.passRepo.GetPass(ctx, id)
+}
+
+// CancelPass cancels the pass, the security gets a correction if the pass was already sent
+func (s *PassService) CancelPass(ctx context.Context, passID string) (*entity.Pass, error) {
+	pass, err := s.passRepo.GetPass(ctx, passID)
+	if err != nil {
+		return nil, err
+	}
+	if pass.Status == entity.PassStatusCancelled {
+		return pass, nil
+	}
+
+	pass.Cancel()
+	if _, err = s.passRepo.UpdatePass(ctx, pass); err != nil {
+		return nil, fmt.Errorf("failed to cancel pass: %w", err)
+	}
+
+	s.logger.Infow("Pass cancelled", "passID", pass.ID, "eventID", pass.EventID, "wasSent", pass.SentAt != nil)
+	return pass, nil
+}
+
+// AddGuest создает пропуск гостя мероприятия без аккаунта в боте
+//
+// Гость попадает в сводку вместе с участниками, если сводка уже отправлена,
+// он будет отправлен поправкой к ней
+func (s *PassService) AddGuest(
+	ctx context.Context,
+	event *entity.Event,
+	fio string,
+	phone string,
+	requesterType entity.PassRequesterType,
+	requesterID any,
+) (*entity.Pass, error) {
+	switch {
+	case event.IsCancelled():
+		return nil, errorz.ErrEventCancelled
+	case !event.PassRequired:
+		return nil, errorz.ErrPassNotRequired
+	case !event.StartTime.After(time.Now()):
+		return nil, errorz.ErrRegistrationEnded
+	}
+
+	fio = strings.Join(strings.Fields(fio), " ")
+	guests, err := s.GetGuests(ctx, event.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, guest := range guests {
+		if guest.Status != entity.PassStatusCancelled && strings.EqualFold(guest.GuestFIO, fio) {
+			return nil, errorz.ErrGuestAlreadyAdded
+		}
+	}
+
+	scheduledAt, err := s.CalculateScheduledAt(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+
+	pass := &entity.Pass{
+		EventID:     event.ID,
+		Type:        entity.PassTypeManual,
+		Status:      entity.PassStatusPending,
+		GuestFIO:    fio,
+		GuestPhone:  strings.TrimSpace(phone),
+		Reason:      "guest",
+		ScheduledAt: scheduledAt,
+	}
+	pass.SetRequester(requesterType, requesterID)
+
+	created, err := s.passRepo.CreatePass(ctx, pass)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create guest pass: %w", err)
+	}
+
+	s.logger.Infow("Guest pass created", "passID", created.ID, "eventID", event.ID, "requester", requesterType)
+	return created, nil
+}
+
+// GetGuests returns the guest passes of the event, including the cancelled ones
+func (s *PassService) GetGuests(ctx context.Context, eventID string) ([]entity.Pass, error) {
+	passes, err := s.passRepo.GetPassesByEventID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event passes: %w", err)
+	}
+
+	var guests []entity.Pass
+	for _, pass := range passes {
+		if pass.IsGuest() {
+			guests = append(guests, pass)
+		}
+	}
+
+	return guests, nil
+}
+
+// RemoveGuest cancels the guest pass, the guest can only be removed until the pass is sent to the security
+func (s *PassService) RemoveGuest(ctx context.Context, passID string) (*entity.Pass, error) {
+	pass, err := s.passRepo.GetPass(ctx, passID)
+	if err != nil {
+		return nil, err
+	}
+	if !pass.IsGuest() {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	switch pass.Status {
+	case entity.PassStatusCancelled:
+		return pass, nil
+	case entity.PassStatusSent:
+		return nil, errorz.ErrPassAlreadySent
+	}
+
+	pass.Cancel()
+	if _, err = s.passRepo.UpdatePass(ctx, pass); err != nil {
+		return nil, fmt.Errorf("failed to cancel guest pass: %w", err)
+	}
+
+	s.logger.Infow("Guest pass removed", "passID", pass.ID, "eventID", pass.EventID)
+	return pass, nil
+}
+
+func (s *PassService) StartScheduler() error {
+	s.logger.Debug("Initializing pass scheduler...")
+
+	if err := s.seed(context.Background()); err != nil {
+		return fmt.Errorf("failed to create default pass location: %w", err)
+	}
+
+	if err := s.reloadScheduler(context.Background()); err != nil {
+		return err
+	}
+
+	if _, err := s.cron.AddFunc(passCorrectionSchedule, func() {
+		s.processPassCorrections(context.Background())
+	}); err != nil {
+		return fmt.Errorf("failed to add pass corrections job: %w", err)
+	}
+
+	if _, err := s.cron.AddFunc(passDeliveryRetrySchedule, func() {
+		s.retryPassDeliveries(context.Background())
+	}); err != nil {
+		return fmt.Errorf("failed to add pass deliveries retry job: %w", err)
+	}
+
+	s.cron.Start()
+	s.schedulerStarted = true
+	entries := s.cron.Entries()
+	s.logger.Infof("Pass scheduler started with %d jobs", len(entries))
+	for i, entry := range entries {
+		s.logger.Debugf("Job #%d: next run at %s", i+1, entry.Next.Format("2006-01-02 15:04:05"))
+	}
+	return nil
+}
+
+// seed creates the default location with its schedules if there are no locations yet,
+// schedules and events created before locations were introduced are assigned to it
+func (s *PassService) seed(ctx context.Context) error {
+	count, err := s.locationRepo.Count(ctx)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	defaultLocation := defaultPassLocation(s.defaultPatterns, s.defaultEmails, s.defaultTelegramChatID)
+	passLocation, err := s.locationRepo.Create(ctx, &defaultLocation)
+	if err != nil {
+		return err
+	}
+	s.logger.Infof("Created default pass location %s", passLocation.Name)
+
+	schedulesCount, err := s.scheduleRepo.Count(ctx)
+	if err != nil {
+		return err
+	}
+	if schedulesCount == 0 {
+		for _, schedule := range defaultPassSchedules(passLocation.ID) {
+			if _, err = s.scheduleRepo.Create(ctx, &schedule); err != nil {
+				return err
+			}
+			s.logger.Infof("Created default pass schedule %s (%s)", schedule.Name, schedule.CronSchedule)
+		}
+	}
+
+	return s.locationRepo.AssignUnlocated(ctx, passLocation.ID)
+}
+
+// reloadScheduler replaces the cron jobs with the runs of the current active schedules
+func (s *PassService) reloadScheduler(ctx context.Context) error {
+	schedules, err := s.scheduleRepo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get pass schedules: %w", err)
+	}
+
+	s.cronMu.Lock()
+	defer s.cronMu.Unlock()
+
+	for _, id := range s.cronEntries {
+		s.cron.Remove(id)
+	}
+	s.cronEntries = nil
+
+	for _, schedule := range schedules {
+		if !schedule.IsActive {
+			s.logger.Debugf("Skipping schedule %s (active: %v, schedule: %s)", schedule.Name, schedule.IsActive, schedule.CronSchedule)
+			continue
+		}
+
+		scheduleID, scheduleName := schedule.ID, schedule.Name
+		s.logger.Debugf("Adding cron job for schedule %s with schedule: %s", scheduleName, schedule.CronSchedule)
+
+		id, err := s.cron.AddFunc(schedule.CronSchedule, func() {
+			s.logger.Debugf("=== CRON TRIGGERED for %s ===", scheduleName)
+			s.processPendingPasses(context.Background(), scheduleID)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add cron job for schedule %s: %w", schedule.Name, err)
+		}
+		s.cronEntries = append(s.cronEntries, id)
+	}
+
+	return nil
+}
+
+// GetLocations returns all pass locations
+func (s *PassService) GetLocations(ctx context.Context) ([]entity.PassLocation, error) {
+	return s.locationRepo.GetAll(ctx)
+}
+
+func (s *PassService) GetLocation(ctx context.Context, id string) (*entity.PassLocation, error) {
+	return s.locationRepo.Get(ctx, id)
+}
+
+// MatchLocation returns the pass location of the event location or nil if a pass is not required
+func (s *PassService) MatchLocation(ctx context.Context, eventLocation string) (*entity.PassLocation, error) {
+	locations, err := s.locationRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pass locations: %w", err)
+	}
+
+	return entity.MatchPassLocation(eventLocation, locations), nil
+}
+
+// CreateLocation creates a new pass location, its passes are not sent until an admin adds schedules.
+// The location must have at least one recipient, otherwise its passes would never reach the security
+func (s *PassService) CreateLocation(ctx context.Context, passLocation *entity.PassLocation) (*entity.PassLocation, error) {
+	if !passLocation.HasRecipients() {
+		return nil, errorz.ErrPassLocationNoRecipient
+	}
+
+	return s.locationRepo.Create(ctx, passLocation)
+}
+
+// UpdateLocation saves the changed location, events keep the location they were created with
+func (s *PassService) UpdateLocation(ctx context.Context, passLocation *entity.PassLocation) (*entity.PassLocation, error) {
+	if !passLocation.HasRecipients() {
+		return nil, errorz.ErrPassLocationNoRecipient
+	}
+
+	return s.locationRepo.Update(ctx, passLocation)
+}
+
+// GetSchedules returns the schedules of the pass location
+func (s *PassService) GetSchedules(ctx context.Context, locationID string) ([]entity.PassSchedule, error) {
+	return s.scheduleRepo.GetByLocationID(ctx, locationID)
+}
+
+func (s *PassService) GetSchedule(ctx context.Context, id string) (*entity.PassSchedule, error) {
+	return s.scheduleRepo.Get(ctx, id)
+}
+
+// CreateSchedule creates a new pass schedule, the schedule is inactive until an admin enables it
+func (s *PassService) CreateSchedule(ctx context.Context, schedule *entity.PassSchedule) (*entity.PassSchedule, error) {
+	if err := schedule.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", errorz.ErrInvalidPassSchedule, err)
+	}
+	schedule.IsActive = false
+
+	return s.scheduleRepo.Create(ctx, schedule)
+}
+
+// UpdateSchedule saves the changed schedule and applies it to the scheduler and pending passes
+//
+// Returns errorz.ErrInvalidPassSchedule if the schedule is invalid and
+// errorz.ErrPassSchedulesNotCovered if some events of the location would not be covered by any run after the change
+func (s *PassService) UpdateSchedule(ctx context.Context, schedule *entity.PassSchedule) (*entity.PassSchedule, error) {
+	if err := schedule.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", errorz.ErrInvalidPassSchedule, err)
+	}
+
+	schedules, err := s.scheduleRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var locationSchedules []entity.PassSchedule
+	for i := range schedules {
+		if schedules[i].ID == schedule.ID {
+			schedules[i] = *schedule
+		}
+		if schedule.LocationID != nil && schedules[i].LocationID != nil && *schedules[i].LocationID == *schedule.LocationID {
+			locationSchedules = append(locationSchedules, schedules[i])
+		}
+	}
+
+	if uncovered, found := entity.FindUncoveredPassTime(locationSchedules, time.Now()); found {
+		return nil, fmt.Errorf("%w: %s", errorz.ErrPassSchedulesNotCovered, uncovered.Format("Mon 15:04"))
+	}
+
+	updated, err := s.scheduleRepo.Update(ctx, schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.schedulerStarted {
+		if err = s.reloadScheduler(ctx); err != nil {
+			s.logger.Errorf("Failed to reload pass scheduler: %v", err)
+		}
+	}
+
+	if err = s.reschedulePendingPasses(ctx, schedules); err != nil {
+		s.logger.Errorf("Failed to reschedule pending passes: %v", err)
+	}
+
+	return updated, nil
+}
+
+// CalculateScheduledAt returns the time the passes of the event are sent at according to the schedules of its location
+func (s *PassService) CalculateScheduledAt(ctx context.Context, event *entity.Event) (time.Time, error) {
+	locations, err := s.locationRepo.GetAll(ctx)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get pass locations: %w", err)
+	}
+
+	schedules, err := s.scheduleRepo.GetAll(ctx)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get pass schedules: %w", err)
+	}
+
+	return s.scheduledAt(event, locations, schedules), nil
+}
+
+// scheduledAt calculates the send time of the event passes by the schedules of the event location
+func (s *PassService) scheduledAt(event *entity.Event, locations []entity.PassLocation, schedules []entity.PassSchedule) time.Time {
+	passLocation := entity.PassLocationOf(event, locations)
+
+	var locationSchedules []entity.PassSchedule
+	for _, schedule := range schedules {
+		if schedule.BelongsTo(passLocation) {
+			locationSchedules = append(locationSchedules, schedule)
+		}
+	}
+
+	return event.CalculateScheduledAt(locationSchedules)
+}
+
+// reschedulePendingPasses moves pending passes to the send time calculated by the given schedules
+func (s *PassService) reschedulePendingPasses(ctx context.Context, schedules []entity.PassSchedule) error {
+	locations, err := s.locationRepo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get pass locations: %w", err)
+	}
+
+	passes, err := s.passRepo.GetPendingPassesForSchedule(ctx, time.Now().AddDate(1, 0, 0))
+	if err != nil {
+		return fmt.Errorf("failed to get pending passes: %w", err)
+	}
+
+	scheduledAt := make(map[string]time.Time)
+	for i := range passes {
+		pass := &passes[i]
+		if _, exists := scheduledAt[pass.EventID]; !exists {
+			event, err := s.eventRepo.GetEventByID(ctx, pass.EventID)
+			if err != nil {
+				s.logger.Errorf("Failed to get event %s: %v", pass.EventID, err)
+				continue
+			}
+			scheduledAt[pass.EventID] = s.scheduledAt(event, locations, schedules)
+		}
+
+		if pass.ScheduledAt.Equal(scheduledAt[pass.EventID]) {
+			continue
+		}
+
+		pass.ScheduledAt = scheduledAt[pass.EventID]
+		if _, err = s.passRepo.UpdatePass(ctx, pass); err != nil {
+			s.logger.Errorf("Failed to reschedule pass %s: %v", pass.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *PassService) StopScheduler() {
+	if s.cron != nil {
+		s.cron.Stop()
+		s.schedulerStarted = false
+		s.logger.Info("Pass scheduler stopped")
+	}
+}
+
+func (s *PassService) processPendingPasses(ctx context.Context, scheduleID string) {
+	s.logger.Debugf("Processing pending passes for schedule: %s", scheduleID)
+
+	schedule, err := s.scheduleRepo.Get(ctx, scheduleID)
+	if err != nil || !schedule.IsActive {
+		s.logger.Debugf("Schedule %s not found or inactive", scheduleID)
+		return
+	}
+
+	locations, err := s.locationRepo.GetAll(ctx)
+	if err != nil {
+		s.logger.Error("Failed to get pass locations", "error", err)
+		return
+	}
+
+	var passLocation *entity.PassLocation
+	for i := range locations {
+		if schedule.BelongsTo(&locations[i]) {
+			passLocation = &locations[i]
+		}
+	}
+	if passLocation == nil {
+		s.logger.Debugf("Location of schedule %s not found", schedule.Name)
+		return
+	}
+
+	now := time.Now().In(location.Location())
+
+	s.logger.Debugf("=== Pass Scheduler ===")
+	s.logger.Debugf("Current time (local): %s", now.Format("2006-01-02 15:04:05"))
+	s.logger.Debugf("Looking for pending passes with scheduled_at <= %s", now.Format("2006-01-02 15:04:05"))
+
+	pendingPasses, err := s.passRepo.GetPendingPassesForSchedule(ctx, now)
+	if err != nil {
+		s.logger.Error("Failed to get pending passes", "error", err)
+		return
+	}
+
+	s.logger.Debugf("Found %d pending passes", len(pendingPasses))
+
+	// Log each pass details for diagnosis
+	for i, pass := range pendingPasses {
+		s.logger.Debugf("Pass #%d: ID=%s, EventID=%s, UserID=%d, ScheduledAt=%s",
+			i+1, pass.ID, pass.EventID, pass.UserID, pass.ScheduledAt.In(location.Location()).Format("2006-01-02 15:04:05"))
+	}
+
+	// the run reports only the passes of its own location, other locations have their own reports
+	var eventsWithPasses []EventWithPasses
+	if len(pendingPasses) > 0 {
+		for _, eventWithPasses := range s.groupPassesByEvent(ctx, pendingPasses) {
+			if entity.PassLocationOf(&eventWithPasses.Event, locations).ID == passLocation.ID {
+				eventsWithPasses = append(eventsWithPasses, eventWithPasses)
+			}
+		}
+	}
+
+	batch, err := s.newConsolidatedPassBatch(ctx, eventsWithPasses, passLocation)
+	if err != nil {
+		s.logger.Error("Failed to prepare consolidated notification", "error", err)
+		return
+	}
+
+	var passIDs []string
+	for _, eventWithPasses := range eventsWithPasses {
+		for _, pass := range eventWithPasses.Passes {
+			passIDs = append(passIDs, pass.ID)
+		}
+	}
+
+	if err = s.sendBatch(ctx, batch, passIDs); err != nil {
+		s.logger.Error("Failed to send consolidated notification", "error", err)
+		return
+	}
+
+	s.logger.Infow("Processed pending passes",
+		"events", len(eventsWithPasses),
+		"totalPasses", len(pendingPasses),
+		"schedule", schedule.Name,
+		"location", passLocation.Name)
+}
+
+// CancelEventPasses отменяет пропуски отменённого мероприятия
+//
+// - Ожидающие пропуски отменяются и не попадут в сводку
+// - По уже отправленным пропускам в чат пропусков и на почту отправляется исправление
+// - Неудачные отправки исправления повторяются вместе с остальными сводками
+// - Повторный вызов безопасен: уже отменённые пропуски пропускаются
+func (s *PassService) CancelEventPasses(ctx context.Context, event *entity.Event) error {
+	passes, err := s.passRepo.GetPassesByEventID(ctx, event.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get event passes: %w", err)
+	}
+
+	var sentPasses []entity.Pass
+	for i := range passes {
+		if passes[i].Status == entity.PassStatusCancelled {
+			continue
+		}
+
+		wasSent := passes[i].Status == entity.PassStatusSent
+		passes[i].Cancel()
+		if _, err = s.passRepo.UpdatePass(ctx, &passes[i]); err != nil {
+			s.logger.Errorf("Failed to cancel pass %s: %v", passes[i].ID, err)
+			continue
+		}
+		if wasSent {
+			sentPasses = append(sentPasses, passes[i])
+		}
+	}
+
+	if len(sentPasses) == 0 {
+		return nil
+	}
+
+	if err = s.sendPassCorrection(ctx, event, sentPasses); err != nil {
+		return err
+	}
+
+	passIDs := make([]string, 0, len(sentPasses))
+	for _, pass := range sentPasses {
+		passIDs = append(passIDs, pass.ID)
+	}
+
+	return s.passRepo.MarkPassesCancellationSent(ctx, passIDs, time.Now())
+}
+
+// sendPassCorrection сообщает получателям сводки, что уже отправленные пропуски больше не нужны
+func (s *PassService) sendPassCorrection(ctx context.Context, event *entity.Event, passes []entity.Pass) error {
+	users, err := s.passHolders(ctx, passes)
+	if err != nil {
+		return err
+	}
+
+	var holders []passHolder
+	for _, pass := range passes {
+		if holder, ok := users.Get(pass); ok {
+			holders = append(holders, holder)
+		}
+	}
+
+	var message strings.Builder
+	message.WriteString("⚠️ <b>Исправление к сводке пропусков</b>\n\n")
+	_, _ = fmt.Fprintf(&message, "Мероприятие <b>%s</b> отменено\n", html.EscapeString(event.Name))
+	_, _ = fmt.Fprintf(&message, "📅 %s\n", event.StartTime.In(location.Location()).Format("02.01.2006 15:04"))
+	_, _ = fmt.Fprintf(&message, "📍 %s\n\n", html.EscapeString(event.Location))
+	_, _ = fmt.Fprintf(&message, "Пропуски больше не нужны (%d):\n", len(holders))
+	for _, holder := range holders {
+		_, _ = fmt.Fprintf(&message, "— %s\n", html.EscapeString(holder.Title()))
+	}
+
+	locations, err := s.locationRepo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get pass locations: %w", err)
+	}
+
+	passLocation := entity.PassLocationOf(event, locations)
+	if passLocation == nil {
+		return nil
+	}
+
+	batch := newPassBatch(entity.PassBatchKindCorrection, passLocation, event.StartTime,
+		fmt.Sprintf("Отмена пропусков - %s", event.Name), message.String())
+	batch.EmailText = strings.ReplaceAll(message.String(), "\n", "<br>")
+	if err = s.sendBatch(ctx, batch, nil); err != nil {
+		return err
+	}
+
+	s.logger.Infow("Pass correction sent", "eventID", event.ID, "passes", len(passes))
+	return nil
+}
+
+// processPassCorrections sends the security an addendum to the sent consolidated reports:
+// passes which missed the run covering their event (late registrations, manual passes) are added
+// and sent passes cancelled afterwards are removed. Every location gets its own addendum.
+func (s *PassService) processPassCorrections(ctx context.Context) {
+	now := time.Now()
+	passes, err := s.passRepo.GetPassCorrections(ctx, now.Add(-passCorrectionGrace), now)
+	if err != nil {
+		s.logger.Errorw("Failed to get pass corrections", "error", err)
+		return
+	}
+	if len(passes) == 0 {
+		return
+	}
+
+	locations, err := s.locationRepo.GetAll(ctx)
+	if err != nil {
+		s.logger.Errorw("Failed to get pass locations", "error", err)
+		return
+	}
+
+	correctionsByLocation := make(map[string][]EventWithPasses)
+	for _, eventWithPasses := range s.groupPassesByEvent(ctx, passes) {
+		passLocation := entity.PassLocationOf(&eventWithPasses.Event, locations)
+		if passLocation == nil {
+			continue
+		}
+		correctionsByLocation[passLocation.ID] = append(correctionsByLocation[passLocation.ID], eventWithPasses)
+	}
+
+	for i := range locations {
+		eventsWithPasses := correctionsByLocation[locations[i].ID]
+		if len(eventsWithPasses) == 0 {
+			continue
+		}
+
+		corrections := make([]EventPassCorrection, 0, len(eventsWithPasses))
+		for _, eventWithPasses := range eventsWithPasses {
+			correction := newEventPassCorrection(eventWithPasses)
+			if len(correction.Added) > 0 || len(correction.Removed) > 0 {
+				corrections = append(corrections, correction)
+			}
+		}
+
+		var addedIDs, removedIDs []string
+		for _, eventWithPasses := range eventsWithPasses {
+			for _, pass := range eventWithPasses.Passes {
+				if pass.IsCancelledAfterSend() {
+					removedIDs = append(removedIDs, pass.ID)
+				} else {
+					addedIDs = append(addedIDs, pass.ID)
+				}
+			}
+		}
+
+		if len(corrections) > 0 {
+			batch, err := s.newPassCorrectionsBatch(ctx, corrections, &locations[i])
+			if err != nil {
+				s.logger.Errorw("Failed to prepare pass corrections", "location", locations[i].Name, "error", err)
+				continue
+			}
+			if err = s.sendBatch(ctx, batch, addedIDs); err != nil {
+				s.logger.Errorw("Failed to send pass corrections", "location", locations[i].Name, "error", err)
+				continue
+			}
+		} else if len(addedIDs) > 0 {
+			// the additions are netted out by the removals, the report already has these users
+			if err = s.passRepo.MarkPassesAsSent(ctx, addedIDs, time.Now(), nil); err != nil {
+				s.logger.Errorw("Failed to mark corrected passes as sent", "error", err)
+			}
+		}
 
-	return passes, errors
+		if len(removedIDs) > 0 {
+			if err = s.passRepo.MarkPassesCancellationSent(ctx, removedIDs, time.Now()); err != nil {
+				s.logger.Errorw("Failed to mark pass cancellations as sent", "error", err)
+			}
+		}
+
+		s.logger.Infow("Processed pass corrections",
+			"events", len(corrections),
+			"added", len(addedIDs),
+			"removed", len(removedIDs),
+			"location", locations[i].Name)
+	}
 }
 
-func (s *PassService) StartScheduler() error {
-	s.logger.Debug("Initializing pass scheduler...")
+// newPassCorrectionsBatch prepares the addendum with the added and removed passes for the recipients of the location
+func (s *PassService) newPassCorrectionsBatch(ctx context.Context, corrections []EventPassCorrection, passLocation *entity.PassLocation) (*entity.PassBatch, error) {
+	holders, err := s.correctionHolders(ctx, corrections)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, config := range s.configs {
-		if !config.IsActive || config.CronSchedule == "" {
-			s.logger.Debugf("Skipping config %s (active: %v, schedule: %s)", config.Name, config.IsActive, config.CronSchedule)
-			continue
-		}
+	message := s.formatPassCorrectionsMessage(corrections, holders, passLocation.Name)
 
-		configName := config.Name
-		s.logger.Debugf("Adding cron job for config %s with schedule: %s", configName, config.CronSchedule)
+	correctionExcel, err := s.generatePassCorrectionsExcel(corrections, holders)
+	if err != nil {
+		s.logger.Errorw("Failed to generate pass corrections Excel file", "error", err)
+		return nil, err
+	}
 
-		_, err := s.cron.AddFunc(config.CronSchedule, func() {
-			s.logger.Debugf("=== CRON TRIGGERED for %s ===", configName)
-			s.processPendingPasses(context.Background(), configName)
-		})
-		if err != nil {
-			return fmt.Errorf("failed to add cron job for config %s: %w", config.Name, err)
+	deadline := corrections[0].Event.StartTime
+	for _, correction := range corrections {
+		if correction.Event.StartTime.Before(deadline) {
+			deadline = correction.Event.StartTime
 		}
-		s.logger.Debugf("Successfully added cron job for config %s", configName)
 	}
 
-	s.cron.Start()
-	s.schedulerStarted = true
-	entries := s.cron.Entries()
-	s.logger.Infof("Pass scheduler started with %d jobs", len(entries))
-	for i, entry := range entries {
-		s.logger.Debugf("Job #%d: next run at %s", i+1, entry.Next.Format("2006-01-02 15:04:05"))
-	}
-	return nil
+	batch := newPassBatch(entity.PassBatchKindCorrection, passLocation, deadline,
+		fmt.Sprintf("Поправка к сводке пропусков (%s) - %d событий", passLocation.Name, len(corrections)), message)
+	batch.EmailText = strings.ReplaceAll(message, "\n", "<br>")
+	batch.FileName = fmt.Sprintf("passes_correction_%s.xlsx", time.Now().Format("2006-01-02_15-04"))
+	batch.File = correctionExcel.Bytes()
+
+	return batch, nil
 }
 
-func (s *PassService) StopScheduler() {
-	if s.cron != nil {
-		s.cron.Stop()
-		s.schedulerStarted = false
-		s.logger.Info("Pass scheduler stopped")
+// correctionHolders returns the holders of the corrected passes
+func (s *PassService) correctionHolders(ctx context.Context, corrections []EventPassCorrection) (*passHolders, error) {
+	var passes []entity.Pass
+	for _, correction := range corrections {
+		passes = append(passes, correction.Added...)
+		passes = append(passes, correction.Removed...)
 	}
-}
 
-func (s *PassService) processPendingPasses(ctx context.Context, configName string) {
-	s.logger.Debugf("Processing pending passes for config: %s", configName)
+	return s.passHolders(ctx, passes)
+}
 
-	config := s.getConfig(configName)
-	if config == nil || !config.IsActive {
-		s.logger.Debugf("Config %s not found or inactive", configName)
-		return
+// passHolders loads the users of the passes and the current shadow bans, guests have no users
+func (s *PassService) passHolders(ctx context.Context, passes []entity.Pass) (*passHolders, error) {
+	var userIDs []int64
+	for _, pass := range passes {
+		if !pass.IsGuest() {
+			userIDs = append(userIDs, pass.UserID)
+		}
 	}
 
-	now := time.Now().In(location.Location())
-
-	s.logger.Debugf("=== Pass Scheduler ===")
-	s.logger.Debugf("Current time (local): %s", now.Format("2006-01-02 15:04:05"))
-	s.logger.Debugf("Looking for pending passes with scheduled_at <= %s", now.Format("2006-01-02 15:04:05"))
+	users, err := s.userRepo.GetMany(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pass users: %w", err)
+	}
 
-	pendingPasses, err := s.passRepo.GetPendingPassesForSchedule(ctx, now)
+	shadowMatcher, err := shadowban.Load(ctx, s.shadowBanRepo)
 	if err != nil {
-		s.logger.Error("Failed to get pending passes", "error", err)
-		return
+		return nil, fmt.Errorf("failed to get shadow bans: %w", err)
 	}
 
-	s.logger.Debugf("Found %d pending passes", len(pendingPasses))
+	holders := &passHolders{
+		users:         make(map[int64]entity.User, len(users)),
+		shadowMatcher: shadowMatcher,
+	}
+	for _, user := range users {
+		holders.users[user.ID] = user
+	}
 
-	// Log each pass details for diagnosis
-	for i, pass := range pendingPasses {
-		s.logger.Debugf("Pass #%d: ID=%s, EventID=%s, UserID=%d, ScheduledAt=%s",
-			i+1, pass.ID, pass.EventID, pass.UserID, pass.ScheduledAt.In(location.Location()).Format("2006-01-02 15:04:05"))
+	return holders, nil
+}
+
+func (s *PassService) formatPassCorrectionsMessage(corrections []EventPassCorrection, holders *passHolders, locationName string) string {
+	var message strings.Builder
+	_, _ = fmt.Fprintf(&message, "📝 <b>Поправка к сводке пропусков</b> — %s\n", html.EscapeString(locationName))
+
+	writePasses := func(title string, passes []entity.Pass) {
+		if len(passes) == 0 {
+			return
+		}
+		_, _ = fmt.Fprintf(&message, "%s (%d):\n", title, len(passes))
+		for _, pass := range passes {
+			if holder, ok := holders.Get(pass); ok {
+				_, _ = fmt.Fprintf(&message, "— %s\n", html.EscapeString(holder.Title()))
+			}
+		}
 	}
 
-	var eventsWithPasses []EventWithPasses
-	if len(pendingPasses) > 0 {
-		eventsWithPasses = s.groupPassesByEvent(ctx, pendingPasses)
+	for i, correction := range corrections {
+		_, _ = fmt.Fprintf(&message, "\n<b>%d. %s</b>\n", i+1, html.EscapeString(correction.Event.Name))
+		_, _ = fmt.Fprintf(&message, "📅 %s\n", correction.Event.StartTime.In(location.Location()).Format("02.01.2006 15:04"))
+		_, _ = fmt.Fprintf(&message, "📍 %s\n", html.EscapeString(correction.Event.Location))
+		writePasses("➕ Добавить", correction.Added)
+		writePasses("➖ Убрать", correction.Removed)
 	}
 
-	telegramSent, emailSent, err := s.sendConsolidatedPassNotification(ctx, eventsWithPasses, config)
-	if err != nil {
-		s.logger.Error("Failed to send consolidated notification", "error", err)
-		return
+	return message.String()
+}
+
+func (s *PassService) generatePassCorrectionsExcel(corrections []EventPassCorrection, holders *passHolders) (*bytes.Buffer, error) {
+	f := excelize.NewFile()
+	defer func() {
+		if err := f.Close(); err != nil {
+			s.logger.Errorf("Failed to close Excel file: %v", err)
+		}
+	}()
+
+	sheetName := "Поправка"
+	if err := f.SetSheetName("Sheet1", sheetName); err != nil {
+		return nil, fmt.Errorf("failed to set sheet name: %w", err)
 	}
 
-	if len(pendingPasses) > 0 {
-		sentAt := time.Now()
-		var passIDs []string
-		for _, eventWithPasses := range eventsWithPasses {
-			for _, pass := range eventWithPasses.Passes {
-				passIDs = append(passIDs, pass.ID)
-			}
+	headers := []string{"Изменение", "Событие", "Дата", "Время", "Место", "ФИО", "Роль", "Телефон"}
+	for i, header := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		if err := f.SetCellValue(sheetName, cell, header); err != nil {
+			return nil, fmt.Errorf("failed to set header cell: %w", err)
 		}
-		if len(passIDs) > 0 {
-			if err := s.passRepo.MarkPassesAsSent(ctx, passIDs, sentAt, emailSent, telegramSent); err != nil {
-				s.logger.Error("Failed to mark passes as sent", "error", err)
+	}
+
+	row := 2
+	writePasses := func(change string, event entity.Event, passes []entity.Pass) {
+		for _, pass := range passes {
+			holder, ok := holders.Get(pass)
+			if !ok {
+				continue
+			}
+
+			data := []any{
+				change,
+				event.Name,
+				event.StartTime.In(location.Location()).Format("02.01.2006"),
+				event.StartTime.In(location.Location()).Format("15:04"),
+				event.Location,
+				holder.FIO,
+				holder.Role,
+				holder.Phone,
+			}
+
+			for i, value := range data {
+				cell, _ := excelize.CoordinatesToCellName(i+1, row)
+				if err := f.SetCellValue(sheetName, cell, value); err != nil {
+					s.logger.Errorf("Failed to set cell value: %v", err)
+					continue
+				}
 			}
+			row++
 		}
 	}
 
-	s.logger.Infow("Processed pending passes",
-		"events", len(eventsWithPasses),
-		"totalPasses", len(pendingPasses),
-		"config", configName)
+	for _, correction := range corrections {
+		writePasses("Добавить", correction.Event, correction.Added)
+		writePasses("Убрать", correction.Event, correction.Removed)
+	}
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, err
+	}
+
+	return &buf, nil
 }
 
 func (s *PassService) groupPassesByEvent(ctx context.Context, passes []entity.Pass) []EventWithPasses {
@@ -339,65 +1256,289 @@ func (s *PassService) groupPassesByEvent(ctx context.Context, passes []entity.Pa
 	return result
 }
 
-func (s *PassService) sendConsolidatedPassNotification(ctx context.Context, eventsWithPasses []EventWithPasses, config *PassConfig) (telegramSent bool, emailSent bool, err error) {
+// newConsolidatedPassBatch prepares the consolidated report of the location,
+// the report is sent even if there are no passes so the security knows the run happened
+func (s *PassService) newConsolidatedPassBatch(ctx context.Context, eventsWithPasses []EventWithPasses, passLocation *entity.PassLocation) (*entity.PassBatch, error) {
 	totalPasses := 0
+	deadline := time.Now().Add(passBatchEmptyDeadline)
 	for _, eventWithPasses := range eventsWithPasses {
 		totalPasses += len(eventWithPasses.Passes)
+		if eventWithPasses.Event.StartTime.Before(deadline) {
+			deadline = eventWithPasses.Event.StartTime
+		}
 	}
 
-	message := s.formatConsolidatedPassMessage(ctx, eventsWithPasses, totalPasses)
+	message := s.formatConsolidatedPassMessage(ctx, eventsWithPasses, totalPasses, passLocation.Name)
 
-	var consolidatedExcel *bytes.Buffer
+	var (
+		consolidatedExcel *bytes.Buffer
+		err               error
+	)
 	if totalPasses > 0 {
 		consolidatedExcel, err = s.generateConsolidatedPassExcel(ctx, eventsWithPasses)
 		if err != nil {
 			s.logger.Errorw("Failed to generate consolidated Excel file", "error", err)
-			return false, false, err
+			return nil, err
 		}
 	} else {
 		consolidatedExcel, err = s.generateEmptyPassExcel()
 		if err != nil {
 			s.logger.Errorw("Failed to generate empty Excel file", "error", err)
-			return false, false, err
+			return nil, err
 		}
 	}
 
-	if config.TelegramChatID != 0 {
-		buf := bytes.NewBuffer(consolidatedExcel.Bytes())
-		if sendErr := s.sendTelegramNotification(config.TelegramChatID, message, buf); sendErr != nil {
-			s.logger.Errorw("Failed to send consolidated Telegram notification", "error", sendErr)
-			telegramSent = false
-		} else {
-			telegramSent = true
-			s.logger.Info("Consolidated Telegram notification sent")
+	batch := newPassBatch(entity.PassBatchKindReport, passLocation, deadline,
+		fmt.Sprintf("Сводка пропусков (%s) - %d событий (%d пропусков)", passLocation.Name, len(eventsWithPasses), totalPasses), message)
+	batch.FileName = fmt.Sprintf("passes_%s.xlsx", time.Now().Format("2006-01-02"))
+	batch.File = consolidatedExcel.Bytes()
+
+	return batch, nil
+}
+
+// newPassBatch returns the batch with a delivery for every recipient of the location
+func newPassBatch(kind entity.PassBatchKind, passLocation *entity.PassLocation, deadline time.Time, subject, message string) *entity.PassBatch {
+	batch := &entity.PassBatch{
+		LocationID: &passLocation.ID,
+		Kind:       kind,
+		Subject:    subject,
+		Message:    message,
+		Deadline:   deadline,
+	}
+
+	now := time.Now()
+	if passLocation.TelegramChatID != 0 {
+		batch.Deliveries = append(batch.Deliveries, entity.PassBatchDelivery{
+			Channel:       entity.PassDeliveryChannelTelegram,
+			Recipient:     strconv.FormatInt(passLocation.TelegramChatID, 10),
+			Status:        entity.PassDeliveryStatusPending,
+			NextAttemptAt: now,
+		})
+	}
+	for _, email := range passLocation.EmailRecipients {
+		batch.Deliveries = append(batch.Deliveries, entity.PassBatchDelivery{
+			Channel:       entity.PassDeliveryChannelEmail,
+			Recipient:     email,
+			Status:        entity.PassDeliveryStatusPending,
+			NextAttemptAt: now,
+		})
+	}
+
+	return batch
+}
+
+// sendBatch saves the batch, marks the passes as sent in it and delivers it to the recipients,
+// failed deliveries are retried by the scheduler. A batch without recipients is not saved
+// and its passes stay pending until the location gets a recipient
+func (s *PassService) sendBatch(ctx context.Context, batch *entity.PassBatch, passIDs []string) error {
+	if len(batch.Deliveries) == 0 {
+		s.logger.Errorf("Pass batch (%s) has no recipients, %d passes are not sent. Add a Telegram chat or emails to the pass location %s",
+			batch.Subject, len(passIDs), *batch.LocationID)
+		return errorz.ErrPassLocationNoRecipient
+	}
+
+	batch, err := s.batchRepo.Create(ctx, batch)
+	if err != nil {
+		return fmt.Errorf("failed to create pass batch: %w", err)
+	}
+
+	if len(passIDs) > 0 {
+		if err = s.passRepo.MarkPassesAsSent(ctx, passIDs, time.Now(), &batch.ID); err != nil {
+			s.logger.Errorw("Failed to mark passes as sent", "batch", batch.ID, "error", err)
 		}
 	}
 
-	if len(config.EmailRecipients) > 0 {
-		subject := fmt.Sprintf("Сводка пропусков - %d событий (%d пропусков)",
-			len(eventsWithPasses), totalPasses)
+	s.deliverBatch(ctx, batch, false)
+	return nil
+}
+
+// deliverBatch attempts the due deliveries of the batch, forced deliveries are attempted even after the deadline
+func (s *PassService) deliverBatch(ctx context.Context, batch *entity.PassBatch, force bool) {
+	now := time.Now()
+	attempted := false
+	for i := range batch.Deliveries {
+		delivery := &batch.Deliveries[i]
+		if delivery.Status != entity.PassDeliveryStatusPending || (!force && !delivery.IsDue(now)) {
+			continue
+		}
 
-		emailSent = false
-		for _, email := range config.EmailRecipients {
-			buf := bytes.NewBuffer(consolidatedExcel.Bytes())
-			if sendErr := s.smtpClient.Send(email, "", "", subject, buf); sendErr != nil {
-				s.logger.Errorw("Failed to send email", "email", email, "error", sendErr)
+		switch {
+		case !force && now.After(batch.Deadline):
+			delivery.Expire()
+		default:
+			if err := s.deliver(batch, delivery); err != nil {
+				s.logger.Warnw("Failed to deliver pass batch",
+					"batch", batch.ID, "channel", delivery.Channel, "recipient", delivery.Recipient,
+					"attempt", delivery.Attempts+1, "error", err)
+				delivery.Fail(err, batch.Deadline)
 			} else {
-				emailSent = true
+				delivery.MarkSent()
+			}
+		}
+
+		attempted = true
+		if err := s.batchRepo.UpdateDelivery(ctx, delivery); err != nil {
+			s.logger.Errorw("Failed to update pass batch delivery", "batch", batch.ID, "error", err)
+		}
+	}
+
+	if attempted {
+		err := s.passRepo.UpdateBatchDelivery(ctx, batch.ID,
+			batch.IsDeliveredTo(entity.PassDeliveryChannelEmail),
+			batch.IsDeliveredTo(entity.PassDeliveryChannelTelegram))
+		if err != nil {
+			s.logger.Errorw("Failed to update passes delivery", "batch", batch.ID, "error", err)
+		}
+
+		s.logger.Infow("Pass batch delivery results",
+			"batch", batch.ID, "delivered", batch.CountDelivered(), "recipients", len(batch.Deliveries))
+
+		if batch.CountDelivered() > 0 {
+			s.notifyPassHolders(ctx, batch)
+		}
+	}
+
+	s.alertUndeliveredBatch(ctx, batch)
+}
+
+// notifyPassHolders tells the users that their passes of the delivered batch are sent to the security,
+// every holder is notified once, even if the batch is resent
+func (s *PassService) notifyPassHolders(ctx context.Context, batch *entity.PassBatch) {
+	passes, err := s.passRepo.GetUnnotifiedPassesByBatchID(ctx, batch.ID)
+	if err != nil {
+		s.logger.Errorw("Failed to get passes to notify", "batch", batch.ID, "error", err)
+		return
+	}
+	if len(passes) == 0 {
+		return
+	}
+
+	events := make(map[string]*entity.Event)
+	passIDs := make([]string, 0, len(passes))
+	for _, pass := range passes {
+		event, ok := events[pass.EventID]
+		if !ok {
+			event, err = s.eventRepo.GetEventByID(ctx, pass.EventID)
+			if err != nil {
+				s.logger.Errorw("Failed to get event of the sent pass", "event", pass.EventID, "error", err)
+				continue
 			}
+			events[pass.EventID] = event
+		}
+
+		err = s.outboxService.Enqueue(ctx, pass.UserID,
+			s.layout.TextLocale("ru", "pass_sent", struct {
+				Name      string
+				Location  string
+				StartTime string
+			}{
+				Name:      event.Name,
+				Location:  event.Location,
+				StartTime: event.StartTime.In(location.Location()).Format("02.01.2006 15:04"),
+			}),
+			s.layout.MarkupLocale("ru", "core:hide"),
+		)
+		if err != nil {
+			s.logger.Errorw("Failed to enqueue sent pass notification", "user", pass.UserID, "pass", pass.ID, "error", err)
+			continue
+		}
+		passIDs = append(passIDs, pass.ID)
+	}
+
+	if len(passIDs) > 0 {
+		if err = s.passRepo.MarkPassesAsNotified(ctx, passIDs, time.Now()); err != nil {
+			s.logger.Errorw("Failed to mark pass holders as notified", "batch", batch.ID, "error", err)
+		}
+	}
+}
+
+// deliver sends the batch to the recipient of the delivery
+func (s *PassService) deliver(batch *entity.PassBatch, delivery *entity.PassBatchDelivery) error {
+	var file *bytes.Buffer
+	if len(batch.File) > 0 {
+		file = bytes.NewBuffer(batch.File)
+	}
+
+	switch delivery.Channel {
+	case entity.PassDeliveryChannelTelegram:
+		chatID, err := strconv.ParseInt(delivery.Recipient, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid chat id %q: %w", delivery.Recipient, err)
+		}
+		return s.sendTelegramNotification(chatID, batch.Message, batch.FileName, file)
+	case entity.PassDeliveryChannelEmail:
+		return s.smtpClient.Send(delivery.Recipient, "", batch.EmailText, batch.Subject, file)
+	default:
+		return fmt.Errorf("unknown delivery channel %q", delivery.Channel)
+	}
+}
+
+// alertUndeliveredBatch alerts the log channel once if the batch is still undelivered close to its deadline
+func (s *PassService) alertUndeliveredBatch(ctx context.Context, batch *entity.PassBatch) {
+	if batch.AlertedAt != nil || batch.IsDelivered() || time.Until(batch.Deadline) > passBatchAlertBefore {
+		return
+	}
+
+	var failed []string
+	for _, delivery := range batch.Deliveries {
+		if delivery.Status != entity.PassDeliveryStatusSent {
+			failed = append(failed, fmt.Sprintf("%s %s (%s)", delivery.Channel, delivery.Recipient, delivery.LastError))
 		}
 	}
 
-	s.logger.Infow("Notification send results", "telegramSent", telegramSent, "emailSent", emailSent)
+	s.logger.Errorf("Pass batch %s (%s) is not delivered before %s: %s. Resend it with /resend_passes %s",
+		batch.ID, batch.Subject, batch.Deadline.In(location.Location()).Format("02.01.2006 15:04"),
+		strings.Join(failed, "; "), batch.ID)
+
+	now := time.Now()
+	batch.AlertedAt = &now
+	if _, err := s.batchRepo.Update(ctx, batch); err != nil {
+		s.logger.Errorw("Failed to update pass batch", "batch", batch.ID, "error", err)
+	}
+}
+
+// retryPassDeliveries retries the failed deliveries of the pass batches with backoff
+func (s *PassService) retryPassDeliveries(ctx context.Context) {
+	batches, err := s.batchRepo.GetUndelivered(ctx)
+	if err != nil {
+		s.logger.Errorw("Failed to get undelivered pass batches", "error", err)
+		return
+	}
+
+	for i := range batches {
+		s.deliverBatch(ctx, &batches[i], false)
+	}
+}
+
+// GetRecentBatches returns the latest pass batches without their files
+func (s *PassService) GetRecentBatches(ctx context.Context, limit int) ([]entity.PassBatch, error) {
+	return s.batchRepo.GetRecent(ctx, limit)
+}
+
+// ResendBatch sends the batch to all its recipients once more, even if the events have already started
+func (s *PassService) ResendBatch(ctx context.Context, id string) (*entity.PassBatch, error) {
+	batch, err := s.batchRepo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range batch.Deliveries {
+		batch.Deliveries[i].Reset()
+	}
+	batch.AlertedAt = nil
+	if _, err = s.batchRepo.Update(ctx, batch); err != nil {
+		return nil, err
+	}
 
-	return telegramSent, emailSent, nil
+	s.deliverBatch(ctx, batch, true)
+	return batch, nil
 }
 
-func (s *PassService) formatConsolidatedPassMessage(ctx context.Context, eventsWithPasses []EventWithPasses, totalPasses int) string {
+func (s *PassService) formatConsolidatedPassMessage(ctx context.Context, eventsWithPasses []EventWithPasses, totalPasses int, locationName string) string {
 	var message strings.Builder
 	totalShadowBanned := 0
 
-	message.WriteString("📋 <b>Сводка пропусков</b>\n\n")
+	_, _ = fmt.Fprintf(&message, "📋 <b>Сводка пропусков</b> — %s\n\n", html.EscapeString(locationName))
 
 	if totalPasses == 0 {
 		message.WriteString("✅ <b>Нет пропусков для отправки</b>\n\n")
@@ -417,6 +1558,9 @@ func (s *PassService) formatConsolidatedPassMessage(ctx context.Context, eventsW
 		_, _ = fmt.Fprintf(&message, "📅 %s\n", event.StartTime.In(location.Location()).Format("02.01.2006 15:04"))
 		_, _ = fmt.Fprintf(&message, "📍 %s\n", event.Location)
 		_, _ = fmt.Fprintf(&message, "👥 Пропусков: %d\n\n", len(passes))
+		if guestCount := countGuestPasses(passes); guestCount > 0 {
+			_, _ = fmt.Fprintf(&message, "🎫 Из них гостей: %d\n\n", guestCount)
+		}
 		if shadowBannedCount > 0 {
 			_, _ = fmt.Fprintf(&message, "⛔ Не пускать: %d\n\n", shadowBannedCount)
 		}
@@ -447,7 +1591,7 @@ func (s *PassService) generateConsolidatedPassExcel(ctx context.Context, eventsW
 		return nil, fmt.Errorf("failed to set sheet name: %w", err)
 	}
 
-	headers := []string{"Событие", "Дата", "Время", "Место", "ФИО", "Роль"}
+	headers := []string{"Событие", "Дата", "Время", "Место", "ФИО", "Роль", "Телефон"}
 	for i, header := range headers {
 		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
 		if err := f.SetCellValue(sheetName, cell, header); err != nil {
@@ -460,25 +1604,15 @@ func (s *PassService) generateConsolidatedPassExcel(ctx context.Context, eventsW
 		event := eventWithPasses.Event
 		passes := eventWithPasses.Passes
 
-		var userIDs []int64
-		for _, pass := range passes {
-			userIDs = append(userIDs, pass.UserID)
-		}
-
-		users, err := s.userRepo.GetMany(ctx, userIDs)
+		holders, err := s.passHolders(ctx, passes)
 		if err != nil {
 			s.logger.Error("Failed to get users for Excel", "error", err)
 			continue
 		}
 
-		userMap := make(map[int64]entity.User)
-		for _, user := range users {
-			userMap[user.ID] = user
-		}
-
 		for _, pass := range passes {
-			user, exists := userMap[pass.UserID]
-			if !exists {
+			holder, ok := holders.Get(pass)
+			if !ok {
 				continue
 			}
 
@@ -487,8 +1621,9 @@ func (s *PassService) generateConsolidatedPassExcel(ctx context.Context, eventsW
 				event.StartTime.In(location.Location()).Format("02.01.2006"),
 				event.StartTime.In(location.Location()).Format("15:04"),
 				event.Location,
-				s.formatPassFIO(user),
-				user.Role,
+				holder.FIO,
+				holder.Role,
+				holder.Phone,
 			}
 
 			for i, value := range data {
@@ -511,33 +1646,31 @@ func (s *PassService) generateConsolidatedPassExcel(ctx context.Context, eventsW
 }
 
 func (s *PassService) countShadowBannedPasses(ctx context.Context, passes []entity.Pass) int {
-	if s.shadowMatcher == nil || len(passes) == 0 {
+	if len(passes) == 0 {
 		return 0
 	}
 
-	userIDs := make([]int64, 0, len(passes))
-	for _, pass := range passes {
-		userIDs = append(userIDs, pass.UserID)
-	}
-
-	users, err := s.userRepo.GetMany(ctx, userIDs)
+	holders, err := s.passHolders(ctx, passes)
 	if err != nil {
 		s.logger.Errorw("Failed to get users for pass summary", "error", err)
 		return 0
 	}
 
-	userMap := make(map[int64]entity.User, len(users))
-	for _, user := range users {
-		userMap[user.ID] = user
+	count := 0
+	for _, pass := range passes {
+		if holder, ok := holders.Get(pass); ok && holder.ShadowBanned {
+			count++
+		}
 	}
 
+	return count
+}
+
+// countGuestPasses returns the number of guest passes
+func countGuestPasses(passes []entity.Pass) int {
 	count := 0
 	for _, pass := range passes {
-		user, exists := userMap[pass.UserID]
-		if !exists {
-			continue
-		}
-		if s.shadowMatcher.MatchUser(user) {
+		if pass.IsGuest() {
 			count++
 		}
 	}
@@ -545,22 +1678,81 @@ func (s *PassService) countShadowBannedPasses(ctx context.Context, passes []enti
 	return count
 }
 
-func (s *PassService) formatPassFIO(user entity.User) string {
-	if s.shadowMatcher != nil && s.shadowMatcher.MatchUser(user) {
-		return user.FIO.String() + " (НЕ ПУСКАТЬ)"
+// passGuestRole is the role of the guest in the pass reports
+const passGuestRole = "гость"
+
+// passHolder is the person the pass is issued to: a registered user or a guest
+type passHolder struct {
+	FIO          string
+	Role         string
+	Phone        string
+	IsGuest      bool
+	ShadowBanned bool
+}
+
+// Title returns the name of the pass holder for the report messages
+func (h passHolder) Title() string {
+	if h.IsGuest {
+		return h.FIO + " (гость)"
 	}
 
-	return user.FIO.String()
+	return h.FIO
 }
 
-func (s *PassService) sendTelegramNotification(chatID int64, message string, file *bytes.Buffer) error {
+// passHolders are the users of the passes with the shadow bans at the moment they were loaded
+type passHolders struct {
+	users         map[int64]entity.User
+	shadowMatcher *shadowban.Matcher
+}
+
+// Get returns the holder of the pass, false if the user of the pass is not found
+func (h *passHolders) Get(pass entity.Pass) (passHolder, bool) {
+	if pass.IsGuest() {
+		holder := passHolder{
+			FIO:     pass.GuestFIO,
+			Role:    passGuestRole,
+			Phone:   pass.GuestPhone,
+			IsGuest: true,
+		}
+		if fio, err := valueobject.NewFIOFromString(pass.GuestFIO); err == nil && h.shadowMatcher.MatchFIO(fio) {
+			holder.FIO += " (НЕ ПУСКАТЬ)"
+			holder.ShadowBanned = true
+		}
+		return holder, true
+	}
+
+	user, exists := h.users[pass.UserID]
+	if !exists {
+		return passHolder{}, false
+	}
+
+	holder := passHolder{
+		FIO:  user.FIO.String(),
+		Role: string(user.Role),
+	}
+	if h.shadowMatcher.MatchUser(user) {
+		holder.FIO += " (НЕ ПУСКАТЬ)"
+		holder.ShadowBanned = true
+	}
+
+	return holder, true
+}
+
+func (s *PassService) sendTelegramNotification(chatID int64, message string, fileName string, file *bytes.Buffer) error {
 	if file != nil && file.Len() > 0 {
 		document := &tele.Document{
 			File:     tele.FromReader(file),
-			FileName: fmt.Sprintf("passes_%s.xlsx", time.Now().Format("2006-01-02")),
+			FileName: fileName,
 		}
 
-		document.Caption = message
+		// a long message doesn't fit into the caption, so it is sent before the file
+		if utf8.RuneCountInString(message) > telegramCaptionLimit {
+			if _, err := s.bot.Send(&tele.Chat{ID: chatID}, message, &tele.SendOptions{ParseMode: tele.ModeHTML}); err != nil {
+				return err
+			}
+		} else {
+			document.Caption = message
+		}
 		_, err := s.bot.Send(&tele.Chat{ID: chatID}, document, &tele.SendOptions{ParseMode: tele.ModeHTML})
 		return err
 	}
@@ -582,7 +1774,7 @@ func (s *PassService) generateEmptyPassExcel() (*bytes.Buffer, error) {
 		return nil, fmt.Errorf("failed to set sheet name: %w", err)
 	}
 
-	headers := []string{"Событие", "Дата", "Время", "Место", "ФИО", "Роль"}
+	headers := []string{"Событие", "Дата", "Время", "Место", "ФИО", "Роль", "Телефон"}
 	for i, header := range headers {
 		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
 		if err := f.SetCellValue(sheetName, cell, header); err != nil {
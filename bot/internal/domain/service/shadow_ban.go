@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/ports/secondary"
+	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
+)
+
+// shadowBanConfigReason is the reason of the bans imported from the config
+const shadowBanConfigReason = "импортировано из конфигурации"
+
+/*
+ShadowBanService - сервис управления теневыми банами.
+
+Основные принципы работы:
+- Баны хранятся в базе, UserService, EventParticipantService и PassService читают действующие баны при каждой проверке
+- Бан привязан к ID пользователя, шаблоны ФИО ("Имя Фамилия") проверяют гостей без аккаунта в боте
+- Бан без пользователя проверяет всех по имени и фамилии
+- Баны бывают бессрочными или с истечением, удалённые баны остаются в базе
+- Список settings.pass.shadow-ban-name-surnames импортируется один раз, пока в базе нет ни одного бана
+*/
+type ShadowBanService struct {
+	logger *types.Logger
+
+	repo     secondary.ShadowBanRepository
+	userRepo secondary.UserRepository
+
+	// configNameSurnames are the bans of the config, imported on the first start
+	configNameSurnames []string
+}
+
+func NewShadowBanService(
+	logger *types.Logger,
+	repo secondary.ShadowBanRepository,
+	userRepo secondary.UserRepository,
+	configNameSurnames []string,
+) *ShadowBanService {
+	return &ShadowBanService{
+		logger:             logger,
+		repo:               repo,
+		userRepo:           userRepo,
+		configNameSurnames: configNameSurnames,
+	}
+}
+
+// Add creates the shadow ban.
+// Returns errorz.ErrEmptyShadowBan if the ban has neither a user nor patterns
+// and errorz.ErrAlreadyShadowBanned if the user already has an active ban.
+func (s *ShadowBanService) Add(ctx context.Context, ban *entity.ShadowBan) (*entity.ShadowBan, error) {
+	if ban.UserID == 0 && len(ban.Patterns) == 0 {
+		return nil, errorz.ErrEmptyShadowBan
+	}
+
+	if ban.UserID != 0 {
+		bans, err := s.repo.GetActiveByUserID(ctx, ban.UserID, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user shadow bans: %w", err)
+		}
+		if len(bans) > 0 {
+			return nil, errorz.ErrAlreadyShadowBanned
+		}
+	}
+
+	ban, err := s.repo.Create(ctx, ban)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("Shadow ban %s added by %d (user: %d, patterns: %v)", ban.ID, ban.AuthorID, ban.UserID, ban.Patterns)
+	return ban, nil
+}
+
+// Remove removes the shadow ban by its ID
+func (s *ShadowBanService) Remove(ctx context.Context, id string) (*entity.ShadowBan, error) {
+	ban, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.repo.Delete(ctx, []string{ban.ID}); err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("Shadow ban %s removed (user: %d, patterns: %v)", ban.ID, ban.UserID, ban.Patterns)
+	return ban, nil
+}
+
+// RemoveByUserID removes the active shadow bans of the user and returns their number
+func (s *ShadowBanService) RemoveByUserID(ctx context.Context, userID int64) (int, error) {
+	bans, err := s.repo.GetActiveByUserID(ctx, userID, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	if len(bans) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]string, 0, len(bans))
+	for _, ban := range bans {
+		ids = append(ids, ban.ID)
+	}
+	if err = s.repo.Delete(ctx, ids); err != nil {
+		return 0, err
+	}
+
+	s.logger.Infof("Shadow bans of user %d removed: %v", userID, ids)
+	return len(ids), nil
+}
+
+// GetActive returns the shadow bans which are not expired with the names of the banned users
+func (s *ShadowBanService) GetActive(ctx context.Context) ([]dto.ShadowBan, error) {
+	bans, err := s.repo.GetActive(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var userIDs []int64
+	for _, ban := range bans {
+		if ban.UserID != 0 {
+			userIDs = append(userIDs, ban.UserID)
+		}
+	}
+
+	users, err := s.userRepo.GetMany(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shadow banned users: %w", err)
+	}
+
+	names := make(map[int64]string, len(users))
+	for _, user := range users {
+		names[user.ID] = user.FIO.String()
+	}
+
+	result := make([]dto.ShadowBan, 0, len(bans))
+	for _, ban := range bans {
+		result = append(result, dto.ShadowBan{Ban: ban, FIO: names[ban.UserID]})
+	}
+
+	return result, nil
+}
+
+// Seed imports the name and surname pairs of the config as bans without a user,
+// the config is ignored once there has been at least one ban
+func (s *ShadowBanService) Seed(ctx context.Context) error {
+	if len(s.configNameSurnames) == 0 {
+		return nil
+	}
+
+	count, err := s.repo.CountAll(ctx)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	for _, nameSurname := range s.configNameSurnames {
+		if _, err = s.repo.Create(ctx, &entity.ShadowBan{
+			Patterns: []string{nameSurname},
+			Reason:   shadowBanConfigReason,
+		}); err != nil {
+			return err
+		}
+	}
+
+	s.logger.Infof("Imported %d shadow bans from the config", len(s.configNameSurnames))
+	return nil
+}
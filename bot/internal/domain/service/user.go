@@ -20,24 +20,25 @@ type UserService struct {
 	eventParticipantRepo secondary.EventParticipantRepository
 	smtpClient           secondary.SMTPClient
 
+	shadowBanRepo secondary.ShadowBanRepository
+
 	emailHTMLFilePath string
-	shadowMatcher     *shadowban.Matcher
 }
 
 func NewUserService(
 	userRepo secondary.UserRepository,
 	eventParticipantRepo secondary.EventParticipantRepository,
 	smtpClient secondary.SMTPClient,
+	shadowBanRepo secondary.ShadowBanRepository,
 	emailHTMLFilePath string,
-	shadowBanNameSurnames []string,
 ) *UserService {
 	return &UserService{
 		userRepo:             userRepo,
 		eventParticipantRepo: eventParticipantRepo,
 		smtpClient:           smtpClient,
+		shadowBanRepo:        shadowBanRepo,
 
 		emailHTMLFilePath: emailHTMLFilePath,
-		shadowMatcher:     shadowban.NewMatcher(shadowBanNameSurnames),
 	}
 }
 
@@ -53,6 +54,10 @@ func (s *UserService) GetByEmail(ctx context.Context, email valueobject.Email) (
 	return s.userRepo.GetByEmail(ctx, email)
 }
 
+func (s *UserService) GetByUsername(ctx context.Context, username string) (*entity.User, error) {
+	return s.userRepo.GetByUsername(ctx, username)
+}
+
 func (s *UserService) GetByQRCodeID(ctx context.Context, qrCodeID string) (*entity.User, error) {
 	return s.userRepo.GetByQRCodeID(ctx, qrCodeID)
 }
@@ -83,23 +88,13 @@ func (s *UserService) GetWithPagination(ctx context.Context, limit int, offset i
 	return s.userRepo.GetWithPagination(ctx, limit, offset, order)
 }
 
-func (s *UserService) Ban(ctx context.Context, userID int64) (*entity.User, error) {
-	user, err := s.Get(ctx, userID)
-	if err != nil {
-		return nil, err
-	}
-
-	user.IsBanned = true
-	return s.Update(ctx, user)
-}
-
 func (s *UserService) GetUsersByEventID(ctx context.Context, eventID string) ([]entity.User, error) {
 	users, err := s.userRepo.GetUsersByEventID(ctx, eventID)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.filterUsers(users), nil
+	return s.filterUsers(ctx, users)
 }
 
 func (s *UserService) GetEventUsers(ctx context.Context, eventID string) ([]dto.EventUser, error) {
@@ -108,7 +103,7 @@ func (s *UserService) GetEventUsers(ctx context.Context, eventID string) ([]dto.
 		return nil, err
 	}
 
-	return s.filterEventUsers(users), nil
+	return s.filterEventUsers(ctx, users)
 }
 
 func (s *UserService) GetUsersByClubID(ctx context.Context, clubID string) ([]entity.User, error) {
@@ -117,7 +112,7 @@ func (s *UserService) GetUsersByClubID(ctx context.Context, clubID string) ([]en
 		return nil, err
 	}
 
-	return s.filterUsers(users), nil
+	return s.filterUsers(ctx, users)
 }
 
 func (s *UserService) GetUserEvents(ctx context.Context, userID int64, limit, offset int) ([]dto.UserEvent, error) {
@@ -186,34 +181,44 @@ func generateRandomCode(length int) (string, error) {
 	return hex.EncodeToString(bts)[:length], nil
 }
 
-func (s *UserService) filterUsers(users []entity.User) []entity.User {
-	if s.shadowMatcher == nil || len(users) == 0 {
-		return users
+func (s *UserService) filterUsers(ctx context.Context, users []entity.User) ([]entity.User, error) {
+	if len(users) == 0 {
+		return users, nil
+	}
+
+	shadowMatcher, err := shadowban.Load(ctx, s.shadowBanRepo)
+	if err != nil {
+		return nil, err
 	}
 
 	filtered := make([]entity.User, 0, len(users))
 	for _, user := range users {
-		if s.shadowMatcher.MatchUser(user) {
+		if shadowMatcher.MatchUser(user) {
 			continue
 		}
 		filtered = append(filtered, user)
 	}
 
-	return filtered
+	return filtered, nil
 }
 
-func (s *UserService) filterEventUsers(users []dto.EventUser) []dto.EventUser {
-	if s.shadowMatcher == nil || len(users) == 0 {
-		return users
+func (s *UserService) filterEventUsers(ctx context.Context, users []dto.EventUser) ([]dto.EventUser, error) {
+	if len(users) == 0 {
+		return users, nil
+	}
+
+	shadowMatcher, err := shadowban.Load(ctx, s.shadowBanRepo)
+	if err != nil {
+		return nil, err
 	}
 
 	filtered := make([]dto.EventUser, 0, len(users))
 	for _, user := range users {
-		if s.shadowMatcher.MatchUser(user.User) {
+		if shadowMatcher.MatchUser(user.User) {
 			continue
 		}
 		filtered = append(filtered, user)
 	}
 
-	return filtered
+	return filtered, nil
 }
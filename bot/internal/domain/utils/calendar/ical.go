@@ -17,7 +17,11 @@ import (
 // classification. Additionally, reminders are added for one day and one hour before
 // the event. The function returns the serialized iCalendar data as a byte slice or
 // an error if serialization fails.
-func ExportEventToICS(event entity.Event) ([]byte, error) {
+//
+// If series is not nil, the event is exported as the first occurrence of the series:
+// weekly and biweekly series get an RRULE, custom series get RDATE for the following
+// dates, cancelled occurrences are excluded with EXDATE.
+func ExportEventToICS(event entity.Event, series *entity.EventSeries) ([]byte, error) {
 	cal := ics.NewCalendar()
 	cal.SetMethod(ics.MethodPublish)
 	cal.SetProductId("-//CU Clubs Bot//EN")
@@ -26,6 +30,9 @@ func ExportEventToICS(event entity.Event) ([]byte, error) {
 
 	// Создаем уникальный идентификатор события
 	uid := fmt.Sprintf("%s@cu-clubs-bot", event.ID)
+	if series != nil {
+		uid = fmt.Sprintf("%s@cu-clubs-bot", series.ID)
+	}
 	e := cal.AddEvent(uid)
 
 	// Устанавливаем время создания и изменения события
@@ -44,6 +51,11 @@ func ExportEventToICS(event entity.Event) ([]byte, error) {
 		e.SetEndAt(event.StartTime.Add(1 * time.Hour))
 	}
 
+	// Добавляем правила повторения для серии мероприятий
+	if series != nil {
+		addRecurrence(e, event, series)
+	}
+
 	// Устанавливаем основные свойства события
 	e.SetSummary(event.Name)
 	e.SetDescription(event.Description)
@@ -81,3 +93,27 @@ func ExportEventToICS(event entity.Event) ([]byte, error) {
 
 	return buf.Bytes(), nil
 }
+
+// addRecurrence adds recurrence properties of the series starting from the event
+func addRecurrence(e *ics.VEvent, event entity.Event, series *entity.EventSeries) {
+	const icsTimeLayout = "20060102T150405Z"
+
+	if rrule := series.RRule(); rrule != "" {
+		e.AddRrule(rrule)
+	}
+
+	cancelled := make(map[time.Time]bool)
+	for _, date := range series.CancelledOccurrences() {
+		cancelled[date.UTC()] = true
+		if date.After(event.StartTime) {
+			e.AddExdate(date.UTC().Format(icsTimeLayout))
+		}
+	}
+
+	for _, date := range series.CustomDates() {
+		if !date.After(event.StartTime) || cancelled[date.UTC()] {
+			continue
+		}
+		e.AddRdate(date.UTC().Format(icsTimeLayout))
+	}
+}
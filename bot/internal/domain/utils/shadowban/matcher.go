@@ -1,48 +1,103 @@
 package shadowban
 
 import (
+	"context"
 	"strings"
+	"time"
 
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/valueobject"
 )
 
+// Source is the live store of the shadow bans
+type Source interface {
+	GetActive(ctx context.Context, now time.Time) ([]entity.ShadowBan, error)
+}
+
 type Matcher struct {
-	pairs map[string]struct{}
+	userIDs map[int64]struct{}
+	// userPairs are the name and surname pairs of the bans without a user, they match users and guests
+	userPairs map[string]struct{}
+	// guestPairs are the name and surname pairs of all bans, they match guests only
+	guestPairs map[string]struct{}
 }
 
-func NewMatcher(entries []string) *Matcher {
-	pairs := make(map[string]struct{}, len(entries)*2)
+// Load builds the matcher from the active shadow bans of the source
+func Load(ctx context.Context, source Source) (*Matcher, error) {
+	bans, err := source.GetActive(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
 
-	for _, entry := range entries {
-		parts := strings.Fields(entry)
-		if len(parts) < 2 {
-			continue
-		}
+	return NewMatcher(bans), nil
+}
 
-		first := normalizeToken(parts[0])
-		second := normalizeToken(parts[1])
-		if first == "" || second == "" {
-			continue
+func NewMatcher(bans []entity.ShadowBan) *Matcher {
+	m := &Matcher{
+		userIDs:    make(map[int64]struct{}),
+		userPairs:  make(map[string]struct{}),
+		guestPairs: make(map[string]struct{}),
+	}
+
+	for _, ban := range bans {
+		if ban.UserID != 0 {
+			m.userIDs[ban.UserID] = struct{}{}
 		}
 
-		pairs[pairKey(first, second)] = struct{}{}
-		pairs[pairKey(second, first)] = struct{}{}
+		for _, pattern := range ban.Patterns {
+			parts := strings.Fields(pattern)
+			if len(parts) < 2 {
+				continue
+			}
+
+			first := normalizeToken(parts[0])
+			second := normalizeToken(parts[1])
+			if first == "" || second == "" {
+				continue
+			}
+
+			addPair(m.guestPairs, first, second)
+			if ban.UserID == 0 {
+				addPair(m.userPairs, first, second)
+			}
+		}
 	}
 
-	return &Matcher{pairs: pairs}
+	return m
 }
 
 func (m *Matcher) MatchUser(user entity.User) bool {
-	return m.MatchFIO(user.FIO)
+	if m == nil {
+		return false
+	}
+
+	if _, ok := m.userIDs[user.ID]; ok {
+		return true
+	}
+
+	return matchPair(m.userPairs, user.FIO)
 }
 
+// MatchFIO checks the full name of a guest without a bot account
 func (m *Matcher) MatchFIO(fio valueobject.FIO) bool {
-	if m == nil || len(m.pairs) == 0 {
+	if m == nil {
+		return false
+	}
+
+	return matchPair(m.guestPairs, fio)
+}
+
+func addPair(pairs map[string]struct{}, first, second string) {
+	pairs[pairKey(first, second)] = struct{}{}
+	pairs[pairKey(second, first)] = struct{}{}
+}
+
+func matchPair(pairs map[string]struct{}, fio valueobject.FIO) bool {
+	if len(pairs) == 0 {
 		return false
 	}
 
-	_, ok := m.pairs[pairKey(normalizeToken(fio.Name), normalizeToken(fio.Surname))]
+	_, ok := pairs[pairKey(normalizeToken(fio.Name), normalizeToken(fio.Surname))]
 	return ok
 }
 
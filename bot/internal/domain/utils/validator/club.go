@@ -3,9 +3,13 @@ package validator
 import (
 	"net/url"
 	"strconv"
+	"strings"
 	"unicode/utf8"
 )
 
+// ClubApplicationMaxOwners is the maximum number of the club owners proposed in the application
+const ClubApplicationMaxOwners = 5
+
 func ClubName(name string, _ map[string]interface{}) bool {
 	return utf8.RuneCountInString(name) >= 3 && utf8.RuneCountInString(name) <= 30
 }
@@ -22,7 +26,52 @@ func ClubLink(link string, _ map[string]interface{}) bool {
 	return utf8.RuneCountInString(link) <= 100
 }
 
+// ClubApplicationOwners checks the list of user IDs separated by spaces or commas, "-" means no proposed owners
+func ClubApplicationOwners(owners string, _ map[string]interface{}) bool {
+	if strings.TrimSpace(owners) == "-" {
+		return true
+	}
+
+	fields := strings.FieldsFunc(owners, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\n'
+	})
+	if len(fields) == 0 || len(fields) > ClubApplicationMaxOwners {
+		return false
+	}
+	for _, field := range fields {
+		if _, err := strconv.ParseInt(field, 10, 64); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ParseUserIDs returns the user IDs of the list checked by ClubApplicationOwners
+func ParseUserIDs(ids string) []int64 {
+	fields := strings.FieldsFunc(ids, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\n'
+	})
+
+	result := make([]int64, 0, len(fields))
+	for _, field := range fields {
+		if id, err := strconv.ParseInt(field, 10, 64); err == nil {
+			result = append(result, id)
+		}
+	}
+
+	return result
+}
+
+func ClubApplicationComment(comment string, _ map[string]interface{}) bool {
+	return utf8.RuneCountInString(comment) >= 5 && utf8.RuneCountInString(comment) <= 500
+}
+
 func ChannelID(id string, _ map[string]interface{}) bool {
 	_, err := strconv.ParseInt(id, 10, 64)
 	return err == nil
 }
+
+func APITokenName(name string, _ map[string]interface{}) bool {
+	return utf8.RuneCountInString(name) >= 3 && utf8.RuneCountInString(name) <= 40
+}
@@ -2,6 +2,7 @@ package validator
 
 import (
 	"strconv"
+	"strings"
 	"time"
 	"unicode/utf8"
 
@@ -84,6 +85,10 @@ func EventAfterRegistrationText(afterRegistrationText string, _ map[string]inter
 	return utf8.RuneCountInString(afterRegistrationText) >= 10 && utf8.RuneCountInString(afterRegistrationText) <= 150
 }
 
+func EventCancellationReason(reason string, _ map[string]interface{}) bool {
+	return utf8.RuneCountInString(reason) >= 5 && utf8.RuneCountInString(reason) <= 200
+}
+
 func EventMaxParticipants(maxParticipantsStr string, _ map[string]interface{}) bool {
 	maxParticipants, err := strconv.Atoi(maxParticipantsStr)
 	if err != nil {
@@ -114,3 +119,55 @@ func EventEditMaxParticipants(maxParticipantsStr string, params map[string]inter
 	}
 	return maxParticipants > 0 && maxParticipants > previousMaxParticipants
 }
+
+// EventSeriesUntil checks the last date of a weekly or biweekly series ("02.01.2006"):
+// it has to be at least a week and at most a year after the first occurrence
+func EventSeriesUntil(until string, params map[string]interface{}) bool {
+	const layout = "02.01.2006 15:04"
+
+	startTimeStr, ok := params["startTime"].(string)
+	if !ok {
+		return false
+	}
+	startTime, err := time.ParseInLocation(layout, startTimeStr, location.Location())
+	if err != nil {
+		return false
+	}
+
+	untilDate, err := time.ParseInLocation("02.01.2006", until, location.Location())
+	if err != nil {
+		return false
+	}
+	untilDate = untilDate.AddDate(0, 0, 1)
+
+	return !untilDate.Before(startTime.AddDate(0, 0, 7)) && !untilDate.After(startTime.AddDate(1, 0, 0))
+}
+
+// EventSeriesDates checks start times of custom series occurrences, one per line ("02.01.2006 15:04")
+func EventSeriesDates(dates string, params map[string]interface{}) bool {
+	const (
+		layout   = "02.01.2006 15:04"
+		maxDates = 50
+	)
+
+	startTimeStr, ok := params["startTime"].(string)
+	if !ok {
+		return false
+	}
+
+	lines := strings.Split(strings.TrimSpace(dates), "\n")
+	if len(lines) == 0 || len(lines) > maxDates {
+		return false
+	}
+
+	seen := map[string]bool{startTimeStr: true}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if seen[line] || !EventStartTime(line, nil) {
+			return false
+		}
+		seen[line] = true
+	}
+
+	return true
+}
@@ -1,7 +1,27 @@
 package validator
 
-import "unicode/utf8"
+import (
+	"time"
+	"unicode/utf8"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+)
+
+// maxMailingDelay limits how far in advance a mailing may be scheduled
+const maxMailingDelay = 30 * 24 * time.Hour
 
 func MailingText(text string, _ map[string]interface{}) bool {
 	return utf8.RuneCountInString(text) <= 500
 }
+
+func MailingScheduledAt(scheduledAt string, _ map[string]interface{}) bool {
+	const layout = "02.01.2006 15:04"
+
+	t, err := time.ParseInLocation(layout, scheduledAt, location.Location())
+	if err != nil {
+		return false
+	}
+
+	now := time.Now()
+	return t.After(now) && t.Before(now.Add(maxMailingDelay))
+}
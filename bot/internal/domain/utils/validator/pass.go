@@ -0,0 +1,139 @@
+package validator
+
+import (
+	"net/mail"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+func PassScheduleName(name string, _ map[string]interface{}) bool {
+	return utf8.RuneCountInString(name) >= 3 && utf8.RuneCountInString(name) <= 30
+}
+
+func PassScheduleCron(spec string, _ map[string]interface{}) bool {
+	_, err := cron.ParseStandard(spec)
+	return err == nil
+}
+
+// PassScheduleLeadTime checks the lead time in hours
+func PassScheduleLeadTime(hours string, _ map[string]interface{}) bool {
+	h, err := strconv.Atoi(hours)
+	if err != nil {
+		return false
+	}
+
+	return h >= 0 && h < int(entity.PassScheduleMaxAdvance.Hours())
+}
+
+// PassLocationEmails checks the list of emails separated by spaces or commas, "-" clears the list
+func PassLocationEmails(emails string, _ map[string]interface{}) bool {
+	if strings.TrimSpace(emails) == "-" {
+		return true
+	}
+
+	fields := ParseEmails(emails)
+	if len(fields) == 0 {
+		return false
+	}
+	for _, email := range fields {
+		if _, err := mail.ParseAddress(email); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ParseEmails splits the list of emails separated by spaces or commas
+func ParseEmails(emails string) []string {
+	return strings.FieldsFunc(emails, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\n'
+	})
+}
+
+func PassLocationName(name string, _ map[string]interface{}) bool {
+	return utf8.RuneCountInString(name) >= 3 && utf8.RuneCountInString(name) <= 30
+}
+
+// PassLocationPatterns checks the list of location patterns separated by commas or new lines,
+// "-" makes the location match every event
+func PassLocationPatterns(patterns string, _ map[string]interface{}) bool {
+	if strings.TrimSpace(patterns) == "-" {
+		return true
+	}
+
+	fields := ParsePatterns(patterns)
+	if len(fields) == 0 {
+		return false
+	}
+	for _, pattern := range fields {
+		if utf8.RuneCountInString(pattern) > 100 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ParsePatterns splits the list of location patterns separated by commas or new lines
+func ParsePatterns(patterns string) []string {
+	var result []string
+	for _, pattern := range strings.FieldsFunc(patterns, func(r rune) bool {
+		return r == ',' || r == '\n'
+	}) {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			result = append(result, pattern)
+		}
+	}
+
+	return result
+}
+
+// GuestFIO checks the full name of the event guest: surname and name with an optional patronymic
+func GuestFIO(fio string, _ map[string]interface{}) bool {
+	fields := strings.Fields(fio)
+	if len(fields) < 2 || len(fields) > 3 || utf8.RuneCountInString(fio) > 100 {
+		return false
+	}
+	for _, field := range fields {
+		for _, r := range field {
+			if !unicode.IsLetter(r) && r != '-' {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// GuestPhone checks the phone number of the event guest, "-" skips the phone
+func GuestPhone(phone string, _ map[string]interface{}) bool {
+	phone = strings.TrimSpace(phone)
+	if phone == "-" {
+		return true
+	}
+
+	digits := 0
+	for _, r := range phone {
+		switch {
+		case unicode.IsDigit(r):
+			digits++
+		case strings.ContainsRune("+-() ", r):
+		default:
+			return false
+		}
+	}
+
+	return digits >= 10 && digits <= 15
+}
+
+// PassReason checks the reason of the manual pass
+func PassReason(reason string, _ map[string]interface{}) bool {
+	return utf8.RuneCountInString(strings.TrimSpace(reason)) >= 3 && utf8.RuneCountInString(reason) <= 200
+}
@@ -0,0 +1,81 @@
+package valueobject
+
+// AdminRole represents a role of the bot admin
+type AdminRole string
+
+func (r AdminRole) String() string {
+	return string(r)
+}
+
+func (r AdminRole) IsValid() bool {
+	switch r {
+	case SuperAdmin, ClubModerator, PassOfficer, Support:
+		return true
+	default:
+		return false
+	}
+}
+
+// Can reports whether the role grants the permission
+func (r AdminRole) Can(permission AdminPermission) bool {
+	for _, p := range r.Permissions() {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAny reports whether the role grants at least one of the permissions
+func (r AdminRole) CanAny(permissions ...AdminPermission) bool {
+	for _, permission := range permissions {
+		if r.Can(permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// Permissions returns the permissions granted by the role
+func (r AdminRole) Permissions() []AdminPermission {
+	switch r {
+	case SuperAdmin:
+		return []AdminPermission{ClubsPermission, PassesPermission, UsersPermission, AdminsPermission, AuditPermission}
+	case ClubModerator:
+		return []AdminPermission{ClubsPermission}
+	case PassOfficer:
+		return []AdminPermission{PassesPermission}
+	case Support:
+		return []AdminPermission{UsersPermission}
+	default:
+		return nil
+	}
+}
+
+const (
+	SuperAdmin    AdminRole = "superadmin"
+	ClubModerator AdminRole = "club_moderator"
+	PassOfficer   AdminRole = "pass_officer"
+	Support       AdminRole = "support"
+)
+
+// AllAdminRoles returns all available admin roles
+func AllAdminRoles() []AdminRole {
+	return []AdminRole{SuperAdmin, ClubModerator, PassOfficer, Support}
+}
+
+// AdminPermission represents an area of the admin actions
+type AdminPermission string
+
+const (
+	// ClubsPermission allows to create and manage clubs, their owners, API tokens and mailings
+	ClubsPermission AdminPermission = "clubs"
+	// PassesPermission allows to manage pass locations, schedules, event passes and guests
+	PassesPermission AdminPermission = "passes"
+	// UsersPermission allows to ban and shadow ban users
+	UsersPermission AdminPermission = "users"
+	// AdminsPermission allows to grant and revoke admin roles
+	AdminsPermission AdminPermission = "admins"
+	// AuditPermission allows to search and export the audit log
+	AuditPermission AdminPermission = "audit"
+)
@@ -0,0 +1,64 @@
+package valueobject
+
+// ClubOwnerRole represents a role of the club staff member
+type ClubOwnerRole string
+
+func (r ClubOwnerRole) String() string {
+	return string(r)
+}
+
+func (r ClubOwnerRole) IsValid() bool {
+	switch r {
+	case ClubCoOwner, ClubEditor, ClubScanner:
+		return true
+	default:
+		return false
+	}
+}
+
+// Can reports whether the role grants the permission
+func (r ClubOwnerRole) Can(permission ClubPermission) bool {
+	for _, p := range r.Permissions() {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Permissions returns the permissions granted by the role
+func (r ClubOwnerRole) Permissions() []ClubPermission {
+	switch r {
+	case ClubCoOwner:
+		return []ClubPermission{ClubScanPermission, ClubEventsPermission, ClubManagePermission}
+	case ClubEditor:
+		return []ClubPermission{ClubScanPermission, ClubEventsPermission}
+	case ClubScanner:
+		return []ClubPermission{ClubScanPermission}
+	default:
+		return nil
+	}
+}
+
+const (
+	ClubCoOwner ClubOwnerRole = "co_owner"
+	ClubEditor  ClubOwnerRole = "editor"
+	ClubScanner ClubOwnerRole = "scanner"
+)
+
+// AllClubOwnerRoles returns all available club staff roles
+func AllClubOwnerRoles() []ClubOwnerRole {
+	return []ClubOwnerRole{ClubCoOwner, ClubEditor, ClubScanner}
+}
+
+// ClubPermission represents an area of the club staff actions
+type ClubPermission string
+
+const (
+	// ClubScanPermission allows to view the club events, show the event QR and scan QR codes of the users
+	ClubScanPermission ClubPermission = "scan"
+	// ClubEventsPermission allows to create and edit events, manage their guests, participants and mailings
+	ClubEventsPermission ClubPermission = "events"
+	// ClubManagePermission allows to cancel events, send club mailings, change the club settings and staff
+	ClubManagePermission ClubPermission = "manage"
+)
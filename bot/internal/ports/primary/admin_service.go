@@ -0,0 +1,17 @@
+package primary
+
+import (
+	"context"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/valueobject"
+)
+
+// AdminService defines the interface for admin role use cases
+type AdminService interface {
+	GetRole(ctx context.Context, userID int64) (valueobject.AdminRole, error)
+	Grant(ctx context.Context, userID int64, role valueobject.AdminRole, grantedBy int64) (*entity.User, error)
+	Revoke(ctx context.Context, userID int64) (*entity.User, error)
+	GetAll(ctx context.Context) ([]dto.Admin, error)
+}
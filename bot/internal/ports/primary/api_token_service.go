@@ -0,0 +1,17 @@
+package primary
+
+import (
+	"context"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// APITokenService defines the interface for club API token use cases
+type APITokenService interface {
+	Issue(ctx context.Context, clubID, name string, createdBy int64) (string, *entity.APIToken, error)
+	Authenticate(ctx context.Context, plain string) (*entity.APIToken, error)
+	Get(ctx context.Context, id string) (*entity.APIToken, error)
+	GetByClubID(ctx context.Context, clubID string) ([]entity.APIToken, error)
+	Revoke(ctx context.Context, id string) (*entity.APIToken, error)
+	LogRequest(ctx context.Context, log *entity.APIRequestLog) error
+}
@@ -0,0 +1,14 @@
+package primary
+
+import (
+	"context"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// AuditService defines the interface for audit log use cases
+type AuditService interface {
+	Record(ctx context.Context, actorID int64, action entity.AuditAction, targetType entity.AuditTarget, targetID string, before, after interface{})
+	Search(ctx context.Context, filter dto.AuditFilter) ([]entity.AuditEntry, error)
+}
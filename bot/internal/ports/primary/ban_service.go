@@ -0,0 +1,19 @@
+package primary
+
+import (
+	"context"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// BanService defines the interface for user ban use cases
+type BanService interface {
+	Ban(ctx context.Context, userID, adminID int64, reason string, duration time.Duration) (*entity.UserBan, *entity.User, error)
+	Unban(ctx context.Context, userID, adminID int64) (*entity.User, error)
+	GetActiveBan(ctx context.Context, userID int64) (*entity.UserBan, error)
+	GetActive(ctx context.Context) ([]dto.UserBan, error)
+	StartScheduler()
+	StopScheduler()
+}
@@ -0,0 +1,20 @@
+package primary
+
+import (
+	"context"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/valueobject"
+)
+
+// ClubApplicationService defines the interface for club application use cases
+type ClubApplicationService interface {
+	Create(ctx context.Context, application *entity.ClubApplication) (*entity.ClubApplication, error)
+	Get(ctx context.Context, id string) (*entity.ClubApplication, error)
+	HasPending(ctx context.Context, applicantID int64) (bool, error)
+	ToggleRole(ctx context.Context, id string, role valueobject.Role) (*entity.ClubApplication, error)
+	Submit(ctx context.Context, id string) (*entity.ClubApplication, error)
+	GetPending(ctx context.Context) ([]entity.ClubApplication, error)
+	Approve(ctx context.Context, id string, adminID int64) (*entity.ClubApplication, *entity.Club, error)
+	Reject(ctx context.Context, id string, adminID int64, comment string) (*entity.ClubApplication, error)
+}
@@ -0,0 +1,16 @@
+package primary
+
+import (
+	"context"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// ClubFollowerService defines the interface for club follow use cases
+type ClubFollowerService interface {
+	Follow(ctx context.Context, userID int64, clubID string) error
+	Unfollow(ctx context.Context, userID int64, clubID string) error
+	IsFollowing(ctx context.Context, userID int64, clubID string) (bool, error)
+	GetFollowedClubs(ctx context.Context, userID int64) ([]entity.Club, error)
+	CountFollowers(ctx context.Context, clubID string) (int64, error)
+}
@@ -5,13 +5,20 @@ import (
 
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/valueobject"
 )
 
 // ClubOwnerService defines the interface for club owner-related use cases
 type ClubOwnerService interface {
-	Add(ctx context.Context, userID int64, clubID string) (*entity.ClubOwner, error)
+	Add(ctx context.Context, userID int64, clubID string, role valueobject.ClubOwnerRole) (*entity.ClubOwner, error)
 	Remove(ctx context.Context, userID int64, clubID string) error
 	Get(ctx context.Context, clubID string, userID int64) (*entity.ClubOwner, error)
+	GetRole(ctx context.Context, clubID string, userID int64) (valueobject.ClubOwnerRole, error)
+	SetRole(ctx context.Context, clubID string, userID int64, role valueobject.ClubOwnerRole) (*entity.ClubOwner, error)
+	Leave(ctx context.Context, clubID string, userID int64) error
+	Transfer(ctx context.Context, clubID string, fromUserID, toUserID int64) error
+	HasActiveOwners(ctx context.Context, clubID string) (bool, error)
+	WarnIfOrphaned(ctx context.Context, clubID string)
 	Update(ctx context.Context, clubOwner *entity.ClubOwner) (*entity.ClubOwner, error)
 	GetByClubID(ctx context.Context, clubID string) ([]dto.ClubOwner, error)
 	GetByUserID(ctx context.Context, userID int64) ([]dto.ClubOwner, error)
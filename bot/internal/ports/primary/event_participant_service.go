@@ -10,9 +10,12 @@ import (
 // EventParticipantService defines the interface for event participant-related use cases
 type EventParticipantService interface {
 	Register(ctx context.Context, eventID string, userID int64) (*entity.EventParticipant, error)
+	RegisterOnSite(ctx context.Context, eventID string, userID int64) (*entity.EventParticipant, error)
 	Get(ctx context.Context, eventID string, userID int64) (*entity.EventParticipant, error)
 	Update(ctx context.Context, eventParticipant *entity.EventParticipant) (*entity.EventParticipant, error)
 	Delete(ctx context.Context, eventID string, userID int64) error
+	RemoveFromUpcomingEvents(ctx context.Context, userID int64) (int, error)
+	SyncPasses(ctx context.Context, event *entity.Event) error
 	GetByEventID(ctx context.Context, eventID string) ([]entity.EventParticipant, error)
 	CountByEventID(ctx context.Context, eventID string) (int, error)
 	CountVisitedByEventID(ctx context.Context, eventID string) (int, error)
@@ -21,8 +24,18 @@ type EventParticipantService interface {
 	MarkAsVisited(ctx context.Context, eventID string, userID int64, isUserQR, isEventQR bool) error
 	IsUserRegistered(ctx context.Context, eventID string, userID int64) (bool, error)
 	IsShadowBanned(ctx context.Context, userID int64) (bool, error)
+	GetUserPass(ctx context.Context, event *entity.Event, user *entity.User) (*entity.Pass, error)
 	CanCancelRegistration(ctx context.Context, eventID string) (bool, error)
 	BulkRegister(ctx context.Context, eventID string, userIDs []int64) ([]entity.EventParticipant, error)
 	GetVisitedParticipants(ctx context.Context, eventID string) ([]entity.EventParticipant, error)
 	GetNotVisitedParticipants(ctx context.Context, eventID string) ([]entity.EventParticipant, error)
+	JoinWaitlist(ctx context.Context, eventID string, userID int64) (*entity.WaitlistEntry, error)
+	LeaveWaitlist(ctx context.Context, eventID string, userID int64) error
+	GetWaitlistEntry(ctx context.Context, eventID string, userID int64) (*entity.WaitlistEntry, error)
+	GetWaitlistPosition(ctx context.Context, eventID string, userID int64) (int, error)
+	CountWaitlist(ctx context.Context, eventID string) (int, error)
+	ConfirmWaitlistOffer(ctx context.Context, eventID string, userID int64) error
+	DeclineWaitlistOffer(ctx context.Context, eventID string, userID int64) error
+	StartWaitlistScheduler()
+	StopWaitlistScheduler()
 }
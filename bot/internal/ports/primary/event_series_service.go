@@ -0,0 +1,20 @@
+package primary
+
+import (
+	"context"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// EventSeriesService defines the interface for recurring event use cases
+type EventSeriesService interface {
+	Create(ctx context.Context, event entity.Event, rule entity.RecurrenceRule, until time.Time, dates []time.Time) (*entity.EventSeries, error)
+	Get(ctx context.Context, id string) (*entity.EventSeries, error)
+	GetFutureOccurrences(ctx context.Context, id string) ([]entity.Event, error)
+	UpdateFutureOccurrences(ctx context.Context, eventID string) ([]entity.Event, error)
+	CancelOccurrence(ctx context.Context, eventID string) (*entity.Event, error)
+	Materialize(ctx context.Context) error
+	StartScheduler() error
+	StopScheduler()
+}
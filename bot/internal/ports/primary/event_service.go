@@ -21,6 +21,7 @@ type EventService interface {
 	GetFutureByClubID(ctx context.Context, limit, offset int, order string, clubID string, additionalTime time.Duration) ([]entity.Event, error)
 	Update(ctx context.Context, event *entity.Event) (*entity.Event, error)
 	Delete(ctx context.Context, id string) error
+	Cancel(ctx context.Context, id string, reason string) (*entity.Event, error)
 	Count(ctx context.Context, role valueobject.Role) (int64, error)
 	GetWithPagination(ctx context.Context, limit, offset int, order string, role valueobject.Role, userID int64) ([]dto.Event, error)
 	GetWeeklyEvents(ctx context.Context) ([]entity.Event, error)
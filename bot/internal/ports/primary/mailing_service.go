@@ -0,0 +1,26 @@
+package primary
+
+import (
+	"context"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// MailingService defines the interface for club mailing use cases
+type MailingService interface {
+	Send(ctx context.Context, mailing *entity.Mailing, what interface{}) (*entity.Mailing, error)
+	Get(ctx context.Context, id string) (*entity.Mailing, error)
+	GetByClubID(ctx context.Context, clubID string, limit, offset int) ([]entity.Mailing, error)
+	GetScheduledByClubID(ctx context.Context, clubID string) ([]entity.Mailing, error)
+	CountByClubID(ctx context.Context, clubID string) (int64, error)
+	GetStats(ctx context.Context, id string) (dto.MailingStats, error)
+	Reschedule(ctx context.Context, id string, scheduledAt time.Time) (*entity.Mailing, error)
+	UpdateText(ctx context.Context, id string, text string) (*entity.Mailing, error)
+	CancelScheduled(ctx context.Context, id string) (*entity.Mailing, error)
+	RetryFailed(ctx context.Context, id string) (int, error)
+	DispatchDue(ctx context.Context) error
+	StartScheduler() error
+	StopScheduler()
+}
@@ -3,6 +3,7 @@ package primary
 import (
 	"go.uber.org/zap/zapcore"
 
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
 	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
 )
 
@@ -10,7 +11,10 @@ import (
 type NotifyService interface {
 	LogHook(channelID int64, locale string, level zapcore.Level) (types.LogHook, error)
 	SendClubWarning(clubID string, what interface{}, opts ...interface{}) error
+	SendClubStaffUpdate(clubID string, what interface{}, opts ...interface{}) error
+	SendAdminWarning(what interface{}, opts ...interface{}) error
 	SendEventUpdate(eventID string, what interface{}, opts ...interface{}) error
+	SendEventAnnouncement(event *entity.Event) error
 	StartNotifyScheduler()
 	StartClubOwnerReminderScheduler() error
 	StopClubOwnerReminderScheduler()
@@ -0,0 +1,12 @@
+package primary
+
+import "context"
+
+// OutboxService defines the interface for queued delivery of Telegram messages
+type OutboxService interface {
+	Enqueue(ctx context.Context, chatID int64, what interface{}, opts ...interface{}) error
+	EnqueueMany(ctx context.Context, chatIDs []int64, what interface{}, opts ...interface{}) error
+	EnqueueMailing(ctx context.Context, mailingID string, chatIDs []int64, what interface{}, opts ...interface{}) error
+	Start() error
+	Stop()
+}
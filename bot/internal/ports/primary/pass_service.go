@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
 )
 
@@ -36,5 +37,39 @@ type PassService interface {
 		reason string,
 		scheduledAt time.Time,
 	) ([]entity.Pass, []error)
+	CreateManualPasses(
+		ctx context.Context,
+		event *entity.Event,
+		userIDs []int64,
+		adminID int64,
+		reason string,
+		sendNow bool,
+	) ([]entity.Pass, []error)
+	GetEventPasses(ctx context.Context, eventID string) ([]dto.EventPass, error)
+	GetPass(ctx context.Context, id string) (*entity.Pass, error)
+	CancelPass(ctx context.Context, passID string) (*entity.Pass, error)
+	AddGuest(
+		ctx context.Context,
+		event *entity.Event,
+		fio string,
+		phone string,
+		requesterType entity.PassRequesterType,
+		requesterID any,
+	) (*entity.Pass, error)
+	GetGuests(ctx context.Context, eventID string) ([]entity.Pass, error)
+	RemoveGuest(ctx context.Context, passID string) (*entity.Pass, error)
+	CancelEventPasses(ctx context.Context, event *entity.Event) error
+	GetLocations(ctx context.Context) ([]entity.PassLocation, error)
+	GetLocation(ctx context.Context, id string) (*entity.PassLocation, error)
+	MatchLocation(ctx context.Context, eventLocation string) (*entity.PassLocation, error)
+	CreateLocation(ctx context.Context, location *entity.PassLocation) (*entity.PassLocation, error)
+	UpdateLocation(ctx context.Context, location *entity.PassLocation) (*entity.PassLocation, error)
+	GetSchedules(ctx context.Context, locationID string) ([]entity.PassSchedule, error)
+	GetSchedule(ctx context.Context, id string) (*entity.PassSchedule, error)
+	CreateSchedule(ctx context.Context, schedule *entity.PassSchedule) (*entity.PassSchedule, error)
+	UpdateSchedule(ctx context.Context, schedule *entity.PassSchedule) (*entity.PassSchedule, error)
+	CalculateScheduledAt(ctx context.Context, event *entity.Event) (time.Time, error)
+	GetRecentBatches(ctx context.Context, limit int) ([]entity.PassBatch, error)
+	ResendBatch(ctx context.Context, id string) (*entity.PassBatch, error)
 	StopScheduler()
 }
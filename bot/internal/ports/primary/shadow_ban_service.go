@@ -0,0 +1,17 @@
+package primary
+
+import (
+	"context"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// ShadowBanService defines the interface for shadow ban use cases
+type ShadowBanService interface {
+	Add(ctx context.Context, ban *entity.ShadowBan) (*entity.ShadowBan, error)
+	Remove(ctx context.Context, id string) (*entity.ShadowBan, error)
+	RemoveByUserID(ctx context.Context, userID int64) (int, error)
+	GetActive(ctx context.Context) ([]dto.ShadowBan, error)
+	Seed(ctx context.Context) error
+}
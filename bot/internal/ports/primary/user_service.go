@@ -15,13 +15,13 @@ type UserService interface {
 	Create(ctx context.Context, user entity.User) (*entity.User, error)
 	Get(ctx context.Context, userID int64) (*entity.User, error)
 	GetByEmail(ctx context.Context, email valueobject.Email) (*entity.User, error)
+	GetByUsername(ctx context.Context, username string) (*entity.User, error)
 	GetByQRCodeID(ctx context.Context, qrCodeID string) (*entity.User, error)
 	GetAll(ctx context.Context) ([]entity.User, error)
 	Update(ctx context.Context, user *entity.User) (*entity.User, error)
 	UpdateData(ctx context.Context, c tele.Context) (*entity.User, error)
 	Count(ctx context.Context) (int64, error)
 	GetWithPagination(ctx context.Context, limit int, offset int, order string) ([]entity.User, error)
-	Ban(ctx context.Context, userID int64) (*entity.User, error)
 	GetUsersByEventID(ctx context.Context, eventID string) ([]entity.User, error)
 	GetEventUsers(ctx context.Context, eventID string) ([]dto.EventUser, error)
 	GetUsersByClubID(ctx context.Context, clubID string) ([]entity.User, error)
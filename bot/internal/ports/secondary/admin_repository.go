@@ -0,0 +1,15 @@
+package secondary
+
+import (
+	"context"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// AdminRepository defines the interface for admin data access
+type AdminRepository interface {
+	Get(ctx context.Context, userID int64) (*entity.Admin, error)
+	GetAll(ctx context.Context) ([]entity.Admin, error)
+	Save(ctx context.Context, admin *entity.Admin) (*entity.Admin, error)
+	Delete(ctx context.Context, userID int64) error
+}
@@ -0,0 +1,23 @@
+package secondary
+
+import (
+	"context"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// APITokenRepository defines the interface for club API token data access
+type APITokenRepository interface {
+	Create(ctx context.Context, token *entity.APIToken) (*entity.APIToken, error)
+	Get(ctx context.Context, id string) (*entity.APIToken, error)
+	GetByHash(ctx context.Context, hash string) (*entity.APIToken, error)
+	GetByClubID(ctx context.Context, clubID string) ([]entity.APIToken, error)
+	Update(ctx context.Context, token *entity.APIToken) (*entity.APIToken, error)
+	UpdateLastUsedAt(ctx context.Context, id string, lastUsedAt time.Time) error
+}
+
+// APIRequestLogRepository defines the interface for HTTP API audit log data access
+type APIRequestLogRepository interface {
+	Create(ctx context.Context, log *entity.APIRequestLog) error
+}
@@ -0,0 +1,14 @@
+package secondary
+
+import (
+	"context"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// AuditRepository defines the interface for audit log data access, the log is append-only
+type AuditRepository interface {
+	Create(ctx context.Context, entry *entity.AuditEntry) (*entity.AuditEntry, error)
+	Search(ctx context.Context, filter dto.AuditFilter) ([]entity.AuditEntry, error)
+}
@@ -0,0 +1,18 @@
+package secondary
+
+import (
+	"context"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// ClubApplicationRepository defines the interface for club application data access
+type ClubApplicationRepository interface {
+	Create(ctx context.Context, application *entity.ClubApplication) (*entity.ClubApplication, error)
+	Get(ctx context.Context, id string) (*entity.ClubApplication, error)
+	Update(ctx context.Context, application *entity.ClubApplication) (*entity.ClubApplication, error)
+	GetPending(ctx context.Context) ([]entity.ClubApplication, error)
+	CountPendingByApplicantID(ctx context.Context, applicantID int64) (int64, error)
+	Approve(ctx context.Context, application *entity.ClubApplication, club *entity.Club, owners []entity.ClubOwner) error
+	Reject(ctx context.Context, application *entity.ClubApplication) error
+}
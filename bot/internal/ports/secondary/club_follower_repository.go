@@ -0,0 +1,16 @@
+package secondary
+
+import (
+	"context"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// ClubFollowerRepository defines the interface for club follower data access
+type ClubFollowerRepository interface {
+	Create(ctx context.Context, follower *entity.ClubFollower) error
+	Delete(ctx context.Context, userID int64, clubID string) error
+	Exists(ctx context.Context, userID int64, clubID string) (bool, error)
+	GetClubsByUserID(ctx context.Context, userID int64) ([]entity.Club, error)
+	CountByClubID(ctx context.Context, clubID string) (int64, error)
+}
@@ -13,6 +13,8 @@ type ClubOwnerRepository interface {
 	Delete(ctx context.Context, userID int64, clubID string) error
 	Get(ctx context.Context, clubID string, userID int64) (*entity.ClubOwner, error)
 	Update(ctx context.Context, clubOwner *entity.ClubOwner) (*entity.ClubOwner, error)
+	Transfer(ctx context.Context, clubID string, fromUserID, toUserID int64) error
+	Leave(ctx context.Context, clubID string, userID int64) error
 	GetByClubID(ctx context.Context, clubID string) ([]dto.ClubOwner, error)
 	GetByUserID(ctx context.Context, userID int64) ([]dto.ClubOwner, error)
 	GetAllUniqueClubOwners(ctx context.Context) ([]dto.ClubOwner, error)
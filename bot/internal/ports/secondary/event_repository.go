@@ -19,7 +19,9 @@ type EventRepository interface {
 	GetByClubID(ctx context.Context, limit, offset int, clubID string) ([]entity.Event, error)
 	GetFutureByClubID(ctx context.Context, limit, offset int, order string, clubID string, additionalTime time.Duration) ([]entity.Event, error)
 	GetUpcomingEvents(ctx context.Context, before time.Time) ([]entity.Event, error)
+	GetFutureBySeriesID(ctx context.Context, seriesID string, after time.Time) ([]entity.Event, error)
 	Update(ctx context.Context, event *entity.Event) (*entity.Event, error)
+	Cancel(ctx context.Context, id string, reason string) (*entity.Event, error)
 	Delete(ctx context.Context, id string) error
 	Count(ctx context.Context, role string) (int64, error)
 	CountByClubID(ctx context.Context, clubID string) (int64, error)
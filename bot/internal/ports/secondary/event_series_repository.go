@@ -0,0 +1,17 @@
+package secondary
+
+import (
+	"context"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// EventSeriesRepository defines the interface for event series data access
+type EventSeriesRepository interface {
+	Create(ctx context.Context, series *entity.EventSeries) (*entity.EventSeries, error)
+	Get(ctx context.Context, id string) (*entity.EventSeries, error)
+	Update(ctx context.Context, series *entity.EventSeries) (*entity.EventSeries, error)
+	GetActive(ctx context.Context, now time.Time) ([]entity.EventSeries, error)
+	CreateOccurrences(ctx context.Context, series *entity.EventSeries, occurrences []entity.Event) error
+}
@@ -0,0 +1,24 @@
+package secondary
+
+import (
+	"context"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// MailingRepository defines the interface for mailing data access
+type MailingRepository interface {
+	Create(ctx context.Context, mailing *entity.Mailing) (*entity.Mailing, error)
+	UpdateScheduled(ctx context.Context, mailing *entity.Mailing) (*entity.Mailing, error)
+	Dispatch(ctx context.Context, mailing *entity.Mailing, recipients []entity.MailingRecipient, messages []entity.OutboxMessage) error
+	Get(ctx context.Context, id string) (*entity.Mailing, error)
+	GetScheduledByClubID(ctx context.Context, clubID string) ([]entity.Mailing, error)
+	GetDue(ctx context.Context, before time.Time) ([]entity.Mailing, error)
+	GetByClubID(ctx context.Context, clubID string, limit, offset int) ([]entity.Mailing, error)
+	CountByClubID(ctx context.Context, clubID string) (int64, error)
+	GetStats(ctx context.Context, id string) (dto.MailingStats, error)
+	GetRecipientsByStatus(ctx context.Context, id string, statuses ...entity.MailingRecipientStatus) ([]entity.MailingRecipient, error)
+	UpdateRecipientStatus(ctx context.Context, id string, userIDs []int64, status entity.MailingRecipientStatus, errText string) error
+}
@@ -0,0 +1,18 @@
+package secondary
+
+import (
+	"context"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// OutboxRepository defines the interface for outbox message data access
+type OutboxRepository interface {
+	CreateMany(ctx context.Context, messages []entity.OutboxMessage) error
+	// ClaimDue returns pending messages due at now and postpones them until leaseUntil,
+	// so they are not claimed again while being sent
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]entity.OutboxMessage, error)
+	Update(ctx context.Context, message *entity.OutboxMessage) (*entity.OutboxMessage, error)
+	DeleteSentBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,17 @@
+package secondary
+
+import (
+	"context"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// PassBatchRepository defines the interface for pass batch data access
+type PassBatchRepository interface {
+	Create(ctx context.Context, batch *entity.PassBatch) (*entity.PassBatch, error)
+	Get(ctx context.Context, id string) (*entity.PassBatch, error)
+	GetRecent(ctx context.Context, limit int) ([]entity.PassBatch, error)
+	GetUndelivered(ctx context.Context) ([]entity.PassBatch, error)
+	Update(ctx context.Context, batch *entity.PassBatch) (*entity.PassBatch, error)
+	UpdateDelivery(ctx context.Context, delivery *entity.PassBatchDelivery) error
+}
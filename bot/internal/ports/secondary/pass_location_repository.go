@@ -0,0 +1,17 @@
+package secondary
+
+import (
+	"context"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// PassLocationRepository defines the interface for pass location data access
+type PassLocationRepository interface {
+	Create(ctx context.Context, location *entity.PassLocation) (*entity.PassLocation, error)
+	Get(ctx context.Context, id string) (*entity.PassLocation, error)
+	GetAll(ctx context.Context) ([]entity.PassLocation, error)
+	Update(ctx context.Context, location *entity.PassLocation) (*entity.PassLocation, error)
+	Count(ctx context.Context) (int64, error)
+	AssignUnlocated(ctx context.Context, locationID string) error
+}
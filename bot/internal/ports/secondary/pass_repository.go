@@ -16,11 +16,17 @@ type PassRepository interface {
 	GetPassesByUserID(ctx context.Context, userID int64, limit, offset int) ([]entity.Pass, error)
 	GetPendingPassesForSchedule(ctx context.Context, before time.Time) ([]entity.Pass, error)
 	MarkPassAsSent(ctx context.Context, id string, sentAt time.Time, emailSent, telegramSent bool) error
-	MarkPassesAsSent(ctx context.Context, ids []string, sentAt time.Time, emailSent, telegramSent bool) error
+	MarkPassesAsSent(ctx context.Context, ids []string, sentAt time.Time, batchID *string) error
+	UpdateBatchDelivery(ctx context.Context, batchID string, emailSent, telegramSent bool) error
+	GetUnnotifiedPassesByBatchID(ctx context.Context, batchID string) ([]entity.Pass, error)
+	MarkPassesAsNotified(ctx context.Context, ids []string, notifiedAt time.Time) error
 	CreateBulkPasses(ctx context.Context, passes []entity.Pass) error
 	GetActivePassForUser(ctx context.Context, eventID string, userID int64) (*entity.Pass, error)
 	HasActivePass(ctx context.Context, eventID string, userID int64) (bool, error)
 	CancelPassesByEventAndUser(ctx context.Context, eventID string, userID int64) error
+	CancelPendingPassesByUserID(ctx context.Context, userID int64) error
+	GetPassCorrections(ctx context.Context, scheduledBefore, startAfter time.Time) ([]entity.Pass, error)
+	MarkPassesCancellationSent(ctx context.Context, ids []string, sentAt time.Time) error
 	GetPassesByRequester(ctx context.Context, requesterType entity.PassRequesterType, requesterID string, limit, offset int) ([]entity.Pass, error)
 	CountPassesByRequester(ctx context.Context, requesterType entity.PassRequesterType, requesterID string) (int64, error)
 	GetPassesByEventAndRequester(ctx context.Context, eventID string, requesterType entity.PassRequesterType, requesterID string) ([]entity.Pass, error)
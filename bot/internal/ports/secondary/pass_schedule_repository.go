@@ -0,0 +1,17 @@
+package secondary
+
+import (
+	"context"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// PassScheduleRepository defines the interface for pass schedule data access
+type PassScheduleRepository interface {
+	Create(ctx context.Context, schedule *entity.PassSchedule) (*entity.PassSchedule, error)
+	Get(ctx context.Context, id string) (*entity.PassSchedule, error)
+	GetAll(ctx context.Context) ([]entity.PassSchedule, error)
+	GetByLocationID(ctx context.Context, locationID string) ([]entity.PassSchedule, error)
+	Update(ctx context.Context, schedule *entity.PassSchedule) (*entity.PassSchedule, error)
+	Count(ctx context.Context) (int64, error)
+}
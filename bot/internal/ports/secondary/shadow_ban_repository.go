@@ -0,0 +1,18 @@
+package secondary
+
+import (
+	"context"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// ShadowBanRepository defines the interface for shadow ban data access
+type ShadowBanRepository interface {
+	Create(ctx context.Context, ban *entity.ShadowBan) (*entity.ShadowBan, error)
+	Get(ctx context.Context, id string) (*entity.ShadowBan, error)
+	GetActive(ctx context.Context, now time.Time) ([]entity.ShadowBan, error)
+	GetActiveByUserID(ctx context.Context, userID int64, now time.Time) ([]entity.ShadowBan, error)
+	Delete(ctx context.Context, ids []string) error
+	CountAll(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,17 @@
+package secondary
+
+import (
+	"context"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// UserBanRepository defines the interface for user ban data access
+type UserBanRepository interface {
+	Create(ctx context.Context, ban *entity.UserBan) (*entity.UserBan, error)
+	Update(ctx context.Context, ban *entity.UserBan) (*entity.UserBan, error)
+	GetActiveByUserID(ctx context.Context, userID int64, now time.Time) (*entity.UserBan, error)
+	GetActive(ctx context.Context, now time.Time) ([]entity.UserBan, error)
+	GetExpired(ctx context.Context, now time.Time) ([]entity.UserBan, error)
+}
@@ -2,6 +2,7 @@ package secondary
 
 import (
 	"context"
+	"time"
 
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
@@ -16,12 +17,15 @@ type UserRepository interface {
 	GetByQRCodeID(ctx context.Context, qrCodeID string) (*entity.User, error)
 	GetMany(ctx context.Context, ids []int64) ([]entity.User, error)
 	GetByEmail(ctx context.Context, email valueobject.Email) (*entity.User, error)
+	GetByUsername(ctx context.Context, username string) (*entity.User, error)
 	GetAll(ctx context.Context) ([]entity.User, error)
 	GetEventUsers(ctx context.Context, eventID string) ([]dto.EventUser, error)
 	GetUsersByEventID(ctx context.Context, eventID string) ([]entity.User, error)
 	GetUsersByClubID(ctx context.Context, clubID string) ([]entity.User, error)
+	GetClubFollowers(ctx context.Context, clubID string) ([]entity.User, error)
 	GetManyUsersByEventIDs(ctx context.Context, eventIDs []string) ([]entity.User, error)
 	Update(ctx context.Context, user *entity.User) (*entity.User, error)
+	SetBotBlocked(ctx context.Context, userID int64, blockedAt *time.Time) error
 	Count(ctx context.Context) (int64, error)
 	GetWithPagination(ctx context.Context, limit, offset int, order string) ([]entity.User, error)
 	IgnoreMailing(ctx context.Context, userID int64, clubID string) (bool, error)
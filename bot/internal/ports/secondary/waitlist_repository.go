@@ -0,0 +1,23 @@
+package secondary
+
+import (
+	"context"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// WaitlistRepository defines the interface for event waitlist data access
+type WaitlistRepository interface {
+	Create(ctx context.Context, entry *entity.WaitlistEntry) (*entity.WaitlistEntry, error)
+	Update(ctx context.Context, entry *entity.WaitlistEntry) (*entity.WaitlistEntry, error)
+	// Transition saves the status and the offer of the entry only if its status is still from,
+	// it returns false if the entry was changed concurrently
+	Transition(ctx context.Context, entry *entity.WaitlistEntry, from entity.WaitlistStatus) (bool, error)
+	GetActive(ctx context.Context, eventID string, userID int64) (*entity.WaitlistEntry, error)
+	GetNextWaiting(ctx context.Context, eventID string) (*entity.WaitlistEntry, error)
+	CountWaiting(ctx context.Context, eventID string) (int64, error)
+	CountWaitingBefore(ctx context.Context, eventID string, createdAt time.Time) (int64, error)
+	GetExpiredOffers(ctx context.Context, now time.Time) ([]entity.WaitlistEntry, error)
+	CancelWaitingByUserID(ctx context.Context, userID int64) error
+}